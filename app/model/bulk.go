@@ -0,0 +1,88 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Operations of POST /alumni/bulk and /pekerjaan/bulk. soft_delete only
+// applies to pekerjaan, delete of a pekerjaan only to one in the trash.
+const (
+    BulkCreate     = "create"
+    BulkUpdate     = "update"
+    BulkSoftDelete = "soft_delete"
+    BulkDelete     = "delete"
+)
+
+// Outcomes of one bulk operation. Skipped operations were not run because
+// an earlier one of an ordered batch failed.
+const (
+    BulkOK      = "ok"
+    BulkFailed  = "failed"
+    BulkSkipped = "skipped"
+)
+
+// MaxBulkOperations caps the operations of one bulk request
+const MaxBulkOperations = 500
+
+// AlumniBulkRequest - Request for POST /alumni/bulk. Batches are ordered
+// unless ordered is false, as with Mongo's BulkWrite.
+type AlumniBulkRequest struct {
+    Ordered    *bool                 `json:"ordered"`
+    Operations []AlumniBulkOperation `json:"operations" validate:"required"`
+}
+
+// AlumniBulkOperation is one create, update or delete
+type AlumniBulkOperation struct {
+    Op     string               `json:"op" validate:"required"`
+    ID     string               `json:"id"`     // update and delete
+    Alumni *CreateAlumniRequest `json:"alumni"` // create and update; user_id is only read on create
+}
+
+// PekerjaanBulkRequest - Request for POST /pekerjaan/bulk. Batches are
+// ordered unless ordered is false, as with Mongo's BulkWrite.
+type PekerjaanBulkRequest struct {
+    Ordered    *bool                    `json:"ordered"`
+    Operations []PekerjaanBulkOperation `json:"operations" validate:"required"`
+}
+
+// PekerjaanBulkOperation is one create, update, soft_delete or delete
+type PekerjaanBulkOperation struct {
+    Op        string                  `json:"op" validate:"required"`
+    ID        string                  `json:"id"`        // update, soft_delete and delete
+    Pekerjaan *CreatePekerjaanRequest `json:"pekerjaan"` // create and update
+}
+
+// BulkItemResult is the outcome of one operation. Status is what the
+// single-item endpoint would have replied, none for skipped operations.
+type BulkItemResult struct {
+    Index  int    `json:"index"`
+    Op     string `json:"op"`
+    ID     string `json:"id,omitempty"`
+    Result string `json:"result"`
+    Status int    `json:"status,omitempty"`
+    Error  string `json:"error,omitempty"`
+}
+
+// BulkResponse - Response for the bulk endpoints, one result per operation
+// in request order
+type BulkResponse struct {
+    Ordered   bool             `json:"ordered"`
+    Succeeded int              `json:"succeeded"`
+    Failed    int              `json:"failed"`
+    Skipped   int              `json:"skipped"`
+    Results   []BulkItemResult `json:"results"`
+}
+
+// AlumniWrite is one checked operation of a bulk write. Creates carry their
+// ID already.
+type AlumniWrite struct {
+    Op     string
+    ID     primitive.ObjectID
+    Alumni Alumni
+}
+
+// PekerjaanWrite is one checked operation of a bulk write. Creates carry
+// their ID already.
+type PekerjaanWrite struct {
+    Op        string
+    ID        primitive.ObjectID
+    Pekerjaan Pekerjaan
+}
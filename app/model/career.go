@@ -0,0 +1,232 @@
+package model
+
+import (
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Position levels read from posisi_jabatan, most junior first
+const (
+    LevelMagang    = "magang"
+    LevelJunior    = "junior"
+    LevelStaf      = "staf"
+    LevelSenior    = "senior"
+    LevelManajer   = "manajer"
+    LevelEksekutif = "eksekutif"
+)
+
+// PositionLevels lists the levels in order
+var PositionLevels = []string{LevelMagang, LevelJunior, LevelStaf, LevelSenior, LevelManajer, LevelEksekutif}
+
+// levelPatterns are tried in order, so "Senior Manager" is a manajer; what
+// matches none is staf
+var levelPatterns = []struct {
+    level   string
+    pattern *regexp.Regexp
+}{
+    {LevelEksekutif, regexp.MustCompile(`\b(direktur|director|chief|ceo|cto|cfo|coo|vp|vice president|komisaris)\b`)},
+    {LevelManajer, regexp.MustCompile(`\b(manajer|manager|kepala|head|supervisor|koordinator|coordinator)\b`)},
+    {LevelMagang, regexp.MustCompile(`\b(magang|intern|internship|trainee|praktikan)\b`)},
+    {LevelJunior, regexp.MustCompile(`\b(junior|jr\.?|associate|asisten|assistant)\b`)},
+    {LevelSenior, regexp.MustCompile(`\b(senior|sr\.?|lead|principal|ahli|expert|specialist|spesialis)\b`)},
+}
+
+// PositionLevel classifies a posisi_jabatan such as "Senior Backend
+// Developer" into one of PositionLevels
+func PositionLevel(posisi string) string {
+    posisi = strings.ToLower(posisi)
+    for _, lp := range levelPatterns {
+        if lp.pattern.MatchString(posisi) {
+            return lp.level
+        }
+    }
+    return LevelStaf
+}
+
+// labeler names values differing only in case or surrounding spaces after
+// the first one seen
+func labeler() func(string) string {
+    labels := map[string]string{}
+    return func(value string) string {
+        value = strings.TrimSpace(value)
+        key := strings.ToLower(value)
+        if label, ok := labels[key]; ok {
+            return label
+        }
+        labels[key] = value
+        return value
+    }
+}
+
+// TransitionMatrix counts the moves from one pekerjaan to the next of the
+// same alumni: Counts[i][j] moved from Labels[i] to Labels[j]
+type TransitionMatrix struct {
+    Labels []string `json:"labels"`
+    Counts [][]int  `json:"counts"`
+}
+
+func newTransitionMatrix(labels []string) TransitionMatrix {
+    m := TransitionMatrix{Labels: labels, Counts: make([][]int, len(labels))}
+    for i := range m.Counts {
+        m.Counts[i] = make([]int, len(labels))
+    }
+    return m
+}
+
+func (m TransitionMatrix) add(from, to string) {
+    i, j := -1, -1
+    for k, label := range m.Labels {
+        if label == from {
+            i = k
+        }
+        if label == to {
+            j = k
+        }
+    }
+    m.Counts[i][j]++
+}
+
+// LevelTenure - how long pekerjaan of one position level last
+type LevelTenure struct {
+    Level           string  `json:"level"`
+    Pekerjaan       int     `json:"pekerjaan"`
+    AvgTenureMonths float64 `json:"avg_tenure_months"`
+}
+
+// CareerPath - a sequence of industries or levels followed by at least
+// the minimum group size of alumni, repeats in a row counted once
+type CareerPath struct {
+    Steps []string `json:"steps"`
+    Total int      `json:"total"`
+}
+
+// CareerStats - Response for GET /stats/career-paths. Tenures run to today
+// for pekerjaan without tanggal_selesai_kerja.
+type CareerStats struct {
+    Alumni              int              `json:"alumni"`      // with at least one pekerjaan
+    Transitions         int              `json:"transitions"` // moves to a next pekerjaan
+    MinGroupSize        int              `json:"min_group_size"`
+    IndustryTransitions TransitionMatrix `json:"industry_transitions"`
+    LevelTransitions    TransitionMatrix `json:"level_transitions"`
+    AvgTenureMonths     float64          `json:"avg_tenure_months"`
+    Tenure              []LevelTenure    `json:"tenure"`
+    IndustryPaths       []CareerPath     `json:"industry_paths"`
+    LevelPaths          []CareerPath     `json:"level_paths"`
+}
+
+// maxPathSteps caps the length of a CareerPath; longer careers count by
+// their first steps
+const maxPathSteps = 4
+
+// daysPerMonth is the average length of a month
+const daysPerMonth = 30.4375
+
+// Careers orders the pekerjaan of every alumni of facts by start and
+// follows them from one to the next. paths caps each list of CareerPath.
+func Careers(facts []PekerjaanFact, now time.Time, paths, minSize int) CareerStats {
+    byAlumni := map[string][]PekerjaanFact{}
+    var alumni []string
+    industry := labeler()
+    industries := map[string]bool{}
+    for _, f := range facts {
+        key := f.AlumniID.Hex()
+        if _, ok := byAlumni[key]; !ok {
+            alumni = append(alumni, key)
+        }
+        f.BidangIndustri = industry(f.BidangIndustri)
+        industries[f.BidangIndustri] = true
+        byAlumni[key] = append(byAlumni[key], f)
+    }
+
+    labels := make([]string, 0, len(industries))
+    for label := range industries {
+        labels = append(labels, label)
+    }
+    sort.Strings(labels)
+
+    stats := CareerStats{
+        Alumni:              len(alumni),
+        MinGroupSize:        minSize,
+        IndustryTransitions: newTransitionMatrix(labels),
+        LevelTransitions:    newTransitionMatrix(PositionLevels),
+    }
+
+    totalDays, tenureDays, tenureJobs := 0, map[string]int{}, map[string]int{}
+    industryPaths, levelPaths := map[string]int{}, map[string]int{}
+    for _, id := range alumni {
+        jobs := byAlumni[id]
+        sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].TanggalMulaiKerja.Before(jobs[j].TanggalMulaiKerja) })
+
+        var industrySteps, levelSteps []string
+        for i, job := range jobs {
+            level := PositionLevel(job.PosisiJabatan)
+            d := days(job.TanggalMulaiKerja, job.TanggalSelesaiKerja, now)
+            totalDays += d
+            tenureDays[level] += d
+            tenureJobs[level]++
+
+            if i > 0 {
+                stats.Transitions++
+                stats.IndustryTransitions.add(jobs[i-1].BidangIndustri, job.BidangIndustri)
+                stats.LevelTransitions.add(PositionLevel(jobs[i-1].PosisiJabatan), level)
+            }
+            industrySteps = appendStep(industrySteps, job.BidangIndustri)
+            levelSteps = appendStep(levelSteps, level)
+        }
+        if len(industrySteps) > 1 {
+            industryPaths[strings.Join(industrySteps, "\x00")]++
+        }
+        if len(levelSteps) > 1 {
+            levelPaths[strings.Join(levelSteps, "\x00")]++
+        }
+    }
+
+    if len(facts) > 0 {
+        stats.AvgTenureMonths = round2(float64(totalDays) / float64(len(facts)) / daysPerMonth)
+    }
+    stats.Tenure = []LevelTenure{}
+    for _, level := range PositionLevels {
+        if n := tenureJobs[level]; n > 0 {
+            stats.Tenure = append(stats.Tenure, LevelTenure{
+                Level:           level,
+                Pekerjaan:       n,
+                AvgTenureMonths: round2(float64(tenureDays[level]) / float64(n) / daysPerMonth),
+            })
+        }
+    }
+    stats.IndustryPaths = commonPaths(industryPaths, paths, minSize)
+    stats.LevelPaths = commonPaths(levelPaths, paths, minSize)
+    return stats
+}
+
+// appendStep adds step to a path unless it repeats the last one or the path
+// is full
+func appendStep(steps []string, step string) []string {
+    if len(steps) == maxPathSteps || (len(steps) > 0 && steps[len(steps)-1] == step) {
+        return steps
+    }
+    return append(steps, step)
+}
+
+// commonPaths lists the paths followed by at least minSize alumni, most
+// common first, at most limit of them
+func commonPaths(counts map[string]int, limit, minSize int) []CareerPath {
+    list := []CareerPath{}
+    for key, total := range counts {
+        if total >= minSize {
+            list = append(list, CareerPath{Steps: strings.Split(key, "\x00"), Total: total})
+        }
+    }
+    sort.Slice(list, func(i, j int) bool {
+        if list[i].Total != list[j].Total {
+            return list[i].Total > list[j].Total
+        }
+        return strings.Join(list[i].Steps, "\x00") < strings.Join(list[j].Steps, "\x00")
+    })
+    if len(list) > limit {
+        list = list[:limit]
+    }
+    return list
+}
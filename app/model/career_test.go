@@ -0,0 +1,88 @@
+package model
+
+import (
+    "reflect"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPositionLevel(t *testing.T) {
+    tests := map[string]string{
+        "Backend Developer":        LevelStaf,
+        "Junior Data Analyst":      LevelJunior,
+        "Jr. Accountant":           LevelJunior,
+        "Senior Backend Developer": LevelSenior,
+        "Tech Lead":                LevelSenior,
+        "Senior Manager":           LevelManajer,
+        "Kepala Cabang":            LevelManajer,
+        "Magang IT Support":        LevelMagang,
+        "Chief Technology Officer": LevelEksekutif,
+        "Leader":                   LevelStaf, // whole words only
+    }
+    for posisi, want := range tests {
+        if got := PositionLevel(posisi); got != want {
+            t.Errorf("PositionLevel(%q) = %s, want %s", posisi, got, want)
+        }
+    }
+}
+
+func TestCareers(t *testing.T) {
+    career := func(steps ...PekerjaanFact) []PekerjaanFact {
+        id := primitive.NewObjectID()
+        for i := range steps {
+            steps[i].AlumniID = id
+        }
+        return steps
+    }
+    step := func(industri, posisi string, year int, end *int) PekerjaanFact {
+        f := PekerjaanFact{BidangIndustri: industri, PosisiJabatan: posisi, TanggalMulaiKerja: day(year, 1, 1)}
+        if end != nil {
+            f.TanggalSelesaiKerja = dayPtr(*end, 1, 1)
+        }
+        return f
+    }
+    y := func(year int) *int { return &year }
+
+    var facts []PekerjaanFact
+    for i := 0; i < 2; i++ {
+        // Listed out of order, Careers sorts by start
+        facts = append(facts, career(
+            step("Banking", "Senior Analyst", 2022, nil),
+            step("Technology", "Junior Developer", 2020, y(2021)),
+            step("technology", "Developer", 2021, y(2022)),
+        )...)
+    }
+    facts = append(facts, career(step("Retail", "Store Manager", 2020, y(2022)), step("Banking", "Analyst", 2022, nil))...)
+    facts = append(facts, career(step("Government", "Staff", 2021, y(2023)))...)
+
+    stats := Careers(facts, day(2024, 1, 1), 10, 2)
+    if stats.Alumni != 4 || stats.Transitions != 5 {
+        t.Errorf("alumni = %d, transitions = %d", stats.Alumni, stats.Transitions)
+    }
+
+    wantLabels := []string{"Banking", "Government", "Retail", "Technology"}
+    wantCounts := [][]int{{0, 0, 0, 0}, {0, 0, 0, 0}, {1, 0, 0, 0}, {2, 0, 0, 2}}
+    if !reflect.DeepEqual(stats.IndustryTransitions.Labels, wantLabels) || !reflect.DeepEqual(stats.IndustryTransitions.Counts, wantCounts) {
+        t.Errorf("industry transitions = %+v", stats.IndustryTransitions)
+    }
+    // junior -> staf and staf -> senior twice, manajer -> staf once
+    levels := stats.LevelTransitions.Counts
+    if levels[1][2] != 2 || levels[2][3] != 2 || levels[4][2] != 1 {
+        t.Errorf("level transitions = %v", levels)
+    }
+
+    want := []CareerPath{{Steps: []string{"Technology", "Banking"}, Total: 2}}
+    if !reflect.DeepEqual(stats.IndustryPaths, want) {
+        t.Errorf("industry paths = %+v, want %+v", stats.IndustryPaths, want)
+    }
+    if len(stats.LevelPaths) != 1 || !reflect.DeepEqual(stats.LevelPaths[0].Steps, []string{LevelJunior, LevelStaf, LevelSenior}) {
+        t.Errorf("level paths = %+v", stats.LevelPaths)
+    }
+
+    // Four pekerjaan of a year, the others two years including the open
+    // ones until 2024
+    if stats.AvgTenureMonths != 18.66 || len(stats.Tenure) != 4 || stats.Tenure[0] != (LevelTenure{LevelJunior, 2, 12.02}) {
+        t.Errorf("tenure = %v, %+v", stats.AvgTenureMonths, stats.Tenure)
+    }
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+    "math"
+    "sort"
+    "strings"
+    "time"
+    "unicode"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Company - an employer in the companies registry. NamaKey and AliasKeys are
+// the normalized names pekerjaan are matched on.
+type Company struct {
+    ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+    Nama           string             `json:"nama" bson:"nama"`
+    NamaKey        string             `json:"-" bson:"nama_key"`
+    Aliases        []string           `json:"aliases" bson:"aliases"`
+    AliasKeys      []string           `json:"-" bson:"alias_keys"`
+    BidangIndustri string             `json:"bidang_industri" bson:"bidang_industri"`
+    Lokasi         string             `json:"lokasi" bson:"lokasi"`
+    CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+    UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// CompanyAlumni - an alumni with the pekerjaan they had at one company
+type CompanyAlumni struct {
+    Alumni    Alumni
+    Pekerjaan []Pekerjaan
+}
+
+// CreateCompanyRequest - Request for POST /companies
+type CreateCompanyRequest struct {
+    Nama           string   `json:"nama" validate:"required"`
+    Aliases        []string `json:"aliases"`
+    BidangIndustri string   `json:"bidang_industri"`
+    Lokasi         string   `json:"lokasi"`
+}
+
+// UpdateCompanyRequest - Request for PUT /companies/:id
+type UpdateCompanyRequest struct {
+    Nama           string   `json:"nama" validate:"required"`
+    Aliases        []string `json:"aliases"`
+    BidangIndustri string   `json:"bidang_industri"`
+    Lokasi         string   `json:"lokasi"`
+}
+
+// CompanyResponse - Response for single company
+type CompanyResponse struct {
+    ID             string    `json:"id"`
+    Nama           string    `json:"nama"`
+    Aliases        []string  `json:"aliases"`
+    BidangIndustri string    `json:"bidang_industri"`
+    Lokasi         string    `json:"lokasi"`
+    CreatedAt      time.Time `json:"created_at"`
+    UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CompanyMatch - a registered company suggested for a free-text name
+type CompanyMatch struct {
+    Company CompanyResponse `json:"company"`
+    Score   float64         `json:"score"`
+    Exact   bool            `json:"exact"` // same name once normalized
+}
+
+// CompanyAlumniResponse - Response item for GET /companies/:id/alumni
+type CompanyAlumniResponse struct {
+    AlumniID   string                     `json:"alumni_id"`
+    NIM        string                     `json:"nim"`
+    Nama       string                     `json:"nama"`
+    Jurusan    string                     `json:"jurusan"`
+    TahunLulus int                        `json:"tahun_lulus"`
+    Pekerjaan  []CompanyPekerjaanResponse `json:"pekerjaan"`
+}
+
+// CompanyPekerjaanResponse - one pekerjaan of an alumni at the company
+type CompanyPekerjaanResponse struct {
+    ID                  string     `json:"id"`
+    PosisiJabatan       string     `json:"posisi_jabatan"`
+    TanggalMulaiKerja   time.Time  `json:"tanggal_mulai_kerja"`
+    TanggalSelesaiKerja *time.Time `json:"tanggal_selesai_kerja"`
+    StatusPekerjaan     string     `json:"status_pekerjaan"`
+}
+
+// CompanySuggestThreshold is the lowest score suggested as a match
+const CompanySuggestThreshold = 0.7
+
+// legal forms dropped when normalizing, so "PT. Digital Indonesia Tbk" and
+// "Digital Indonesia" are the same company
+var companyLegalForms = map[string]bool{
+    "pt": true, "cv": true, "ud": true, "pd": true, "fa": true, "tbk": true, "persero": true, "perum": true,
+    "ltd": true, "limited": true, "inc": true, "corp": true, "corporation": true, "co": true, "llc": true,
+    "pte": true, "sdn": true, "bhd": true, "gmbh": true, "plc": true,
+}
+
+// NormalizeCompanyName lowercases name, drops punctuation and legal forms and
+// collapses whitespace
+func NormalizeCompanyName(name string) string {
+    fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+
+    var kept []string
+    for _, field := range fields {
+        if !companyLegalForms[field] {
+            kept = append(kept, field)
+        }
+    }
+    // A name that is only a legal form, e.g. "PT", stays as written
+    if len(kept) == 0 {
+        kept = fields
+    }
+    return strings.Join(kept, " ")
+}
+
+// SetNames stores the canonical name and aliases with their normalized keys.
+// Blank aliases and aliases normalizing to another name are dropped.
+func (c *Company) SetNames(nama string, aliases []string) {
+    c.Nama = strings.TrimSpace(nama)
+    c.NamaKey = NormalizeCompanyName(c.Nama)
+
+    c.Aliases, c.AliasKeys = []string{}, []string{}
+    seen := map[string]bool{c.NamaKey: true}
+    for _, alias := range aliases {
+        alias = strings.TrimSpace(alias)
+        key := NormalizeCompanyName(alias)
+        if key == "" || seen[key] {
+            continue
+        }
+        seen[key] = true
+        c.Aliases = append(c.Aliases, alias)
+        c.AliasKeys = append(c.AliasKeys, key)
+    }
+}
+
+// Keys are the normalized canonical name and aliases
+func (c *Company) Keys() []string {
+    return append([]string{c.NamaKey}, c.AliasKeys...)
+}
+
+// MatchScore is how closely name resembles the company's best matching
+// name, from 0 to 1. 1 means an exact match once normalized.
+func (c *Company) MatchScore(name string) float64 {
+    key := NormalizeCompanyName(name)
+    best := 0.0
+    for _, candidate := range c.Keys() {
+        best = math.Max(best, CompanySimilarity(key, candidate))
+    }
+    return best
+}
+
+// CompanySimilarity compares two normalized names: the better of their edit
+// distance ratio and the overlap of their words
+func CompanySimilarity(a, b string) float64 {
+    if a == "" || b == "" {
+        return 0
+    }
+    if a == b {
+        return 1
+    }
+
+    ra, rb := []rune(a), []rune(b)
+    edit := 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+
+    words := map[string]int{}
+    for _, w := range strings.Fields(a) {
+        words[w] |= 1
+    }
+    for _, w := range strings.Fields(b) {
+        words[w] |= 2
+    }
+    shared := 0
+    for _, in := range words {
+        if in == 3 {
+            shared++
+        }
+    }
+    overlap := float64(shared) / float64(len(words))
+
+    // Distinct names never score a full match
+    return math.Min(math.Max(edit, overlap), 0.99)
+}
+
+func levenshtein(a, b []rune) int {
+    previous := make([]int, len(b)+1)
+    current := make([]int, len(b)+1)
+    for j := range previous {
+        previous[j] = j
+    }
+    for i := 1; i <= len(a); i++ {
+        current[0] = i
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] {
+                cost = 0
+            }
+            current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+        }
+        previous, current = current, previous
+    }
+    return previous[len(b)]
+}
+
+// MatchCompanies suggests up to limit companies for a free-text name, best
+// first. Only scores of at least CompanySuggestThreshold are returned.
+func MatchCompanies(companies []Company, name string, limit int) []CompanyMatch {
+    matches := []CompanyMatch{}
+    for i := range companies {
+        score := companies[i].MatchScore(name)
+        if score < CompanySuggestThreshold {
+            continue
+        }
+        matches = append(matches, CompanyMatch{
+            Company: companies[i].ToCompanyResponse(),
+            Score:   math.Round(score*100) / 100,
+            Exact:   score == 1,
+        })
+    }
+
+    sort.SliceStable(matches, func(i, j int) bool {
+        if matches[i].Score != matches[j].Score {
+            return matches[i].Score > matches[j].Score
+        }
+        return matches[i].Company.Nama < matches[j].Company.Nama
+    })
+    if limit > 0 && len(matches) > limit {
+        matches = matches[:limit]
+    }
+    return matches
+}
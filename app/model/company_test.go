@@ -0,0 +1,76 @@
+package model
+
+import "testing"
+
+func TestNormalizeCompanyName(t *testing.T) {
+    for name, want := range map[string]string{
+        "PT Digital Indonesia":          "digital indonesia",
+        "PT. Digital Indonesia":         "digital indonesia",
+        "Digital Indonesia":             "digital indonesia",
+        "  pt digital   INDONESIA, Tbk": "digital indonesia",
+        "PT Bank Rakyat (Persero) Tbk":  "bank rakyat",
+        "Google Asia Pacific Pte. Ltd.": "google asia pacific",
+        "PT":                            "pt",
+        "":                              "",
+    } {
+        if got := NormalizeCompanyName(name); got != want {
+            t.Errorf("NormalizeCompanyName(%q) = %q, want %q", name, got, want)
+        }
+    }
+}
+
+func TestCompanySetNames(t *testing.T) {
+    var c Company
+    c.SetNames(" PT Digital Indonesia ", []string{"Digital Indonesia", "DigiIndo", " ", "digiindo", "Digital Indo"})
+
+    if c.Nama != "PT Digital Indonesia" || c.NamaKey != "digital indonesia" {
+        t.Errorf("nama = %q, key %q", c.Nama, c.NamaKey)
+    }
+    if len(c.Aliases) != 2 || c.Aliases[0] != "DigiIndo" || c.Aliases[1] != "Digital Indo" {
+        t.Errorf("aliases = %q", c.Aliases)
+    }
+    if len(c.AliasKeys) != 2 || c.AliasKeys[0] != "digiindo" {
+        t.Errorf("alias keys = %q", c.AliasKeys)
+    }
+}
+
+func TestMatchCompanies(t *testing.T) {
+    companies := make([]Company, 3)
+    companies[0].SetNames("PT Digital Indonesia", []string{"DigiIndo"})
+    companies[1].SetNames("PT Media Online", nil)
+    companies[2].SetNames("PT Digital Nusantara", nil)
+
+    matches := MatchCompanies(companies, "PT. Digital Indonesia", 5)
+    if len(matches) == 0 || !matches[0].Exact || matches[0].Company.Nama != "PT Digital Indonesia" || matches[0].Score != 1 {
+        t.Fatalf("exact match: %+v", matches)
+    }
+
+    if matches := MatchCompanies(companies, "digiindo", 5); len(matches) == 0 || !matches[0].Exact {
+        t.Errorf("alias match: %+v", matches)
+    }
+
+    matches = MatchCompanies(companies, "PT Digital Indonesa", 5)
+    if len(matches) == 0 || matches[0].Exact || matches[0].Company.Nama != "PT Digital Indonesia" {
+        t.Errorf("typo: %+v", matches)
+    }
+
+    if matches := MatchCompanies(companies, "CV Sumber Rejeki", 5); len(matches) != 0 {
+        t.Errorf("unrelated name matched: %+v", matches)
+    }
+
+    if matches := MatchCompanies(companies, "Digital", 1); len(matches) > 1 {
+        t.Errorf("limit ignored: %+v", matches)
+    }
+}
+
+func TestCompanySimilarity(t *testing.T) {
+    if s := CompanySimilarity("digital indonesia", "digital indonesia"); s != 1 {
+        t.Errorf("equal names = %v, want 1", s)
+    }
+    if s := CompanySimilarity("digital indonesia", "indonesia digital"); s < CompanySuggestThreshold || s >= 1 {
+        t.Errorf("reordered words = %v", s)
+    }
+    if s := CompanySimilarity("digital indonesia", ""); s != 0 {
+        t.Errorf("empty name = %v, want 0", s)
+    }
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// Salary periods
+const (
+    GajiMonthly = "monthly"
+    GajiAnnual  = "annual"
+)
+
+// CurrencyIDR is the currency of salaries written without one
+const CurrencyIDR = "IDR"
+
+// Gaji is a salary range in whole units of Currency per Period. Min or Max
+// is nil for an open range, e.g. "mulai 10 juta".
+type Gaji struct {
+    Min      *int64
+    Max      *int64
+    Currency string
+    Period   string
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Validate checks the bounds, currency and period of a range
+func (g Gaji) Validate() error {
+    if g.Min == nil && g.Max == nil {
+        return errors.New("gaji_min atau gaji_max wajib diisi")
+    }
+    if (g.Min != nil && *g.Min < 0) || (g.Max != nil && *g.Max < 0) {
+        return errors.New("gaji tidak boleh negatif")
+    }
+    if g.Min != nil && g.Max != nil && *g.Min > *g.Max {
+        return errors.New("gaji_min tidak boleh lebih besar dari gaji_max")
+    }
+    if !currencyCode.MatchString(g.Currency) {
+        return fmt.Errorf("mata uang %q tidak valid, gunakan kode ISO seperti IDR", g.Currency)
+    }
+    if g.Period != GajiMonthly && g.Period != GajiAnnual {
+        return fmt.Errorf("periode gaji harus %s atau %s", GajiMonthly, GajiAnnual)
+    }
+    return nil
+}
+
+// Range renders the range the way gaji_range was written before it was
+// structured: "8-12 juta", "mulai 10 juta per tahun", "USD 3,000-4,000"
+func (g Gaji) Range() string {
+    if g.Min == nil && g.Max == nil {
+        return ""
+    }
+
+    var bounds []int64
+    for _, bound := range []*int64{g.Min, g.Max} {
+        if bound != nil {
+            bounds = append(bounds, *bound)
+        }
+    }
+    text := formatAmounts(bounds, g.Currency)
+    switch {
+    case g.Min == nil:
+        text = "hingga " + text
+    case g.Max == nil:
+        text = "mulai " + text
+    }
+    if g.Period == GajiAnnual {
+        text += " per tahun"
+    }
+    return text
+}
+
+// formatAmounts writes one or two amounts joined by "-", in juta when every
+// IDR amount is a whole tenth of a million
+func formatAmounts(amounts []int64, currency string) string {
+    if len(amounts) == 2 && amounts[0] == amounts[1] {
+        amounts = amounts[:1]
+    }
+
+    inJuta := currency == CurrencyIDR
+    for _, amount := range amounts {
+        if amount < 1_000_000 || amount%100_000 != 0 {
+            inJuta = false
+        }
+    }
+
+    parts := make([]string, len(amounts))
+    for i, amount := range amounts {
+        if inJuta {
+            parts[i] = strings.Replace(strconv.FormatFloat(float64(amount)/1_000_000, 'f', -1, 64), ".", ",", 1)
+        } else if currency == CurrencyIDR {
+            parts[i] = groupThousands(amount, ".")
+        } else {
+            parts[i] = groupThousands(amount, ",")
+        }
+    }
+
+    joined := strings.Join(parts, "-")
+    switch {
+    case inJuta:
+        return joined + " juta"
+    case currency == CurrencyIDR:
+        return "Rp " + joined
+    default:
+        return currency + " " + joined
+    }
+}
+
+func groupThousands(n int64, sep string) string {
+    digits := strconv.FormatInt(n, 10)
+    var b strings.Builder
+    for i, d := range digits {
+        if i > 0 && (len(digits)-i)%3 == 0 {
+            b.WriteString(sep)
+        }
+        b.WriteRune(d)
+    }
+    return b.String()
+}
+
+var (
+    // currencies recognised in free text, by how they are written
+    gajiCurrencies = map[string]string{
+        "rp": "IDR", "idr": "IDR", "$": "USD", "usd": "USD", "sgd": "SGD", "myr": "MYR", "rm": "MYR", "eur": "EUR", "€": "EUR",
+    }
+    gajiCurrencyPattern = regexp.MustCompile(`(?:^|[^a-z])(rp|idr|usd|sgd|myr|rm|eur)(?:[^a-z]|$)|([$€])`)
+    gajiAnnualPattern   = regexp.MustCompile(`(?:per|/)\s*(?:tahun|thn|th|year|yr|annum)\b|\b(?:tahunan|annual|annually|yearly|p\.?a\.?)(?:\W|$)`)
+    gajiMonthlyPattern  = regexp.MustCompile(`(?:per|/)\s*(?:bulan|bln|month|mo)\b|\b(?:bulanan|monthly)\b`)
+    gajiAmountPattern   = regexp.MustCompile(`(\d[\d.,]*)\s*(juta|jt|ribu|rb|k)?\b`)
+    gajiMinPrefix       = regexp.MustCompile(`^(>=?|≥|di atas|diatas|lebih dari|mulai|min(imal|\.)?|minimum)`)
+    gajiMaxPrefix       = regexp.MustCompile(`^(<=?|≤|di bawah|dibawah|kurang dari|hingga|sampai|maks(imal|\.)?|max(imum)?|up to)`)
+    gajiGrouped         = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+)
+
+var gajiUnits = map[string]float64{"juta": 1e6, "jt": 1e6, "ribu": 1e3, "rb": 1e3, "k": 1e3}
+
+// ErrGajiFormat is returned for salary text the parser does not understand
+var ErrGajiFormat = errors.New("format gaji tidak dikenali")
+
+// ParseGajiRange reads a free-text salary such as "8-12 juta", "Rp 8.000.000
+// - 12.000.000", "7,5 jt/bulan", "mulai 150 juta per tahun" or "USD
+// 3,000-4,000". The currency defaults to IDR and the period to monthly.
+// Amounts without a unit must be written in full, so "8-12" is rejected.
+func ParseGajiRange(text string) (Gaji, error) {
+    s := strings.ToLower(strings.TrimSpace(text))
+    if s == "" {
+        return Gaji{}, ErrGajiFormat
+    }
+    g := Gaji{Currency: CurrencyIDR, Period: GajiMonthly}
+
+    if m := gajiCurrencyPattern.FindStringSubmatch(s); m != nil {
+        g.Currency = gajiCurrencies[m[1]+m[2]]
+        s = strings.TrimSpace(strings.Replace(s, m[1]+m[2], " ", 1))
+    }
+    if loc := gajiAnnualPattern.FindStringIndex(s); loc != nil {
+        g.Period = GajiAnnual
+        s = s[:loc[0]] + " " + s[loc[1]:]
+    } else if loc := gajiMonthlyPattern.FindStringIndex(s); loc != nil {
+        s = s[:loc[0]] + " " + s[loc[1]:]
+    }
+    s = strings.TrimSpace(s)
+
+    matches := gajiAmountPattern.FindAllStringSubmatch(s, -1)
+    if len(matches) == 0 || len(matches) > 2 {
+        return Gaji{}, ErrGajiFormat
+    }
+
+    // A unit written once applies to every amount: "8-12 juta"
+    unit := ""
+    for _, m := range matches {
+        if m[2] != "" {
+            unit = m[2]
+        }
+    }
+
+    amounts := make([]int64, len(matches))
+    for i, m := range matches {
+        u := m[2]
+        if u == "" {
+            u = unit
+        }
+        n, err := parseGajiNumber(m[1], u != "")
+        if err != nil {
+            return Gaji{}, err
+        }
+        if u == "" {
+            // "8-12" could be juta or a typo; only full amounts are safe
+            if n < 1000 {
+                return Gaji{}, ErrGajiFormat
+            }
+            amounts[i] = int64(math.Round(n))
+        } else {
+            amounts[i] = int64(math.Round(n * gajiUnits[u]))
+        }
+    }
+
+    switch {
+    case len(amounts) == 2:
+        g.Min, g.Max = &amounts[0], &amounts[1]
+    case gajiMinPrefix.MatchString(s) || strings.HasSuffix(s, "+"):
+        g.Min = &amounts[0]
+    case gajiMaxPrefix.MatchString(s):
+        g.Max = &amounts[0]
+    default:
+        g.Min, g.Max = &amounts[0], &amounts[0]
+    }
+
+    if err := g.Validate(); err != nil {
+        return Gaji{}, fmt.Errorf("%w: %v", ErrGajiFormat, err)
+    }
+    return g, nil
+}
+
+// parseGajiNumber reads "8.000.000", "3,000", "7,5" or "7.5". Separators
+// grouping thousands are dropped, unless a unit follows: "1.500 juta" is
+// read as a decimal, like "7,5 juta".
+func parseGajiNumber(s string, withUnit bool) (float64, error) {
+    s = strings.TrimRight(s, ".,")
+    if gajiGrouped.MatchString(s) && !(withUnit && strings.Count(s, ".")+strings.Count(s, ",") == 1) {
+        s = strings.NewReplacer(".", "", ",", "").Replace(s)
+    }
+    n, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+    if err != nil {
+        return 0, ErrGajiFormat
+    }
+    return n, nil
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+    "errors"
+    "testing"
+)
+
+func bound(n int64) *int64 {
+    return &n
+}
+
+func TestParseGajiRange(t *testing.T) {
+    for text, want := range map[string]Gaji{
+        "8-12 juta":                   {Min: bound(8_000_000), Max: bound(12_000_000), Currency: "IDR", Period: "monthly"},
+        "15 - 20 Juta":                {Min: bound(15_000_000), Max: bound(20_000_000), Currency: "IDR", Period: "monthly"},
+        "Rp 8.000.000 - 12.000.000":   {Min: bound(8_000_000), Max: bound(12_000_000), Currency: "IDR", Period: "monthly"},
+        "7,5 jt/bulan":                {Min: bound(7_500_000), Max: bound(7_500_000), Currency: "IDR", Period: "monthly"},
+        "mulai 150 juta per tahun":    {Min: bound(150_000_000), Currency: "IDR", Period: "annual"},
+        "10jt+":                       {Min: bound(10_000_000), Currency: "IDR", Period: "monthly"},
+        "hingga 5 juta":               {Max: bound(5_000_000), Currency: "IDR", Period: "monthly"},
+        "USD 3,000-4,000":             {Min: bound(3_000), Max: bound(4_000), Currency: "USD", Period: "monthly"},
+        "$60k - 80k annually":         {Min: bound(60_000), Max: bound(80_000), Currency: "USD", Period: "annual"},
+        "SGD 5000":                    {Min: bound(5_000), Max: bound(5_000), Currency: "SGD", Period: "monthly"},
+        "Rp 4.550.000":                {Min: bound(4_550_000), Max: bound(4_550_000), Currency: "IDR", Period: "monthly"},
+        "500 ribu - 1,5 juta":         {Min: bound(500_000), Max: bound(1_500_000), Currency: "IDR", Period: "monthly"},
+    } {
+        got, err := ParseGajiRange(text)
+        if err != nil {
+            t.Errorf("%q: %v", text, err)
+            continue
+        }
+        if !sameBound(got.Min, want.Min) || !sameBound(got.Max, want.Max) || got.Currency != want.Currency || got.Period != want.Period {
+            t.Errorf("%q = %s, want %s", text, describe(got), describe(want))
+        }
+    }
+}
+
+func TestParseGajiRangeRejects(t *testing.T) {
+    for _, text := range []string{"", "negotiable", "8-12", "20-10 juta", "1-2-3 juta", "UMR"} {
+        if g, err := ParseGajiRange(text); !errors.Is(err, ErrGajiFormat) {
+            t.Errorf("%q = %s, %v, want ErrGajiFormat", text, describe(g), err)
+        }
+    }
+}
+
+func TestGajiRangeRoundTrip(t *testing.T) {
+    for _, text := range []string{
+        "8-12 juta", "7,5 juta", "mulai 150 juta per tahun", "hingga 5 juta", "Rp 4.550.000", "USD 3,000-4,000",
+    } {
+        g, err := ParseGajiRange(text)
+        if err != nil {
+            t.Fatalf("%q: %v", text, err)
+        }
+        if got := g.Range(); got != text {
+            t.Errorf("Range of %q = %q", text, got)
+        }
+    }
+}
+
+func TestGajiValidate(t *testing.T) {
+    for name, g := range map[string]Gaji{
+        "no bounds":     {Currency: "IDR", Period: GajiMonthly},
+        "negative":      {Min: bound(-1), Currency: "IDR", Period: GajiMonthly},
+        "min above max": {Min: bound(2), Max: bound(1), Currency: "IDR", Period: GajiMonthly},
+        "currency":      {Min: bound(1), Currency: "rupiah", Period: GajiMonthly},
+        "period":        {Min: bound(1), Currency: "IDR", Period: "weekly"},
+    } {
+        if err := g.Validate(); err == nil {
+            t.Errorf("%s: Validate() = nil", name)
+        }
+    }
+}
+
+func sameBound(a, b *int64) bool {
+    return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func describe(g Gaji) string {
+    return g.Range() + " " + g.Currency + " " + g.Period
+}
@@ -0,0 +1,8 @@
+package model
+
+// HealthResponse - Result of the liveness and readiness probes. Checks maps
+// each dependency to "ok" or the reason it failed.
+type HealthResponse struct {
+    Status string            `json:"status"`
+    Checks map[string]string `json:"checks,omitempty"`
+}
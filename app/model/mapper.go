@@ -36,14 +36,23 @@ func (p *Pekerjaan) ToPekerjaanResponse() PekerjaanResponse {
         ID:                  p.ID.Hex(),
         AlumniID:            p.AlumniID.Hex(),
         NamaPerusahaan:      p.NamaPerusahaan,
+        CompanyID:           p.CompanyHex(),
         PosisiJabatan:       p.PosisiJabatan,
         BidangIndustri:      p.BidangIndustri,
         LokasiKerja:         p.LokasiKerja,
-        GajiRange:           p.GajiRange,
+        GajiMin:             p.GajiMin,
+        GajiMax:             p.GajiMax,
+        GajiCurrency:        p.GajiCurrency,
+        GajiPeriod:          p.GajiPeriod,
+        GajiRange:           p.GajiText(),
+        GajiReview:          p.GajiReview,
         TanggalMulaiKerja:   p.TanggalMulaiKerja,
         TanggalSelesaiKerja: p.TanggalSelesaiKerja,
         StatusPekerjaan:     p.StatusPekerjaan,
         DeskripsiPekerjaan:  p.DeskripsiPekerjaan,
+        StatusModerasi:      p.ModerationStatus(),
+        CatatanModerasi:     p.CatatanModerasi,
+        ModeratedAt:         p.ModeratedAt,
         CreatedAt:           p.CreatedAt,
         UpdatedAt:           p.UpdatedAt,
     }
@@ -60,10 +69,16 @@ func (p *Pekerjaan) ToPekerjaanTrashResponse() PekerjaanTrashResponse {
         ID:                  p.ID.Hex(),
         AlumniID:            p.AlumniID.Hex(),
         NamaPerusahaan:      p.NamaPerusahaan,
+        CompanyID:           p.CompanyHex(),
         PosisiJabatan:       p.PosisiJabatan,
         BidangIndustri:      p.BidangIndustri,
         LokasiKerja:         p.LokasiKerja,
-        GajiRange:           p.GajiRange,
+        GajiMin:             p.GajiMin,
+        GajiMax:             p.GajiMax,
+        GajiCurrency:        p.GajiCurrency,
+        GajiPeriod:          p.GajiPeriod,
+        GajiRange:           p.GajiText(),
+        GajiReview:          p.GajiReview,
         TanggalMulaiKerja:   p.TanggalMulaiKerja,
         TanggalSelesaiKerja: p.TanggalSelesaiKerja,
         StatusPekerjaan:     p.StatusPekerjaan,
@@ -72,4 +87,65 @@ func (p *Pekerjaan) ToPekerjaanTrashResponse() PekerjaanTrashResponse {
         UpdatedAt:           p.UpdatedAt,
         DeletedAt:           deletedAt,
     }
-}
\ No newline at end of file
+}
+
+// ToCompanyResponse converts Company to CompanyResponse
+func (c *Company) ToCompanyResponse() CompanyResponse {
+    aliases := c.Aliases
+    if aliases == nil {
+        aliases = []string{}
+    }
+    return CompanyResponse{
+        ID:             c.ID.Hex(),
+        Nama:           c.Nama,
+        Aliases:        aliases,
+        BidangIndustri: c.BidangIndustri,
+        Lokasi:         c.Lokasi,
+        CreatedAt:      c.CreatedAt,
+        UpdatedAt:      c.UpdatedAt,
+    }
+}
+
+// ToCompanyAlumniResponse converts CompanyAlumni to CompanyAlumniResponse
+func (a *CompanyAlumni) ToCompanyAlumniResponse() CompanyAlumniResponse {
+    pekerjaan := make([]CompanyPekerjaanResponse, len(a.Pekerjaan))
+    for i, p := range a.Pekerjaan {
+        pekerjaan[i] = CompanyPekerjaanResponse{
+            ID:                  p.ID.Hex(),
+            PosisiJabatan:       p.PosisiJabatan,
+            TanggalMulaiKerja:   p.TanggalMulaiKerja,
+            TanggalSelesaiKerja: p.TanggalSelesaiKerja,
+            StatusPekerjaan:     p.StatusPekerjaan,
+        }
+    }
+    return CompanyAlumniResponse{
+        AlumniID:   a.Alumni.ID.Hex(),
+        NIM:        a.Alumni.NIM,
+        Nama:       a.Alumni.Nama,
+        Jurusan:    a.Alumni.Jurusan,
+        TahunLulus: a.Alumni.TahunLulus,
+        Pekerjaan:  pekerjaan,
+    }
+}
+
+// ToReportResponse converts Report to ReportResponse, linking each file to
+// its download under GET /reports/:id/download/:format
+func (r *Report) ToReportResponse() ReportResponse {
+    files := make([]ReportFileResponse, len(r.Files))
+    for i, file := range r.Files {
+        files[i] = ReportFileResponse{ReportFile: file, URL: "/reports/" + r.ID.Hex() + "/download/" + file.Format}
+    }
+    return ReportResponse{
+        ID:          r.ID.Hex(),
+        Jurusan:     r.Jurusan,
+        TahunLulus:  r.TahunLulus,
+        Formats:     r.Formats,
+        Status:      r.Status,
+        Error:       r.Error,
+        Files:       files,
+        RequestedBy: r.RequestedBy.Hex(),
+        CreatedAt:   r.CreatedAt,
+        StartedAt:   r.StartedAt,
+        FinishedAt:  r.FinishedAt,
+    }
+}
@@ -10,27 +10,111 @@ type Pekerjaan struct {
     ID                  primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
     AlumniID            primitive.ObjectID  `json:"alumni_id" bson:"alumni_id"`
     NamaPerusahaan      string              `json:"nama_perusahaan" bson:"nama_perusahaan"`
+    CompanyID           *primitive.ObjectID `json:"company_id,omitempty" bson:"company_id,omitempty"`
     PosisiJabatan       string              `json:"posisi_jabatan" bson:"posisi_jabatan"`
     BidangIndustri      string              `json:"bidang_industri" bson:"bidang_industri"`
     LokasiKerja         string              `json:"lokasi_kerja" bson:"lokasi_kerja"`
-    GajiRange           string              `json:"gaji_range" bson:"gaji_range"`
+    GajiMin             *int64              `json:"gaji_min" bson:"gaji_min"`
+    GajiMax             *int64              `json:"gaji_max" bson:"gaji_max"`
+    GajiCurrency        string              `json:"gaji_currency" bson:"gaji_currency,omitempty"`
+    GajiPeriod          string              `json:"gaji_period" bson:"gaji_period,omitempty"`
+    GajiRange           string              `json:"gaji_range" bson:"gaji_range,omitempty"` // free text left over when it could not be parsed
+    GajiReview          bool                `json:"gaji_review" bson:"gaji_review,omitempty"`
     TanggalMulaiKerja   time.Time           `json:"tanggal_mulai_kerja" bson:"tanggal_mulai_kerja"`
     TanggalSelesaiKerja *time.Time          `json:"tanggal_selesai_kerja" bson:"tanggal_selesai_kerja"`
     StatusPekerjaan     string              `json:"status_pekerjaan" bson:"status_pekerjaan"`
     DeskripsiPekerjaan  string              `json:"deskripsi_pekerjaan" bson:"deskripsi_pekerjaan"`
+    StatusModerasi      string              `json:"status_moderasi" bson:"status_moderasi,omitempty"` // empty on pekerjaan written before moderation, counts as approved
+    CatatanModerasi     string              `json:"catatan_moderasi,omitempty" bson:"catatan_moderasi,omitempty"`
+    ModeratedBy         *primitive.ObjectID `json:"moderated_by,omitempty" bson:"moderated_by,omitempty"`
+    ModeratedAt         *time.Time          `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
     CreatedAt           time.Time           `json:"created_at" bson:"created_at"`
     UpdatedAt           time.Time           `json:"updated_at" bson:"updated_at"`
     IsDelete            *time.Time          `json:"is_delete,omitempty" bson:"is_delete,omitempty"`
 }
 
+// Values of status_moderasi. Pekerjaan submitted by alumni wait as pending
+// until an admin approves or rejects them; only approved ones show up in
+// listings and statistics.
+const (
+    ModerasiPending  = "pending"
+    ModerasiApproved = "approved"
+    ModerasiRejected = "rejected"
+)
+
+// Approved reports whether p is visible outside the moderation queue
+func (p *Pekerjaan) Approved() bool {
+    return p.StatusModerasi == "" || p.StatusModerasi == ModerasiApproved
+}
+
+// ModerationStatus is status_moderasi with the empty value of older
+// pekerjaan spelled out
+func (p *Pekerjaan) ModerationStatus() string {
+    if p.StatusModerasi == "" {
+        return ModerasiApproved
+    }
+    return p.StatusModerasi
+}
+
+// CompanyHex is the linked company id, empty when not linked
+func (p *Pekerjaan) CompanyHex() string {
+    if p.CompanyID == nil {
+        return ""
+    }
+    return p.CompanyID.Hex()
+}
+
+// Gaji returns the structured salary, empty when there is none
+func (p *Pekerjaan) Gaji() Gaji {
+    return Gaji{Min: p.GajiMin, Max: p.GajiMax, Currency: p.GajiCurrency, Period: p.GajiPeriod}
+}
+
+// SetGaji stores a structured salary, dropping the free text it replaces
+func (p *Pekerjaan) SetGaji(g Gaji) {
+    p.GajiMin, p.GajiMax = g.Min, g.Max
+    p.GajiCurrency, p.GajiPeriod = g.Currency, g.Period
+    p.GajiRange, p.GajiReview = "", false
+}
+
+// GajiText is gaji_range as clients know it: rendered from the structured
+// salary, or the free text kept for review
+func (p *Pekerjaan) GajiText() string {
+    if p.GajiMin != nil || p.GajiMax != nil {
+        return p.Gaji().Range()
+    }
+    return p.GajiRange
+}
+
+// PekerjaanFilter narrows the active pekerjaan listing. The salary bounds
+// match ranges overlapping them: GajiMin keeps pekerjaan paying up to at
+// least that much, GajiMax those starting at most there. Pekerjaan without
+// a structured salary are left out once a salary filter is set.
+type PekerjaanFilter struct {
+    Search       string
+    GajiMin      *int64
+    GajiMax      *int64
+    GajiCurrency string
+    GajiPeriod   string
+}
+
+// HasGaji reports whether the filter restricts the salary
+func (f PekerjaanFilter) HasGaji() bool {
+    return f.GajiMin != nil || f.GajiMax != nil || f.GajiCurrency != "" || f.GajiPeriod != ""
+}
+
 // CreatePekerjaanRequest - Request for POST /pekerjaan
 type CreatePekerjaanRequest struct {
-    AlumniID            string     `json:"alumni_id" validate:"required"`
+    AlumniID            string     `json:"alumni_id"` // required from admins, the caller's own alumni otherwise
     NamaPerusahaan      string     `json:"nama_perusahaan" validate:"required"`
+    CompanyID           string     `json:"company_id"` // matched from nama_perusahaan when empty
     PosisiJabatan       string     `json:"posisi_jabatan" validate:"required"`
     BidangIndustri      string     `json:"bidang_industri" validate:"required"`
     LokasiKerja         string     `json:"lokasi_kerja" validate:"required"`
-    GajiRange           string     `json:"gaji_range"`
+    GajiMin             *int64     `json:"gaji_min"`
+    GajiMax             *int64     `json:"gaji_max"`
+    GajiCurrency        string     `json:"gaji_currency"`
+    GajiPeriod          string     `json:"gaji_period"`
+    GajiRange           string     `json:"gaji_range"` // parsed when gaji_min and gaji_max are not given
     TanggalMulaiKerja   time.Time  `json:"tanggal_mulai_kerja" validate:"required"`
     TanggalSelesaiKerja *time.Time `json:"tanggal_selesai_kerja"`
     StatusPekerjaan     string     `json:"status_pekerjaan" validate:"required"`
@@ -39,12 +123,17 @@ type CreatePekerjaanRequest struct {
 
 // UpdatePekerjaanRequest - Request for PUT /pekerjaan/:id
 type UpdatePekerjaanRequest struct {
-    AlumniID            string     `json:"alumni_id" validate:"required"`
+    AlumniID            string     `json:"alumni_id"` // required from admins, the caller's own alumni otherwise
     NamaPerusahaan      string     `json:"nama_perusahaan" validate:"required"`
+    CompanyID           string     `json:"company_id"` // matched from nama_perusahaan when empty
     PosisiJabatan       string     `json:"posisi_jabatan" validate:"required"`
     BidangIndustri      string     `json:"bidang_industri" validate:"required"`
     LokasiKerja         string     `json:"lokasi_kerja" validate:"required"`
-    GajiRange           string     `json:"gaji_range"`
+    GajiMin             *int64     `json:"gaji_min"`
+    GajiMax             *int64     `json:"gaji_max"`
+    GajiCurrency        string     `json:"gaji_currency"`
+    GajiPeriod          string     `json:"gaji_period"`
+    GajiRange           string     `json:"gaji_range"` // parsed when gaji_min and gaji_max are not given
     TanggalMulaiKerja   time.Time  `json:"tanggal_mulai_kerja" validate:"required"`
     TanggalSelesaiKerja *time.Time `json:"tanggal_selesai_kerja"`
     StatusPekerjaan     string     `json:"status_pekerjaan" validate:"required"`
@@ -56,16 +145,26 @@ type PekerjaanResponse struct {
     ID                  string     `json:"id"`
     AlumniID            string     `json:"alumni_id"`
     NamaPerusahaan      string     `json:"nama_perusahaan"`
+    CompanyID           string     `json:"company_id,omitempty"`
     PosisiJabatan       string     `json:"posisi_jabatan"`
     BidangIndustri      string     `json:"bidang_industri"`
     LokasiKerja         string     `json:"lokasi_kerja"`
+    GajiMin             *int64     `json:"gaji_min"`
+    GajiMax             *int64     `json:"gaji_max"`
+    GajiCurrency        string     `json:"gaji_currency,omitempty"`
+    GajiPeriod          string     `json:"gaji_period,omitempty"`
     GajiRange           string     `json:"gaji_range"`
+    GajiReview          bool       `json:"gaji_review,omitempty"`
     TanggalMulaiKerja   time.Time  `json:"tanggal_mulai_kerja"`
     TanggalSelesaiKerja *time.Time `json:"tanggal_selesai_kerja"`
     StatusPekerjaan     string     `json:"status_pekerjaan"`
     DeskripsiPekerjaan  string     `json:"deskripsi_pekerjaan"`
+    StatusModerasi      string     `json:"status_moderasi"`
+    CatatanModerasi     string     `json:"catatan_moderasi,omitempty"`
+    ModeratedAt         *time.Time `json:"moderated_at,omitempty"`
     CreatedAt           time.Time  `json:"created_at"`
     UpdatedAt           time.Time  `json:"updated_at"`
+    CompanySuggestions  []CompanyMatch `json:"company_suggestions,omitempty"` // on create and update when no company was linked
 }
 
 // PekerjaanTrashResponse - Response for trash items
@@ -73,10 +172,16 @@ type PekerjaanTrashResponse struct {
     ID                  string     `json:"id"`
     AlumniID            string     `json:"alumni_id"`
     NamaPerusahaan      string     `json:"nama_perusahaan"`
+    CompanyID           string     `json:"company_id,omitempty"`
     PosisiJabatan       string     `json:"posisi_jabatan"`
     BidangIndustri      string     `json:"bidang_industri"`
     LokasiKerja         string     `json:"lokasi_kerja"`
+    GajiMin             *int64     `json:"gaji_min"`
+    GajiMax             *int64     `json:"gaji_max"`
+    GajiCurrency        string     `json:"gaji_currency,omitempty"`
+    GajiPeriod          string     `json:"gaji_period,omitempty"`
     GajiRange           string     `json:"gaji_range"`
+    GajiReview          bool       `json:"gaji_review,omitempty"`
     TanggalMulaiKerja   time.Time  `json:"tanggal_mulai_kerja"`
     TanggalSelesaiKerja *time.Time `json:"tanggal_selesai_kerja"`
     StatusPekerjaan     string     `json:"status_pekerjaan"`
@@ -98,4 +203,30 @@ type PekerjaanTrashListResponse struct {
     Meta MetaInfo                 `json:"meta"`
 }
 
+// TrashBulkRequest - Request for POST /pekerjaan/trash/restore and
+// /pekerjaan/trash/purge
+type TrashBulkRequest struct {
+    IDs []string `json:"ids" validate:"required"`
+}
+
+// TrashBulkResponse - Response for the bulk trash endpoints
+type TrashBulkResponse struct {
+    Processed []string `json:"processed"`
+    Skipped   []string `json:"skipped"` // not in the caller's trash
+}
+
+// TrashPurgeReport - Response for GET /pekerjaan/trash/purge/preview, what
+// the next scheduled purge removes
+type TrashPurgeReport struct {
+    Retention string                   `json:"retention"` // empty when the trash is kept forever
+    Cutoff    *time.Time               `json:"cutoff"`    // trashed before this are purged
+    Total     int                      `json:"total"`
+    Items     []PekerjaanTrashResponse `json:"items"` // oldest first, up to limit
+}
+
+// ModerasiRequest - Request for PUT /pekerjaan/moderation/reject/:id
+type ModerasiRequest struct {
+    Catatan string `json:"catatan"` // reason shown to the alumni
+}
+
 // MetaInfo for pagination
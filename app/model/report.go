@@ -0,0 +1,223 @@
+package model
+
+import (
+    "strconv"
+    "strings"
+    "time"
+    "unicode"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report job statuses, in the order a job goes through them
+const (
+    ReportPending = "pending"
+    ReportRunning = "running"
+    ReportDone    = "done"
+    ReportFailed  = "failed"
+)
+
+// Report file formats
+const (
+    ReportPDF  = "pdf"
+    ReportXLSX = "xlsx"
+)
+
+// ReportFormats lists the formats a report can be generated in
+var ReportFormats = []string{ReportPDF, ReportXLSX}
+
+// ReportContentType is the MIME type of a report file format
+func ReportContentType(format string) string {
+    if format == ReportXLSX {
+        return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+    }
+    return "application/pdf"
+}
+
+// Report - a job generating the accreditation report of one jurusan and
+// tahun_lulus. Files lists what was generated once the job is done.
+type Report struct {
+    ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+    Jurusan     string             `json:"jurusan" bson:"jurusan"`
+    TahunLulus  int                `json:"tahun_lulus" bson:"tahun_lulus"`
+    Formats     []string           `json:"formats" bson:"formats"`
+    Status      string             `json:"status" bson:"status"`
+    Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+    Files       []ReportFile       `json:"files" bson:"files"`
+    RequestedBy primitive.ObjectID `json:"requested_by" bson:"requested_by"`
+    CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+    StartedAt   *time.Time         `json:"started_at,omitempty" bson:"started_at,omitempty"`
+    FinishedAt  *time.Time         `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// ReportFile - one generated file of a report
+type ReportFile struct {
+    Format      string `json:"format" bson:"format"`
+    Name        string `json:"name" bson:"name"`
+    ContentType string `json:"content_type" bson:"content_type"`
+    Size        int    `json:"size" bson:"size"`
+}
+
+// ReportContent - the bytes of one generated file, stored apart from the
+// report so listing the jobs stays small
+type ReportContent struct {
+    ReportID  primitive.ObjectID `bson:"report_id"`
+    Format    string             `bson:"format"`
+    Data      []byte             `bson:"data"`
+    CreatedAt time.Time          `bson:"created_at"`
+}
+
+// Finished reports whether the job is done or failed
+func (r Report) Finished() bool {
+    return r.Status == ReportDone || r.Status == ReportFailed
+}
+
+// File returns the generated file of format
+func (r Report) File(format string) (ReportFile, bool) {
+    for _, file := range r.Files {
+        if file.Format == format {
+            return file, true
+        }
+    }
+    return ReportFile{}, false
+}
+
+// FileName names the file of a report, e.g.
+// tracer-study-teknik-informatika-2022.pdf
+func (r Report) FileName(format string) string {
+    var slug strings.Builder
+    dash := false
+    for _, ch := range strings.ToLower(r.Jurusan) {
+        if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+            slug.WriteRune(ch)
+            dash = false
+        } else if !dash && slug.Len() > 0 {
+            slug.WriteByte('-')
+            dash = true
+        }
+    }
+    name := strings.TrimSuffix(slug.String(), "-")
+    if name == "" {
+        name = "jurusan"
+    }
+    return "tracer-study-" + name + "-" + strconv.Itoa(r.TahunLulus) + "." + format
+}
+
+// CreateReportRequest - Request for POST /reports. Formats defaults to
+// every one of ReportFormats.
+type CreateReportRequest struct {
+    Jurusan    string   `json:"jurusan" validate:"required"`
+    TahunLulus int      `json:"tahun_lulus" validate:"required"`
+    Formats    []string `json:"formats"`
+}
+
+// ReportResponse - Response for a report job
+type ReportResponse struct {
+    ID          string               `json:"id"`
+    Jurusan     string               `json:"jurusan"`
+    TahunLulus  int                  `json:"tahun_lulus"`
+    Formats     []string             `json:"formats"`
+    Status      string               `json:"status"`
+    Error       string               `json:"error,omitempty"`
+    Files       []ReportFileResponse `json:"files"`
+    RequestedBy string               `json:"requested_by"`
+    CreatedAt   time.Time            `json:"created_at"`
+    StartedAt   *time.Time           `json:"started_at,omitempty"`
+    FinishedAt  *time.Time           `json:"finished_at,omitempty"`
+}
+
+// ReportFileResponse - a generated file and where to download it
+type ReportFileResponse struct {
+    ReportFile
+    URL string `json:"url"`
+}
+
+// SalaryBands are the monthly salary bands of the accreditation report, in
+// Rupiah
+var SalaryBands = []SalaryBand{
+    {Label: "< 5 juta", Max: 5_000_000},
+    {Label: "5-10 juta", Min: 5_000_000, Max: 10_000_000},
+    {Label: "10-15 juta", Min: 10_000_000, Max: 15_000_000},
+    {Label: "> 15 juta", Min: 15_000_000},
+}
+
+// SalaryBand is a range of monthly salaries, Min included and Max excluded;
+// a zero Max is open
+type SalaryBand struct {
+    Label   string  `json:"label"`
+    Min     int64   `json:"min"`
+    Max     int64   `json:"max,omitempty"`
+    Total   int     `json:"total"`
+    Percent float64 `json:"percent"` // of the salaries reported
+}
+
+// AccreditationReport is the content of the tracer study report of one
+// jurusan and tahun_lulus. Respondents are the alumni with at least one
+// approved pekerjaan; Employed those of them with an aktif one. Salaries
+// are those of each respondent's first pekerjaan in IDR, left out when
+// fewer than MinGroupSize reported one.
+type AccreditationReport struct {
+    Jurusan          string
+    TahunLulus       int
+    GeneratedAt      time.Time
+    MinGroupSize     int
+    Alumni           int
+    Respondents      int
+    ResponseRate     float64 // of the alumni
+    Employed         int
+    EmploymentRate   float64 // of the respondents
+    WaitingTime      WaitingTimeStats
+    Industries       DistributionStats
+    Salaries         int
+    SalarySuppressed bool
+    SalaryBands      []SalaryBand
+}
+
+// Accreditation builds the report of cohort from the pekerjaan facts of
+// its alumni, ordered per alumni by tanggal_mulai_kerja as the
+// StatsRepository returns them
+func Accreditation(cohort CohortAggregate, facts []PekerjaanFact, minSize int, now time.Time) AccreditationReport {
+    report := AccreditationReport{
+        Jurusan:        cohort.Jurusan,
+        TahunLulus:     cohort.TahunLulus,
+        GeneratedAt:    now,
+        MinGroupSize:   minSize,
+        Alumni:         cohort.Alumni,
+        Respondents:    cohort.Employed,
+        ResponseRate:   percent(cohort.Employed, cohort.Alumni),
+        Employed:       cohort.Aktif,
+        EmploymentRate: percent(cohort.Aktif, cohort.Employed),
+        WaitingTime:    cohort.WaitingTime(minSize),
+        Industries:     Distribution(facts, DimensionIndustri, minSize),
+        SalaryBands:    make([]SalaryBand, len(SalaryBands)),
+    }
+    copy(report.SalaryBands, SalaryBands)
+
+    var first []PekerjaanFact
+    seen := map[primitive.ObjectID]bool{}
+    for _, f := range facts {
+        if !seen[f.AlumniID] {
+            seen[f.AlumniID] = true
+            first = append(first, f)
+        }
+    }
+    list, alumni := salaries(first, CurrencyIDR)
+    report.Salaries = len(list)
+    if alumni < minSize {
+        report.SalarySuppressed = len(list) > 0
+        return report
+    }
+
+    for _, gaji := range list {
+        for i, band := range report.SalaryBands {
+            if gaji >= float64(band.Min) && (band.Max == 0 || gaji < float64(band.Max)) {
+                report.SalaryBands[i].Total++
+                break
+            }
+        }
+    }
+    for i := range report.SalaryBands {
+        report.SalaryBands[i].Percent = percent(report.SalaryBands[i].Total, len(list))
+    }
+    return report
+}
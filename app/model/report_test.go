@@ -0,0 +1,80 @@
+package model
+
+import (
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAccreditation(t *testing.T) {
+    cohort := CohortAggregate{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022,
+        Alumni: 4, Employed: 3, Aktif: 2, WaitingMonths: []int{2, 8, 20},
+    }
+
+    // Only the first pekerjaan of each alumni counts for the salaries
+    alumni := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+    facts := []PekerjaanFact{
+        fact("Technology", 2022, 3_000_000, GajiMonthly),
+        fact("Technology", 2023, 30_000_000, GajiMonthly),
+        fact("Technology", 2022, 96_000_000, GajiAnnual),
+        fact("Banking", 2022, 20_000_000, GajiMonthly),
+        fact("Banking", 2022, 2_000, GajiMonthly),
+    }
+    for i, a := range []int{0, 0, 1, 2, 3} {
+        facts[i].AlumniID = alumni[a]
+    }
+    facts[4].GajiCurrency = "USD"
+
+    report := Accreditation(cohort, facts, 3, time.Now())
+    if report.Alumni != 4 || report.Respondents != 3 || report.ResponseRate != 75 || report.Employed != 2 || report.EmploymentRate != 66.67 {
+        t.Errorf("rates = %+v", report)
+    }
+    if report.WaitingTime.Employed != 3 || report.WaitingTime.Bands[0].Total != 1 || report.WaitingTime.Bands[2].Total != 1 {
+        t.Errorf("waiting time = %+v", report.WaitingTime)
+    }
+    // Three Technology pekerjaan, but of two alumni
+    if groups := report.Industries.Groups; len(groups) != 1 || groups[0].Value != OtherGroup || groups[0].Alumni != 4 {
+        t.Errorf("industries = %+v", groups)
+    }
+    if groups := Accreditation(cohort, facts, 2, time.Now()).Industries.Groups; len(groups) != 2 || groups[0].Value != "Technology" || groups[1].Value != "Banking" {
+        t.Errorf("industries of two alumni = %+v", groups)
+    }
+
+    if report.Salaries != 3 || report.SalarySuppressed {
+        t.Fatalf("salaries = %d, suppressed = %v", report.Salaries, report.SalarySuppressed)
+    }
+    totals := []int{1, 1, 0, 1}
+    for i, band := range report.SalaryBands {
+        if band.Total != totals[i] {
+            t.Errorf("band %s = %d, want %d", band.Label, band.Total, totals[i])
+        }
+    }
+    if report.SalaryBands[0].Percent != 33.33 || SalaryBands[0].Total != 0 {
+        t.Errorf("bands = %+v, shared bands = %+v", report.SalaryBands, SalaryBands)
+    }
+
+    small := Accreditation(cohort, facts, 4, time.Now())
+    if !small.SalarySuppressed || small.SalaryBands[0].Total != 0 {
+        t.Errorf("under the minimum size: %+v", small.SalaryBands)
+    }
+    if !small.WaitingTime.Suppressed || len(small.WaitingTime.Bands) != 0 {
+        t.Errorf("waiting time under the minimum size = %+v", small.WaitingTime)
+    }
+}
+
+func TestReportFileName(t *testing.T) {
+    tests := []struct {
+        jurusan, want string
+    }{
+        {"Teknik Informatika", "tracer-study-teknik-informatika-2022.pdf"},
+        {" S1 - Sistem Informasi ", "tracer-study-s1-sistem-informasi-2022.pdf"},
+        {"---", "tracer-study-jurusan-2022.pdf"},
+    }
+    for _, tt := range tests {
+        if got := (Report{Jurusan: tt.jurusan, TahunLulus: 2022}).FileName(ReportPDF); got != tt.want {
+            t.Errorf("FileName(%q) = %q, want %q", tt.jurusan, got, tt.want)
+        }
+    }
+}
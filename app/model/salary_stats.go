@@ -0,0 +1,295 @@
+package model
+
+import (
+    "math"
+    "sort"
+    "strconv"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Dimensions of GET /stats/distribution, named after the pekerjaan field
+// they group by
+const (
+    DimensionIndustri = "bidang_industri"
+    DimensionLokasi   = "lokasi_kerja"
+    DimensionPosisi   = "posisi_jabatan"
+)
+
+// Groupings of GET /stats/salary
+const (
+    SalaryByJurusan  = "jurusan"
+    SalaryByAngkatan = "angkatan"
+)
+
+// DefaultMinGroupSize is the smallest group, in distinct alumni, the
+// analytics report on its own. Smaller groups are merged into OtherGroup or
+// suppressed, so no single alumni can be picked out.
+const DefaultMinGroupSize = 5
+
+// OtherGroup collects the groups under the minimum size
+const OtherGroup = "Lainnya"
+
+// ValidCurrency reports whether code is an ISO currency code such as IDR
+func ValidCurrency(code string) bool {
+    return currencyCode.MatchString(code)
+}
+
+// dimensionValue is the value of f grouped by dimension
+func dimensionValue(f PekerjaanFact, dimension string) string {
+    switch dimension {
+    case DimensionLokasi:
+        return f.LokasiKerja
+    case DimensionPosisi:
+        return f.PosisiJabatan
+    }
+    return f.BidangIndustri
+}
+
+// DistributionGroup - pekerjaan sharing one value of the dimension and the
+// alumni who hold them
+type DistributionGroup struct {
+    Value   string  `json:"value"`
+    Total   int     `json:"total"`
+    Alumni  int     `json:"alumni"`
+    Percent float64 `json:"percent"`
+}
+
+// DistributionStats - Response for GET /stats/distribution. Groups of fewer
+// than MinGroupSize alumni are counted together as OtherGroup, listed last.
+type DistributionStats struct {
+    By           string              `json:"by"`
+    Total        int                 `json:"total"`
+    MinGroupSize int                 `json:"min_group_size"`
+    Groups       []DistributionGroup `json:"groups"`
+}
+
+// Distribution counts facts per value of dimension. Values differing only
+// in case or surrounding spaces are one group, named as first written.
+func Distribution(facts []PekerjaanFact, dimension string, minSize int) DistributionStats {
+    stats := DistributionStats{By: dimension, Total: len(facts), MinGroupSize: minSize, Groups: []DistributionGroup{}}
+
+    label := labeler()
+    index := map[string]int{}
+    var groups []DistributionGroup
+    var alumni []map[primitive.ObjectID]bool
+    for _, f := range facts {
+        value := label(dimensionValue(f, dimension))
+        i, ok := index[value]
+        if !ok {
+            i = len(groups)
+            index[value] = i
+            groups = append(groups, DistributionGroup{Value: value})
+            alumni = append(alumni, map[primitive.ObjectID]bool{})
+        }
+        groups[i].Total++
+        alumni[i][f.AlumniID] = true
+    }
+    for i := range groups {
+        groups[i].Alumni = len(alumni[i])
+    }
+
+    other := DistributionGroup{Value: OtherGroup}
+    otherAlumni := map[primitive.ObjectID]bool{}
+    for i, g := range groups {
+        if g.Alumni < minSize {
+            other.Total += g.Total
+            for id := range alumni[i] {
+                otherAlumni[id] = true
+            }
+            continue
+        }
+        g.Percent = percent(g.Total, stats.Total)
+        stats.Groups = append(stats.Groups, g)
+    }
+    sort.SliceStable(stats.Groups, func(i, j int) bool {
+        if stats.Groups[i].Total != stats.Groups[j].Total {
+            return stats.Groups[i].Total > stats.Groups[j].Total
+        }
+        return stats.Groups[i].Value < stats.Groups[j].Value
+    })
+    if other.Total > 0 {
+        other.Alumni = len(otherAlumni)
+        other.Percent = percent(other.Total, stats.Total)
+        stats.Groups = append(stats.Groups, other)
+    }
+    return stats
+}
+
+// MonthlyGaji is the middle of the salary range per month, or of its one
+// bound for an open range; ok is false without a salary
+func (f PekerjaanFact) MonthlyGaji() (float64, bool) {
+    var sum float64
+    var n int
+    for _, bound := range []*int64{f.GajiMin, f.GajiMax} {
+        if bound != nil {
+            sum += float64(*bound)
+            n++
+        }
+    }
+    if n == 0 {
+        return 0, false
+    }
+    gaji := sum / float64(n)
+    if f.GajiPeriod == GajiAnnual {
+        gaji /= 12
+    }
+    return gaji, true
+}
+
+// SalaryPercentiles of monthly salaries, in whole units of the currency
+type SalaryPercentiles struct {
+    P25 float64 `json:"p25"`
+    P50 float64 `json:"p50"`
+    P75 float64 `json:"p75"`
+    P90 float64 `json:"p90"`
+}
+
+// SalaryGroup - salaries of one jurusan or angkatan. Percentiles is null
+// when fewer alumni than the minimum group size reported a salary.
+type SalaryGroup struct {
+    Value       string             `json:"value"`
+    Pekerjaan   int                `json:"pekerjaan"`
+    Salaries    int                `json:"salaries"`
+    Alumni      int                `json:"alumni"` // who reported a salary
+    Suppressed  bool               `json:"suppressed"`
+    Percentiles *SalaryPercentiles `json:"percentiles"`
+}
+
+// SalaryStats - Response for GET /stats/salary
+type SalaryStats struct {
+    By           string        `json:"by"`
+    Currency     string        `json:"currency"`
+    Period       string        `json:"period"`
+    MinGroupSize int           `json:"min_group_size"`
+    Groups       []SalaryGroup `json:"groups"`
+}
+
+// salaries collects the monthly salaries in currency of facts and counts
+// the alumni they belong to
+func salaries(facts []PekerjaanFact, currency string) ([]float64, int) {
+    var list []float64
+    alumni := map[primitive.ObjectID]bool{}
+    for _, f := range facts {
+        if f.GajiCurrency != currency {
+            continue
+        }
+        if gaji, ok := f.MonthlyGaji(); ok {
+            list = append(list, gaji)
+            alumni[f.AlumniID] = true
+        }
+    }
+    return list, len(alumni)
+}
+
+// salaryPercentiles of list, nil when it comes from fewer than minSize
+// alumni
+func salaryPercentiles(list []float64, alumni, minSize int) *SalaryPercentiles {
+    if len(list) == 0 || alumni < minSize {
+        return nil
+    }
+    sorted := append([]float64(nil), list...)
+    sort.Float64s(sorted)
+    return &SalaryPercentiles{
+        P25: percentile(sorted, 0.25),
+        P50: percentile(sorted, 0.50),
+        P75: percentile(sorted, 0.75),
+        P90: percentile(sorted, 0.90),
+    }
+}
+
+// percentile interpolates linearly between the closest ranks of sorted,
+// rounded to a whole unit
+func percentile(sorted []float64, p float64) float64 {
+    pos := p * float64(len(sorted)-1)
+    lo := int(math.Floor(pos))
+    hi := min(lo+1, len(sorted)-1)
+    return math.Round(sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo)))
+}
+
+// Salary computes the monthly salary percentiles in currency per jurusan or
+// angkatan, in that order
+func Salary(facts []PekerjaanFact, by, currency string, minSize int) SalaryStats {
+    stats := SalaryStats{By: by, Currency: currency, Period: GajiMonthly, MinGroupSize: minSize, Groups: []SalaryGroup{}}
+
+    groups := map[string][]PekerjaanFact{}
+    var order []string
+    for _, f := range facts {
+        value := f.Jurusan
+        if by == SalaryByAngkatan {
+            value = strconv.Itoa(f.Angkatan)
+        }
+        if _, ok := groups[value]; !ok {
+            order = append(order, value)
+        }
+        groups[value] = append(groups[value], f)
+    }
+    sort.Strings(order)
+
+    for _, value := range order {
+        list, alumni := salaries(groups[value], currency)
+        group := SalaryGroup{Value: value, Pekerjaan: len(groups[value]), Salaries: len(list), Alumni: alumni}
+        group.Percentiles = salaryPercentiles(list, alumni, minSize)
+        group.Suppressed = len(list) > 0 && group.Percentiles == nil
+        stats.Groups = append(stats.Groups, group)
+    }
+    return stats
+}
+
+// TrendYear - pekerjaan started in one year. MedianGaji is null when fewer
+// alumni than the minimum group size reported a salary; TopIndustries only
+// lists industries of at least that many alumni.
+type TrendYear struct {
+    Tahun         int             `json:"tahun"`
+    Pekerjaan     int             `json:"pekerjaan"`
+    Salaries      int             `json:"salaries"`
+    Suppressed    bool            `json:"suppressed"`
+    MedianGaji    *float64        `json:"median_gaji"`
+    TopIndustries []IndustryCount `json:"top_industries"`
+}
+
+// TrendStats - Response for GET /stats/trends
+type TrendStats struct {
+    Currency     string      `json:"currency"`
+    Period       string      `json:"period"`
+    MinGroupSize int         `json:"min_group_size"`
+    Years        []TrendYear `json:"years"`
+}
+
+// Trends follows the pekerjaan, monthly salaries in currency and industries
+// per year of tanggal_mulai_kerja, oldest first
+func Trends(facts []PekerjaanFact, currency string, top, minSize int) TrendStats {
+    stats := TrendStats{Currency: currency, Period: GajiMonthly, MinGroupSize: minSize, Years: []TrendYear{}}
+
+    byYear := map[int][]PekerjaanFact{}
+    for _, f := range facts {
+        year := f.TanggalMulaiKerja.UTC().Year()
+        byYear[year] = append(byYear[year], f)
+    }
+    years := make([]int, 0, len(byYear))
+    for year := range byYear {
+        years = append(years, year)
+    }
+    sort.Ints(years)
+
+    for _, year := range years {
+        list, alumni := salaries(byYear[year], currency)
+        trend := TrendYear{Tahun: year, Pekerjaan: len(byYear[year]), Salaries: len(list)}
+        if p := salaryPercentiles(list, alumni, minSize); p != nil {
+            trend.MedianGaji = &p.P50
+        }
+        trend.Suppressed = len(list) > 0 && trend.MedianGaji == nil
+
+        industries := map[primitive.ObjectID][]string{}
+        for _, f := range byYear[year] {
+            industries[f.AlumniID] = append(industries[f.AlumniID], f.BidangIndustri)
+        }
+        perAlumni := make([][]string, 0, len(industries))
+        for _, list := range industries {
+            perAlumni = append(perAlumni, list)
+        }
+        trend.TopIndustries = topIndustries(CountIndustries(perAlumni), top, minSize)
+        stats.Years = append(stats.Years, trend)
+    }
+    return stats
+}
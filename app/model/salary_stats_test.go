@@ -0,0 +1,117 @@
+package model
+
+import (
+    "reflect"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fact is a pekerjaan of a new alumni
+func fact(industri string, year int, gaji int64, period string) PekerjaanFact {
+    f := PekerjaanFact{AlumniID: primitive.NewObjectID(), Jurusan: "Teknik Informatika", Angkatan: 2018, BidangIndustri: industri, TanggalMulaiKerja: day(year, 3, 1)}
+    if gaji > 0 {
+        f.GajiMin, f.GajiMax, f.GajiCurrency, f.GajiPeriod = &gaji, &gaji, CurrencyIDR, period
+    }
+    return f
+}
+
+func TestDistribution(t *testing.T) {
+    var facts []PekerjaanFact
+    for i := 0; i < 3; i++ {
+        facts = append(facts, fact("Technology", 2022, 0, ""), fact(" technology", 2022, 0, ""))
+    }
+    facts = append(facts, fact("Banking", 2022, 0, ""), fact("Retail", 2022, 0, ""))
+
+    stats := Distribution(facts, DimensionIndustri, 2)
+    want := []DistributionGroup{{Value: "Technology", Total: 6, Alumni: 6, Percent: 75}, {Value: OtherGroup, Total: 2, Alumni: 2, Percent: 25}}
+    if stats.Total != 8 || !reflect.DeepEqual(stats.Groups, want) {
+        t.Errorf("groups = %+v, want %+v", stats.Groups, want)
+    }
+
+    // Many pekerjaan of one alumni are still a group of one
+    for i := range facts[:6] {
+        facts[i].AlumniID = facts[0].AlumniID
+    }
+    stats = Distribution(facts, DimensionIndustri, 2)
+    want = []DistributionGroup{{Value: OtherGroup, Total: 8, Alumni: 3, Percent: 100}}
+    if !reflect.DeepEqual(stats.Groups, want) {
+        t.Errorf("one alumni: groups = %+v, want %+v", stats.Groups, want)
+    }
+}
+
+func TestMonthlyGaji(t *testing.T) {
+    low, high := int64(8_000_000), int64(12_000_000)
+    if gaji, ok := (PekerjaanFact{GajiMin: &low, GajiMax: &high, GajiPeriod: GajiMonthly}).MonthlyGaji(); !ok || gaji != 10_000_000 {
+        t.Errorf("range = %v, %v", gaji, ok)
+    }
+    annual := int64(120_000_000)
+    if gaji, ok := (PekerjaanFact{GajiMin: &annual, GajiPeriod: GajiAnnual}).MonthlyGaji(); !ok || gaji != 10_000_000 {
+        t.Errorf("open annual = %v, %v", gaji, ok)
+    }
+    if _, ok := (PekerjaanFact{}).MonthlyGaji(); ok {
+        t.Error("no salary should not count")
+    }
+}
+
+func TestSalary(t *testing.T) {
+    var facts []PekerjaanFact
+    for _, gaji := range []int64{5, 6, 7, 8, 9} {
+        facts = append(facts, fact("Technology", 2022, gaji*1_000_000, GajiMonthly))
+    }
+    other := fact("Technology", 2022, 120_000_000, GajiAnnual)
+    other.Jurusan = "Sistem Informasi"
+    facts = append(facts, other, fact("Technology", 2022, 0, ""))
+
+    stats := Salary(facts, SalaryByJurusan, CurrencyIDR, 5)
+    if len(stats.Groups) != 2 {
+        t.Fatalf("groups = %+v", stats.Groups)
+    }
+    si, ti := stats.Groups[0], stats.Groups[1]
+    if si.Value != "Sistem Informasi" || !si.Suppressed || si.Percentiles != nil {
+        t.Errorf("small group = %+v", si)
+    }
+    want := SalaryPercentiles{P25: 6_000_000, P50: 7_000_000, P75: 8_000_000, P90: 8_600_000}
+    if ti.Pekerjaan != 6 || ti.Salaries != 5 || ti.Suppressed || ti.Percentiles == nil || *ti.Percentiles != want {
+        t.Errorf("Teknik Informatika = %+v, percentiles %+v", ti, ti.Percentiles)
+    }
+
+    if groups := Salary(facts, SalaryByAngkatan, "USD", 5).Groups; len(groups) != 1 || groups[0].Value != "2018" || groups[0].Salaries != 0 || groups[0].Suppressed {
+        t.Errorf("USD by angkatan = %+v", groups)
+    }
+
+    // Five salaries of two alumni are too few
+    for i := range facts[:5] {
+        facts[i].AlumniID = facts[i%2].AlumniID
+    }
+    if ti := Salary(facts, SalaryByJurusan, CurrencyIDR, 5).Groups[1]; ti.Salaries != 5 || ti.Alumni != 2 || !ti.Suppressed || ti.Percentiles != nil {
+        t.Errorf("two alumni = %+v", ti)
+    }
+}
+
+func TestTrends(t *testing.T) {
+    var facts []PekerjaanFact
+    for i := 0; i < 5; i++ {
+        facts = append(facts, fact("Technology", 2023, 10_000_000, GajiMonthly))
+    }
+    facts = append(facts, fact("Banking", 2023, 0, ""), fact("Banking", 2021, 9_000_000, GajiMonthly))
+
+    stats := Trends(facts, CurrencyIDR, 3, 5)
+    if len(stats.Years) != 2 || stats.Years[0].Tahun != 2021 || stats.Years[1].Tahun != 2023 {
+        t.Fatalf("years = %+v", stats.Years)
+    }
+    if y := stats.Years[0]; !y.Suppressed || y.MedianGaji != nil || len(y.TopIndustries) != 0 {
+        t.Errorf("2021 = %+v", y)
+    }
+    y := stats.Years[1]
+    if y.Pekerjaan != 6 || y.MedianGaji == nil || *y.MedianGaji != 10_000_000 || !reflect.DeepEqual(y.TopIndustries, []IndustryCount{{BidangIndustri: "Technology", Total: 5, Alumni: 5}}) {
+        t.Errorf("2023 = %+v", y)
+    }
+
+    for i := range facts[:5] {
+        facts[i].AlumniID = facts[0].AlumniID
+    }
+    if y := Trends(facts, CurrencyIDR, 3, 5).Years[1]; !y.Suppressed || y.MedianGaji != nil || len(y.TopIndustries) != 0 {
+        t.Errorf("2023 of one alumni = %+v", y)
+    }
+}
@@ -0,0 +1,272 @@
+package model
+
+import (
+    "math"
+    "sort"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CohortFilter narrows the cohort analytics; zero fields match every alumni
+type CohortFilter struct {
+    Jurusan        string
+    Angkatan       int
+    TahunLulusFrom int
+    TahunLulusTo   int
+}
+
+// PekerjaanFact is one approved pekerjaan outside the trash together with
+// the cohort of its alumni, the input of the per-pekerjaan analytics
+type PekerjaanFact struct {
+    ID                  primitive.ObjectID `bson:"_id"` // of the pekerjaan
+    AlumniID            primitive.ObjectID `bson:"alumni_id"`
+    Jurusan             string             `bson:"jurusan"`
+    Angkatan            int                `bson:"angkatan"`
+    TahunLulus          int                `bson:"tahun_lulus"`
+    PosisiJabatan       string             `bson:"posisi_jabatan"`
+    BidangIndustri      string             `bson:"bidang_industri"`
+    LokasiKerja         string             `bson:"lokasi_kerja"`
+    GajiMin             *int64             `bson:"gaji_min"`
+    GajiMax             *int64             `bson:"gaji_max"`
+    GajiCurrency        string             `bson:"gaji_currency"`
+    GajiPeriod          string             `bson:"gaji_period"`
+    TanggalMulaiKerja   time.Time          `bson:"tanggal_mulai_kerja"`
+    TanggalSelesaiKerja *time.Time         `bson:"tanggal_selesai_kerja"`
+    StatusPekerjaan     string             `bson:"status_pekerjaan"`
+}
+
+// CohortAggregate is what the repositories compute for one jurusan and
+// tahun_lulus from the alumni and their approved pekerjaan outside the
+// trash. WaitingMonths holds, per employed alumni, WaitingMonths of their
+// first pekerjaan; Industries counts bidang_industri over every pekerjaan
+// and their alumni, most common first.
+type CohortAggregate struct {
+    Jurusan       string          `bson:"jurusan"`
+    TahunLulus    int             `bson:"tahun_lulus"`
+    Alumni        int             `bson:"alumni"`
+    Employed      int             `bson:"employed"`
+    Aktif         int             `bson:"aktif"`
+    Pekerjaan     int             `bson:"pekerjaan"`
+    Changed       int             `bson:"changed"` // employed alumni with more than one pekerjaan
+    WaitingMonths []int           `bson:"waiting_months"`
+    Industries    []IndustryCount `bson:"-"`
+}
+
+// WaitingMonths is the number of months from graduation to start. Only
+// tahun_lulus is known, so graduation is taken as its January; pekerjaan
+// started earlier give a negative number.
+func WaitingMonths(tahunLulus int, start time.Time) int {
+    start = start.UTC()
+    return (start.Year()-tahunLulus)*12 + int(start.Month()) - 1
+}
+
+// IndustryCount - pekerjaan in one bidang_industri and the alumni who hold
+// them
+type IndustryCount struct {
+    BidangIndustri string `json:"bidang_industri" bson:"bidang_industri"`
+    Total          int    `json:"total" bson:"total"`
+    Alumni         int    `json:"alumni" bson:"alumni"`
+}
+
+// CountIndustries counts each bidang_industri of perAlumni, the industries
+// of each alumni's pekerjaan, most common first and by name among equals
+func CountIndustries(perAlumni [][]string) []IndustryCount {
+    totals, alumni := map[string]int{}, map[string]int{}
+    for _, list := range perAlumni {
+        seen := map[string]bool{}
+        for _, industri := range list {
+            totals[industri]++
+            if !seen[industri] {
+                seen[industri] = true
+                alumni[industri]++
+            }
+        }
+    }
+
+    counts := make([]IndustryCount, 0, len(totals))
+    for industri, total := range totals {
+        counts = append(counts, IndustryCount{BidangIndustri: industri, Total: total, Alumni: alumni[industri]})
+    }
+    SortIndustries(counts)
+    return counts
+}
+
+// topIndustries returns up to top of counts held by at least minSize alumni
+func topIndustries(counts []IndustryCount, top, minSize int) []IndustryCount {
+    result := []IndustryCount{}
+    for _, count := range counts {
+        if count.Alumni >= minSize && len(result) < top {
+            result = append(result, count)
+        }
+    }
+    return result
+}
+
+// SortIndustries orders counts most common first and by name among equals
+func SortIndustries(counts []IndustryCount) {
+    sort.Slice(counts, func(i, j int) bool {
+        if counts[i].Total != counts[j].Total {
+            return counts[i].Total > counts[j].Total
+        }
+        return counts[i].BidangIndustri < counts[j].BidangIndustri
+    })
+}
+
+// CohortStats - Response item for GET /stats/cohorts. Rates are percentages
+// of the cohort's alumni, churn of its employed alumni. A cohort of fewer
+// than the minimum group size of alumni is Suppressed and only shows Alumni;
+// with fewer employed alumni the figures about them are left out and top
+// industries only list those of at least that many alumni.
+type CohortStats struct {
+    Jurusan             string          `json:"jurusan"`
+    TahunLulus          int             `json:"tahun_lulus"`
+    Alumni              int             `json:"alumni"`
+    Suppressed          bool            `json:"suppressed"`
+    Employed            int             `json:"employed"`
+    EmploymentRate      float64         `json:"employment_rate"`
+    AktifRate           float64         `json:"aktif_rate"`
+    MedianWaitingMonths *float64        `json:"median_waiting_months"` // null without employed alumni
+    AvgPekerjaan        float64         `json:"avg_pekerjaan"`         // per employed alumni
+    ChurnRate           float64         `json:"churn_rate"`            // employed alumni with more than one pekerjaan
+    TopIndustries       []IndustryCount `json:"top_industries"`
+}
+
+// Stats turns the aggregate into its response with the top industries
+func (a CohortAggregate) Stats(top, minSize int) CohortStats {
+    stats := CohortStats{
+        Jurusan:       a.Jurusan,
+        TahunLulus:    a.TahunLulus,
+        Alumni:        a.Alumni,
+        TopIndustries: []IndustryCount{},
+    }
+    if a.Alumni > 0 && a.Alumni < minSize {
+        stats.Suppressed = true
+        return stats
+    }
+
+    stats.Employed = a.Employed
+    stats.EmploymentRate = percent(a.Employed, a.Alumni)
+    stats.AktifRate = percent(a.Aktif, a.Alumni)
+    if a.Employed == 0 {
+        return stats
+    }
+    if a.Employed < minSize {
+        stats.Suppressed = true
+        return stats
+    }
+
+    stats.MedianWaitingMonths = median(a.waiting())
+    stats.ChurnRate = percent(a.Changed, a.Employed)
+    stats.AvgPekerjaan = round2(float64(a.Pekerjaan) / float64(a.Employed))
+    stats.TopIndustries = topIndustries(a.Industries, top, minSize)
+    return stats
+}
+
+// waiting is WaitingMonths with pekerjaan started before graduation
+// counted as no wait
+func (a CohortAggregate) waiting() []int {
+    months := make([]int, len(a.WaitingMonths))
+    for i, m := range a.WaitingMonths {
+        months[i] = max(m, 0)
+    }
+    return months
+}
+
+// WaitingBands are the waiting time bands of the tracer study used for
+// accreditation, in months after graduation
+var WaitingBands = []WaitingBand{
+    {Label: "< 6 bulan", MaxMonths: 6},
+    {Label: "6-18 bulan", MinMonths: 6, MaxMonths: 18},
+    {Label: "> 18 bulan", MinMonths: 18},
+}
+
+// WaitingBand is a range of waiting months, MinMonths included and
+// MaxMonths excluded; a zero MaxMonths is open
+type WaitingBand struct {
+    Label     string  `json:"label"`
+    MinMonths int     `json:"min_months"`
+    MaxMonths int     `json:"max_months,omitempty"`
+    Total     int     `json:"total"`
+    Percent   float64 `json:"percent"` // of the employed alumni
+}
+
+// WaitingTimeStats - Response item for GET /stats/waiting-time. With fewer
+// employed alumni than the minimum group size it is Suppressed: no median,
+// mean or bands.
+type WaitingTimeStats struct {
+    Jurusan             string        `json:"jurusan"`
+    TahunLulus          int           `json:"tahun_lulus"`
+    Employed            int           `json:"employed"`
+    Suppressed          bool          `json:"suppressed"`
+    MedianWaitingMonths *float64      `json:"median_waiting_months"`
+    MeanWaitingMonths   *float64      `json:"mean_waiting_months"`
+    Bands               []WaitingBand `json:"bands"`
+}
+
+// WaitingTime sorts the employed alumni of the aggregate into WaitingBands
+func (a CohortAggregate) WaitingTime(minSize int) WaitingTimeStats {
+    months := a.waiting()
+    stats := WaitingTimeStats{
+        Jurusan:    a.Jurusan,
+        TahunLulus: a.TahunLulus,
+        Employed:   len(months),
+    }
+    if len(months) > 0 && len(months) < minSize {
+        stats.Suppressed = true
+        stats.Bands = []WaitingBand{}
+        return stats
+    }
+
+    stats.MedianWaitingMonths = median(months)
+    stats.Bands = make([]WaitingBand, len(WaitingBands))
+    copy(stats.Bands, WaitingBands)
+
+    sum := 0
+    for _, m := range months {
+        sum += m
+        for i, band := range stats.Bands {
+            if m >= band.MinMonths && (band.MaxMonths == 0 || m < band.MaxMonths) {
+                stats.Bands[i].Total++
+                break
+            }
+        }
+    }
+    for i := range stats.Bands {
+        stats.Bands[i].Percent = percent(stats.Bands[i].Total, len(months))
+    }
+    if len(months) > 0 {
+        mean := round2(float64(sum) / float64(len(months)))
+        stats.MeanWaitingMonths = &mean
+    }
+    return stats
+}
+
+// percent is part of total in percent with two decimals, 0 for an empty
+// total
+func percent(part, total int) float64 {
+    if total == 0 {
+        return 0
+    }
+    return round2(float64(part) * 100 / float64(total))
+}
+
+func round2(f float64) float64 {
+    return math.Round(f*100) / 100
+}
+
+// median of values, nil when there are none
+func median(values []int) *float64 {
+    if len(values) == 0 {
+        return nil
+    }
+    sorted := append([]int(nil), values...)
+    sort.Ints(sorted)
+
+    mid := len(sorted) / 2
+    m := float64(sorted[mid])
+    if len(sorted)%2 == 0 {
+        m = float64(sorted[mid-1]+sorted[mid]) / 2
+    }
+    return &m
+}
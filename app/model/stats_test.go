@@ -0,0 +1,89 @@
+package model
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestWaitingMonths(t *testing.T) {
+    tests := []struct {
+        tahunLulus  int
+        year, month int
+        want        int
+    }{
+        {2022, 2022, 1, 0},
+        {2022, 2022, 7, 6},
+        {2022, 2023, 3, 14},
+        {2022, 2021, 9, -4},
+    }
+    for _, tt := range tests {
+        if got := WaitingMonths(tt.tahunLulus, day(tt.year, 1, 15).AddDate(0, tt.month-1, 0)); got != tt.want {
+            t.Errorf("WaitingMonths(%d, %d-%02d) = %d, want %d", tt.tahunLulus, tt.year, tt.month, got, tt.want)
+        }
+    }
+}
+
+func TestCohortStats(t *testing.T) {
+    a := CohortAggregate{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022,
+        Alumni: 4, Employed: 3, Aktif: 2, Pekerjaan: 5, Changed: 1,
+        WaitingMonths: []int{-3, 8, 2},
+        Industries:    CountIndustries([][]string{{"Technology", "Banking"}, {"Technology", "Retail"}, {"Banking"}}),
+    }
+
+    stats := a.Stats(2, 2)
+    if stats.EmploymentRate != 75 || stats.AktifRate != 50 || stats.ChurnRate != 33.33 || stats.AvgPekerjaan != 1.67 {
+        t.Errorf("rates = %+v", stats)
+    }
+    if stats.MedianWaitingMonths == nil || *stats.MedianWaitingMonths != 2 {
+        t.Errorf("median = %v, want 2", stats.MedianWaitingMonths)
+    }
+    want := []IndustryCount{{BidangIndustri: "Banking", Total: 2, Alumni: 2}, {BidangIndustri: "Technology", Total: 2, Alumni: 2}}
+    if !reflect.DeepEqual(stats.TopIndustries, want) {
+        t.Errorf("top industries = %v, want %v", stats.TopIndustries, want)
+    }
+
+    if small := a.Stats(2, 5); !small.Suppressed || small.Employed != 0 || small.EmploymentRate != 0 || len(small.TopIndustries) != 0 {
+        t.Errorf("cohort under the minimum size = %+v", small)
+    }
+    few := a.Stats(2, 4)
+    if !few.Suppressed || few.EmploymentRate != 75 || few.MedianWaitingMonths != nil || few.ChurnRate != 0 || len(few.TopIndustries) != 0 {
+        t.Errorf("employed under the minimum size = %+v", few)
+    }
+
+    empty := CohortAggregate{Alumni: 2}.Stats(3, 1)
+    if empty.EmploymentRate != 0 || empty.MedianWaitingMonths != nil || empty.TopIndustries == nil {
+        t.Errorf("empty cohort = %+v", empty)
+    }
+}
+
+func TestCountIndustries(t *testing.T) {
+    got := CountIndustries([][]string{{"Technology", "Technology", "Banking"}, {"Technology"}})
+    want := []IndustryCount{{BidangIndustri: "Technology", Total: 3, Alumni: 2}, {BidangIndustri: "Banking", Total: 1, Alumni: 1}}
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("CountIndustries = %v, want %v", got, want)
+    }
+}
+
+func TestWaitingTime(t *testing.T) {
+    a := CohortAggregate{WaitingMonths: []int{-3, 5, 6, 17, 18, 30}}
+    stats := a.WaitingTime(1)
+
+    var totals []int
+    for _, band := range stats.Bands {
+        totals = append(totals, band.Total)
+    }
+    if !reflect.DeepEqual(totals, []int{2, 2, 2}) || stats.Bands[0].Percent != 33.33 {
+        t.Errorf("bands = %+v", stats.Bands)
+    }
+    if *stats.MedianWaitingMonths != 11.5 || *stats.MeanWaitingMonths != 12.67 {
+        t.Errorf("median = %v, mean = %v", *stats.MedianWaitingMonths, *stats.MeanWaitingMonths)
+    }
+    if WaitingBands[0].Total != 0 {
+        t.Error("WaitingTime changed WaitingBands")
+    }
+
+    if small := a.WaitingTime(7); !small.Suppressed || small.Employed != 6 || small.MedianWaitingMonths != nil || small.MeanWaitingMonths != nil || len(small.Bands) != 0 {
+        t.Errorf("under the minimum size = %+v", small)
+    }
+}
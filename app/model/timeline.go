@@ -0,0 +1,244 @@
+package model
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "time"
+)
+
+// Values of status_pekerjaan
+const (
+    StatusAktif        = "aktif"
+    StatusResign       = "resign"
+    StatusKontrakHabis = "kontrak_habis"
+)
+
+// Overlap policies: which pekerjaan of one alumni may run at the same time
+const (
+    OverlapAllow  = "allow"  // any
+    OverlapActive = "active" // any, but only one aktif at a time
+    OverlapNone   = "none"   // no overlapping periods at all
+)
+
+// ErrTimelineOverlap is wrapped by CheckOverlap
+var ErrTimelineOverlap = errors.New("pekerjaan bertabrakan dengan pekerjaan lain")
+
+// ValidateTimeline checks the dates and status of p against each other. An
+// aktif pekerjaan may have a planned end date, but not one already past;
+// resign and kontrak_habis need the date they ended.
+func (p *Pekerjaan) ValidateTimeline(now time.Time) error {
+    if p.TanggalMulaiKerja.IsZero() {
+        return errors.New("tanggal_mulai_kerja wajib diisi")
+    }
+
+    end := p.TanggalSelesaiKerja
+    if end != nil && end.Before(p.TanggalMulaiKerja) {
+        return errors.New("tanggal_selesai_kerja tidak boleh sebelum tanggal_mulai_kerja")
+    }
+
+    switch p.StatusPekerjaan {
+    case StatusAktif:
+        if end != nil && end.Before(now) {
+            return fmt.Errorf("pekerjaan dengan tanggal_selesai_kerja %s yang sudah lewat tidak bisa berstatus %s", end.Format("2006-01-02"), StatusAktif)
+        }
+    case StatusResign, StatusKontrakHabis:
+        if end == nil {
+            return fmt.Errorf("status %s memerlukan tanggal_selesai_kerja", p.StatusPekerjaan)
+        }
+        if end.After(now) {
+            return fmt.Errorf("status %s tidak boleh memiliki tanggal_selesai_kerja di masa depan", p.StatusPekerjaan)
+        }
+    default:
+        return fmt.Errorf("status_pekerjaan harus %s, %s atau %s", StatusAktif, StatusResign, StatusKontrakHabis)
+    }
+    return nil
+}
+
+// overlaps reports whether the periods of a and b share a day; an end date
+// may touch the next start
+func overlaps(a, b Pekerjaan) bool {
+    endsBefore := func(p Pekerjaan, start time.Time) bool {
+        return p.TanggalSelesaiKerja != nil && !p.TanggalSelesaiKerja.After(start)
+    }
+    return !endsBefore(a, b.TanggalMulaiKerja) && !endsBefore(b, a.TanggalMulaiKerja)
+}
+
+// CheckOverlap checks p against the other pekerjaan of the same alumni under
+// policy. An empty policy is OverlapActive.
+func CheckOverlap(p Pekerjaan, others []Pekerjaan, policy string) error {
+    if policy == OverlapAllow {
+        return nil
+    }
+    for _, other := range others {
+        if other.ID == p.ID || other.IsDelete != nil {
+            continue
+        }
+        switch policy {
+        case OverlapNone:
+            if overlaps(p, other) {
+                return fmt.Errorf("%w: %s di %s", ErrTimelineOverlap, other.PosisiJabatan, other.NamaPerusahaan)
+            }
+        default:
+            if p.StatusPekerjaan == StatusAktif && other.StatusPekerjaan == StatusAktif {
+                return fmt.Errorf("%w: alumni masih aktif sebagai %s di %s", ErrTimelineOverlap, other.PosisiJabatan, other.NamaPerusahaan)
+            }
+        }
+    }
+    return nil
+}
+
+// Kinds of timeline entries
+const (
+    TimelinePekerjaan = "pekerjaan"
+    TimelineGap       = "gap"
+)
+
+// TimelineEntry - one pekerjaan or one period without any in a career
+// history. End is nil while it is still running.
+type TimelineEntry struct {
+    Type      string             `json:"type"`
+    Start     time.Time          `json:"start"`
+    End       *time.Time         `json:"end"`
+    Days      int                `json:"days"`
+    Overlap   bool               `json:"overlap,omitempty"` // starts before an earlier pekerjaan ended
+    Pekerjaan *PekerjaanResponse `json:"pekerjaan,omitempty"`
+}
+
+// AlumniTimelineResponse - Response for GET /alumni/:id/timeline
+type AlumniTimelineResponse struct {
+    AlumniID       string          `json:"alumni_id"`
+    Nama           string          `json:"nama"`
+    TahunLulus     int             `json:"tahun_lulus"`
+    Entries        []TimelineEntry `json:"entries"`
+    TotalKerjaDays int             `json:"total_kerja_days"` // days covered by at least one pekerjaan
+    TotalGapDays   int             `json:"total_gap_days"`
+}
+
+// days counts whole days from start to end, or to now while open
+func days(start time.Time, end *time.Time, now time.Time) int {
+    until := now
+    if end != nil {
+        until = *end
+    }
+    if until.Before(start) {
+        return 0
+    }
+    return int(until.Sub(start).Hours() / 24)
+}
+
+// BuildTimeline orders the pekerjaan of one alumni by start date and puts a
+// gap entry wherever more than a day passes without any pekerjaan, including
+// after the last one ended
+func BuildTimeline(list []Pekerjaan, now time.Time) []TimelineEntry {
+    sorted := append([]Pekerjaan(nil), list...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        if !sorted[i].TanggalMulaiKerja.Equal(sorted[j].TanggalMulaiKerja) {
+            return sorted[i].TanggalMulaiKerja.Before(sorted[j].TanggalMulaiKerja)
+        }
+        return sorted[i].ID.Hex() < sorted[j].ID.Hex()
+    })
+
+    entries := []TimelineEntry{}
+    var covered *time.Time // end of the coverage so far
+    open := false          // a pekerjaan without end date is running
+    for i, p := range sorted {
+        start := p.TanggalMulaiKerja
+        if i > 0 && !open && start.Sub(*covered) > 24*time.Hour {
+            gapEnd := start
+            entries = append(entries, TimelineEntry{
+                Type:  TimelineGap,
+                Start: *covered,
+                End:   &gapEnd,
+                Days:  days(*covered, &gapEnd, now),
+            })
+        }
+
+        response := p.ToPekerjaanResponse()
+        entries = append(entries, TimelineEntry{
+            Type:      TimelinePekerjaan,
+            Start:     start,
+            End:       p.TanggalSelesaiKerja,
+            Days:      days(start, p.TanggalSelesaiKerja, now),
+            Overlap:   i > 0 && (open || start.Before(*covered)),
+            Pekerjaan: &response,
+        })
+
+        switch {
+        case p.TanggalSelesaiKerja == nil:
+            open = true
+        case covered == nil || p.TanggalSelesaiKerja.After(*covered):
+            end := *p.TanggalSelesaiKerja
+            covered = &end
+        }
+    }
+
+    if len(sorted) > 0 && !open && covered != nil && now.Sub(*covered) > 24*time.Hour {
+        entries = append(entries, TimelineEntry{
+            Type:  TimelineGap,
+            Start: *covered,
+            Days:  days(*covered, nil, now),
+        })
+    }
+    return entries
+}
+
+// ToAlumniTimelineResponse builds the career history of a
+func (a *Alumni) ToAlumniTimelineResponse(list []Pekerjaan, now time.Time) AlumniTimelineResponse {
+    response := AlumniTimelineResponse{
+        AlumniID:   a.ID.Hex(),
+        Nama:       a.Nama,
+        TahunLulus: a.TahunLulus,
+        Entries:    BuildTimeline(list, now),
+    }
+    for _, entry := range response.Entries {
+        if entry.Type == TimelineGap {
+            response.TotalGapDays += entry.Days
+        }
+    }
+    response.TotalKerjaDays = coveredDays(list, now)
+    return response
+}
+
+// coveredDays counts the days with at least one pekerjaan, overlaps counted
+// once
+func coveredDays(list []Pekerjaan, now time.Time) int {
+    type period struct{ start, end time.Time }
+    periods := make([]period, 0, len(list))
+    for _, p := range list {
+        end := now
+        if p.TanggalSelesaiKerja != nil {
+            end = *p.TanggalSelesaiKerja
+        }
+        if end.After(p.TanggalMulaiKerja) {
+            periods = append(periods, period{p.TanggalMulaiKerja, end})
+        }
+    }
+    sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+    total := time.Duration(0)
+    var current *period
+    for i := range periods {
+        switch {
+        case current == nil:
+            current = &periods[i]
+        case !periods[i].start.After(current.end):
+            if periods[i].end.After(current.end) {
+                current.end = periods[i].end
+            }
+        default:
+            total += current.end.Sub(current.start)
+            current = &periods[i]
+        }
+    }
+    if current != nil {
+        total += current.end.Sub(current.start)
+    }
+    return int(total.Hours() / 24)
+}
+
+// ExpiredContract is an aktif pekerjaan whose tanggal_selesai_kerja passed
+// before now
+func ExpiredContract(p Pekerjaan, now time.Time) bool {
+    return p.IsDelete == nil && p.StatusPekerjaan == StatusAktif && p.TanggalSelesaiKerja != nil && p.TanggalSelesaiKerja.Before(now)
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+    return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func job(start time.Time, end *time.Time, status string) Pekerjaan {
+    return Pekerjaan{ID: primitive.NewObjectID(), TanggalMulaiKerja: start, TanggalSelesaiKerja: end, StatusPekerjaan: status}
+}
+
+func dayPtr(year int, month time.Month, d int) *time.Time {
+    t := day(year, month, d)
+    return &t
+}
+
+func TestValidateTimeline(t *testing.T) {
+    now := day(2024, 6, 1)
+
+    valid := map[string]Pekerjaan{
+        "aktif open":         job(day(2022, 1, 1), nil, StatusAktif),
+        "aktif planned end":  job(day(2022, 1, 1), dayPtr(2025, 1, 1), StatusAktif),
+        "resign":             job(day(2022, 1, 1), dayPtr(2023, 1, 1), StatusResign),
+        "kontrak habis":      job(day(2022, 1, 1), dayPtr(2023, 1, 1), StatusKontrakHabis),
+        "one day, same date": job(day(2022, 1, 1), dayPtr(2022, 1, 1), StatusResign),
+    }
+    for name, p := range valid {
+        if err := p.ValidateTimeline(now); err != nil {
+            t.Errorf("%s: %v", name, err)
+        }
+    }
+
+    invalid := map[string]Pekerjaan{
+        "no start":           job(time.Time{}, nil, StatusAktif),
+        "end before start":   job(day(2022, 1, 1), dayPtr(2021, 1, 1), StatusResign),
+        "aktif past end":     job(day(2022, 1, 1), dayPtr(2023, 1, 1), StatusAktif),
+        "resign without end": job(day(2022, 1, 1), nil, StatusResign),
+        "resign future end":  job(day(2022, 1, 1), dayPtr(2025, 1, 1), StatusResign),
+        "unknown status":     job(day(2022, 1, 1), nil, "freelance"),
+    }
+    for name, p := range invalid {
+        if err := p.ValidateTimeline(now); err == nil {
+            t.Errorf("%s: accepted", name)
+        }
+    }
+}
+
+func TestCheckOverlap(t *testing.T) {
+    current := job(day(2022, 1, 1), nil, StatusAktif)
+    past := job(day(2019, 1, 1), dayPtr(2021, 12, 31), StatusResign)
+    others := []Pekerjaan{current, past}
+
+    second := job(day(2023, 1, 1), nil, StatusAktif)
+    sideJob := job(day(2023, 1, 1), dayPtr(2023, 6, 1), StatusResign)
+    before := job(day(2017, 1, 1), dayPtr(2019, 1, 1), StatusResign) // ends the day past starts
+
+    tests := []struct {
+        policy  string
+        p       Pekerjaan
+        overlap bool
+    }{
+        {OverlapAllow, second, false},
+        {OverlapActive, second, true},
+        {"", second, true},
+        {OverlapActive, sideJob, false},
+        {OverlapNone, sideJob, true},
+        {OverlapNone, before, false},
+        {OverlapActive, current, false}, // itself
+    }
+    for _, tt := range tests {
+        err := CheckOverlap(tt.p, others, tt.policy)
+        if tt.overlap != errors.Is(err, ErrTimelineOverlap) {
+            t.Errorf("policy %q, %s-%v %s: err = %v", tt.policy, tt.p.TanggalMulaiKerja.Format("2006-01-02"), tt.p.TanggalSelesaiKerja, tt.p.StatusPekerjaan, err)
+        }
+    }
+}
+
+func TestBuildTimeline(t *testing.T) {
+    now := day(2024, 1, 1)
+    first := job(day(2019, 1, 1), dayPtr(2020, 1, 1), StatusResign)
+    side := job(day(2019, 6, 1), dayPtr(2019, 9, 1), StatusResign)
+    second := job(day(2020, 3, 1), dayPtr(2022, 3, 1), StatusKontrakHabis)
+
+    // Out of order on purpose
+    entries := BuildTimeline([]Pekerjaan{second, side, first}, now)
+
+    want := []struct {
+        typ     string
+        start   time.Time
+        days    int
+        overlap bool
+    }{
+        {TimelinePekerjaan, first.TanggalMulaiKerja, 365, false},
+        {TimelinePekerjaan, side.TanggalMulaiKerja, 92, true},
+        {TimelineGap, day(2020, 1, 1), 60, false},
+        {TimelinePekerjaan, second.TanggalMulaiKerja, 730, false},
+        {TimelineGap, day(2022, 3, 1), 671, false},
+    }
+    if len(entries) != len(want) {
+        t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+    }
+    for i, w := range want {
+        e := entries[i]
+        if e.Type != w.typ || !e.Start.Equal(w.start) || e.Days != w.days || e.Overlap != w.overlap {
+            t.Errorf("entry %d = %s %s %d days overlap=%v, want %s %s %d days overlap=%v", i,
+                e.Type, e.Start.Format("2006-01-02"), e.Days, e.Overlap, w.typ, w.start.Format("2006-01-02"), w.days, w.overlap)
+        }
+    }
+    if entries[4].End != nil {
+        t.Errorf("trailing gap should be open, ends %v", entries[4].End)
+    }
+
+    // Still employed: no trailing gap
+    current := job(day(2022, 4, 1), nil, StatusAktif)
+    entries = BuildTimeline([]Pekerjaan{first, second, current}, now)
+    if last := entries[len(entries)-1]; last.Type != TimelinePekerjaan || last.End != nil {
+        t.Errorf("last entry = %+v, want the running pekerjaan", last)
+    }
+
+    if entries := BuildTimeline(nil, now); len(entries) != 0 {
+        t.Errorf("no pekerjaan: %+v", entries)
+    }
+}
+
+func TestAlumniTimelineTotals(t *testing.T) {
+    now := day(2024, 1, 1)
+    a := Alumni{ID: primitive.NewObjectID(), Nama: "John Doe", TahunLulus: 2018}
+    response := a.ToAlumniTimelineResponse([]Pekerjaan{
+        job(day(2019, 1, 1), dayPtr(2020, 1, 1), StatusResign),
+        job(day(2019, 6, 1), dayPtr(2019, 9, 1), StatusResign),
+        job(day(2020, 3, 1), nil, StatusAktif),
+    }, now)
+
+    // Overlapping days count once
+    if response.TotalKerjaDays != 365+1401 || response.TotalGapDays != 60 {
+        t.Errorf("totals = %d kerja, %d gap", response.TotalKerjaDays, response.TotalGapDays)
+    }
+}
+
+func TestExpiredContract(t *testing.T) {
+    now := day(2024, 1, 1)
+    deleted := now
+
+    if !ExpiredContract(job(day(2022, 1, 1), dayPtr(2023, 12, 31), StatusAktif), now) {
+        t.Error("aktif past its end should expire")
+    }
+    for name, p := range map[string]Pekerjaan{
+        "open":          job(day(2022, 1, 1), nil, StatusAktif),
+        "future end":    job(day(2022, 1, 1), dayPtr(2024, 6, 1), StatusAktif),
+        "already ended": job(day(2022, 1, 1), dayPtr(2023, 1, 1), StatusResign),
+        "in trash":      {TanggalMulaiKerja: day(2022, 1, 1), TanggalSelesaiKerja: dayPtr(2023, 1, 1), StatusPekerjaan: StatusAktif, IsDelete: &deleted},
+    } {
+        if ExpiredContract(p, now) {
+            t.Errorf("%s: expired", name)
+        }
+    }
+}
@@ -14,17 +14,16 @@ import (
 
 const alumniCollection = "alumni"
 
-type AlumniRepository struct {
+type MongoAlumniRepository struct {
     DB *mongo.Database
 }
 
-func NewAlumniRepository(db *mongo.Database) *AlumniRepository {
-    return &AlumniRepository{DB: db}
+func NewMongoAlumniRepository(db *mongo.Database) *MongoAlumniRepository {
+    return &MongoAlumniRepository{DB: db}
 }
 
-func (r *AlumniRepository) CreateAlumni(alumni model.Alumni) (*model.Alumni, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoAlumniRepository) CreateAlumni(ctx context.Context, alumni model.Alumni) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.CreateAlumni")()
 
     collection := r.DB.Collection(alumniCollection)
     
@@ -40,15 +39,26 @@ func (r *AlumniRepository) CreateAlumni(alumni model.Alumni) (*model.Alumni, err
     return &alumni, nil
 }
 
-func (r *AlumniRepository) UpdateAlumni(id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoAlumniRepository) UpdateAlumni(ctx context.Context, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.UpdateAlumni")()
 
     collection := r.DB.Collection(alumniCollection)
     
     alumni.UpdatedAt = time.Now()
     
-    update := bson.M{
+    filter := bson.M{"_id": id}
+    _, err := collection.UpdateOne(ctx, filter, alumniUpdate(alumni))
+    if err != nil {
+        return nil, err
+    }
+    
+    alumni.ID = id
+    return &alumni, nil
+}
+
+// alumniUpdate is the update document of UpdateAlumni
+func alumniUpdate(alumni model.Alumni) bson.M {
+    return bson.M{
         "$set": bson.M{
             "nim":         alumni.NIM,
             "nama":        alumni.Nama,
@@ -61,30 +71,52 @@ func (r *AlumniRepository) UpdateAlumni(id primitive.ObjectID, alumni model.Alum
             "updated_at":  alumni.UpdatedAt,
         },
     }
+}
+
+func (r *MongoAlumniRepository) DeleteAlumni(ctx context.Context, id primitive.ObjectID) error {
+    defer observe(ctx, "AlumniRepository.DeleteAlumni")()
+
+    collection := r.DB.Collection(alumniCollection)
     
-    filter := bson.M{"_id": id}
-    _, err := collection.UpdateOne(ctx, filter, update)
+    _, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+    return err
+}
+
+func (r *MongoAlumniRepository) FindAlumniByID(ctx context.Context, id primitive.ObjectID) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.FindAlumniByID")()
+
+    var alumni model.Alumni
+    err := r.DB.Collection(alumniCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&alumni)
+    if err == mongo.ErrNoDocuments {
+        return nil, ErrAlumniNotFound
+    }
     if err != nil {
         return nil, err
     }
-    
-    alumni.ID = id
+
     return &alumni, nil
 }
 
-func (r *AlumniRepository) DeleteAlumni(id primitive.ObjectID) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// FindAlumniByUserID returns the alumni linked to a user account, the first
+// one when several are
+func (r *MongoAlumniRepository) FindAlumniByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.FindAlumniByUserID")()
 
-    collection := r.DB.Collection(alumniCollection)
-    
-    _, err := collection.DeleteOne(ctx, bson.M{"_id": id})
-    return err
+    var alumni model.Alumni
+    opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+    err := r.DB.Collection(alumniCollection).FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&alumni)
+    if err == mongo.ErrNoDocuments {
+        return nil, ErrAlumniNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &alumni, nil
 }
 
-func (r *AlumniRepository) GetAlumni(search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoAlumniRepository) GetAlumni(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.GetAlumni")()
 
     collection := r.DB.Collection(alumniCollection)
     
@@ -128,9 +160,8 @@ func (r *AlumniRepository) GetAlumni(search, sortBy, order string, limit, offset
     return list, nil
 }
 
-func (r *AlumniRepository) CountAlumni(search string) (int, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoAlumniRepository) CountAlumni(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "AlumniRepository.CountAlumni")()
 
     collection := r.DB.Collection(alumniCollection)
     
@@ -151,9 +182,8 @@ func (r *AlumniRepository) CountAlumni(search string) (int, error) {
     return int(count), nil
 }
 
-func (r *AlumniRepository) GetAlumniStatsByJurusan() ([]model.AlumniStatsByJurusanResponse, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoAlumniRepository) GetAlumniStatsByJurusan(ctx context.Context) ([]model.AlumniStatsByJurusanResponse, error) {
+    defer observe(ctx, "AlumniRepository.GetAlumniStatsByJurusan")()
 
     collection := r.DB.Collection(alumniCollection)
     
@@ -184,33 +214,31 @@ func (r *AlumniRepository) GetAlumniStatsByJurusan() ([]model.AlumniStatsByJurus
     return stats, nil
 }
 
-// Legacy functions for compatibility (will be deprecated)
-func CreateAlumni(db *mongo.Database, alumni model.Alumni) (*model.Alumni, error) {
-    repo := NewAlumniRepository(db)
-    return repo.CreateAlumni(alumni)
-}
+// BulkWriteAlumni runs the writes as one BulkWrite
+func (r *MongoAlumniRepository) BulkWriteAlumni(ctx context.Context, writes []model.AlumniWrite, ordered bool) ([]error, error) {
+    defer observe(ctx, "AlumniRepository.BulkWriteAlumni")()
 
-func UpdateAlumni(db *mongo.Database, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
-    repo := NewAlumniRepository(db)
-    return repo.UpdateAlumni(id, alumni)
-}
-
-func DeleteAlumni(db *mongo.Database, id primitive.ObjectID) error {
-    repo := NewAlumniRepository(db)
-    return repo.DeleteAlumni(id)
-}
+    if len(writes) == 0 {
+        return nil, nil
+    }
 
-func GetAlumniRepo(db *mongo.Database, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
-    repo := NewAlumniRepository(db)
-    return repo.GetAlumni(search, sortBy, order, limit, offset)
-}
+    now := time.Now()
+    models := make([]mongo.WriteModel, len(writes))
+    for i, w := range writes {
+        alumni := w.Alumni
+        alumni.UpdatedAt = now
+        switch w.Op {
+        case model.BulkCreate:
+            alumni.ID = w.ID
+            alumni.CreatedAt = now
+            models[i] = mongo.NewInsertOneModel().SetDocument(alumni)
+        case model.BulkUpdate:
+            models[i] = mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": w.ID}).SetUpdate(alumniUpdate(alumni))
+        default:
+            models[i] = mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": w.ID})
+        }
+    }
 
-func CountAlumniRepo(db *mongo.Database, search string) (int, error) {
-    repo := NewAlumniRepository(db)
-    return repo.CountAlumni(search)
+    _, err := r.DB.Collection(alumniCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+    return bulkWriteErrors(len(writes), ordered, err)
 }
-
-func GetAlumniStatsByJurusan(db *mongo.Database) ([]model.AlumniStatsByJurusanResponse, error) {
-    repo := NewAlumniRepository(db)
-    return repo.GetAlumniStatsByJurusan()
-}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package repository
+
+import (
+    "context"
+    "errors"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// errDuplicateID fails a memory bulk create whose id is taken, like the
+// duplicate key error of Mongo
+var errDuplicateID = errors.New("id sudah digunakan")
+
+// runBulk runs write for each of n operations the way Mongo's BulkWrite
+// does: an ordered batch stops at the first error and reports the rest as
+// ErrBulkSkipped, an unordered one runs them all
+func runBulk(n int, ordered bool, write func(i int) error) []error {
+    errs := make([]error, n)
+    failed := false
+    for i := range errs {
+        if failed && ordered {
+            errs[i] = ErrBulkSkipped
+            continue
+        }
+        if errs[i] = write(i); errs[i] != nil {
+            failed = true
+        }
+    }
+    return errs
+}
+
+// anyWritten reports whether a bulk write changed anything
+func anyWritten(errs []error) bool {
+    for _, err := range errs {
+        if err == nil {
+            return true
+        }
+    }
+    return false
+}
+
+// bulkWriteErrors maps the error of a Mongo BulkWrite of n models to one
+// error per model. Anything but a write error fails the whole batch.
+func bulkWriteErrors(n int, ordered bool, err error) ([]error, error) {
+    errs := make([]error, n)
+    if err == nil {
+        return errs, nil
+    }
+    var bwe mongo.BulkWriteException
+    if !errors.As(err, &bwe) || len(bwe.WriteErrors) == 0 {
+        return nil, err
+    }
+    first := n
+    for _, we := range bwe.WriteErrors {
+        if we.Index >= 0 && we.Index < n {
+            errs[we.Index] = we
+            first = min(first, we.Index)
+        }
+    }
+    if ordered {
+        for i := first + 1; i < n; i++ {
+            errs[i] = ErrBulkSkipped
+        }
+    }
+    return errs, nil
+}
+
+// writeAlumni runs one write of a bulk request through the single-item
+// methods, for the backends without a BulkWrite
+func writeAlumni(ctx context.Context, r AlumniRepository, w model.AlumniWrite) error {
+    switch w.Op {
+    case model.BulkCreate:
+        w.Alumni.ID = w.ID
+        _, err := r.CreateAlumni(ctx, w.Alumni)
+        return err
+    case model.BulkUpdate:
+        _, err := r.UpdateAlumni(ctx, w.ID, w.Alumni)
+        return err
+    default:
+        return r.DeleteAlumni(ctx, w.ID)
+    }
+}
+
+// writePekerjaan is writeAlumni for pekerjaan
+func writePekerjaan(ctx context.Context, r PekerjaanRepository, w model.PekerjaanWrite) error {
+    switch w.Op {
+    case model.BulkCreate:
+        w.Pekerjaan.ID = w.ID
+        _, err := r.CreatePekerjaan(ctx, w.Pekerjaan)
+        return err
+    case model.BulkUpdate:
+        _, err := r.UpdatePekerjaan(ctx, w.ID, w.Pekerjaan)
+        return err
+    case model.BulkSoftDelete:
+        return r.SoftDelete(ctx, w.ID, primitive.NilObjectID, true)
+    default:
+        return r.HardDeletePekerjaan(ctx, w.ID, primitive.NilObjectID, true)
+    }
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log/slog"
+    "strconv"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/cache"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cache namespaces, invalidated by every write to their collection
+const (
+    AlumniCacheNamespace    = "alumni"
+    PekerjaanCacheNamespace = "pekerjaan"
+)
+
+// NewCachedRepositories caches the stats and list queries of repos in c.
+// Trash queries depend on the caller and are not cached. Companies are not
+// cached, but linking and unlinking pekerjaan invalidates the pekerjaan
+// namespace.
+func NewCachedRepositories(repos Repositories, c *cache.Cache) Repositories {
+    repos.Alumni = &cachedAlumniRepository{AlumniRepository: repos.Alumni, cache: c}
+    repos.Pekerjaan = &cachedPekerjaanRepository{PekerjaanRepository: repos.Pekerjaan, cache: c}
+    if repos.Company != nil {
+        repos.Company = &cachedCompanyRepository{CompanyRepository: repos.Company, cache: c}
+    }
+    if repos.Stats != nil {
+        repos.Stats = &cachedStatsRepository{StatsRepository: repos.Stats, cache: c}
+    }
+    return repos
+}
+
+// invalidate records a write; a failure is only logged, the entries cached
+// before it expire after the cache TTL
+func invalidate(ctx context.Context, c *cache.Cache, namespace string) {
+    if err := c.Invalidate(ctx, namespace); err != nil {
+        slog.ErrorContext(ctx, "cache invalidation failed", "namespace", namespace, "error", err)
+    }
+}
+
+type cachedAlumniRepository struct {
+    AlumniRepository
+    cache *cache.Cache
+}
+
+func (r *cachedAlumniRepository) CreateAlumni(ctx context.Context, alumni model.Alumni) (*model.Alumni, error) {
+    created, err := r.AlumniRepository.CreateAlumni(ctx, alumni)
+    if err == nil {
+        invalidate(ctx, r.cache, AlumniCacheNamespace)
+    }
+    return created, err
+}
+
+func (r *cachedAlumniRepository) UpdateAlumni(ctx context.Context, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
+    updated, err := r.AlumniRepository.UpdateAlumni(ctx, id, alumni)
+    if err == nil {
+        invalidate(ctx, r.cache, AlumniCacheNamespace)
+    }
+    return updated, err
+}
+
+func (r *cachedAlumniRepository) DeleteAlumni(ctx context.Context, id primitive.ObjectID) error {
+    err := r.AlumniRepository.DeleteAlumni(ctx, id)
+    if err == nil {
+        invalidate(ctx, r.cache, AlumniCacheNamespace)
+    }
+    return err
+}
+
+func (r *cachedAlumniRepository) BulkWriteAlumni(ctx context.Context, writes []model.AlumniWrite, ordered bool) ([]error, error) {
+    errs, err := r.AlumniRepository.BulkWriteAlumni(ctx, writes, ordered)
+    if anyWritten(errs) {
+        invalidate(ctx, r.cache, AlumniCacheNamespace)
+    }
+    return errs, err
+}
+
+func (r *cachedAlumniRepository) GetAlumni(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+    key := fmt.Sprintf("list:%q:%s:%s:%d:%d", search, sortBy, order, limit, offset)
+    return cache.Remember(ctx, r.cache, AlumniCacheNamespace, key, func() ([]model.Alumni, error) {
+        return r.AlumniRepository.GetAlumni(ctx, search, sortBy, order, limit, offset)
+    })
+}
+
+func (r *cachedAlumniRepository) CountAlumni(ctx context.Context, search string) (int, error) {
+    return cache.Remember(ctx, r.cache, AlumniCacheNamespace, fmt.Sprintf("count:%q", search), func() (int, error) {
+        return r.AlumniRepository.CountAlumni(ctx, search)
+    })
+}
+
+func (r *cachedAlumniRepository) GetAlumniStatsByJurusan(ctx context.Context) ([]model.AlumniStatsByJurusanResponse, error) {
+    return cache.Remember(ctx, r.cache, AlumniCacheNamespace, "stats:jurusan", func() ([]model.AlumniStatsByJurusanResponse, error) {
+        return r.AlumniRepository.GetAlumniStatsByJurusan(ctx)
+    })
+}
+
+type cachedPekerjaanRepository struct {
+    PekerjaanRepository
+    cache *cache.Cache
+}
+
+func (r *cachedPekerjaanRepository) CreatePekerjaan(ctx context.Context, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    created, err := r.PekerjaanRepository.CreatePekerjaan(ctx, p)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return created, err
+}
+
+func (r *cachedPekerjaanRepository) UpdatePekerjaan(ctx context.Context, id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    updated, err := r.PekerjaanRepository.UpdatePekerjaan(ctx, id, p)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return updated, err
+}
+
+func (r *cachedPekerjaanRepository) SoftDelete(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    err := r.PekerjaanRepository.SoftDelete(ctx, id, userID, isAdmin)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return err
+}
+
+func (r *cachedPekerjaanRepository) RestorePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    err := r.PekerjaanRepository.RestorePekerjaan(ctx, id, userID, isAdmin)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return err
+}
+
+func (r *cachedPekerjaanRepository) HardDeletePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    err := r.PekerjaanRepository.HardDeletePekerjaan(ctx, id, userID, isAdmin)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return err
+}
+
+func (r *cachedPekerjaanRepository) RestorePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    found, err := r.PekerjaanRepository.RestorePekerjaanBulk(ctx, ids, userID, isAdmin)
+    if len(found) > 0 {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return found, err
+}
+
+func (r *cachedPekerjaanRepository) HardDeletePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    found, err := r.PekerjaanRepository.HardDeletePekerjaanBulk(ctx, ids, userID, isAdmin)
+    if len(found) > 0 {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return found, err
+}
+
+func (r *cachedPekerjaanRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+    n, err := r.PekerjaanRepository.PurgeTrash(ctx, before)
+    if n > 0 {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return n, err
+}
+
+func (r *cachedPekerjaanRepository) BulkWritePekerjaan(ctx context.Context, writes []model.PekerjaanWrite, ordered bool) ([]error, error) {
+    errs, err := r.PekerjaanRepository.BulkWritePekerjaan(ctx, writes, ordered)
+    if anyWritten(errs) {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return errs, err
+}
+
+func (r *cachedPekerjaanRepository) FindPekerjaanByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, "alumni:"+alumniID.Hex(), func() ([]model.Pekerjaan, error) {
+        return r.PekerjaanRepository.FindPekerjaanByAlumniID(ctx, alumniID)
+    })
+}
+
+// pekerjaanFilterKey writes every field of the filter into the cache key
+func pekerjaanFilterKey(f model.PekerjaanFilter) string {
+    bound := func(n *int64) string {
+        if n == nil {
+            return "-"
+        }
+        return strconv.FormatInt(*n, 10)
+    }
+    return fmt.Sprintf("%q:%s:%s:%s:%s", f.Search, bound(f.GajiMin), bound(f.GajiMax), f.GajiCurrency, f.GajiPeriod)
+}
+
+func (r *cachedPekerjaanRepository) GetPekerjaan(ctx context.Context, filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    key := fmt.Sprintf("list:%s:%s:%s:%d:%d", pekerjaanFilterKey(filter), sortBy, order, limit, offset)
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, key, func() ([]model.Pekerjaan, error) {
+        return r.PekerjaanRepository.GetPekerjaan(ctx, filter, sortBy, order, limit, offset)
+    })
+}
+
+func (r *cachedPekerjaanRepository) CountPekerjaan(ctx context.Context, filter model.PekerjaanFilter) (int, error) {
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, "count:"+pekerjaanFilterKey(filter), func() (int, error) {
+        return r.PekerjaanRepository.CountPekerjaan(ctx, filter)
+    })
+}
+
+func (r *cachedPekerjaanRepository) CountPekerjaanByStatus(ctx context.Context, status string) (int, error) {
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, "count_status:"+status, func() (int, error) {
+        return r.PekerjaanRepository.CountPekerjaanByStatus(ctx, status)
+    })
+}
+
+func (r *cachedPekerjaanRepository) ExpireContracts(ctx context.Context, now time.Time) (int, error) {
+    n, err := r.PekerjaanRepository.ExpireContracts(ctx, now)
+    if n > 0 {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return n, err
+}
+
+func (r *cachedPekerjaanRepository) ModeratePekerjaan(ctx context.Context, id primitive.ObjectID, status, catatan string, moderatorID primitive.ObjectID) error {
+    err := r.PekerjaanRepository.ModeratePekerjaan(ctx, id, status, catatan, moderatorID)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return err
+}
+
+type cachedCompanyRepository struct {
+    CompanyRepository
+    cache *cache.Cache
+}
+
+func (r *cachedCompanyRepository) DeleteCompany(ctx context.Context, id primitive.ObjectID) error {
+    err := r.CompanyRepository.DeleteCompany(ctx, id)
+    if err == nil {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return err
+}
+
+func (r *cachedCompanyRepository) LinkPekerjaan(ctx context.Context, companyID primitive.ObjectID, pekerjaanIDs []primitive.ObjectID) (int, error) {
+    linked, err := r.CompanyRepository.LinkPekerjaan(ctx, companyID, pekerjaanIDs)
+    if err == nil && linked > 0 {
+        invalidate(ctx, r.cache, PekerjaanCacheNamespace)
+    }
+    return linked, err
+}
+
+// cachedStatsRepository caches the analytics, which read both alumni and
+// pekerjaan: entries live in the pekerjaan namespace with the alumni version
+// in their key
+type cachedStatsRepository struct {
+    StatsRepository
+    cache *cache.Cache
+}
+
+// statsKey prefixes key with the alumni version, ok is false when the cache
+// is unavailable
+func (r *cachedStatsRepository) statsKey(ctx context.Context, key string) (string, bool) {
+    version, err := r.cache.Version(ctx, AlumniCacheNamespace)
+    if err != nil {
+        slog.WarnContext(ctx, "cache unavailable", "namespace", AlumniCacheNamespace, "error", err)
+        return "", false
+    }
+    return "stats:" + strconv.FormatInt(version.Unix(), 10) + ":" + key, true
+}
+
+// cohortFilterKey writes every field of the filter into the cache key
+func cohortFilterKey(f model.CohortFilter) string {
+    return fmt.Sprintf("%q:%d:%d:%d", f.Jurusan, f.Angkatan, f.TahunLulusFrom, f.TahunLulusTo)
+}
+
+func (r *cachedStatsRepository) GetCohorts(ctx context.Context, filter model.CohortFilter) ([]model.CohortAggregate, error) {
+    key, ok := r.statsKey(ctx, "cohorts:"+cohortFilterKey(filter))
+    if !ok {
+        return r.StatsRepository.GetCohorts(ctx, filter)
+    }
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, key, func() ([]model.CohortAggregate, error) {
+        return r.StatsRepository.GetCohorts(ctx, filter)
+    })
+}
+
+func (r *cachedStatsRepository) GetPekerjaanFacts(ctx context.Context, filter model.CohortFilter) ([]model.PekerjaanFact, error) {
+    key, ok := r.statsKey(ctx, "facts:"+cohortFilterKey(filter))
+    if !ok {
+        return r.StatsRepository.GetPekerjaanFacts(ctx, filter)
+    }
+    return cache.Remember(ctx, r.cache, PekerjaanCacheNamespace, key, func() ([]model.PekerjaanFact, error) {
+        return r.StatsRepository.GetPekerjaanFacts(ctx, filter)
+    })
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+    "context"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const companyCollection = "companies"
+
+type MongoCompanyRepository struct {
+    DB *mongo.Database
+}
+
+func NewMongoCompanyRepository(db *mongo.Database) *MongoCompanyRepository {
+    return &MongoCompanyRepository{DB: db}
+}
+
+func (r *MongoCompanyRepository) CreateCompany(ctx context.Context, company model.Company) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.CreateCompany")()
+
+    company.CreatedAt = time.Now()
+    company.UpdatedAt = time.Now()
+
+    result, err := r.DB.Collection(companyCollection).InsertOne(ctx, company)
+    if err != nil {
+        if mongo.IsDuplicateKeyError(err) {
+            return nil, ErrCompanyExists
+        }
+        return nil, err
+    }
+
+    company.ID = result.InsertedID.(primitive.ObjectID)
+    return &company, nil
+}
+
+func (r *MongoCompanyRepository) UpdateCompany(ctx context.Context, id primitive.ObjectID, company model.Company) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.UpdateCompany")()
+
+    company.UpdatedAt = time.Now()
+
+    var updated model.Company
+    err := r.DB.Collection(companyCollection).FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{
+        "$set": bson.M{
+            "nama":            company.Nama,
+            "nama_key":        company.NamaKey,
+            "aliases":         company.Aliases,
+            "alias_keys":      company.AliasKeys,
+            "bidang_industri": company.BidangIndustri,
+            "lokasi":          company.Lokasi,
+            "updated_at":      company.UpdatedAt,
+        },
+    }, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+    switch {
+    case errors.Is(err, mongo.ErrNoDocuments):
+        return nil, ErrCompanyNotFound
+    case mongo.IsDuplicateKeyError(err):
+        return nil, ErrCompanyExists
+    case err != nil:
+        return nil, err
+    }
+
+    return &updated, nil
+}
+
+func (r *MongoCompanyRepository) DeleteCompany(ctx context.Context, id primitive.ObjectID) error {
+    defer observe(ctx, "CompanyRepository.DeleteCompany")()
+
+    result, err := r.DB.Collection(companyCollection).DeleteOne(ctx, bson.M{"_id": id})
+    if err != nil {
+        return err
+    }
+    if result.DeletedCount == 0 {
+        return ErrCompanyNotFound
+    }
+
+    _, err = r.DB.Collection(pekerjaanCollection).UpdateMany(ctx, bson.M{"company_id": id}, bson.M{"$unset": bson.M{"company_id": ""}})
+    return err
+}
+
+func (r *MongoCompanyRepository) FindCompanyByID(ctx context.Context, id primitive.ObjectID) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.FindCompanyByID")()
+
+    var company model.Company
+    err := r.DB.Collection(companyCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&company)
+    if errors.Is(err, mongo.ErrNoDocuments) {
+        return nil, ErrCompanyNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &company, nil
+}
+
+func companySearch(search string) bson.M {
+    filter := bson.M{}
+    if search != "" {
+        filter["$or"] = []bson.M{
+            {"nama": bson.M{"$regex": search, "$options": "i"}},
+            {"aliases": bson.M{"$regex": search, "$options": "i"}},
+            {"bidang_industri": bson.M{"$regex": search, "$options": "i"}},
+            {"lokasi": bson.M{"$regex": search, "$options": "i"}},
+        }
+    }
+    return filter
+}
+
+func (r *MongoCompanyRepository) GetCompanies(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Company, error) {
+    defer observe(ctx, "CompanyRepository.GetCompanies")()
+
+    sortOrder := 1
+    if order == "desc" {
+        sortOrder = -1
+    }
+
+    allowedSort := map[string]bool{"_id": true, "nama": true, "bidang_industri": true, "lokasi": true}
+    if !allowedSort[sortBy] {
+        sortBy = "_id"
+    }
+
+    opts := options.Find().
+        SetSort(bson.D{{Key: sortBy, Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+        SetLimit(int64(limit)).
+        SetSkip(int64(offset))
+
+    cursor, err := r.DB.Collection(companyCollection).Find(ctx, companySearch(search), opts)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Company{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+func (r *MongoCompanyRepository) CountCompanies(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "CompanyRepository.CountCompanies")()
+
+    count, err := r.DB.Collection(companyCollection).CountDocuments(ctx, companySearch(search))
+    if err != nil {
+        return 0, err
+    }
+
+    return int(count), nil
+}
+
+// AllCompanies returns the whole registry for matching names against it
+func (r *MongoCompanyRepository) AllCompanies(ctx context.Context) ([]model.Company, error) {
+    defer observe(ctx, "CompanyRepository.AllCompanies")()
+
+    cursor, err := r.DB.Collection(companyCollection).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Company{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+// GetCompanyAlumni groups the active, approved pekerjaan linked to the company by
+// alumni, ordered by name and then start date
+func (r *MongoCompanyRepository) GetCompanyAlumni(ctx context.Context, id primitive.ObjectID) ([]model.CompanyAlumni, error) {
+    defer observe(ctx, "CompanyRepository.GetCompanyAlumni")()
+
+    pipeline := mongo.Pipeline{
+        {{Key: "$match", Value: bson.D{
+            {Key: "company_id", Value: id},
+            {Key: "is_delete", Value: bson.D{{Key: "$exists", Value: false}}},
+            {Key: "status_moderasi", Value: approvedModeration},
+        }}},
+        {{Key: "$sort", Value: bson.D{{Key: "tanggal_mulai_kerja", Value: 1}, {Key: "_id", Value: 1}}}},
+        {{Key: "$group", Value: bson.D{
+            {Key: "_id", Value: "$alumni_id"},
+            {Key: "pekerjaan", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}},
+        }}},
+        {{Key: "$lookup", Value: bson.D{
+            {Key: "from", Value: alumniCollection},
+            {Key: "localField", Value: "_id"},
+            {Key: "foreignField", Value: "_id"},
+            {Key: "as", Value: "alumni"},
+        }}},
+        {{Key: "$unwind", Value: "$alumni"}},
+        {{Key: "$sort", Value: bson.D{{Key: "alumni.nama", Value: 1}, {Key: "_id", Value: 1}}}},
+    }
+
+    cursor, err := r.DB.Collection(pekerjaanCollection).Aggregate(ctx, pipeline)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var rows []struct {
+        Alumni    model.Alumni      `bson:"alumni"`
+        Pekerjaan []model.Pekerjaan `bson:"pekerjaan"`
+    }
+    if err = cursor.All(ctx, &rows); err != nil {
+        return nil, err
+    }
+
+    list := make([]model.CompanyAlumni, len(rows))
+    for i, row := range rows {
+        list[i] = model.CompanyAlumni{Alumni: row.Alumni, Pekerjaan: row.Pekerjaan}
+    }
+    return list, nil
+}
+
+// UnlinkedPekerjaan returns every pekerjaan, trashed ones included, that is
+// not linked to a company
+func (r *MongoCompanyRepository) UnlinkedPekerjaan(ctx context.Context) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "CompanyRepository.UnlinkedPekerjaan")()
+
+    cursor, err := r.DB.Collection(pekerjaanCollection).Find(ctx, bson.M{"company_id": nil}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Pekerjaan{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+// LinkPekerjaan links the pekerjaan to the company and returns how many were
+// changed
+func (r *MongoCompanyRepository) LinkPekerjaan(ctx context.Context, companyID primitive.ObjectID, pekerjaanIDs []primitive.ObjectID) (int, error) {
+    defer observe(ctx, "CompanyRepository.LinkPekerjaan")()
+
+    if len(pekerjaanIDs) == 0 {
+        return 0, nil
+    }
+    result, err := r.DB.Collection(pekerjaanCollection).UpdateMany(ctx,
+        bson.M{"_id": bson.M{"$in": pekerjaanIDs}},
+        bson.M{"$set": bson.M{"company_id": companyID}})
+    if err != nil {
+        return 0, err
+    }
+
+    return int(result.ModifiedCount), nil
+}
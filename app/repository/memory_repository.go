@@ -0,0 +1,1316 @@
+package repository
+
+import (
+    "cmp"
+    "context"
+    "regexp"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryStore holds the documents of the in-memory repositories. The
+// repositories built from one store see each other's data, the same way the
+// Mongo repositories share one database.
+type MemoryStore struct {
+    mu        sync.RWMutex
+    alumni    map[primitive.ObjectID]model.Alumni
+    pekerjaan map[primitive.ObjectID]model.Pekerjaan
+    users     map[primitive.ObjectID]model.User
+    companies map[primitive.ObjectID]model.Company
+    reports   map[primitive.ObjectID]model.Report
+    files     map[reportFileKey]model.ReportContent
+}
+
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{
+        alumni:    map[primitive.ObjectID]model.Alumni{},
+        pekerjaan: map[primitive.ObjectID]model.Pekerjaan{},
+        users:     map[primitive.ObjectID]model.User{},
+        companies: map[primitive.ObjectID]model.Company{},
+        reports:   map[primitive.ObjectID]model.Report{},
+        files:     map[reportFileKey]model.ReportContent{},
+    }
+}
+
+// NewMemoryRepositories returns repositories backed by a fresh MemoryStore
+func NewMemoryRepositories() Repositories {
+    return NewMemoryStore().Repositories()
+}
+
+// Repositories returns the repositories backed by this store
+func (s *MemoryStore) Repositories() Repositories {
+    return Repositories{
+        Alumni:    &MemoryAlumniRepository{store: s},
+        Pekerjaan: &MemoryPekerjaanRepository{store: s},
+        User:      &MemoryUserRepository{store: s},
+        Company:   &MemoryCompanyRepository{store: s},
+        Stats:     &MemoryStatsRepository{store: s},
+        Report:    &MemoryReportRepository{store: s},
+    }
+}
+
+// AddUser inserts a user as-is. The HTTP API cannot create users, so tests and
+// fixtures use this instead.
+func (s *MemoryStore) AddUser(user model.User) model.User {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if user.ID.IsZero() {
+        user.ID = primitive.NewObjectID()
+    }
+    if user.CreatedAt.IsZero() {
+        user.CreatedAt = time.Now()
+    }
+    s.users[user.ID] = user
+    return user
+}
+
+// alumniIDForUser mirrors the Mongo ownership lookup: the first alumni whose
+// user_id matches
+func (s *MemoryStore) alumniIDForUser(userID primitive.ObjectID) (primitive.ObjectID, bool) {
+    var ids []primitive.ObjectID
+    for id, a := range s.alumni {
+        if a.UserID == userID {
+            ids = append(ids, id)
+        }
+    }
+    if len(ids) == 0 {
+        return primitive.NilObjectID, false
+    }
+    sortObjectIDs(ids)
+    return ids[0], true
+}
+
+// matcher compiles search the way the Mongo repositories use it, as a
+// case-insensitive $regex
+func matcher(search string) func(fields ...string) bool {
+    if search == "" {
+        return func(...string) bool { return true }
+    }
+
+    re, err := regexp.Compile("(?i)" + search)
+    return func(fields ...string) bool {
+        for _, field := range fields {
+            if err != nil {
+                if strings.Contains(strings.ToLower(field), strings.ToLower(search)) {
+                    return true
+                }
+            } else if re.MatchString(field) {
+                return true
+            }
+        }
+        return false
+    }
+}
+
+func sortObjectIDs(ids []primitive.ObjectID) {
+    sort.Slice(ids, func(i, j int) bool { return ids[i].Hex() < ids[j].Hex() })
+}
+
+// paginate applies offset and limit like SetSkip and SetLimit; a limit of 0
+// means no limit
+func paginate[T any](list []T, limit, offset int) []T {
+    if offset < 0 {
+        offset = 0
+    }
+    if offset >= len(list) {
+        return []T{}
+    }
+    list = list[offset:]
+    if limit > 0 && limit < len(list) {
+        list = list[:limit]
+    }
+    return list
+}
+
+// sortList orders list with compare, falling back to the ID so
+// ties stay deterministic
+func sortList[T any](list []T, compare func(a, b T) int, id func(T) primitive.ObjectID, desc bool) {
+    sort.SliceStable(list, func(i, j int) bool {
+        c := compare(list[i], list[j])
+        if c == 0 {
+            c = strings.Compare(id(list[i]).Hex(), id(list[j]).Hex())
+        }
+        if desc {
+            return c > 0
+        }
+        return c < 0
+    })
+}
+
+func compareInt(a, b int) int {
+    switch {
+    case a < b:
+        return -1
+    case a > b:
+        return 1
+    }
+    return 0
+}
+
+func compareTime(a, b time.Time) int {
+    switch {
+    case a.Before(b):
+        return -1
+    case a.After(b):
+        return 1
+    }
+    return 0
+}
+
+// MemoryAlumniRepository is an AlumniRepository kept in a MemoryStore
+type MemoryAlumniRepository struct {
+    store *MemoryStore
+}
+
+func (r *MemoryAlumniRepository) CreateAlumni(ctx context.Context, alumni model.Alumni) (*model.Alumni, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    alumni.ID = primitive.NewObjectID()
+    alumni.CreatedAt = time.Now()
+    alumni.UpdatedAt = time.Now()
+    r.store.alumni[alumni.ID] = alumni
+
+    return &alumni, nil
+}
+
+func (r *MemoryAlumniRepository) UpdateAlumni(ctx context.Context, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    alumni.UpdatedAt = time.Now()
+
+    // Like UpdateOne, a missing document is not an error
+    if existing, ok := r.store.alumni[id]; ok {
+        existing.NIM = alumni.NIM
+        existing.Nama = alumni.Nama
+        existing.Jurusan = alumni.Jurusan
+        existing.Angkatan = alumni.Angkatan
+        existing.TahunLulus = alumni.TahunLulus
+        existing.Email = alumni.Email
+        existing.NoTelepon = alumni.NoTelepon
+        existing.Alamat = alumni.Alamat
+        existing.UpdatedAt = alumni.UpdatedAt
+        r.store.alumni[id] = existing
+    }
+
+    alumni.ID = id
+    return &alumni, nil
+}
+
+func (r *MemoryAlumniRepository) DeleteAlumni(ctx context.Context, id primitive.ObjectID) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    delete(r.store.alumni, id)
+    return nil
+}
+
+func (r *MemoryAlumniRepository) FindAlumniByID(ctx context.Context, id primitive.ObjectID) (*model.Alumni, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    alumni, ok := r.store.alumni[id]
+    if !ok {
+        return nil, ErrAlumniNotFound
+    }
+    return &alumni, nil
+}
+
+func (r *MemoryAlumniRepository) FindAlumniByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Alumni, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    id, ok := r.store.alumniIDForUser(userID)
+    if !ok {
+        return nil, ErrAlumniNotFound
+    }
+    alumni := r.store.alumni[id]
+    return &alumni, nil
+}
+
+// BulkWriteAlumni runs the writes one at a time. Unlike CreateAlumni, which
+// always picks a new id so tests can copy records, creates keep their id.
+func (r *MemoryAlumniRepository) BulkWriteAlumni(ctx context.Context, writes []model.AlumniWrite, ordered bool) ([]error, error) {
+    return runBulk(len(writes), ordered, func(i int) error {
+        w := writes[i]
+        if w.Op != model.BulkCreate {
+            return writeAlumni(ctx, r, w)
+        }
+
+        r.store.mu.Lock()
+        defer r.store.mu.Unlock()
+        if _, ok := r.store.alumni[w.ID]; ok {
+            return errDuplicateID
+        }
+        w.Alumni.ID = w.ID
+        w.Alumni.CreatedAt = time.Now()
+        w.Alumni.UpdatedAt = w.Alumni.CreatedAt
+        r.store.alumni[w.ID] = w.Alumni
+        return nil
+    }), nil
+}
+
+func (r *MemoryAlumniRepository) filter(search string) []model.Alumni {
+    match := matcher(search)
+
+    list := []model.Alumni{}
+    for _, a := range r.store.alumni {
+        if match(a.Nama, a.NIM, a.Email) {
+            list = append(list, a)
+        }
+    }
+    return list
+}
+
+func (r *MemoryAlumniRepository) GetAlumni(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filter(search)
+
+    compare := map[string]func(a, b model.Alumni) int{
+        "nama":        func(a, b model.Alumni) int { return strings.Compare(a.Nama, b.Nama) },
+        "angkatan":    func(a, b model.Alumni) int { return compareInt(a.Angkatan, b.Angkatan) },
+        "tahun_lulus": func(a, b model.Alumni) int { return compareInt(a.TahunLulus, b.TahunLulus) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.Alumni) int { return 0 }
+    }
+    sortList(list, compare, func(a model.Alumni) primitive.ObjectID { return a.ID }, order == "desc")
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryAlumniRepository) CountAlumni(ctx context.Context, search string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filter(search)), nil
+}
+
+func (r *MemoryAlumniRepository) GetAlumniStatsByJurusan(ctx context.Context) ([]model.AlumniStatsByJurusanResponse, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    totals := map[string]int{}
+    for _, a := range r.store.alumni {
+        totals[a.Jurusan]++
+    }
+
+    stats := []model.AlumniStatsByJurusanResponse{}
+    for jurusan, total := range totals {
+        stats = append(stats, model.AlumniStatsByJurusanResponse{Jurusan: jurusan, Total: total})
+    }
+    sort.Slice(stats, func(i, j int) bool {
+        if stats[i].Total != stats[j].Total {
+            return stats[i].Total > stats[j].Total
+        }
+        return stats[i].Jurusan < stats[j].Jurusan
+    })
+
+    return stats, nil
+}
+
+// MemoryPekerjaanRepository is a PekerjaanRepository kept in a MemoryStore
+type MemoryPekerjaanRepository struct {
+    store *MemoryStore
+}
+
+func (r *MemoryPekerjaanRepository) CreatePekerjaan(ctx context.Context, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    p.ID = primitive.NewObjectID()
+    p.CreatedAt = time.Now()
+    p.UpdatedAt = time.Now()
+    r.store.pekerjaan[p.ID] = p
+
+    return &p, nil
+}
+
+func (r *MemoryPekerjaanRepository) UpdatePekerjaan(ctx context.Context, id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    p.UpdatedAt = time.Now()
+
+    existing, ok := r.store.pekerjaan[id]
+    if !ok {
+        return nil, ErrNotFoundOrNoAccess
+    }
+    existing.AlumniID = p.AlumniID
+    existing.NamaPerusahaan = p.NamaPerusahaan
+    existing.CompanyID = p.CompanyID
+    existing.PosisiJabatan = p.PosisiJabatan
+    existing.BidangIndustri = p.BidangIndustri
+    existing.LokasiKerja = p.LokasiKerja
+    existing.GajiMin = p.GajiMin
+    existing.GajiMax = p.GajiMax
+    existing.GajiCurrency = p.GajiCurrency
+    existing.GajiPeriod = p.GajiPeriod
+    existing.GajiRange = p.GajiRange
+    existing.GajiReview = p.GajiReview
+    existing.TanggalMulaiKerja = p.TanggalMulaiKerja
+    existing.TanggalSelesaiKerja = p.TanggalSelesaiKerja
+    existing.StatusPekerjaan = p.StatusPekerjaan
+    existing.DeskripsiPekerjaan = p.DeskripsiPekerjaan
+    existing.UpdatedAt = p.UpdatedAt
+    if p.StatusModerasi != "" {
+        existing.StatusModerasi = p.StatusModerasi
+        existing.CatatanModerasi = ""
+        existing.ModeratedBy = nil
+        existing.ModeratedAt = nil
+    }
+    r.store.pekerjaan[id] = existing
+
+    p.ID = id
+    return &p, nil
+}
+
+func (r *MemoryPekerjaanRepository) FindPekerjaanByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.AlumniID == alumniID && p.IsDelete == nil && p.Approved() {
+            list = append(list, p)
+        }
+    }
+    sortList(list, func(a, b model.Pekerjaan) int { return 0 }, pekerjaanID, false)
+
+    return list, nil
+}
+
+func (r *MemoryPekerjaanRepository) FindPekerjaanByID(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    p, ok := r.store.pekerjaan[id]
+    if !ok || p.IsDelete != nil {
+        return nil, ErrNotFoundOrNoAccess
+    }
+    return &p, nil
+}
+
+func (r *MemoryPekerjaanRepository) FindPekerjaanByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := []model.Pekerjaan{}
+    for _, id := range ids {
+        if p, ok := r.store.pekerjaan[id]; ok {
+            list = append(list, p)
+        }
+    }
+    return list, nil
+}
+
+// BulkWritePekerjaan is BulkWriteAlumni for pekerjaan
+func (r *MemoryPekerjaanRepository) BulkWritePekerjaan(ctx context.Context, writes []model.PekerjaanWrite, ordered bool) ([]error, error) {
+    return runBulk(len(writes), ordered, func(i int) error {
+        w := writes[i]
+        if w.Op != model.BulkCreate {
+            return writePekerjaan(ctx, r, w)
+        }
+
+        r.store.mu.Lock()
+        defer r.store.mu.Unlock()
+        if _, ok := r.store.pekerjaan[w.ID]; ok {
+            return errDuplicateID
+        }
+        w.Pekerjaan.ID = w.ID
+        w.Pekerjaan.CreatedAt = time.Now()
+        w.Pekerjaan.UpdatedAt = w.Pekerjaan.CreatedAt
+        r.store.pekerjaan[w.ID] = w.Pekerjaan
+        return nil
+    }), nil
+}
+
+func pekerjaanID(p model.Pekerjaan) primitive.ObjectID {
+    return p.ID
+}
+
+// matchGaji applies the salary part of the filter like the Mongo query
+func matchGaji(f model.PekerjaanFilter, p model.Pekerjaan) bool {
+    if !f.HasGaji() {
+        return true
+    }
+    switch {
+    case p.GajiMin == nil && p.GajiMax == nil:
+        return false
+    case f.GajiMin != nil && p.GajiMax != nil && *p.GajiMax < *f.GajiMin:
+        return false
+    case f.GajiMax != nil && p.GajiMin != nil && *p.GajiMin > *f.GajiMax:
+        return false
+    case f.GajiCurrency != "" && p.GajiCurrency != f.GajiCurrency:
+        return false
+    case f.GajiPeriod != "" && p.GajiPeriod != f.GajiPeriod:
+        return false
+    }
+    return true
+}
+
+func (r *MemoryPekerjaanRepository) filterActive(f model.PekerjaanFilter) []model.Pekerjaan {
+    match := matcher(f.Search)
+
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete == nil && p.Approved() && match(p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja) && matchGaji(f, p) {
+            list = append(list, p)
+        }
+    }
+    return list
+}
+
+func (r *MemoryPekerjaanRepository) GetPekerjaan(ctx context.Context, filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filterActive(filter)
+
+    compare := map[string]func(a, b model.Pekerjaan) int{
+        "nama_perusahaan":     func(a, b model.Pekerjaan) int { return strings.Compare(a.NamaPerusahaan, b.NamaPerusahaan) },
+        "posisi_jabatan":      func(a, b model.Pekerjaan) int { return strings.Compare(a.PosisiJabatan, b.PosisiJabatan) },
+        "tanggal_mulai_kerja": func(a, b model.Pekerjaan) int { return compareTime(a.TanggalMulaiKerja, b.TanggalMulaiKerja) },
+        "gaji_min":            func(a, b model.Pekerjaan) int { return compareBound(a.GajiMin, b.GajiMin) },
+        "gaji_max":            func(a, b model.Pekerjaan) int { return compareBound(a.GajiMax, b.GajiMax) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.Pekerjaan) int { return 0 }
+    }
+    sortList(list, compare, pekerjaanID, order == "desc")
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryPekerjaanRepository) CountPekerjaan(ctx context.Context, filter model.PekerjaanFilter) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filterActive(filter)), nil
+}
+
+// compareBound orders salary bounds with a missing bound first, as Mongo
+// sorts null before numbers
+func compareBound(a, b *int64) int {
+    switch {
+    case a == nil && b == nil:
+        return 0
+    case a == nil:
+        return -1
+    case b == nil:
+        return 1
+    }
+    return cmp.Compare(*a, *b)
+}
+
+func (r *MemoryPekerjaanRepository) CountPekerjaanByStatus(ctx context.Context, status string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    count := 0
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete == nil && p.Approved() && p.StatusPekerjaan == status {
+            count++
+        }
+    }
+    return count, nil
+}
+
+func (r *MemoryPekerjaanRepository) ExpireContracts(ctx context.Context, now time.Time) (int, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    count := 0
+    for id, p := range r.store.pekerjaan {
+        if model.ExpiredContract(p, now) {
+            p.StatusPekerjaan = model.StatusKontrakHabis
+            p.UpdatedAt = now
+            r.store.pekerjaan[id] = p
+            count++
+        }
+    }
+    return count, nil
+}
+
+// owned returns the pekerjaan id if it exists and the caller may touch it
+func (r *MemoryPekerjaanRepository) owned(id, userID primitive.ObjectID, isAdmin bool, deleted bool) (model.Pekerjaan, error) {
+    p, ok := r.store.pekerjaan[id]
+
+    if !isAdmin {
+        alumniID, found := r.store.alumniIDForUser(userID)
+        if !found {
+            return p, ErrAlumniNotFound
+        }
+        if ok && p.AlumniID != alumniID {
+            ok = false
+        }
+    }
+
+    if !ok || (deleted && p.IsDelete == nil) {
+        return p, ErrNotFoundOrNoAccess
+    }
+    return p, nil
+}
+
+func (r *MemoryPekerjaanRepository) SoftDelete(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    // Like the Mongo filter, an already deleted document is matched again and
+    // its timestamp refreshed
+    p, err := r.owned(id, userID, isAdmin, false)
+    if err != nil {
+        return err
+    }
+
+    now := time.Now()
+    p.IsDelete = &now
+    r.store.pekerjaan[id] = p
+    return nil
+}
+
+func (r *MemoryPekerjaanRepository) filterTrash(userID primitive.ObjectID, isAdmin bool, search string) []model.Pekerjaan {
+    match := matcher(search)
+
+    var alumniID primitive.ObjectID
+    if !isAdmin {
+        var found bool
+        if alumniID, found = r.store.alumniIDForUser(userID); !found {
+            return []model.Pekerjaan{}
+        }
+    }
+
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete == nil || (!isAdmin && p.AlumniID != alumniID) {
+            continue
+        }
+        if match(p.NamaPerusahaan, p.PosisiJabatan) {
+            list = append(list, p)
+        }
+    }
+    return list
+}
+
+func (r *MemoryPekerjaanRepository) GetTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filterTrash(userID, isAdmin, search)
+
+    compare := map[string]func(a, b model.Pekerjaan) int{
+        "_id":             func(a, b model.Pekerjaan) int { return 0 },
+        "nama_perusahaan": func(a, b model.Pekerjaan) int { return strings.Compare(a.NamaPerusahaan, b.NamaPerusahaan) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.Pekerjaan) int { return compareTime(*a.IsDelete, *b.IsDelete) }
+    }
+    sortList(list, compare, pekerjaanID, order != "asc")
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryPekerjaanRepository) CountTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filterTrash(userID, isAdmin, search)), nil
+}
+
+func (r *MemoryPekerjaanRepository) RestorePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    p, err := r.owned(id, userID, isAdmin, true)
+    if err != nil {
+        return err
+    }
+
+    p.IsDelete = nil
+    r.store.pekerjaan[id] = p
+    return nil
+}
+
+func (r *MemoryPekerjaanRepository) HardDeletePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if _, err := r.owned(id, userID, isAdmin, true); err != nil {
+        return err
+    }
+
+    delete(r.store.pekerjaan, id)
+    return nil
+}
+
+// trashIDs returns those of ids that are in the caller's trash
+func (r *MemoryPekerjaanRepository) trashIDs(ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) []primitive.ObjectID {
+    var found []primitive.ObjectID
+    for _, id := range ids {
+        if _, err := r.owned(id, userID, isAdmin, true); err == nil {
+            found = append(found, id)
+        }
+    }
+    return found
+}
+
+func (r *MemoryPekerjaanRepository) RestorePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    found := r.trashIDs(ids, userID, isAdmin)
+    for _, id := range found {
+        p := r.store.pekerjaan[id]
+        p.IsDelete = nil
+        r.store.pekerjaan[id] = p
+    }
+    return found, nil
+}
+
+func (r *MemoryPekerjaanRepository) HardDeletePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    found := r.trashIDs(ids, userID, isAdmin)
+    for _, id := range found {
+        delete(r.store.pekerjaan, id)
+    }
+    return found, nil
+}
+
+func (r *MemoryPekerjaanRepository) expiredTrash(before time.Time) []model.Pekerjaan {
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete != nil && p.IsDelete.Before(before) {
+            list = append(list, p)
+        }
+    }
+    return list
+}
+
+func (r *MemoryPekerjaanRepository) GetExpiredTrash(ctx context.Context, before time.Time, limit int) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.expiredTrash(before)
+    sortList(list, func(a, b model.Pekerjaan) int { return compareTime(*a.IsDelete, *b.IsDelete) }, pekerjaanID, false)
+
+    return paginate(list, limit, 0), nil
+}
+
+func (r *MemoryPekerjaanRepository) CountExpiredTrash(ctx context.Context, before time.Time) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.expiredTrash(before)), nil
+}
+
+func (r *MemoryPekerjaanRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    expired := r.expiredTrash(before)
+    for _, p := range expired {
+        delete(r.store.pekerjaan, p.ID)
+    }
+    return len(expired), nil
+}
+
+func (r *MemoryPekerjaanRepository) filterModeration(userID primitive.ObjectID, isAdmin bool, status, search string) []model.Pekerjaan {
+    match := matcher(search)
+
+    var alumniID primitive.ObjectID
+    if !isAdmin {
+        var found bool
+        if alumniID, found = r.store.alumniIDForUser(userID); !found {
+            return []model.Pekerjaan{}
+        }
+    }
+
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete != nil || p.StatusModerasi != status || (!isAdmin && p.AlumniID != alumniID) {
+            continue
+        }
+        if match(p.NamaPerusahaan, p.PosisiJabatan) {
+            list = append(list, p)
+        }
+    }
+    return list
+}
+
+func (r *MemoryPekerjaanRepository) GetModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filterModeration(userID, isAdmin, status, search)
+
+    compare := map[string]func(a, b model.Pekerjaan) int{
+        "_id":             func(a, b model.Pekerjaan) int { return 0 },
+        "nama_perusahaan": func(a, b model.Pekerjaan) int { return strings.Compare(a.NamaPerusahaan, b.NamaPerusahaan) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.Pekerjaan) int { return compareTime(a.UpdatedAt, b.UpdatedAt) }
+    }
+    sortList(list, compare, pekerjaanID, order == "desc")
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryPekerjaanRepository) CountModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filterModeration(userID, isAdmin, status, search)), nil
+}
+
+func (r *MemoryPekerjaanRepository) ModeratePekerjaan(ctx context.Context, id primitive.ObjectID, status, catatan string, moderatorID primitive.ObjectID) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    p, ok := r.store.pekerjaan[id]
+    if !ok || p.IsDelete != nil || p.StatusModerasi != model.ModerasiPending {
+        return ErrNotFoundOrNoAccess
+    }
+
+    now := time.Now()
+    p.StatusModerasi = status
+    p.CatatanModerasi = catatan
+    p.ModeratedBy = &moderatorID
+    p.ModeratedAt = &now
+    p.UpdatedAt = now
+    r.store.pekerjaan[id] = p
+    return nil
+}
+
+// MemoryUserRepository is a UserRepository kept in a MemoryStore
+type MemoryUserRepository struct {
+    store *MemoryStore
+}
+
+func (r *MemoryUserRepository) FindUserByUsernameOrEmail(ctx context.Context, identifier string) (*model.User, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    ids := make([]primitive.ObjectID, 0, len(r.store.users))
+    for id := range r.store.users {
+        ids = append(ids, id)
+    }
+    sortObjectIDs(ids)
+
+    for _, id := range ids {
+        user := r.store.users[id]
+        if user.Username == identifier || user.Email == identifier {
+            return &user, nil
+        }
+    }
+    return nil, ErrUserNotFound
+}
+
+func (r *MemoryUserRepository) filter(search string) []model.User {
+    match := matcher(search)
+
+    list := []model.User{}
+    for _, u := range r.store.users {
+        if match(u.Username, u.Email) {
+            list = append(list, u)
+        }
+    }
+    return list
+}
+
+func (r *MemoryUserRepository) GetUsers(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.User, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filter(search)
+
+    compare := map[string]func(a, b model.User) int{
+        "username":   func(a, b model.User) int { return strings.Compare(a.Username, b.Username) },
+        "email":      func(a, b model.User) int { return strings.Compare(a.Email, b.Email) },
+        "role":       func(a, b model.User) int { return strings.Compare(a.Role, b.Role) },
+        "created_at": func(a, b model.User) int { return compareTime(a.CreatedAt, b.CreatedAt) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.User) int { return 0 }
+    }
+    desc := order == "DESC" || order == "desc"
+    sortList(list, compare, func(u model.User) primitive.ObjectID { return u.ID }, desc)
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryUserRepository) CountUsers(ctx context.Context, search string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filter(search)), nil
+}
+
+func (r *MemoryUserRepository) CreateUser(ctx context.Context, user model.User) (*model.User, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    // Mirrors the unique idx_username and idx_email indexes
+    for _, u := range r.store.users {
+        if u.Username == user.Username || u.Email == user.Email {
+            return nil, ErrUserExists
+        }
+    }
+
+    user.ID = primitive.NewObjectID()
+    user.CreatedAt = time.Now()
+    r.store.users[user.ID] = user
+    return &user, nil
+}
+
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    user, ok := r.store.users[id]
+    if !ok {
+        return ErrUserNotFound
+    }
+
+    user.PasswordHash = passwordHash
+    r.store.users[id] = user
+    return nil
+}
+
+// MemoryCompanyRepository is a CompanyRepository kept in a MemoryStore
+type MemoryCompanyRepository struct {
+    store *MemoryStore
+}
+
+// nameTaken mirrors the unique idx_company_nama_key index
+func (r *MemoryCompanyRepository) nameTaken(id primitive.ObjectID, key string) bool {
+    for _, c := range r.store.companies {
+        if c.ID != id && c.NamaKey == key {
+            return true
+        }
+    }
+    return false
+}
+
+func (r *MemoryCompanyRepository) CreateCompany(ctx context.Context, company model.Company) (*model.Company, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if r.nameTaken(primitive.NilObjectID, company.NamaKey) {
+        return nil, ErrCompanyExists
+    }
+
+    company.ID = primitive.NewObjectID()
+    company.CreatedAt = time.Now()
+    company.UpdatedAt = time.Now()
+    r.store.companies[company.ID] = company
+    return &company, nil
+}
+
+func (r *MemoryCompanyRepository) UpdateCompany(ctx context.Context, id primitive.ObjectID, company model.Company) (*model.Company, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    existing, ok := r.store.companies[id]
+    if !ok {
+        return nil, ErrCompanyNotFound
+    }
+    if r.nameTaken(id, company.NamaKey) {
+        return nil, ErrCompanyExists
+    }
+
+    existing.Nama = company.Nama
+    existing.NamaKey = company.NamaKey
+    existing.Aliases = company.Aliases
+    existing.AliasKeys = company.AliasKeys
+    existing.BidangIndustri = company.BidangIndustri
+    existing.Lokasi = company.Lokasi
+    existing.UpdatedAt = time.Now()
+    r.store.companies[id] = existing
+    return &existing, nil
+}
+
+func (r *MemoryCompanyRepository) DeleteCompany(ctx context.Context, id primitive.ObjectID) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    if _, ok := r.store.companies[id]; !ok {
+        return ErrCompanyNotFound
+    }
+    delete(r.store.companies, id)
+
+    for pid, p := range r.store.pekerjaan {
+        if p.CompanyID != nil && *p.CompanyID == id {
+            p.CompanyID = nil
+            r.store.pekerjaan[pid] = p
+        }
+    }
+    return nil
+}
+
+func (r *MemoryCompanyRepository) FindCompanyByID(ctx context.Context, id primitive.ObjectID) (*model.Company, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    company, ok := r.store.companies[id]
+    if !ok {
+        return nil, ErrCompanyNotFound
+    }
+    return &company, nil
+}
+
+func (r *MemoryCompanyRepository) filter(search string) []model.Company {
+    match := matcher(search)
+
+    list := []model.Company{}
+    for _, c := range r.store.companies {
+        if match(append([]string{c.Nama, c.BidangIndustri, c.Lokasi}, c.Aliases...)...) {
+            list = append(list, c)
+        }
+    }
+    return list
+}
+
+func companyID(c model.Company) primitive.ObjectID {
+    return c.ID
+}
+
+func (r *MemoryCompanyRepository) GetCompanies(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Company, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filter(search)
+
+    compare := map[string]func(a, b model.Company) int{
+        "nama":            func(a, b model.Company) int { return strings.Compare(a.Nama, b.Nama) },
+        "bidang_industri": func(a, b model.Company) int { return strings.Compare(a.BidangIndustri, b.BidangIndustri) },
+        "lokasi":          func(a, b model.Company) int { return strings.Compare(a.Lokasi, b.Lokasi) },
+    }[sortBy]
+    if compare == nil {
+        compare = func(a, b model.Company) int { return 0 }
+    }
+    sortList(list, compare, companyID, order == "desc")
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryCompanyRepository) CountCompanies(ctx context.Context, search string) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.filter(search)), nil
+}
+
+func (r *MemoryCompanyRepository) AllCompanies(ctx context.Context) ([]model.Company, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := r.filter("")
+    sortList(list, func(a, b model.Company) int { return 0 }, companyID, false)
+    return list, nil
+}
+
+func (r *MemoryCompanyRepository) GetCompanyAlumni(ctx context.Context, id primitive.ObjectID) ([]model.CompanyAlumni, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    byAlumni := map[primitive.ObjectID][]model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete == nil && p.Approved() && p.CompanyID != nil && *p.CompanyID == id {
+            byAlumni[p.AlumniID] = append(byAlumni[p.AlumniID], p)
+        }
+    }
+
+    list := []model.CompanyAlumni{}
+    for alumniID, pekerjaan := range byAlumni {
+        // Like the $lookup and $unwind, pekerjaan of a missing alumni are dropped
+        a, ok := r.store.alumni[alumniID]
+        if !ok {
+            continue
+        }
+        sortList(pekerjaan, func(a, b model.Pekerjaan) int {
+            return compareTime(a.TanggalMulaiKerja, b.TanggalMulaiKerja)
+        }, pekerjaanID, false)
+        list = append(list, model.CompanyAlumni{Alumni: a, Pekerjaan: pekerjaan})
+    }
+    sortList(list, func(a, b model.CompanyAlumni) int {
+        return strings.Compare(a.Alumni.Nama, b.Alumni.Nama)
+    }, func(c model.CompanyAlumni) primitive.ObjectID { return c.Alumni.ID }, false)
+
+    return list, nil
+}
+
+func (r *MemoryCompanyRepository) UnlinkedPekerjaan(ctx context.Context) ([]model.Pekerjaan, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := []model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.CompanyID == nil {
+            list = append(list, p)
+        }
+    }
+    sortList(list, func(a, b model.Pekerjaan) int { return 0 }, pekerjaanID, false)
+    return list, nil
+}
+
+func (r *MemoryCompanyRepository) LinkPekerjaan(ctx context.Context, companyID primitive.ObjectID, pekerjaanIDs []primitive.ObjectID) (int, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    linked := 0
+    for _, id := range pekerjaanIDs {
+        p, ok := r.store.pekerjaan[id]
+        if !ok || (p.CompanyID != nil && *p.CompanyID == companyID) {
+            continue
+        }
+        p.CompanyID = &companyID
+        r.store.pekerjaan[id] = p
+        linked++
+    }
+    return linked, nil
+}
+
+// MemoryStatsRepository is a StatsRepository over a MemoryStore
+type MemoryStatsRepository struct {
+    store *MemoryStore
+}
+
+// inCohort applies a CohortFilter like cohortMatch
+func inCohort(f model.CohortFilter, a model.Alumni) bool {
+    return (f.Jurusan == "" || a.Jurusan == f.Jurusan) && (f.Angkatan == 0 || a.Angkatan == f.Angkatan) &&
+        (f.TahunLulusFrom == 0 || a.TahunLulus >= f.TahunLulusFrom) && (f.TahunLulusTo == 0 || a.TahunLulus <= f.TahunLulusTo)
+}
+
+func (r *MemoryStatsRepository) GetCohorts(ctx context.Context, f model.CohortFilter) ([]model.CohortAggregate, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    byAlumni := map[primitive.ObjectID][]model.Pekerjaan{}
+    for _, p := range r.store.pekerjaan {
+        if p.IsDelete == nil && p.Approved() {
+            byAlumni[p.AlumniID] = append(byAlumni[p.AlumniID], p)
+        }
+    }
+
+    byCohort := map[cohortKey]*model.CohortAggregate{}
+    industries := map[cohortKey][][]string{}
+    for _, a := range r.store.alumni {
+        if !inCohort(f, a) {
+            continue
+        }
+
+        key := cohortKey{a.Jurusan, a.TahunLulus}
+        c, ok := byCohort[key]
+        if !ok {
+            c = &model.CohortAggregate{Jurusan: a.Jurusan, TahunLulus: a.TahunLulus, WaitingMonths: []int{}}
+            byCohort[key] = c
+        }
+        c.Alumni++
+
+        jobs := byAlumni[a.ID]
+        if len(jobs) == 0 {
+            continue
+        }
+        c.Employed++
+        c.Pekerjaan += len(jobs)
+        if len(jobs) > 1 {
+            c.Changed++
+        }
+        first, aktif := jobs[0].TanggalMulaiKerja, false
+        var bidang []string
+        for _, p := range jobs {
+            if p.TanggalMulaiKerja.Before(first) {
+                first = p.TanggalMulaiKerja
+            }
+            aktif = aktif || p.StatusPekerjaan == model.StatusAktif
+            bidang = append(bidang, p.BidangIndustri)
+        }
+        industries[key] = append(industries[key], bidang)
+        if aktif {
+            c.Aktif++
+        }
+        c.WaitingMonths = append(c.WaitingMonths, model.WaitingMonths(a.TahunLulus, first))
+    }
+
+    cohorts := []model.CohortAggregate{}
+    for key, c := range byCohort {
+        c.Industries = model.CountIndustries(industries[key])
+        cohorts = append(cohorts, *c)
+    }
+    sort.Slice(cohorts, func(i, j int) bool {
+        if cohorts[i].Jurusan != cohorts[j].Jurusan {
+            return cohorts[i].Jurusan < cohorts[j].Jurusan
+        }
+        return cohorts[i].TahunLulus < cohorts[j].TahunLulus
+    })
+
+    return cohorts, nil
+}
+
+func (r *MemoryStatsRepository) GetPekerjaanFacts(ctx context.Context, f model.CohortFilter) ([]model.PekerjaanFact, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    facts := []model.PekerjaanFact{}
+    for _, p := range r.store.pekerjaan {
+        a, ok := r.store.alumni[p.AlumniID]
+        if !ok || !inCohort(f, a) || p.IsDelete != nil || !p.Approved() {
+            continue
+        }
+        facts = append(facts, model.PekerjaanFact{
+            ID:                  p.ID,
+            AlumniID:            a.ID,
+            Jurusan:             a.Jurusan,
+            Angkatan:            a.Angkatan,
+            TahunLulus:          a.TahunLulus,
+            PosisiJabatan:       p.PosisiJabatan,
+            BidangIndustri:      p.BidangIndustri,
+            LokasiKerja:         p.LokasiKerja,
+            GajiMin:             p.GajiMin,
+            GajiMax:             p.GajiMax,
+            GajiCurrency:        p.GajiCurrency,
+            GajiPeriod:          p.GajiPeriod,
+            TanggalMulaiKerja:   p.TanggalMulaiKerja,
+            TanggalSelesaiKerja: p.TanggalSelesaiKerja,
+            StatusPekerjaan:     p.StatusPekerjaan,
+        })
+    }
+    sort.Slice(facts, func(i, j int) bool {
+        if c := strings.Compare(facts[i].AlumniID.Hex(), facts[j].AlumniID.Hex()); c != 0 {
+            return c < 0
+        }
+        if c := compareTime(facts[i].TanggalMulaiKerja, facts[j].TanggalMulaiKerja); c != 0 {
+            return c < 0
+        }
+        return facts[i].ID.Hex() < facts[j].ID.Hex()
+    })
+
+    return facts, nil
+}
+
+// MemoryReportRepository is a ReportRepository kept in a MemoryStore
+type MemoryReportRepository struct {
+    store *MemoryStore
+}
+
+type reportFileKey struct {
+    reportID primitive.ObjectID
+    format   string
+}
+
+// cloneReport copies the slices of report, which the caller may reuse
+func cloneReport(report model.Report) model.Report {
+    report.Formats = append([]string(nil), report.Formats...)
+    report.Files = append([]model.ReportFile{}, report.Files...)
+    return report
+}
+
+func (r *MemoryReportRepository) CreateReport(ctx context.Context, report model.Report) (*model.Report, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    report = cloneReport(report)
+    report.ID = primitive.NewObjectID()
+    r.store.reports[report.ID] = report
+    return &report, nil
+}
+
+func (r *MemoryReportRepository) UpdateReport(ctx context.Context, report model.Report) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    stored, ok := r.store.reports[report.ID]
+    if !ok {
+        return ErrReportNotFound
+    }
+    report = cloneReport(report)
+    stored.Status, stored.Error, stored.Files = report.Status, report.Error, report.Files
+    if report.StartedAt != nil {
+        stored.StartedAt = report.StartedAt
+    }
+    if report.FinishedAt != nil {
+        stored.FinishedAt = report.FinishedAt
+    }
+    r.store.reports[report.ID] = stored
+    return nil
+}
+
+func (r *MemoryReportRepository) FindReportByID(ctx context.Context, id primitive.ObjectID) (*model.Report, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    report, ok := r.store.reports[id]
+    if !ok {
+        return nil, ErrReportNotFound
+    }
+    report = cloneReport(report)
+    return &report, nil
+}
+
+func (r *MemoryReportRepository) GetReports(ctx context.Context, limit, offset int) ([]model.Report, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    list := make([]model.Report, 0, len(r.store.reports))
+    for _, report := range r.store.reports {
+        list = append(list, cloneReport(report))
+    }
+    sortList(list, func(a, b model.Report) int { return compareTime(a.CreatedAt, b.CreatedAt) }, func(r model.Report) primitive.ObjectID { return r.ID }, true)
+
+    return paginate(list, limit, offset), nil
+}
+
+func (r *MemoryReportRepository) CountReports(ctx context.Context) (int, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    return len(r.store.reports), nil
+}
+
+func (r *MemoryReportRepository) FailStaleReports(ctx context.Context, before time.Time, message string) (int, error) {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    n, now := 0, time.Now()
+    for id, report := range r.store.reports {
+        if !report.Finished() && report.CreatedAt.Before(before) {
+            report.Status, report.Error, report.FinishedAt = model.ReportFailed, message, &now
+            r.store.reports[id] = report
+            n++
+        }
+    }
+    return n, nil
+}
+
+func (r *MemoryReportRepository) SaveReportFile(ctx context.Context, file model.ReportContent) error {
+    r.store.mu.Lock()
+    defer r.store.mu.Unlock()
+
+    file.Data = append([]byte(nil), file.Data...)
+    file.CreatedAt = time.Now()
+    r.store.files[reportFileKey{file.ReportID, file.Format}] = file
+    return nil
+}
+
+func (r *MemoryReportRepository) GetReportFile(ctx context.Context, id primitive.ObjectID, format string) (*model.ReportContent, error) {
+    r.store.mu.RLock()
+    defer r.store.mu.RUnlock()
+
+    file, ok := r.store.files[reportFileKey{id, format}]
+    if !ok {
+        return nil, ErrReportNotFound
+    }
+    return &file, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+    "context"
+    "log/slog"
+    "time"
+
+    "go-fiber/metrics"
+)
+
+// SlowQueryThreshold is how long a repository call may take before it is
+// logged as slow. Zero disables slow query logging.
+var SlowQueryThreshold = 500 * time.Millisecond
+
+// observe times a repository call; use as defer observe(ctx, name)(). Every
+// call is recorded in metrics.DBDuration; slow ones are also logged, with the
+// request fields of ctx.
+func observe(ctx context.Context, name string) func() {
+    start := time.Now()
+
+    return func() {
+        elapsed := time.Since(start)
+        metrics.DBDuration.WithLabelValues(name).Observe(elapsed.Seconds())
+
+        if SlowQueryThreshold <= 0 || elapsed < SlowQueryThreshold {
+            return
+        }
+
+        args := []interface{}{"query", name, "duration", elapsed}
+        if err := ctx.Err(); err != nil {
+            args = append(args, "error", err.Error())
+        }
+        slog.WarnContext(ctx, "slow query", args...)
+    }
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
     "context"
-    "errors"
     "time"
     
     "go-fiber/app/model"
@@ -15,17 +14,20 @@ import (
 
 const pekerjaanCollection = "pekerjaan_alumni"
 
-type PekerjaanRepository struct {
+// approvedModeration matches approved pekerjaan and those written before
+// status_moderasi existed
+var approvedModeration = bson.M{"$nin": []string{model.ModerasiPending, model.ModerasiRejected}}
+
+type MongoPekerjaanRepository struct {
     DB *mongo.Database
 }
 
-func NewPekerjaanRepository(db *mongo.Database) *PekerjaanRepository {
-    return &PekerjaanRepository{DB: db}
+func NewMongoPekerjaanRepository(db *mongo.Database) *MongoPekerjaanRepository {
+    return &MongoPekerjaanRepository{DB: db}
 }
 
-func (r *PekerjaanRepository) CreatePekerjaan(p model.Pekerjaan) (*model.Pekerjaan, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) CreatePekerjaan(ctx context.Context, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.CreatePekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
@@ -41,14 +43,28 @@ func (r *PekerjaanRepository) CreatePekerjaan(p model.Pekerjaan) (*model.Pekerja
     return &p, nil
 }
 
-func (r *PekerjaanRepository) UpdatePekerjaan(id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) UpdatePekerjaan(ctx context.Context, id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.UpdatePekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
     p.UpdatedAt = time.Now()
     
+    filter := bson.M{"_id": id}
+    result, err := collection.UpdateOne(ctx, filter, pekerjaanUpdate(p))
+    if err != nil {
+        return nil, err
+    }
+    if result.MatchedCount == 0 {
+        return nil, ErrNotFoundOrNoAccess
+    }
+    
+    p.ID = id
+    return &p, nil
+}
+
+// pekerjaanUpdate is the update document of UpdatePekerjaan
+func pekerjaanUpdate(p model.Pekerjaan) bson.M {
     update := bson.M{
         "$set": bson.M{
             "alumni_id":             p.AlumniID,
@@ -56,7 +72,9 @@ func (r *PekerjaanRepository) UpdatePekerjaan(id primitive.ObjectID, p model.Pek
             "posisi_jabatan":        p.PosisiJabatan,
             "bidang_industri":       p.BidangIndustri,
             "lokasi_kerja":          p.LokasiKerja,
-            "gaji_range":            p.GajiRange,
+            "gaji_min":              p.GajiMin,
+            "gaji_max":              p.GajiMax,
+            "gaji_review":           p.GajiReview,
             "tanggal_mulai_kerja":   p.TanggalMulaiKerja,
             "tanggal_selesai_kerja": p.TanggalSelesaiKerja,
             "status_pekerjaan":      p.StatusPekerjaan,
@@ -65,25 +83,42 @@ func (r *PekerjaanRepository) UpdatePekerjaan(id primitive.ObjectID, p model.Pek
         },
     }
     
-    filter := bson.M{"_id": id}
-    _, err := collection.UpdateOne(ctx, filter, update)
-    if err != nil {
-        return nil, err
+    // Empty salary text fields and a missing company are removed, the
+    // validator only accepts set ones
+    unset := bson.M{}
+    for field, value := range map[string]string{"gaji_currency": p.GajiCurrency, "gaji_period": p.GajiPeriod, "gaji_range": p.GajiRange} {
+        if value == "" {
+            unset[field] = ""
+        } else {
+            update["$set"].(bson.M)[field] = value
+        }
     }
-    
-    p.ID = id
-    return &p, nil
+    if p.CompanyID != nil {
+        update["$set"].(bson.M)["company_id"] = p.CompanyID
+    } else {
+        unset["company_id"] = ""
+    }
+    if p.StatusModerasi != "" {
+        update["$set"].(bson.M)["status_moderasi"] = p.StatusModerasi
+        unset["catatan_moderasi"] = ""
+        unset["moderated_by"] = ""
+        unset["moderated_at"] = ""
+    }
+    if len(unset) > 0 {
+        update["$unset"] = unset
+    }
+    return update
 }
 
-func (r *PekerjaanRepository) FindPekerjaanByAlumniID(alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) FindPekerjaanByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByAlumniID")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
     filter := bson.M{
-        "alumni_id": alumniID,
-        "is_delete": bson.M{"$exists": false},
+        "alumni_id":       alumniID,
+        "is_delete":       bson.M{"$exists": false},
+        "status_moderasi": approvedModeration,
     }
     
     cursor, err := collection.Find(ctx, filter)
@@ -100,22 +135,95 @@ func (r *PekerjaanRepository) FindPekerjaanByAlumniID(alumniID primitive.ObjectI
     return list, nil
 }
 
-func (r *PekerjaanRepository) GetPekerjaan(search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// FindPekerjaanByID returns the pekerjaan outside the trash, whatever its
+// status_moderasi
+func (r *MongoPekerjaanRepository) FindPekerjaanByID(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByID")()
+
+    var p model.Pekerjaan
+    err := r.DB.Collection(pekerjaanCollection).FindOne(ctx, bson.M{
+        "_id":       id,
+        "is_delete": bson.M{"$exists": false},
+    }).Decode(&p)
+    if err == mongo.ErrNoDocuments {
+        return nil, ErrNotFoundOrNoAccess
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &p, nil
+}
+
+// FindPekerjaanByIDs returns the pekerjaan of ids found in any state,
+// including the trash
+func (r *MongoPekerjaanRepository) FindPekerjaanByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByIDs")()
+
+    cursor, err := r.DB.Collection(pekerjaanCollection).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var list []model.Pekerjaan
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+// activePekerjaanFilter builds the query of the active listing: search as a
+// case-insensitive $regex and the salary bounds as an overlap with
+// gaji_min..gaji_max. An open end of a stored range matches any bound.
+func activePekerjaanFilter(f model.PekerjaanFilter) bson.M {
+    filter := bson.M{"is_delete": bson.M{"$exists": false}, "status_moderasi": approvedModeration}
+    and := []bson.M{}
+    if f.Search != "" {
+        and = append(and, bson.M{"$or": []bson.M{
+            {"nama_perusahaan": bson.M{"$regex": f.Search, "$options": "i"}},
+            {"posisi_jabatan": bson.M{"$regex": f.Search, "$options": "i"}},
+            {"bidang_industri": bson.M{"$regex": f.Search, "$options": "i"}},
+            {"lokasi_kerja": bson.M{"$regex": f.Search, "$options": "i"}},
+        }})
+    }
+    if f.HasGaji() {
+        and = append(and, bson.M{"$or": []bson.M{
+            {"gaji_min": bson.M{"$type": "number"}},
+            {"gaji_max": bson.M{"$type": "number"}},
+        }})
+    }
+    if f.GajiMin != nil {
+        and = append(and, bson.M{"$or": []bson.M{
+            {"gaji_max": bson.M{"$gte": *f.GajiMin}},
+            {"gaji_max": nil},
+        }})
+    }
+    if f.GajiMax != nil {
+        and = append(and, bson.M{"$or": []bson.M{
+            {"gaji_min": bson.M{"$lte": *f.GajiMax}},
+            {"gaji_min": nil},
+        }})
+    }
+    if f.GajiCurrency != "" {
+        filter["gaji_currency"] = f.GajiCurrency
+    }
+    if f.GajiPeriod != "" {
+        filter["gaji_period"] = f.GajiPeriod
+    }
+    if len(and) > 0 {
+        filter["$and"] = and
+    }
+    return filter
+}
+
+func (r *MongoPekerjaanRepository) GetPekerjaan(ctx context.Context, f model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetPekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
-    // Build filter
-    filter := bson.M{"is_delete": bson.M{"$exists": false}}
-    if search != "" {
-        filter["$or"] = []bson.M{
-            {"nama_perusahaan": bson.M{"$regex": search, "$options": "i"}},
-            {"posisi_jabatan": bson.M{"$regex": search, "$options": "i"}},
-            {"bidang_industri": bson.M{"$regex": search, "$options": "i"}},
-            {"lokasi_kerja": bson.M{"$regex": search, "$options": "i"}},
-        }
-    }
+    filter := activePekerjaanFilter(f)
     
     // Set sort
     sortOrder := 1
@@ -123,7 +231,7 @@ func (r *PekerjaanRepository) GetPekerjaan(search, sortBy, order string, limit,
         sortOrder = -1
     }
     
-    allowedSort := map[string]bool{"_id": true, "nama_perusahaan": true, "posisi_jabatan": true, "tanggal_mulai_kerja": true}
+    allowedSort := map[string]bool{"_id": true, "nama_perusahaan": true, "posisi_jabatan": true, "tanggal_mulai_kerja": true, "gaji_min": true, "gaji_max": true}
     if !allowedSort[sortBy] {
         sortBy = "_id"
     }
@@ -147,21 +255,12 @@ func (r *PekerjaanRepository) GetPekerjaan(search, sortBy, order string, limit,
     return list, nil
 }
 
-func (r *PekerjaanRepository) CountPekerjaan(search string) (int, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) CountPekerjaan(ctx context.Context, f model.PekerjaanFilter) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountPekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
-    filter := bson.M{"is_delete": bson.M{"$exists": false}}
-    if search != "" {
-        filter["$or"] = []bson.M{
-            {"nama_perusahaan": bson.M{"$regex": search, "$options": "i"}},
-            {"posisi_jabatan": bson.M{"$regex": search, "$options": "i"}},
-            {"bidang_industri": bson.M{"$regex": search, "$options": "i"}},
-            {"lokasi_kerja": bson.M{"$regex": search, "$options": "i"}},
-        }
-    }
+    filter := activePekerjaanFilter(f)
     
     count, err := collection.CountDocuments(ctx, filter)
     if err != nil {
@@ -171,9 +270,45 @@ func (r *PekerjaanRepository) CountPekerjaan(search string) (int, error) {
     return int(count), nil
 }
 
-func (r *PekerjaanRepository) SoftDelete(id primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// CountPekerjaanByStatus counts the approved pekerjaan not in the trash with
+// the given status_pekerjaan
+func (r *MongoPekerjaanRepository) CountPekerjaanByStatus(ctx context.Context, status string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountPekerjaanByStatus")()
+
+    count, err := r.DB.Collection(pekerjaanCollection).CountDocuments(ctx, bson.M{
+        "is_delete":        bson.M{"$exists": false},
+        "status_pekerjaan": status,
+        "status_moderasi":  approvedModeration,
+    })
+    if err != nil {
+        return 0, err
+    }
+
+    return int(count), nil
+}
+
+// ExpireContracts moves the aktif pekerjaan whose tanggal_selesai_kerja lies
+// before now to kontrak_habis and returns how many changed
+func (r *MongoPekerjaanRepository) ExpireContracts(ctx context.Context, now time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.ExpireContracts")()
+
+    result, err := r.DB.Collection(pekerjaanCollection).UpdateMany(ctx, bson.M{
+        "is_delete":             bson.M{"$exists": false},
+        "status_pekerjaan":      model.StatusAktif,
+        "tanggal_selesai_kerja": bson.M{"$lt": now},
+    }, bson.M{"$set": bson.M{
+        "status_pekerjaan": model.StatusKontrakHabis,
+        "updated_at":       now,
+    }})
+    if err != nil {
+        return 0, err
+    }
+
+    return int(result.ModifiedCount), nil
+}
+
+func (r *MongoPekerjaanRepository) SoftDelete(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.SoftDelete")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     now := time.Now()
@@ -190,7 +325,7 @@ func (r *PekerjaanRepository) SoftDelete(id primitive.ObjectID, userID primitive
         
         err := alumniCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&alumni)
         if err != nil {
-            return errors.New("alumni tidak ditemukan")
+            return ErrAlumniNotFound
         }
         
         filter = bson.M{
@@ -206,15 +341,14 @@ func (r *PekerjaanRepository) SoftDelete(id primitive.ObjectID, userID primitive
     }
     
     if result.MatchedCount == 0 {
-        return errors.New("data tidak ditemukan atau tidak memiliki akses")
+        return ErrNotFoundOrNoAccess
     }
     
     return nil
 }
 
-func (r *PekerjaanRepository) GetTrashPekerjaan(userID primitive.ObjectID, isAdmin bool, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) GetTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetTrashPekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
@@ -273,9 +407,8 @@ func (r *PekerjaanRepository) GetTrashPekerjaan(userID primitive.ObjectID, isAdm
     return list, nil
 }
 
-func (r *PekerjaanRepository) CountTrashPekerjaan(userID primitive.ObjectID, isAdmin bool, search string) (int, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) CountTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountTrashPekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
@@ -310,9 +443,8 @@ func (r *PekerjaanRepository) CountTrashPekerjaan(userID primitive.ObjectID, isA
     return int(count), nil
 }
 
-func (r *PekerjaanRepository) RestorePekerjaan(id, userID primitive.ObjectID, isAdmin bool) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) RestorePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.RestorePekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
@@ -330,7 +462,7 @@ func (r *PekerjaanRepository) RestorePekerjaan(id, userID primitive.ObjectID, is
         
         err := alumniCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&alumni)
         if err != nil {
-            return errors.New("alumni tidak ditemukan")
+            return ErrAlumniNotFound
         }
         
         filter = bson.M{
@@ -347,15 +479,14 @@ func (r *PekerjaanRepository) RestorePekerjaan(id, userID primitive.ObjectID, is
     }
     
     if result.MatchedCount == 0 {
-        return errors.New("data tidak ditemukan atau tidak memiliki akses")
+        return ErrNotFoundOrNoAccess
     }
     
     return nil
 }
 
-func (r *PekerjaanRepository) HardDeletePekerjaan(id, userID primitive.ObjectID, isAdmin bool) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoPekerjaanRepository) HardDeletePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.HardDeletePekerjaan")()
 
     collection := r.DB.Collection(pekerjaanCollection)
     
@@ -373,7 +504,7 @@ func (r *PekerjaanRepository) HardDeletePekerjaan(id, userID primitive.ObjectID,
         
         err := alumniCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&alumni)
         if err != nil {
-            return errors.New("alumni tidak ditemukan")
+            return ErrAlumniNotFound
         }
         
         filter = bson.M{
@@ -389,8 +520,286 @@ func (r *PekerjaanRepository) HardDeletePekerjaan(id, userID primitive.ObjectID,
     }
     
     if result.DeletedCount == 0 {
-        return errors.New("data tidak ditemukan atau tidak memiliki akses")
+        return ErrNotFoundOrNoAccess
     }
     
     return nil
-}
\ No newline at end of file
+}
+
+// trashIDs returns those of ids that are in the caller's trash. Non-admins
+// without an alumni have an empty trash.
+func (r *MongoPekerjaanRepository) trashIDs(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    filter := bson.M{
+        "_id":       bson.M{"$in": ids},
+        "is_delete": bson.M{"$exists": true},
+    }
+
+    if !isAdmin {
+        var alumni struct {
+            ID primitive.ObjectID `bson:"_id"`
+        }
+        err := r.DB.Collection(alumniCollection).FindOne(ctx, bson.M{"user_id": userID}).Decode(&alumni)
+        if err == mongo.ErrNoDocuments {
+            return nil, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        filter["alumni_id"] = alumni.ID
+    }
+
+    opts := options.Find().SetProjection(bson.M{"_id": 1})
+    cursor, err := r.DB.Collection(pekerjaanCollection).Find(ctx, filter, opts)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var docs []struct {
+        ID primitive.ObjectID `bson:"_id"`
+    }
+    if err = cursor.All(ctx, &docs); err != nil {
+        return nil, err
+    }
+
+    found := make([]primitive.ObjectID, len(docs))
+    for i, d := range docs {
+        found[i] = d.ID
+    }
+    return found, nil
+}
+
+func (r *MongoPekerjaanRepository) RestorePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    defer observe(ctx, "PekerjaanRepository.RestorePekerjaanBulk")()
+
+    found, err := r.trashIDs(ctx, ids, userID, isAdmin)
+    if err != nil || len(found) == 0 {
+        return found, err
+    }
+
+    _, err = r.DB.Collection(pekerjaanCollection).UpdateMany(ctx, bson.M{
+        "_id":       bson.M{"$in": found},
+        "is_delete": bson.M{"$exists": true},
+    }, bson.M{"$unset": bson.M{"is_delete": ""}})
+    if err != nil {
+        return nil, err
+    }
+
+    return found, nil
+}
+
+func (r *MongoPekerjaanRepository) HardDeletePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    defer observe(ctx, "PekerjaanRepository.HardDeletePekerjaanBulk")()
+
+    found, err := r.trashIDs(ctx, ids, userID, isAdmin)
+    if err != nil || len(found) == 0 {
+        return found, err
+    }
+
+    _, err = r.DB.Collection(pekerjaanCollection).DeleteMany(ctx, bson.M{
+        "_id":       bson.M{"$in": found},
+        "is_delete": bson.M{"$exists": true},
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return found, nil
+}
+
+// GetExpiredTrash lists the pekerjaan soft-deleted before the cutoff, oldest
+// first
+func (r *MongoPekerjaanRepository) GetExpiredTrash(ctx context.Context, before time.Time, limit int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetExpiredTrash")()
+
+    opts := options.Find().
+        SetSort(bson.D{{Key: "is_delete", Value: 1}, {Key: "_id", Value: 1}}).
+        SetLimit(int64(limit))
+
+    cursor, err := r.DB.Collection(pekerjaanCollection).Find(ctx, bson.M{"is_delete": bson.M{"$lt": before}}, opts)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Pekerjaan{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+func (r *MongoPekerjaanRepository) CountExpiredTrash(ctx context.Context, before time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountExpiredTrash")()
+
+    count, err := r.DB.Collection(pekerjaanCollection).CountDocuments(ctx, bson.M{"is_delete": bson.M{"$lt": before}})
+    if err != nil {
+        return 0, err
+    }
+
+    return int(count), nil
+}
+
+// PurgeTrash permanently deletes the pekerjaan soft-deleted before the
+// cutoff and returns how many went
+func (r *MongoPekerjaanRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.PurgeTrash")()
+
+    result, err := r.DB.Collection(pekerjaanCollection).DeleteMany(ctx, bson.M{"is_delete": bson.M{"$lt": before}})
+    if err != nil {
+        return 0, err
+    }
+
+    return int(result.DeletedCount), nil
+}
+
+// moderationFilter selects the pekerjaan with the given status_moderasi,
+// scoped to the caller's alumni unless admin. ok is false when a non-admin
+// has no alumni and therefore nothing to moderate.
+func (r *MongoPekerjaanRepository) moderationFilter(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (bson.M, bool) {
+    filter := bson.M{
+        "is_delete":       bson.M{"$exists": false},
+        "status_moderasi": status,
+    }
+
+    if !isAdmin {
+        var alumni struct {
+            ID primitive.ObjectID `bson:"_id"`
+        }
+        err := r.DB.Collection(alumniCollection).FindOne(ctx, bson.M{"user_id": userID}).Decode(&alumni)
+        if err != nil {
+            return nil, false
+        }
+        filter["alumni_id"] = alumni.ID
+    }
+
+    if search != "" {
+        filter["$or"] = []bson.M{
+            {"nama_perusahaan": bson.M{"$regex": search, "$options": "i"}},
+            {"posisi_jabatan": bson.M{"$regex": search, "$options": "i"}},
+        }
+    }
+    return filter, true
+}
+
+func (r *MongoPekerjaanRepository) GetModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetModerationPekerjaan")()
+
+    filter, ok := r.moderationFilter(ctx, userID, isAdmin, status, search)
+    if !ok {
+        return []model.Pekerjaan{}, nil
+    }
+
+    sortOrder := 1
+    if order == "desc" {
+        sortOrder = -1
+    }
+    allowedSort := map[string]bool{"_id": true, "nama_perusahaan": true, "updated_at": true}
+    if !allowedSort[sortBy] {
+        sortBy = "updated_at"
+    }
+
+    opts := options.Find().
+        SetSort(bson.D{{Key: sortBy, Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+        SetLimit(int64(limit)).
+        SetSkip(int64(offset))
+
+    cursor, err := r.DB.Collection(pekerjaanCollection).Find(ctx, filter, opts)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Pekerjaan{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+func (r *MongoPekerjaanRepository) CountModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountModerationPekerjaan")()
+
+    filter, ok := r.moderationFilter(ctx, userID, isAdmin, status, search)
+    if !ok {
+        return 0, nil
+    }
+
+    count, err := r.DB.Collection(pekerjaanCollection).CountDocuments(ctx, filter)
+    if err != nil {
+        return 0, err
+    }
+
+    return int(count), nil
+}
+
+// ModeratePekerjaan records the verdict on a pending pekerjaan. Anything not
+// pending, e.g. already moderated by another admin, is ErrNotFoundOrNoAccess.
+func (r *MongoPekerjaanRepository) ModeratePekerjaan(ctx context.Context, id primitive.ObjectID, status, catatan string, moderatorID primitive.ObjectID) error {
+    defer observe(ctx, "PekerjaanRepository.ModeratePekerjaan")()
+
+    now := time.Now()
+    update := bson.M{"$set": bson.M{
+        "status_moderasi": status,
+        "moderated_by":    moderatorID,
+        "moderated_at":    now,
+        "updated_at":      now,
+    }}
+    if catatan != "" {
+        update["$set"].(bson.M)["catatan_moderasi"] = catatan
+    } else {
+        update["$unset"] = bson.M{"catatan_moderasi": ""}
+    }
+
+    result, err := r.DB.Collection(pekerjaanCollection).UpdateOne(ctx, bson.M{
+        "_id":             id,
+        "is_delete":       bson.M{"$exists": false},
+        "status_moderasi": model.ModerasiPending,
+    }, update)
+    if err != nil {
+        return err
+    }
+
+    if result.MatchedCount == 0 {
+        return ErrNotFoundOrNoAccess
+    }
+
+    return nil
+}
+
+// BulkWritePekerjaan runs the writes as one BulkWrite. Deletes only remove
+// pekerjaan in the trash.
+func (r *MongoPekerjaanRepository) BulkWritePekerjaan(ctx context.Context, writes []model.PekerjaanWrite, ordered bool) ([]error, error) {
+    defer observe(ctx, "PekerjaanRepository.BulkWritePekerjaan")()
+
+    if len(writes) == 0 {
+        return nil, nil
+    }
+
+    now := time.Now()
+    models := make([]mongo.WriteModel, len(writes))
+    for i, w := range writes {
+        p := w.Pekerjaan
+        p.UpdatedAt = now
+        switch w.Op {
+        case model.BulkCreate:
+            p.ID = w.ID
+            p.CreatedAt = now
+            models[i] = mongo.NewInsertOneModel().SetDocument(p)
+        case model.BulkUpdate:
+            models[i] = mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": w.ID}).SetUpdate(pekerjaanUpdate(p))
+        case model.BulkSoftDelete:
+            models[i] = mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": w.ID}).SetUpdate(bson.M{"$set": bson.M{"is_delete": now}})
+        default:
+            models[i] = mongo.NewDeleteOneModel().SetFilter(bson.M{
+                "_id":       w.ID,
+                "is_delete": bson.M{"$exists": true},
+            })
+        }
+    }
+
+    _, err := r.DB.Collection(pekerjaanCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(ordered))
+    return bulkWriteErrors(len(writes), ordered, err)
+}
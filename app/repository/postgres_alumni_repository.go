@@ -0,0 +1,238 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "strconv"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Postgres rows keep the Mongo ObjectID as a 24 character hex primary key so
+// ids in URLs and JWTs stay the same across backends
+
+const alumniColumns = `id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, COALESCE(alamat, ''), user_id, created_at, updated_at`
+
+type PostgresAlumniRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresAlumniRepository(db *sql.DB) *PostgresAlumniRepository {
+    return &PostgresAlumniRepository{DB: db}
+}
+
+// nullableObjectID stores the zero ObjectID as NULL
+func nullableObjectID(id primitive.ObjectID) interface{} {
+    if id.IsZero() {
+        return nil
+    }
+    return id.Hex()
+}
+
+func parseObjectID(hex sql.NullString) primitive.ObjectID {
+    if !hex.Valid {
+        return primitive.NilObjectID
+    }
+    id, _ := primitive.ObjectIDFromHex(hex.String)
+    return id
+}
+
+func scanAlumni(row interface{ Scan(...interface{}) error }) (model.Alumni, error) {
+    var a model.Alumni
+    var id string
+    var userID sql.NullString
+
+    err := row.Scan(&id, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email,
+        &a.NoTelepon, &a.Alamat, &userID, &a.CreatedAt, &a.UpdatedAt)
+    if err != nil {
+        return a, err
+    }
+
+    a.ID, _ = primitive.ObjectIDFromHex(id)
+    a.UserID = parseObjectID(userID)
+    return a, nil
+}
+
+// alumniSearch mirrors the Mongo $regex search on nama, nim and email
+func alumniSearch(search string) (string, []interface{}) {
+    if search == "" {
+        return "", nil
+    }
+    return ` WHERE (nama ~* $1 OR nim ~* $1 OR email ~* $1)`, []interface{}{search}
+}
+
+func (r *PostgresAlumniRepository) CreateAlumni(ctx context.Context, alumni model.Alumni) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.CreateAlumni")()
+
+    // Bulk creates come with the id the service reported, like Mongo's
+    // InsertOne keeps a preset _id
+    if alumni.ID.IsZero() {
+        alumni.ID = primitive.NewObjectID()
+    }
+    alumni.CreatedAt = time.Now()
+    alumni.UpdatedAt = time.Now()
+
+    _, err := r.DB.ExecContext(ctx, `
+        INSERT INTO alumni (id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+        alumni.ID.Hex(), alumni.NIM, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus,
+        alumni.Email, alumni.NoTelepon, alumni.Alamat, nullableObjectID(alumni.UserID),
+        alumni.CreatedAt, alumni.UpdatedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    return &alumni, nil
+}
+
+func (r *PostgresAlumniRepository) UpdateAlumni(ctx context.Context, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.UpdateAlumni")()
+
+    alumni.UpdatedAt = time.Now()
+
+    _, err := r.DB.ExecContext(ctx, `
+        UPDATE alumni
+        SET nim = $2, nama = $3, jurusan = $4, angkatan = $5, tahun_lulus = $6,
+            email = $7, no_telepon = $8, alamat = $9, updated_at = $10
+        WHERE id = $1`,
+        id.Hex(), alumni.NIM, alumni.Nama, alumni.Jurusan, alumni.Angkatan, alumni.TahunLulus,
+        alumni.Email, alumni.NoTelepon, alumni.Alamat, alumni.UpdatedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    alumni.ID = id
+    return &alumni, nil
+}
+
+func (r *PostgresAlumniRepository) DeleteAlumni(ctx context.Context, id primitive.ObjectID) error {
+    defer observe(ctx, "AlumniRepository.DeleteAlumni")()
+
+    _, err := r.DB.ExecContext(ctx, `DELETE FROM alumni WHERE id = $1`, id.Hex())
+    return err
+}
+
+func (r *PostgresAlumniRepository) FindAlumniByID(ctx context.Context, id primitive.ObjectID) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.FindAlumniByID")()
+
+    alumni, err := scanAlumni(r.DB.QueryRowContext(ctx, `SELECT `+alumniColumns+` FROM alumni WHERE id = $1`, id.Hex()))
+    if err == sql.ErrNoRows {
+        return nil, ErrAlumniNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &alumni, nil
+}
+
+func (r *PostgresAlumniRepository) FindAlumniByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.FindAlumniByUserID")()
+
+    alumni, err := scanAlumni(r.DB.QueryRowContext(ctx, `SELECT `+alumniColumns+` FROM alumni WHERE user_id = $1 ORDER BY id LIMIT 1`, userID.Hex()))
+    if err == sql.ErrNoRows {
+        return nil, ErrAlumniNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &alumni, nil
+}
+
+func (r *PostgresAlumniRepository) GetAlumni(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+    defer observe(ctx, "AlumniRepository.GetAlumni")()
+
+    where, args := alumniSearch(search)
+
+    // Whitelisted, so safe to interpolate
+    allowedSort := map[string]string{"_id": "id", "nama": "nama", "angkatan": "angkatan", "tahun_lulus": "tahun_lulus"}
+    column, ok := allowedSort[sortBy]
+    if !ok {
+        column = "id"
+    }
+    direction := "ASC"
+    if order == "desc" {
+        direction = "DESC"
+    }
+
+    query := `SELECT ` + alumniColumns + ` FROM alumni` + where +
+        ` ORDER BY ` + column + ` ` + direction + `, id ` + direction + pageClause(limit, offset)
+
+    rows, err := r.DB.QueryContext(ctx, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.Alumni{}
+    for rows.Next() {
+        a, err := scanAlumni(rows)
+        if err != nil {
+            return nil, err
+        }
+        list = append(list, a)
+    }
+
+    return list, rows.Err()
+}
+
+func (r *PostgresAlumniRepository) CountAlumni(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "AlumniRepository.CountAlumni")()
+
+    where, args := alumniSearch(search)
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM alumni`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresAlumniRepository) GetAlumniStatsByJurusan(ctx context.Context) ([]model.AlumniStatsByJurusanResponse, error) {
+    defer observe(ctx, "AlumniRepository.GetAlumniStatsByJurusan")()
+
+    rows, err := r.DB.QueryContext(ctx, `
+        SELECT jurusan, COUNT(*) AS total
+        FROM alumni
+        GROUP BY jurusan
+        ORDER BY total DESC`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var stats []model.AlumniStatsByJurusanResponse
+    for rows.Next() {
+        var s model.AlumniStatsByJurusanResponse
+        if err := rows.Scan(&s.Jurusan, &s.Total); err != nil {
+            return nil, err
+        }
+        stats = append(stats, s)
+    }
+
+    return stats, rows.Err()
+}
+
+// pageClause mirrors SetLimit/SetSkip, where a limit of 0 means no limit
+func pageClause(limit, offset int) string {
+    clause := ""
+    if limit > 0 {
+        clause += " LIMIT " + strconv.Itoa(limit)
+    }
+    if offset > 0 {
+        clause += " OFFSET " + strconv.Itoa(offset)
+    }
+    return clause
+}
+
+// BulkWriteAlumni runs the writes one statement at a time, stopping at the
+// first failure of an ordered batch like the Mongo BulkWrite
+func (r *PostgresAlumniRepository) BulkWriteAlumni(ctx context.Context, writes []model.AlumniWrite, ordered bool) ([]error, error) {
+    defer observe(ctx, "AlumniRepository.BulkWriteAlumni")()
+
+    return runBulk(len(writes), ordered, func(i int) error {
+        return writeAlumni(ctx, r, writes[i])
+    }), nil
+}
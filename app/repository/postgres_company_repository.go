@@ -0,0 +1,270 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const companyColumns = `id, nama, nama_key, aliases, alias_keys, COALESCE(bidang_industri, ''), COALESCE(lokasi, ''), created_at, updated_at`
+
+type PostgresCompanyRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresCompanyRepository(db *sql.DB) *PostgresCompanyRepository {
+    return &PostgresCompanyRepository{DB: db}
+}
+
+func scanCompany(row interface{ Scan(...interface{}) error }) (model.Company, error) {
+    var c model.Company
+    var id string
+
+    err := row.Scan(&id, &c.Nama, &c.NamaKey, pq.Array(&c.Aliases), pq.Array(&c.AliasKeys),
+        &c.BidangIndustri, &c.Lokasi, &c.CreatedAt, &c.UpdatedAt)
+    if err != nil {
+        return c, err
+    }
+
+    c.ID, _ = primitive.ObjectIDFromHex(id)
+    return c, nil
+}
+
+func (r *PostgresCompanyRepository) queryCompanies(ctx context.Context, query string, args ...interface{}) ([]model.Company, error) {
+    rows, err := r.DB.QueryContext(ctx, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.Company{}
+    for rows.Next() {
+        c, err := scanCompany(rows)
+        if err != nil {
+            return nil, err
+        }
+        list = append(list, c)
+    }
+
+    return list, rows.Err()
+}
+
+// companyWriteError turns a unique violation on nama_key into ErrCompanyExists
+func companyWriteError(err error) error {
+    var pqErr *pq.Error
+    if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+        return ErrCompanyExists
+    }
+    return err
+}
+
+func (r *PostgresCompanyRepository) CreateCompany(ctx context.Context, company model.Company) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.CreateCompany")()
+
+    company.ID = primitive.NewObjectID()
+    company.CreatedAt = time.Now()
+    company.UpdatedAt = time.Now()
+
+    _, err := r.DB.ExecContext(ctx, `
+        INSERT INTO companies (id, nama, nama_key, aliases, alias_keys, bidang_industri, lokasi, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+        company.ID.Hex(), company.Nama, company.NamaKey, pq.Array(company.Aliases), pq.Array(company.AliasKeys),
+        company.BidangIndustri, company.Lokasi, company.CreatedAt, company.UpdatedAt)
+    if err != nil {
+        return nil, companyWriteError(err)
+    }
+
+    return &company, nil
+}
+
+func (r *PostgresCompanyRepository) UpdateCompany(ctx context.Context, id primitive.ObjectID, company model.Company) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.UpdateCompany")()
+
+    row := r.DB.QueryRowContext(ctx, `
+        UPDATE companies
+        SET nama = $2, nama_key = $3, aliases = $4, alias_keys = $5, bidang_industri = $6, lokasi = $7, updated_at = $8
+        WHERE id = $1
+        RETURNING `+companyColumns,
+        id.Hex(), company.Nama, company.NamaKey, pq.Array(company.Aliases), pq.Array(company.AliasKeys),
+        company.BidangIndustri, company.Lokasi, time.Now())
+
+    updated, err := scanCompany(row)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, ErrCompanyNotFound
+    }
+    if err != nil {
+        return nil, companyWriteError(err)
+    }
+
+    return &updated, nil
+}
+
+func (r *PostgresCompanyRepository) DeleteCompany(ctx context.Context, id primitive.ObjectID) error {
+    defer observe(ctx, "CompanyRepository.DeleteCompany")()
+
+    tx, err := r.DB.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.ExecContext(ctx, `UPDATE pekerjaan_alumni SET company_id = NULL WHERE company_id = $1`, id.Hex()); err != nil {
+        return err
+    }
+    if err := expectAffected(tx.ExecContext(ctx, `DELETE FROM companies WHERE id = $1`, id.Hex())); err != nil {
+        if errors.Is(err, ErrNotFoundOrNoAccess) {
+            return ErrCompanyNotFound
+        }
+        return err
+    }
+
+    return tx.Commit()
+}
+
+func (r *PostgresCompanyRepository) FindCompanyByID(ctx context.Context, id primitive.ObjectID) (*model.Company, error) {
+    defer observe(ctx, "CompanyRepository.FindCompanyByID")()
+
+    company, err := scanCompany(r.DB.QueryRowContext(ctx, `SELECT `+companyColumns+` FROM companies WHERE id = $1`, id.Hex()))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, ErrCompanyNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &company, nil
+}
+
+// companySearchClause mirrors the Mongo $regex search; an array matches
+// when any alias does
+func companySearchClause(search string) (string, []interface{}) {
+    if search == "" {
+        return "", nil
+    }
+    return ` WHERE (nama ~* $1 OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE alias ~* $1)
+        OR bidang_industri ~* $1 OR lokasi ~* $1)`, []interface{}{search}
+}
+
+func (r *PostgresCompanyRepository) GetCompanies(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Company, error) {
+    defer observe(ctx, "CompanyRepository.GetCompanies")()
+
+    where, args := companySearchClause(search)
+
+    allowedSort := map[string]string{"_id": "id", "nama": "nama", "bidang_industri": "bidang_industri", "lokasi": "lokasi"}
+    column, ok := allowedSort[sortBy]
+    if !ok {
+        column = "id"
+    }
+    direction := "ASC"
+    if order == "desc" {
+        direction = "DESC"
+    }
+
+    return r.queryCompanies(ctx, `SELECT `+companyColumns+` FROM companies`+where+`
+        ORDER BY `+column+` `+direction+`, id `+direction+pageClause(limit, offset), args...)
+}
+
+func (r *PostgresCompanyRepository) CountCompanies(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "CompanyRepository.CountCompanies")()
+
+    where, args := companySearchClause(search)
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM companies`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresCompanyRepository) AllCompanies(ctx context.Context) ([]model.Company, error) {
+    defer observe(ctx, "CompanyRepository.AllCompanies")()
+
+    return r.queryCompanies(ctx, `SELECT `+companyColumns+` FROM companies ORDER BY id`)
+}
+
+func (r *PostgresCompanyRepository) GetCompanyAlumni(ctx context.Context, id primitive.ObjectID) ([]model.CompanyAlumni, error) {
+    defer observe(ctx, "CompanyRepository.GetCompanyAlumni")()
+
+    rows, err := r.DB.QueryContext(ctx, `
+        SELECT a.id, a.nim, a.nama, a.jurusan, a.angkatan, a.tahun_lulus, a.email, a.no_telepon, COALESCE(a.alamat, ''),
+            a.user_id, a.created_at, a.updated_at,
+            p.id, p.posisi_jabatan, p.tanggal_mulai_kerja, p.tanggal_selesai_kerja, p.status_pekerjaan
+        FROM pekerjaan_alumni p
+        JOIN alumni a ON a.id = p.alumni_id
+        WHERE p.company_id = $1 AND p.is_delete IS NULL AND p.status_moderasi = 'approved'
+        ORDER BY a.nama, a.id, p.tanggal_mulai_kerja, p.id`, id.Hex())
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.CompanyAlumni{}
+    for rows.Next() {
+        var a model.Alumni
+        var p model.Pekerjaan
+        var alumniID, pekerjaanID string
+        var userID sql.NullString
+        err := rows.Scan(&alumniID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus, &a.Email,
+            &a.NoTelepon, &a.Alamat, &userID, &a.CreatedAt, &a.UpdatedAt,
+            &pekerjaanID, &p.PosisiJabatan, &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan)
+        if err != nil {
+            return nil, err
+        }
+        a.ID, _ = primitive.ObjectIDFromHex(alumniID)
+        a.UserID = parseObjectID(userID)
+        p.ID, _ = primitive.ObjectIDFromHex(pekerjaanID)
+        p.AlumniID = a.ID
+
+        if n := len(list); n > 0 && list[n-1].Alumni.ID == a.ID {
+            list[n-1].Pekerjaan = append(list[n-1].Pekerjaan, p)
+            continue
+        }
+        list = append(list, model.CompanyAlumni{Alumni: a, Pekerjaan: []model.Pekerjaan{p}})
+    }
+
+    return list, rows.Err()
+}
+
+func (r *PostgresCompanyRepository) UnlinkedPekerjaan(ctx context.Context) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "CompanyRepository.UnlinkedPekerjaan")()
+
+    rows, err := r.DB.QueryContext(ctx, `SELECT `+pekerjaanColumns+` FROM pekerjaan_alumni WHERE company_id IS NULL ORDER BY id`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.Pekerjaan{}
+    for rows.Next() {
+        p, err := scanPekerjaan(rows)
+        if err != nil {
+            return nil, err
+        }
+        list = append(list, p)
+    }
+
+    return list, rows.Err()
+}
+
+func (r *PostgresCompanyRepository) LinkPekerjaan(ctx context.Context, companyID primitive.ObjectID, pekerjaanIDs []primitive.ObjectID) (int, error) {
+    defer observe(ctx, "CompanyRepository.LinkPekerjaan")()
+
+    ids := make([]string, len(pekerjaanIDs))
+    for i, id := range pekerjaanIDs {
+        ids[i] = id.Hex()
+    }
+
+    result, err := r.DB.ExecContext(ctx, `
+        UPDATE pekerjaan_alumni SET company_id = $1
+        WHERE id = ANY($2) AND company_id IS DISTINCT FROM $1`, companyID.Hex(), pq.Array(ids))
+    if err != nil {
+        return 0, err
+    }
+
+    affected, err := result.RowsAffected()
+    return int(affected), err
+}
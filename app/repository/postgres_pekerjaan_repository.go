@@ -0,0 +1,586 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "strconv"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const pekerjaanColumns = `id, alumni_id, nama_perusahaan, company_id, posisi_jabatan, bidang_industri, lokasi_kerja,
+    gaji_min, gaji_max, COALESCE(gaji_currency, ''), COALESCE(gaji_period, ''), COALESCE(gaji_range, ''), gaji_review,
+    tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan,
+    COALESCE(deskripsi_pekerjaan, ''), created_at, updated_at, is_delete,
+    status_moderasi, COALESCE(catatan_moderasi, ''), moderated_by, moderated_at`
+
+type PostgresPekerjaanRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresPekerjaanRepository(db *sql.DB) *PostgresPekerjaanRepository {
+    return &PostgresPekerjaanRepository{DB: db}
+}
+
+func scanPekerjaan(row interface{ Scan(...interface{}) error }) (model.Pekerjaan, error) {
+    var p model.Pekerjaan
+    var id, alumniID string
+    var companyID, moderatedBy sql.NullString
+
+    err := row.Scan(&id, &alumniID, &p.NamaPerusahaan, &companyID, &p.PosisiJabatan, &p.BidangIndustri, &p.LokasiKerja,
+        &p.GajiMin, &p.GajiMax, &p.GajiCurrency, &p.GajiPeriod, &p.GajiRange, &p.GajiReview,
+        &p.TanggalMulaiKerja, &p.TanggalSelesaiKerja, &p.StatusPekerjaan,
+        &p.DeskripsiPekerjaan, &p.CreatedAt, &p.UpdatedAt, &p.IsDelete,
+        &p.StatusModerasi, &p.CatatanModerasi, &moderatedBy, &p.ModeratedAt)
+    if err != nil {
+        return p, err
+    }
+
+    p.ID, _ = primitive.ObjectIDFromHex(id)
+    p.AlumniID, _ = primitive.ObjectIDFromHex(alumniID)
+    if companyID.Valid {
+        company := parseObjectID(companyID)
+        p.CompanyID = &company
+    }
+    if moderatedBy.Valid {
+        moderator := parseObjectID(moderatedBy)
+        p.ModeratedBy = &moderator
+    }
+    return p, nil
+}
+
+func (r *PostgresPekerjaanRepository) queryPekerjaan(ctx context.Context, query string, args ...interface{}) ([]model.Pekerjaan, error) {
+    rows, err := r.DB.QueryContext(ctx, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.Pekerjaan{}
+    for rows.Next() {
+        p, err := scanPekerjaan(rows)
+        if err != nil {
+            return nil, err
+        }
+        list = append(list, p)
+    }
+
+    return list, rows.Err()
+}
+
+// ownedAlumniID returns the alumni linked to userID, like the Mongo
+// repository's FindOne on alumni.user_id
+func (r *PostgresPekerjaanRepository) ownedAlumniID(ctx context.Context, userID primitive.ObjectID) (string, error) {
+    var alumniID string
+    err := r.DB.QueryRowContext(ctx, `SELECT id FROM alumni WHERE user_id = $1 ORDER BY id LIMIT 1`, userID.Hex()).Scan(&alumniID)
+    if errors.Is(err, sql.ErrNoRows) {
+        return "", ErrAlumniNotFound
+    }
+    return alumniID, err
+}
+
+func (r *PostgresPekerjaanRepository) CreatePekerjaan(ctx context.Context, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.CreatePekerjaan")()
+
+    // Bulk creates come with the id the service reported, like Mongo's
+    // InsertOne keeps a preset _id
+    if p.ID.IsZero() {
+        p.ID = primitive.NewObjectID()
+    }
+    p.StatusModerasi = p.ModerationStatus()
+    p.CreatedAt = time.Now()
+    p.UpdatedAt = time.Now()
+
+    _, err := r.DB.ExecContext(ctx, `
+        INSERT INTO pekerjaan_alumni (id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja,
+            gaji_min, gaji_max, gaji_currency, gaji_period, gaji_range, gaji_review,
+            tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan,
+            created_at, updated_at, company_id, status_moderasi)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+        p.ID.Hex(), p.AlumniID.Hex(), p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja,
+        p.GajiMin, p.GajiMax, nullString(p.GajiCurrency), nullString(p.GajiPeriod), p.GajiRange, p.GajiReview,
+        p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan,
+        p.CreatedAt, p.UpdatedAt, companyRef(p.CompanyID), p.ModerationStatus())
+    if err != nil {
+        return nil, err
+    }
+
+    return &p, nil
+}
+
+func (r *PostgresPekerjaanRepository) UpdatePekerjaan(ctx context.Context, id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.UpdatePekerjaan")()
+
+    p.UpdatedAt = time.Now()
+
+    err := expectAffected(r.DB.ExecContext(ctx, `
+        UPDATE pekerjaan_alumni
+        SET alumni_id = $2, nama_perusahaan = $3, posisi_jabatan = $4, bidang_industri = $5, lokasi_kerja = $6,
+            gaji_min = $7, gaji_max = $8, gaji_currency = $9, gaji_period = $10, gaji_range = $11, gaji_review = $12,
+            tanggal_mulai_kerja = $13, tanggal_selesai_kerja = $14, status_pekerjaan = $15,
+            deskripsi_pekerjaan = $16, updated_at = $17, company_id = $18,
+            status_moderasi = COALESCE(NULLIF($19, ''), status_moderasi),
+            catatan_moderasi = CASE WHEN $19 = '' THEN catatan_moderasi END,
+            moderated_by = CASE WHEN $19 = '' THEN moderated_by END,
+            moderated_at = CASE WHEN $19 = '' THEN moderated_at END
+        WHERE id = $1`,
+        id.Hex(), p.AlumniID.Hex(), p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja,
+        p.GajiMin, p.GajiMax, nullString(p.GajiCurrency), nullString(p.GajiPeriod), p.GajiRange, p.GajiReview,
+        p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan,
+        p.UpdatedAt, companyRef(p.CompanyID), p.StatusModerasi))
+    if err != nil {
+        return nil, err
+    }
+
+    p.ID = id
+    return &p, nil
+}
+
+func (r *PostgresPekerjaanRepository) FindPekerjaanByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByAlumniID")()
+
+    return r.queryPekerjaan(ctx, `
+        SELECT `+pekerjaanColumns+`
+        FROM pekerjaan_alumni
+        WHERE alumni_id = $1 AND is_delete IS NULL AND status_moderasi = 'approved'
+        ORDER BY id`, alumniID.Hex())
+}
+
+func (r *PostgresPekerjaanRepository) FindPekerjaanByID(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByID")()
+
+    p, err := scanPekerjaan(r.DB.QueryRowContext(ctx, `SELECT `+pekerjaanColumns+` FROM pekerjaan_alumni WHERE id = $1 AND is_delete IS NULL`, id.Hex()))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, ErrNotFoundOrNoAccess
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &p, nil
+}
+
+// FindPekerjaanByIDs returns the pekerjaan of ids found in any state,
+// including the trash
+func (r *PostgresPekerjaanRepository) FindPekerjaanByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.FindPekerjaanByIDs")()
+
+    hexes := make([]string, len(ids))
+    for i, id := range ids {
+        hexes[i] = id.Hex()
+    }
+    return r.queryPekerjaan(ctx, `SELECT `+pekerjaanColumns+` FROM pekerjaan_alumni WHERE id = ANY($1) ORDER BY id`, pq.Array(hexes))
+}
+
+// pekerjaanSearch mirrors the Mongo filter of the active listing: search as
+// a $regex and the salary bounds as an overlap with gaji_min..gaji_max
+func pekerjaanSearch(f model.PekerjaanFilter, args []interface{}) (string, []interface{}) {
+    where := ""
+    param := func(value interface{}) string {
+        args = append(args, value)
+        return "$" + strconv.Itoa(len(args))
+    }
+
+    if f.Search != "" {
+        n := param(f.Search)
+        where += ` AND (nama_perusahaan ~* ` + n + ` OR posisi_jabatan ~* ` + n +
+            ` OR bidang_industri ~* ` + n + ` OR lokasi_kerja ~* ` + n + `)`
+    }
+    if f.HasGaji() {
+        where += ` AND (gaji_min IS NOT NULL OR gaji_max IS NOT NULL)`
+    }
+    if f.GajiMin != nil {
+        where += ` AND (gaji_max IS NULL OR gaji_max >= ` + param(*f.GajiMin) + `)`
+    }
+    if f.GajiMax != nil {
+        where += ` AND (gaji_min IS NULL OR gaji_min <= ` + param(*f.GajiMax) + `)`
+    }
+    if f.GajiCurrency != "" {
+        where += ` AND gaji_currency = ` + param(f.GajiCurrency)
+    }
+    if f.GajiPeriod != "" {
+        where += ` AND gaji_period = ` + param(f.GajiPeriod)
+    }
+    return where, args
+}
+
+// companyRef stores a missing company link as NULL
+func companyRef(id *primitive.ObjectID) interface{} {
+    if id == nil {
+        return nil
+    }
+    return id.Hex()
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+    return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (r *PostgresPekerjaanRepository) GetPekerjaan(ctx context.Context, filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetPekerjaan")()
+
+    where, args := pekerjaanSearch(filter, nil)
+
+    allowedSort := map[string]string{"_id": "id", "nama_perusahaan": "nama_perusahaan", "posisi_jabatan": "posisi_jabatan", "tanggal_mulai_kerja": "tanggal_mulai_kerja", "gaji_min": "gaji_min", "gaji_max": "gaji_max"}
+    column, ok := allowedSort[sortBy]
+    if !ok {
+        column = "id"
+    }
+    direction := "ASC"
+    if order == "desc" {
+        direction = "DESC"
+    }
+
+    return r.queryPekerjaan(ctx, `
+        SELECT `+pekerjaanColumns+`
+        FROM pekerjaan_alumni
+        WHERE is_delete IS NULL AND status_moderasi = 'approved'`+where+`
+        ORDER BY `+column+` `+direction+`, id `+direction+pageClause(limit, offset), args...)
+}
+
+func (r *PostgresPekerjaanRepository) CountPekerjaan(ctx context.Context, filter model.PekerjaanFilter) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountPekerjaan")()
+
+    where, args := pekerjaanSearch(filter, nil)
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pekerjaan_alumni WHERE is_delete IS NULL AND status_moderasi = 'approved'`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresPekerjaanRepository) CountPekerjaanByStatus(ctx context.Context, status string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountPekerjaanByStatus")()
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pekerjaan_alumni WHERE is_delete IS NULL AND status_moderasi = 'approved' AND status_pekerjaan = $1`, status).Scan(&count)
+    return count, err
+}
+
+// ExpireContracts moves the aktif pekerjaan whose tanggal_selesai_kerja lies
+// before now to kontrak_habis and returns how many changed
+func (r *PostgresPekerjaanRepository) ExpireContracts(ctx context.Context, now time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.ExpireContracts")()
+
+    result, err := r.DB.ExecContext(ctx, `
+        UPDATE pekerjaan_alumni SET status_pekerjaan = $1, updated_at = $2
+        WHERE is_delete IS NULL AND status_pekerjaan = $3 AND tanggal_selesai_kerja < $2`,
+        model.StatusKontrakHabis, now, model.StatusAktif)
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := result.RowsAffected()
+    return int(n), err
+}
+
+func (r *PostgresPekerjaanRepository) SoftDelete(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.SoftDelete")()
+
+    query := `UPDATE pekerjaan_alumni SET is_delete = $2 WHERE id = $1`
+    args := []interface{}{id.Hex(), time.Now()}
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if err != nil {
+            return err
+        }
+        query += ` AND alumni_id = $3`
+        args = append(args, alumniID)
+    }
+
+    return expectAffected(r.DB.ExecContext(ctx, query, args...))
+}
+
+// trashFilter scopes the trash to the caller's alumni unless admin. ok is
+// false when a non-admin has no alumni and therefore an empty trash.
+func (r *PostgresPekerjaanRepository) trashFilter(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search string) (where string, args []interface{}, ok bool, err error) {
+    where = ` WHERE is_delete IS NOT NULL`
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if errors.Is(err, ErrAlumniNotFound) {
+            return "", nil, false, nil
+        }
+        if err != nil {
+            return "", nil, false, err
+        }
+        args = append(args, alumniID)
+        where += ` AND alumni_id = $1`
+    }
+
+    if search != "" {
+        args = append(args, search)
+        n := "$" + strconv.Itoa(len(args))
+        where += ` AND (nama_perusahaan ~* ` + n + ` OR posisi_jabatan ~* ` + n + `)`
+    }
+
+    return where, args, true, nil
+}
+
+func (r *PostgresPekerjaanRepository) GetTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetTrashPekerjaan")()
+
+    where, args, ok, err := r.trashFilter(ctx, userID, isAdmin, search)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        return []model.Pekerjaan{}, nil
+    }
+
+    allowedSort := map[string]string{"_id": "id", "nama_perusahaan": "nama_perusahaan", "is_delete": "is_delete"}
+    column, found := allowedSort[sortBy]
+    if !found {
+        column = "is_delete"
+    }
+    direction := "DESC"
+    if order == "asc" {
+        direction = "ASC"
+    }
+
+    return r.queryPekerjaan(ctx, `
+        SELECT `+pekerjaanColumns+`
+        FROM pekerjaan_alumni`+where+`
+        ORDER BY `+column+` `+direction+`, id `+direction+pageClause(limit, offset), args...)
+}
+
+func (r *PostgresPekerjaanRepository) CountTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountTrashPekerjaan")()
+
+    where, args, ok, err := r.trashFilter(ctx, userID, isAdmin, search)
+    if err != nil || !ok {
+        return 0, err
+    }
+
+    var count int
+    err = r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pekerjaan_alumni`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresPekerjaanRepository) RestorePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.RestorePekerjaan")()
+
+    query := `UPDATE pekerjaan_alumni SET is_delete = NULL WHERE id = $1 AND is_delete IS NOT NULL`
+    args := []interface{}{id.Hex()}
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if err != nil {
+            return err
+        }
+        query += ` AND alumni_id = $2`
+        args = append(args, alumniID)
+    }
+
+    return expectAffected(r.DB.ExecContext(ctx, query, args...))
+}
+
+func (r *PostgresPekerjaanRepository) HardDeletePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error {
+    defer observe(ctx, "PekerjaanRepository.HardDeletePekerjaan")()
+
+    query := `DELETE FROM pekerjaan_alumni WHERE id = $1 AND is_delete IS NOT NULL`
+    args := []interface{}{id.Hex()}
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if err != nil {
+            return err
+        }
+        query += ` AND alumni_id = $2`
+        args = append(args, alumniID)
+    }
+
+    return expectAffected(r.DB.ExecContext(ctx, query, args...))
+}
+
+// bulkTrash runs statement, an UPDATE or DELETE of pekerjaan_alumni
+// returning id, on those of ids in the caller's trash
+func (r *PostgresPekerjaanRepository) bulkTrash(ctx context.Context, statement string, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    hexes := make([]string, len(ids))
+    for i, id := range ids {
+        hexes[i] = id.Hex()
+    }
+    query := statement + ` WHERE id = ANY($1) AND is_delete IS NOT NULL`
+    args := []interface{}{pq.Array(hexes)}
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if errors.Is(err, ErrAlumniNotFound) {
+            return nil, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        query += ` AND alumni_id = $2`
+        args = append(args, alumniID)
+    }
+
+    rows, err := r.DB.QueryContext(ctx, query+` RETURNING id`, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var found []primitive.ObjectID
+    for rows.Next() {
+        var id sql.NullString
+        if err := rows.Scan(&id); err != nil {
+            return nil, err
+        }
+        found = append(found, parseObjectID(id))
+    }
+    return found, rows.Err()
+}
+
+func (r *PostgresPekerjaanRepository) RestorePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    defer observe(ctx, "PekerjaanRepository.RestorePekerjaanBulk")()
+
+    return r.bulkTrash(ctx, `UPDATE pekerjaan_alumni SET is_delete = NULL`, ids, userID, isAdmin)
+}
+
+func (r *PostgresPekerjaanRepository) HardDeletePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error) {
+    defer observe(ctx, "PekerjaanRepository.HardDeletePekerjaanBulk")()
+
+    return r.bulkTrash(ctx, `DELETE FROM pekerjaan_alumni`, ids, userID, isAdmin)
+}
+
+func (r *PostgresPekerjaanRepository) GetExpiredTrash(ctx context.Context, before time.Time, limit int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetExpiredTrash")()
+
+    return r.queryPekerjaan(ctx, `
+        SELECT `+pekerjaanColumns+`
+        FROM pekerjaan_alumni
+        WHERE is_delete < $1
+        ORDER BY is_delete, id`+pageClause(limit, 0), before)
+}
+
+func (r *PostgresPekerjaanRepository) CountExpiredTrash(ctx context.Context, before time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountExpiredTrash")()
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pekerjaan_alumni WHERE is_delete < $1`, before).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresPekerjaanRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.PurgeTrash")()
+
+    result, err := r.DB.ExecContext(ctx, `DELETE FROM pekerjaan_alumni WHERE is_delete < $1`, before)
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := result.RowsAffected()
+    return int(n), err
+}
+
+// moderationFilter is trashFilter for the moderation queue: the pekerjaan
+// with the given status_moderasi, scoped to the caller's alumni unless admin
+func (r *PostgresPekerjaanRepository) moderationFilter(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (where string, args []interface{}, ok bool, err error) {
+    args = []interface{}{status}
+    where = ` WHERE is_delete IS NULL AND status_moderasi = $1`
+
+    if !isAdmin {
+        alumniID, err := r.ownedAlumniID(ctx, userID)
+        if errors.Is(err, ErrAlumniNotFound) {
+            return "", nil, false, nil
+        }
+        if err != nil {
+            return "", nil, false, err
+        }
+        args = append(args, alumniID)
+        where += ` AND alumni_id = $2`
+    }
+
+    if search != "" {
+        args = append(args, search)
+        n := "$" + strconv.Itoa(len(args))
+        where += ` AND (nama_perusahaan ~* ` + n + ` OR posisi_jabatan ~* ` + n + `)`
+    }
+
+    return where, args, true, nil
+}
+
+func (r *PostgresPekerjaanRepository) GetModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error) {
+    defer observe(ctx, "PekerjaanRepository.GetModerationPekerjaan")()
+
+    where, args, ok, err := r.moderationFilter(ctx, userID, isAdmin, status, search)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        return []model.Pekerjaan{}, nil
+    }
+
+    allowedSort := map[string]string{"_id": "id", "nama_perusahaan": "nama_perusahaan", "updated_at": "updated_at"}
+    column, found := allowedSort[sortBy]
+    if !found {
+        column = "updated_at"
+    }
+    direction := "ASC"
+    if order == "desc" {
+        direction = "DESC"
+    }
+
+    return r.queryPekerjaan(ctx, `
+        SELECT `+pekerjaanColumns+`
+        FROM pekerjaan_alumni`+where+`
+        ORDER BY `+column+` `+direction+`, id `+direction+pageClause(limit, offset), args...)
+}
+
+func (r *PostgresPekerjaanRepository) CountModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (int, error) {
+    defer observe(ctx, "PekerjaanRepository.CountModerationPekerjaan")()
+
+    where, args, ok, err := r.moderationFilter(ctx, userID, isAdmin, status, search)
+    if err != nil || !ok {
+        return 0, err
+    }
+
+    var count int
+    err = r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM pekerjaan_alumni`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresPekerjaanRepository) ModeratePekerjaan(ctx context.Context, id primitive.ObjectID, status, catatan string, moderatorID primitive.ObjectID) error {
+    defer observe(ctx, "PekerjaanRepository.ModeratePekerjaan")()
+
+    now := time.Now()
+    return expectAffected(r.DB.ExecContext(ctx, `
+        UPDATE pekerjaan_alumni
+        SET status_moderasi = $2, catatan_moderasi = $3, moderated_by = $4, moderated_at = $5, updated_at = $5
+        WHERE id = $1 AND is_delete IS NULL AND status_moderasi = $6`,
+        id.Hex(), status, nullString(catatan), moderatorID.Hex(), now, model.ModerasiPending))
+}
+
+// expectAffected turns "no row matched" into ErrNotFoundOrNoAccess
+func expectAffected(result sql.Result, err error) error {
+    if err != nil {
+        return err
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return ErrNotFoundOrNoAccess
+    }
+
+    return nil
+}
+
+// BulkWritePekerjaan runs the writes one statement at a time, stopping at
+// the first failure of an ordered batch like the Mongo BulkWrite
+func (r *PostgresPekerjaanRepository) BulkWritePekerjaan(ctx context.Context, writes []model.PekerjaanWrite, ordered bool) ([]error, error) {
+    defer observe(ctx, "PekerjaanRepository.BulkWritePekerjaan")()
+
+    return runBulk(len(writes), ordered, func(i int) error {
+        return writePekerjaan(ctx, r, writes[i])
+    }), nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reportColumns = `id, jurusan, tahun_lulus, formats, status, COALESCE(error, ''), files, requested_by, created_at, started_at, finished_at`
+
+type PostgresReportRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresReportRepository(db *sql.DB) *PostgresReportRepository {
+    return &PostgresReportRepository{DB: db}
+}
+
+func scanReport(row interface{ Scan(...interface{}) error }) (model.Report, error) {
+    var report model.Report
+    var id, requestedBy string
+    var files []byte
+
+    err := row.Scan(&id, &report.Jurusan, &report.TahunLulus, pq.Array(&report.Formats), &report.Status, &report.Error,
+        &files, &requestedBy, &report.CreatedAt, &report.StartedAt, &report.FinishedAt)
+    if err != nil {
+        return report, err
+    }
+    if err := json.Unmarshal(files, &report.Files); err != nil {
+        return report, err
+    }
+
+    report.ID, _ = primitive.ObjectIDFromHex(id)
+    report.RequestedBy, _ = primitive.ObjectIDFromHex(requestedBy)
+    return report, nil
+}
+
+// reportFiles encodes the files column, [] rather than null when empty
+func reportFiles(files []model.ReportFile) ([]byte, error) {
+    if files == nil {
+        files = []model.ReportFile{}
+    }
+    return json.Marshal(files)
+}
+
+func (r *PostgresReportRepository) CreateReport(ctx context.Context, report model.Report) (*model.Report, error) {
+    defer observe(ctx, "ReportRepository.CreateReport")()
+
+    report.ID = primitive.NewObjectID()
+    if report.Files == nil {
+        report.Files = []model.ReportFile{}
+    }
+    files, err := reportFiles(report.Files)
+    if err != nil {
+        return nil, err
+    }
+
+    _, err = r.DB.ExecContext(ctx, `
+        INSERT INTO reports (id, jurusan, tahun_lulus, formats, status, error, files, requested_by, created_at, started_at, finished_at)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11)`,
+        report.ID.Hex(), report.Jurusan, report.TahunLulus, pq.Array(report.Formats), report.Status, report.Error,
+        files, report.RequestedBy.Hex(), report.CreatedAt, report.StartedAt, report.FinishedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    return &report, nil
+}
+
+func (r *PostgresReportRepository) UpdateReport(ctx context.Context, report model.Report) error {
+    defer observe(ctx, "ReportRepository.UpdateReport")()
+
+    files, err := reportFiles(report.Files)
+    if err != nil {
+        return err
+    }
+
+    result, err := r.DB.ExecContext(ctx, `
+        UPDATE reports SET status = $2, error = NULLIF($3, ''), files = $4,
+            started_at = COALESCE($5, started_at), finished_at = COALESCE($6, finished_at)
+        WHERE id = $1`,
+        report.ID.Hex(), report.Status, report.Error, files, report.StartedAt, report.FinishedAt)
+    if err != nil {
+        return err
+    }
+    if n, _ := result.RowsAffected(); n == 0 {
+        return ErrReportNotFound
+    }
+    return nil
+}
+
+func (r *PostgresReportRepository) FindReportByID(ctx context.Context, id primitive.ObjectID) (*model.Report, error) {
+    defer observe(ctx, "ReportRepository.FindReportByID")()
+
+    report, err := scanReport(r.DB.QueryRowContext(ctx, `SELECT `+reportColumns+` FROM reports WHERE id = $1`, id.Hex()))
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, ErrReportNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &report, nil
+}
+
+func (r *PostgresReportRepository) GetReports(ctx context.Context, limit, offset int) ([]model.Report, error) {
+    defer observe(ctx, "ReportRepository.GetReports")()
+
+    rows, err := r.DB.QueryContext(ctx, `SELECT `+reportColumns+` FROM reports ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := []model.Report{}
+    for rows.Next() {
+        report, err := scanReport(rows)
+        if err != nil {
+            return nil, err
+        }
+        list = append(list, report)
+    }
+
+    return list, rows.Err()
+}
+
+func (r *PostgresReportRepository) CountReports(ctx context.Context) (int, error) {
+    defer observe(ctx, "ReportRepository.CountReports")()
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM reports`).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresReportRepository) FailStaleReports(ctx context.Context, before time.Time, message string) (int, error) {
+    defer observe(ctx, "ReportRepository.FailStaleReports")()
+
+    result, err := r.DB.ExecContext(ctx, `
+        UPDATE reports SET status = $1, error = $2, finished_at = $3
+        WHERE status IN ($4, $5) AND created_at < $6`,
+        model.ReportFailed, message, time.Now(), model.ReportPending, model.ReportRunning, before)
+    if err != nil {
+        return 0, err
+    }
+    n, err := result.RowsAffected()
+    return int(n), err
+}
+
+func (r *PostgresReportRepository) SaveReportFile(ctx context.Context, file model.ReportContent) error {
+    defer observe(ctx, "ReportRepository.SaveReportFile")()
+
+    _, err := r.DB.ExecContext(ctx, `
+        INSERT INTO report_files (report_id, format, data, created_at) VALUES ($1, $2, $3, $4)
+        ON CONFLICT (report_id, format) DO UPDATE SET data = EXCLUDED.data, created_at = EXCLUDED.created_at`,
+        file.ReportID.Hex(), file.Format, file.Data, time.Now())
+    return err
+}
+
+func (r *PostgresReportRepository) GetReportFile(ctx context.Context, id primitive.ObjectID, format string) (*model.ReportContent, error) {
+    defer observe(ctx, "ReportRepository.GetReportFile")()
+
+    file := model.ReportContent{ReportID: id, Format: format}
+    err := r.DB.QueryRowContext(ctx, `SELECT data, created_at FROM report_files WHERE report_id = $1 AND format = $2`,
+        id.Hex(), format).Scan(&file.Data, &file.CreatedAt)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, ErrReportNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &file, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "strconv"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PostgresStatsRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresStatsRepository(db *sql.DB) *PostgresStatsRepository {
+    return &PostgresStatsRepository{DB: db}
+}
+
+// cohortWhere mirrors cohortMatch on the alumni aliased a
+func cohortWhere(f model.CohortFilter) (string, []interface{}) {
+    where := ` WHERE TRUE`
+    var args []interface{}
+    param := func(value interface{}) string {
+        args = append(args, value)
+        return "$" + strconv.Itoa(len(args))
+    }
+
+    if f.Jurusan != "" {
+        where += ` AND a.jurusan = ` + param(f.Jurusan)
+    }
+    if f.Angkatan != 0 {
+        where += ` AND a.angkatan = ` + param(f.Angkatan)
+    }
+    if f.TahunLulusFrom != 0 {
+        where += ` AND a.tahun_lulus >= ` + param(f.TahunLulusFrom)
+    }
+    if f.TahunLulusTo != 0 {
+        where += ` AND a.tahun_lulus <= ` + param(f.TahunLulusTo)
+    }
+    return where, args
+}
+
+type cohortKey struct {
+    jurusan    string
+    tahunLulus int
+}
+
+// GetCohorts reduces each alumni to its approved pekerjaan outside the trash
+// with a lateral join, groups them per jurusan and tahun_lulus, then counts
+// the industries of those cohorts in a second query
+func (r *PostgresStatsRepository) GetCohorts(ctx context.Context, f model.CohortFilter) ([]model.CohortAggregate, error) {
+    defer observe(ctx, "StatsRepository.GetCohorts")()
+
+    where, args := cohortWhere(f)
+    rows, err := r.DB.QueryContext(ctx, `
+        WITH per_alumni AS (
+            SELECT a.jurusan, a.tahun_lulus, j.jobs, j.aktif,
+                (EXTRACT(YEAR FROM j.first_start AT TIME ZONE 'UTC')::int - a.tahun_lulus) * 12
+                    + EXTRACT(MONTH FROM j.first_start AT TIME ZONE 'UTC')::int - 1 AS waiting
+            FROM alumni a
+            CROSS JOIN LATERAL (
+                SELECT COUNT(*) AS jobs,
+                    COALESCE(bool_or(p.status_pekerjaan = 'aktif'), FALSE) AS aktif,
+                    MIN(p.tanggal_mulai_kerja) AS first_start
+                FROM pekerjaan_alumni p
+                WHERE p.alumni_id = a.id AND p.is_delete IS NULL AND p.status_moderasi = 'approved'
+            ) j`+where+`
+        )
+        SELECT jurusan, tahun_lulus, COUNT(*),
+            COUNT(*) FILTER (WHERE jobs > 0),
+            COUNT(*) FILTER (WHERE aktif),
+            COALESCE(SUM(jobs), 0)::int,
+            COUNT(*) FILTER (WHERE jobs > 1),
+            COALESCE(array_agg(waiting) FILTER (WHERE waiting IS NOT NULL), '{}')
+        FROM per_alumni
+        GROUP BY jurusan, tahun_lulus
+        ORDER BY jurusan, tahun_lulus`, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    cohorts := []model.CohortAggregate{}
+    index := map[cohortKey]int{}
+    for rows.Next() {
+        var c model.CohortAggregate
+        var waiting []int64
+        err := rows.Scan(&c.Jurusan, &c.TahunLulus, &c.Alumni, &c.Employed, &c.Aktif, &c.Pekerjaan, &c.Changed, pq.Array(&waiting))
+        if err != nil {
+            return nil, err
+        }
+        c.WaitingMonths = make([]int, len(waiting))
+        for i, m := range waiting {
+            c.WaitingMonths[i] = int(m)
+        }
+        index[cohortKey{c.Jurusan, c.TahunLulus}] = len(cohorts)
+        cohorts = append(cohorts, c)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    industries, err := r.DB.QueryContext(ctx, `
+        SELECT a.jurusan, a.tahun_lulus, p.bidang_industri, COUNT(*), COUNT(DISTINCT a.id)
+        FROM alumni a
+        JOIN pekerjaan_alumni p ON p.alumni_id = a.id AND p.is_delete IS NULL AND p.status_moderasi = 'approved'`+where+`
+        GROUP BY a.jurusan, a.tahun_lulus, p.bidang_industri`, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer industries.Close()
+
+    for industries.Next() {
+        var key cohortKey
+        var count model.IndustryCount
+        if err := industries.Scan(&key.jurusan, &key.tahunLulus, &count.BidangIndustri, &count.Total, &count.Alumni); err != nil {
+            return nil, err
+        }
+        if i, ok := index[key]; ok {
+            cohorts[i].Industries = append(cohorts[i].Industries, count)
+        }
+    }
+    for i := range cohorts {
+        model.SortIndustries(cohorts[i].Industries)
+    }
+
+    return cohorts, industries.Err()
+}
+
+func (r *PostgresStatsRepository) GetPekerjaanFacts(ctx context.Context, f model.CohortFilter) ([]model.PekerjaanFact, error) {
+    defer observe(ctx, "StatsRepository.GetPekerjaanFacts")()
+
+    where, args := cohortWhere(f)
+    rows, err := r.DB.QueryContext(ctx, `
+        SELECT p.id, a.id, a.jurusan, a.angkatan, a.tahun_lulus, p.posisi_jabatan, p.bidang_industri, p.lokasi_kerja,
+            p.gaji_min, p.gaji_max, COALESCE(p.gaji_currency, ''), COALESCE(p.gaji_period, ''),
+            p.tanggal_mulai_kerja, p.tanggal_selesai_kerja, p.status_pekerjaan
+        FROM alumni a
+        JOIN pekerjaan_alumni p ON p.alumni_id = a.id AND p.is_delete IS NULL AND p.status_moderasi = 'approved'`+where+`
+        ORDER BY a.id, p.tanggal_mulai_kerja, p.id`, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    facts := []model.PekerjaanFact{}
+    for rows.Next() {
+        var fact model.PekerjaanFact
+        var id, alumniID string
+        err := rows.Scan(&id, &alumniID, &fact.Jurusan, &fact.Angkatan, &fact.TahunLulus, &fact.PosisiJabatan, &fact.BidangIndustri,
+            &fact.LokasiKerja, &fact.GajiMin, &fact.GajiMax, &fact.GajiCurrency, &fact.GajiPeriod,
+            &fact.TanggalMulaiKerja, &fact.TanggalSelesaiKerja, &fact.StatusPekerjaan)
+        if err != nil {
+            return nil, err
+        }
+        fact.ID, _ = primitive.ObjectIDFromHex(id)
+        fact.AlumniID, _ = primitive.ObjectIDFromHex(alumniID)
+        facts = append(facts, fact)
+    }
+
+    return facts, rows.Err()
+}
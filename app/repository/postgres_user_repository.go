@@ -0,0 +1,149 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userColumns = `id, username, email, password_hash, role, created_at`
+
+type PostgresUserRepository struct {
+    DB *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+    return &PostgresUserRepository{DB: db}
+}
+
+func scanUser(row interface{ Scan(...interface{}) error }) (model.User, error) {
+    var u model.User
+    var id string
+
+    if err := row.Scan(&id, &u.Username, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt); err != nil {
+        return u, err
+    }
+
+    u.ID, _ = primitive.ObjectIDFromHex(id)
+    return u, nil
+}
+
+func userSearch(search string) (string, []interface{}) {
+    if search == "" {
+        return "", nil
+    }
+    return ` WHERE (username ~* $1 OR email ~* $1)`, []interface{}{search}
+}
+
+func (r *PostgresUserRepository) FindUserByUsernameOrEmail(ctx context.Context, identifier string) (*model.User, error) {
+    defer observe(ctx, "UserRepository.FindUserByUsernameOrEmail")()
+
+    row := r.DB.QueryRowContext(ctx, `
+        SELECT `+userColumns+`
+        FROM users
+        WHERE username = $1 OR email = $1
+        ORDER BY id
+        LIMIT 1`, identifier)
+
+    user, err := scanUser(row)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, ErrUserNotFound
+        }
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+func (r *PostgresUserRepository) GetUsers(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.User, error) {
+    defer observe(ctx, "UserRepository.GetUsers")()
+
+    where, args := userSearch(search)
+
+    sortByMap := map[string]string{
+        "id":         "id",
+        "username":   "username",
+        "email":      "email",
+        "role":       "role",
+        "created_at": "created_at",
+    }
+    column := sortByMap[sortBy]
+    if column == "" {
+        column = "id"
+    }
+    direction := "ASC"
+    if order == "DESC" || order == "desc" {
+        direction = "DESC"
+    }
+
+    rows, err := r.DB.QueryContext(ctx, `SELECT `+userColumns+` FROM users`+where+
+        ` ORDER BY `+column+` `+direction+`, id `+direction+pageClause(limit, offset), args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var users []model.User
+    for rows.Next() {
+        u, err := scanUser(rows)
+        if err != nil {
+            return nil, err
+        }
+        users = append(users, u)
+    }
+
+    return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) CountUsers(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "UserRepository.CountUsers")()
+
+    where, args := userSearch(search)
+
+    var count int
+    err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&count)
+    return count, err
+}
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, user model.User) (*model.User, error) {
+    defer observe(ctx, "UserRepository.CreateUser")()
+
+    user.ID = primitive.NewObjectID()
+    user.CreatedAt = time.Now()
+
+    _, err := r.DB.ExecContext(ctx, `
+        INSERT INTO users (`+userColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+        user.ID.Hex(), user.Username, user.Email, user.PasswordHash, user.Role, user.CreatedAt)
+    if err != nil {
+        var pqErr *pq.Error
+        if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+            return nil, ErrUserExists
+        }
+        return nil, err
+    }
+
+    return &user, nil
+}
+
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error {
+    defer observe(ctx, "UserRepository.UpdatePassword")()
+
+    result, err := r.DB.ExecContext(ctx, `UPDATE users SET password_hash = $2 WHERE id = $1`, id.Hex(), passwordHash)
+    if err != nil {
+        return err
+    }
+
+    if n, _ := result.RowsAffected(); n == 0 {
+        return ErrUserNotFound
+    }
+
+    return nil
+}
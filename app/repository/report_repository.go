@@ -0,0 +1,159 @@
+package repository
+
+import (
+    "context"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+    reportCollection     = "reports"
+    reportFileCollection = "report_files"
+)
+
+type MongoReportRepository struct {
+    DB *mongo.Database
+}
+
+func NewMongoReportRepository(db *mongo.Database) *MongoReportRepository {
+    return &MongoReportRepository{DB: db}
+}
+
+func (r *MongoReportRepository) CreateReport(ctx context.Context, report model.Report) (*model.Report, error) {
+    defer observe(ctx, "ReportRepository.CreateReport")()
+
+    if report.Files == nil {
+        report.Files = []model.ReportFile{}
+    }
+    result, err := r.DB.Collection(reportCollection).InsertOne(ctx, report)
+    if err != nil {
+        return nil, err
+    }
+
+    report.ID = result.InsertedID.(primitive.ObjectID)
+    return &report, nil
+}
+
+func (r *MongoReportRepository) UpdateReport(ctx context.Context, report model.Report) error {
+    defer observe(ctx, "ReportRepository.UpdateReport")()
+
+    if report.Files == nil {
+        report.Files = []model.ReportFile{}
+    }
+    set := bson.M{"status": report.Status, "files": report.Files}
+    unset := bson.M{}
+    if report.Error != "" {
+        set["error"] = report.Error
+    } else {
+        unset["error"] = ""
+    }
+    if report.StartedAt != nil {
+        set["started_at"] = report.StartedAt
+    }
+    if report.FinishedAt != nil {
+        set["finished_at"] = report.FinishedAt
+    }
+    update := bson.M{"$set": set}
+    if len(unset) > 0 {
+        update["$unset"] = unset
+    }
+
+    result, err := r.DB.Collection(reportCollection).UpdateOne(ctx, bson.M{"_id": report.ID}, update)
+    if err != nil {
+        return err
+    }
+    if result.MatchedCount == 0 {
+        return ErrReportNotFound
+    }
+    return nil
+}
+
+func (r *MongoReportRepository) FindReportByID(ctx context.Context, id primitive.ObjectID) (*model.Report, error) {
+    defer observe(ctx, "ReportRepository.FindReportByID")()
+
+    var report model.Report
+    err := r.DB.Collection(reportCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&report)
+    if errors.Is(err, mongo.ErrNoDocuments) {
+        return nil, ErrReportNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &report, nil
+}
+
+func (r *MongoReportRepository) GetReports(ctx context.Context, limit, offset int) ([]model.Report, error) {
+    defer observe(ctx, "ReportRepository.GetReports")()
+
+    opts := options.Find().
+        SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+        SetLimit(int64(limit)).
+        SetSkip(int64(offset))
+
+    cursor, err := r.DB.Collection(reportCollection).Find(ctx, bson.M{}, opts)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    list := []model.Report{}
+    if err = cursor.All(ctx, &list); err != nil {
+        return nil, err
+    }
+
+    return list, nil
+}
+
+func (r *MongoReportRepository) CountReports(ctx context.Context) (int, error) {
+    defer observe(ctx, "ReportRepository.CountReports")()
+
+    count, err := r.DB.Collection(reportCollection).CountDocuments(ctx, bson.M{})
+    return int(count), err
+}
+
+func (r *MongoReportRepository) FailStaleReports(ctx context.Context, before time.Time, message string) (int, error) {
+    defer observe(ctx, "ReportRepository.FailStaleReports")()
+
+    result, err := r.DB.Collection(reportCollection).UpdateMany(ctx, bson.M{
+        "status":     bson.M{"$in": []string{model.ReportPending, model.ReportRunning}},
+        "created_at": bson.M{"$lt": before},
+    }, bson.M{"$set": bson.M{"status": model.ReportFailed, "error": message, "finished_at": time.Now()}})
+    if err != nil {
+        return 0, err
+    }
+    return int(result.ModifiedCount), nil
+}
+
+// SaveReportFile replaces the file of the same report and format, so a
+// retried generation does not leave duplicates
+func (r *MongoReportRepository) SaveReportFile(ctx context.Context, file model.ReportContent) error {
+    defer observe(ctx, "ReportRepository.SaveReportFile")()
+
+    file.CreatedAt = time.Now()
+    _, err := r.DB.Collection(reportFileCollection).ReplaceOne(ctx,
+        bson.M{"report_id": file.ReportID, "format": file.Format}, file, options.Replace().SetUpsert(true))
+    return err
+}
+
+func (r *MongoReportRepository) GetReportFile(ctx context.Context, id primitive.ObjectID, format string) (*model.ReportContent, error) {
+    defer observe(ctx, "ReportRepository.GetReportFile")()
+
+    var file model.ReportContent
+    err := r.DB.Collection(reportFileCollection).FindOne(ctx, bson.M{"report_id": id, "format": format}).Decode(&file)
+    if errors.Is(err, mongo.ErrNoDocuments) {
+        return nil, ErrReportNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return &file, nil
+}
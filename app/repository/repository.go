@@ -0,0 +1,160 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// Errors shared by every repository implementation
+var (
+    ErrUserNotFound       = errors.New("user not found")
+    ErrAlumniNotFound     = errors.New("alumni tidak ditemukan")
+    ErrNotFoundOrNoAccess = errors.New("data tidak ditemukan atau tidak memiliki akses")
+    ErrUserExists         = errors.New("username atau email sudah digunakan")
+    ErrCompanyNotFound    = errors.New("perusahaan tidak ditemukan")
+    ErrCompanyExists      = errors.New("nama perusahaan sudah terdaftar")
+    ErrBulkSkipped        = errors.New("dilewati karena operasi sebelumnya gagal")
+    ErrReportNotFound     = errors.New("laporan tidak ditemukan")
+)
+
+// AlumniRepository stores alumni. BulkWriteAlumni returns one error per write,
+// nil for those that succeeded and ErrBulkSkipped for those an ordered batch
+// did not reach.
+type AlumniRepository interface {
+    CreateAlumni(ctx context.Context, alumni model.Alumni) (*model.Alumni, error)
+    UpdateAlumni(ctx context.Context, id primitive.ObjectID, alumni model.Alumni) (*model.Alumni, error)
+    DeleteAlumni(ctx context.Context, id primitive.ObjectID) error
+    FindAlumniByID(ctx context.Context, id primitive.ObjectID) (*model.Alumni, error)
+    FindAlumniByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Alumni, error)
+    GetAlumni(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Alumni, error)
+    CountAlumni(ctx context.Context, search string) (int, error)
+    GetAlumniStatsByJurusan(ctx context.Context) ([]model.AlumniStatsByJurusanResponse, error)
+    BulkWriteAlumni(ctx context.Context, writes []model.AlumniWrite, ordered bool) ([]error, error)
+}
+
+// PekerjaanRepository stores pekerjaan alumni. Soft-deleted documents carry an
+// is_delete timestamp and are only visible through the trash methods; pending
+// and rejected ones only through FindPekerjaanByID and the moderation
+// methods. Non-admin callers may only touch pekerjaan of the alumni linked to
+// their user ID. UpdatePekerjaan returns ErrNotFoundOrNoAccess for an unknown
+// id, leaves status_moderasi alone when p has none and clears the previous
+// verdict when it has. The bulk trash methods return
+// the ids they changed, skipping those not in the caller's trash; the
+// expired trash is what was deleted before a retention cutoff.
+// FindPekerjaanByIDs returns the ones found in any state, and
+// BulkWritePekerjaan reports per write like BulkWriteAlumni.
+type PekerjaanRepository interface {
+    CreatePekerjaan(ctx context.Context, p model.Pekerjaan) (*model.Pekerjaan, error)
+    UpdatePekerjaan(ctx context.Context, id primitive.ObjectID, p model.Pekerjaan) (*model.Pekerjaan, error)
+    FindPekerjaanByID(ctx context.Context, id primitive.ObjectID) (*model.Pekerjaan, error)
+    FindPekerjaanByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Pekerjaan, error)
+    FindPekerjaanByAlumniID(ctx context.Context, alumniID primitive.ObjectID) ([]model.Pekerjaan, error)
+    GetPekerjaan(ctx context.Context, filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error)
+    CountPekerjaan(ctx context.Context, filter model.PekerjaanFilter) (int, error)
+    CountPekerjaanByStatus(ctx context.Context, status string) (int, error)
+    ExpireContracts(ctx context.Context, now time.Time) (int, error)
+    SoftDelete(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error
+    GetTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error)
+    CountTrashPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, search string) (int, error)
+    RestorePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error
+    HardDeletePekerjaan(ctx context.Context, id, userID primitive.ObjectID, isAdmin bool) error
+    RestorePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error)
+    HardDeletePekerjaanBulk(ctx context.Context, ids []primitive.ObjectID, userID primitive.ObjectID, isAdmin bool) ([]primitive.ObjectID, error)
+    GetExpiredTrash(ctx context.Context, before time.Time, limit int) ([]model.Pekerjaan, error)
+    CountExpiredTrash(ctx context.Context, before time.Time) (int, error)
+    PurgeTrash(ctx context.Context, before time.Time) (int, error)
+    BulkWritePekerjaan(ctx context.Context, writes []model.PekerjaanWrite, ordered bool) ([]error, error)
+    GetModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search, sortBy, order string, limit, offset int) ([]model.Pekerjaan, error)
+    CountModerationPekerjaan(ctx context.Context, userID primitive.ObjectID, isAdmin bool, status, search string) (int, error)
+    ModeratePekerjaan(ctx context.Context, id primitive.ObjectID, status, catatan string, moderatorID primitive.ObjectID) error
+}
+
+// CompanyRepository stores the companies registry and the links from
+// pekerjaan to it. Deleting a company unlinks its pekerjaan.
+type CompanyRepository interface {
+    CreateCompany(ctx context.Context, company model.Company) (*model.Company, error)
+    UpdateCompany(ctx context.Context, id primitive.ObjectID, company model.Company) (*model.Company, error)
+    DeleteCompany(ctx context.Context, id primitive.ObjectID) error
+    FindCompanyByID(ctx context.Context, id primitive.ObjectID) (*model.Company, error)
+    GetCompanies(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.Company, error)
+    CountCompanies(ctx context.Context, search string) (int, error)
+    AllCompanies(ctx context.Context) ([]model.Company, error)
+    GetCompanyAlumni(ctx context.Context, id primitive.ObjectID) ([]model.CompanyAlumni, error)
+    UnlinkedPekerjaan(ctx context.Context) ([]model.Pekerjaan, error)
+    LinkPekerjaan(ctx context.Context, companyID primitive.ObjectID, pekerjaanIDs []primitive.ObjectID) (int, error)
+}
+
+// UserRepository stores users
+type UserRepository interface {
+    FindUserByUsernameOrEmail(ctx context.Context, identifier string) (*model.User, error)
+    GetUsers(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.User, error)
+    CountUsers(ctx context.Context, search string) (int, error)
+    CreateUser(ctx context.Context, user model.User) (*model.User, error)
+    UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error
+}
+
+// StatsRepository computes the alumni analytics. Only approved pekerjaan
+// outside the trash count. GetPekerjaanFacts lists them with their alumni's
+// cohort, per alumni in order of tanggal_mulai_kerja.
+type StatsRepository interface {
+    GetCohorts(ctx context.Context, filter model.CohortFilter) ([]model.CohortAggregate, error)
+    GetPekerjaanFacts(ctx context.Context, filter model.CohortFilter) ([]model.PekerjaanFact, error)
+}
+
+// ReportRepository stores the report jobs and their generated files, newest
+// job first. UpdateReport writes the status, error and files of a job and
+// keeps the start and finish times it already has when report has none.
+// FailStaleReports fails the jobs still pending or running that were
+// created before a cutoff, which no server can be working on anymore.
+type ReportRepository interface {
+    CreateReport(ctx context.Context, report model.Report) (*model.Report, error)
+    UpdateReport(ctx context.Context, report model.Report) error
+    FindReportByID(ctx context.Context, id primitive.ObjectID) (*model.Report, error)
+    GetReports(ctx context.Context, limit, offset int) ([]model.Report, error)
+    CountReports(ctx context.Context) (int, error)
+    FailStaleReports(ctx context.Context, before time.Time, message string) (int, error)
+    SaveReportFile(ctx context.Context, file model.ReportContent) error
+    GetReportFile(ctx context.Context, id primitive.ObjectID, format string) (*model.ReportContent, error)
+}
+
+// Repositories groups the repositories of one storage backend
+type Repositories struct {
+    Alumni    AlumniRepository
+    Pekerjaan PekerjaanRepository
+    Company   CompanyRepository
+    User      UserRepository
+    Stats     StatsRepository
+    Report    ReportRepository
+}
+
+// NewMongoRepositories returns the MongoDB backed repositories
+func NewMongoRepositories(db *mongo.Database) Repositories {
+    return Repositories{
+        Alumni:    NewMongoAlumniRepository(db),
+        Pekerjaan: NewMongoPekerjaanRepository(db),
+        Company:   NewMongoCompanyRepository(db),
+        User:      NewMongoUserRepository(db),
+        Stats:     NewMongoStatsRepository(db),
+        Report:    NewMongoReportRepository(db),
+    }
+}
+
+// NewPostgresRepositories returns the PostgreSQL backed repositories. The
+// schema is created by database.RunPostgresMigrations.
+func NewPostgresRepositories(db *sql.DB) Repositories {
+    return Repositories{
+        Alumni:    NewPostgresAlumniRepository(db),
+        Pekerjaan: NewPostgresPekerjaanRepository(db),
+        Company:   NewPostgresCompanyRepository(db),
+        User:      NewPostgresUserRepository(db),
+        Stats:     NewPostgresStatsRepository(db),
+        Report:    NewPostgresReportRepository(db),
+    }
+}
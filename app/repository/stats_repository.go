@@ -0,0 +1,175 @@
+package repository
+
+import (
+    "context"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoStatsRepository runs the analytics as aggregation pipelines over the
+// alumni and pekerjaan_alumni collections
+type MongoStatsRepository struct {
+    DB *mongo.Database
+}
+
+func NewMongoStatsRepository(db *mongo.Database) *MongoStatsRepository {
+    return &MongoStatsRepository{DB: db}
+}
+
+// cohortMatch is the $match of the alumni a CohortFilter selects
+func cohortMatch(f model.CohortFilter) bson.D {
+    match := bson.D{}
+    if f.Jurusan != "" {
+        match = append(match, bson.E{Key: "jurusan", Value: f.Jurusan})
+    }
+    if f.Angkatan != 0 {
+        match = append(match, bson.E{Key: "angkatan", Value: f.Angkatan})
+    }
+    tahun := bson.D{}
+    if f.TahunLulusFrom != 0 {
+        tahun = append(tahun, bson.E{Key: "$gte", Value: f.TahunLulusFrom})
+    }
+    if f.TahunLulusTo != 0 {
+        tahun = append(tahun, bson.E{Key: "$lte", Value: f.TahunLulusTo})
+    }
+    if len(tahun) > 0 {
+        match = append(match, bson.E{Key: "tahun_lulus", Value: tahun})
+    }
+    return match
+}
+
+// lookupPekerjaan joins every alumni with its approved pekerjaan outside
+// the trash as "pekerjaan"
+var lookupPekerjaan = bson.D{{Key: "$lookup", Value: bson.D{
+    {Key: "from", Value: pekerjaanCollection},
+    {Key: "let", Value: bson.D{{Key: "alumni_id", Value: "$_id"}}},
+    {Key: "pipeline", Value: mongo.Pipeline{
+        {{Key: "$match", Value: bson.D{
+            {Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$alumni_id", "$$alumni_id"}}}},
+            {Key: "is_delete", Value: bson.D{{Key: "$exists", Value: false}}},
+            {Key: "status_moderasi", Value: approvedModeration},
+        }}},
+    }},
+    {Key: "as", Value: "pekerjaan"},
+}}}
+
+// GetCohorts aggregates the alumni per jurusan and tahun_lulus. Each alumni
+// is first reduced to its number of pekerjaan, whether one is aktif and the
+// months it waited for the first; the groups then sum those up.
+func (r *MongoStatsRepository) GetCohorts(ctx context.Context, f model.CohortFilter) ([]model.CohortAggregate, error) {
+    defer observe(ctx, "StatsRepository.GetCohorts")()
+
+    firstStart := bson.D{{Key: "$min", Value: "$pekerjaan.tanggal_mulai_kerja"}}
+    pipeline := mongo.Pipeline{
+        {{Key: "$match", Value: cohortMatch(f)}},
+        lookupPekerjaan,
+        {{Key: "$project", Value: bson.D{
+            {Key: "jurusan", Value: 1},
+            {Key: "tahun_lulus", Value: 1},
+            {Key: "jobs", Value: bson.D{{Key: "$size", Value: "$pekerjaan"}}},
+            {Key: "aktif", Value: bson.D{{Key: "$in", Value: bson.A{model.StatusAktif, "$pekerjaan.status_pekerjaan"}}}},
+            {Key: "industries", Value: "$pekerjaan.bidang_industri"},
+            // Same as model.WaitingMonths, null without pekerjaan
+            {Key: "waiting", Value: bson.D{{Key: "$cond", Value: bson.A{
+                bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$size", Value: "$pekerjaan"}}, 0}}},
+                nil,
+                bson.D{{Key: "$add", Value: bson.A{
+                    bson.D{{Key: "$multiply", Value: bson.A{
+                        bson.D{{Key: "$subtract", Value: bson.A{bson.D{{Key: "$year", Value: firstStart}}, "$tahun_lulus"}}},
+                        12,
+                    }}},
+                    bson.D{{Key: "$subtract", Value: bson.A{bson.D{{Key: "$month", Value: firstStart}}, 1}}},
+                }}},
+            }}}},
+        }}},
+        {{Key: "$group", Value: bson.D{
+            {Key: "_id", Value: bson.D{{Key: "jurusan", Value: "$jurusan"}, {Key: "tahun_lulus", Value: "$tahun_lulus"}}},
+            {Key: "alumni", Value: bson.D{{Key: "$sum", Value: 1}}},
+            {Key: "employed", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$gt", Value: bson.A{"$jobs", 0}}}, 1, 0}}}}}},
+            {Key: "aktif", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{"$aktif", 1, 0}}}}}},
+            {Key: "pekerjaan", Value: bson.D{{Key: "$sum", Value: "$jobs"}}},
+            {Key: "changed", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$gt", Value: bson.A{"$jobs", 1}}}, 1, 0}}}}}},
+            {Key: "waiting_months", Value: bson.D{{Key: "$push", Value: "$waiting"}}},
+            {Key: "industries", Value: bson.D{{Key: "$push", Value: "$industries"}}},
+        }}},
+        {{Key: "$project", Value: bson.D{
+            {Key: "_id", Value: 0},
+            {Key: "jurusan", Value: "$_id.jurusan"},
+            {Key: "tahun_lulus", Value: "$_id.tahun_lulus"},
+            {Key: "alumni", Value: 1},
+            {Key: "employed", Value: 1},
+            {Key: "aktif", Value: 1},
+            {Key: "pekerjaan", Value: 1},
+            {Key: "changed", Value: 1},
+            // $push keeps the nulls of alumni without pekerjaan
+            {Key: "waiting_months", Value: bson.D{{Key: "$filter", Value: bson.D{
+                {Key: "input", Value: "$waiting_months"},
+                {Key: "cond", Value: bson.D{{Key: "$ne", Value: bson.A{"$$this", nil}}}},
+            }}}},
+            // One list per alumni, so industries count distinct alumni
+            {Key: "industries", Value: 1},
+        }}},
+        {{Key: "$sort", Value: bson.D{{Key: "jurusan", Value: 1}, {Key: "tahun_lulus", Value: 1}}}},
+    }
+
+    cursor, err := r.DB.Collection(alumniCollection).Aggregate(ctx, pipeline)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var rows []struct {
+        model.CohortAggregate `bson:",inline"`
+        Industries            [][]string `bson:"industries"`
+    }
+    if err = cursor.All(ctx, &rows); err != nil {
+        return nil, err
+    }
+
+    cohorts := make([]model.CohortAggregate, len(rows))
+    for i, row := range rows {
+        cohorts[i] = row.CohortAggregate
+        cohorts[i].Industries = model.CountIndustries(row.Industries)
+    }
+    return cohorts, nil
+}
+
+// GetPekerjaanFacts unwinds the joined pekerjaan of the alumni filter
+// selects, ordered per alumni by tanggal_mulai_kerja
+func (r *MongoStatsRepository) GetPekerjaanFacts(ctx context.Context, f model.CohortFilter) ([]model.PekerjaanFact, error) {
+    defer observe(ctx, "StatsRepository.GetPekerjaanFacts")()
+
+    project := bson.D{
+        {Key: "_id", Value: "$pekerjaan._id"},
+        {Key: "alumni_id", Value: "$_id"},
+        {Key: "jurusan", Value: 1},
+        {Key: "angkatan", Value: 1},
+        {Key: "tahun_lulus", Value: 1},
+    }
+    for _, field := range []string{"posisi_jabatan", "bidang_industri", "lokasi_kerja", "gaji_min", "gaji_max", "gaji_currency",
+        "gaji_period", "tanggal_mulai_kerja", "tanggal_selesai_kerja", "status_pekerjaan"} {
+        project = append(project, bson.E{Key: field, Value: "$pekerjaan." + field})
+    }
+    pipeline := mongo.Pipeline{
+        {{Key: "$match", Value: cohortMatch(f)}},
+        lookupPekerjaan,
+        {{Key: "$unwind", Value: "$pekerjaan"}},
+        {{Key: "$project", Value: project}},
+        {{Key: "$sort", Value: bson.D{{Key: "alumni_id", Value: 1}, {Key: "tanggal_mulai_kerja", Value: 1}, {Key: "_id", Value: 1}}}},
+    }
+
+    cursor, err := r.DB.Collection(alumniCollection).Aggregate(ctx, pipeline)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    facts := []model.PekerjaanFact{}
+    if err = cursor.All(ctx, &facts); err != nil {
+        return nil, err
+    }
+    return facts, nil
+}
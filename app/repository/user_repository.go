@@ -2,30 +2,28 @@ package repository
 
 import (
     "context"
-    "errors"
     "time"
     
     "go-fiber/app/model"
     
     "go.mongodb.org/mongo-driver/bson"
-    // "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/bson/primitive"
     "go.mongodb.org/mongo-driver/mongo"
     "go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const userCollection = "users"
 
-type UserRepository struct {
+type MongoUserRepository struct {
     DB *mongo.Database
 }
 
-func NewUserRepository(db *mongo.Database) *UserRepository {
-    return &UserRepository{DB: db}
+func NewMongoUserRepository(db *mongo.Database) *MongoUserRepository {
+    return &MongoUserRepository{DB: db}
 }
 
-func (r *UserRepository) FindUserByUsernameOrEmail(identifier string) (*model.User, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoUserRepository) FindUserByUsernameOrEmail(ctx context.Context, identifier string) (*model.User, error) {
+    defer observe(ctx, "UserRepository.FindUserByUsernameOrEmail")()
 
     collection := r.DB.Collection(userCollection)
     
@@ -40,7 +38,7 @@ func (r *UserRepository) FindUserByUsernameOrEmail(identifier string) (*model.Us
     err := collection.FindOne(ctx, filter).Decode(&user)
     if err != nil {
         if err == mongo.ErrNoDocuments {
-            return nil, errors.New("user not found")
+            return nil, ErrUserNotFound
         }
         return nil, err
     }
@@ -48,9 +46,8 @@ func (r *UserRepository) FindUserByUsernameOrEmail(identifier string) (*model.Us
     return &user, nil
 }
 
-func (r *UserRepository) GetUsers(search, sortBy, order string, limit, offset int) ([]model.User, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoUserRepository) GetUsers(ctx context.Context, search, sortBy, order string, limit, offset int) ([]model.User, error) {
+    defer observe(ctx, "UserRepository.GetUsers")()
 
     collection := r.DB.Collection(userCollection)
     
@@ -102,9 +99,8 @@ func (r *UserRepository) GetUsers(search, sortBy, order string, limit, offset in
     return users, nil
 }
 
-func (r *UserRepository) CountUsers(search string) (int, error) {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (r *MongoUserRepository) CountUsers(ctx context.Context, search string) (int, error) {
+    defer observe(ctx, "UserRepository.CountUsers")()
 
     collection := r.DB.Collection(userCollection)
     
@@ -124,22 +120,38 @@ func (r *UserRepository) CountUsers(search string) (int, error) {
     return int(count), nil
 }
 
-// Legacy functions for compatibility
-func FindUserByUsernameOrEmail(db *mongo.Database, identifier string) (*model.User, string, error) {
-    repo := NewUserRepository(db)
-    user, err := repo.FindUserByUsernameOrEmail(identifier)
+func (r *MongoUserRepository) CreateUser(ctx context.Context, user model.User) (*model.User, error) {
+    defer observe(ctx, "UserRepository.CreateUser")()
+
+    collection := r.DB.Collection(userCollection)
+
+    user.CreatedAt = time.Now()
+
+    result, err := collection.InsertOne(ctx, user)
     if err != nil {
-        return nil, "", err
+        if mongo.IsDuplicateKeyError(err) {
+            return nil, ErrUserExists
+        }
+        return nil, err
     }
-    return user, user.PasswordHash, nil
-}
 
-func GetUsersRepo(db *mongo.Database, search, sortBy, order string, limit, offset int) ([]model.User, error) {
-    repo := NewUserRepository(db)
-    return repo.GetUsers(search, sortBy, order, limit, offset)
+    user.ID = result.InsertedID.(primitive.ObjectID)
+    return &user, nil
 }
 
-func CountUsersRepo(db *mongo.Database, search string) (int, error) {
-    repo := NewUserRepository(db)
-    return repo.CountUsers(search)
-}
\ No newline at end of file
+func (r *MongoUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error {
+    defer observe(ctx, "UserRepository.UpdatePassword")()
+
+    collection := r.DB.Collection(userCollection)
+
+    result, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"password_hash": passwordHash}})
+    if err != nil {
+        return err
+    }
+
+    if result.MatchedCount == 0 {
+        return ErrUserNotFound
+    }
+
+    return nil
+}
@@ -1,17 +1,33 @@
 package service
 
 import (
+    "errors"
+    "fmt"
+    "log/slog"
     "strconv"
     "math"
+    "time"
 
     "github.com/gofiber/fiber/v2"
     "go-fiber/app/model"
     "go-fiber/app/repository"
     "go.mongodb.org/mongo-driver/bson/primitive"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func CreateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
+// AlumniService handles the alumni endpoints
+type AlumniService struct {
+    Repo repository.AlumniRepository
+    // Pekerjaan builds the career timeline of an alumni
+    Pekerjaan repository.PekerjaanRepository
+}
+
+func NewAlumniService(repo repository.AlumniRepository) *AlumniService {
+    return &AlumniService{Repo: repo}
+}
+
+func (s *AlumniService) CreateAlumni(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.CreateAlumni")()
+
     var req model.CreateAlumniRequest
     if err := c.BodyParser(&req); err != nil {
         return c.Status(400).JSON(fiber.Map{
@@ -45,8 +61,9 @@ func CreateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
         UserID:     userID,
     }
 
-    newAlumni, err := repository.CreateAlumni(db, alumni)
+    newAlumni, err := s.Repo.CreateAlumni(c.UserContext(), alumni)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "CreateAlumni failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menambahkan alumni: " + err.Error(),
             "success": false,
@@ -60,7 +77,9 @@ func CreateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func UpdateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *AlumniService) UpdateAlumni(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.UpdateAlumni")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -89,8 +108,9 @@ func UpdateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
         Alamat:     req.Alamat,
     }
 
-    updatedAlumni, err := repository.UpdateAlumni(db, id, alumni)
+    updatedAlumni, err := s.Repo.UpdateAlumni(c.UserContext(), id, alumni)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "UpdateAlumni failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal update alumni: " + err.Error(),
             "success": false,
@@ -104,7 +124,9 @@ func UpdateAlumniService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func DeleteAlumniService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *AlumniService) DeleteAlumni(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.DeleteAlumni")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -114,7 +136,8 @@ func DeleteAlumniService(c *fiber.Ctx, db *mongo.Database) error {
         })
     }
 
-    if err := repository.DeleteAlumni(db, id); err != nil {
+    if err := s.Repo.DeleteAlumni(c.UserContext(), id); err != nil {
+        slog.ErrorContext(c.UserContext(), "DeleteAlumni failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menghapus alumni: " + err.Error(),
             "success": false,
@@ -127,7 +150,9 @@ func DeleteAlumniService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func GetAllAlumniServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
+func (s *AlumniService) GetAllAlumniDatatable(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.GetAllAlumniDatatable")()
+
     page, _ := strconv.Atoi(c.Query("page", "1"))
     limit, _ := strconv.Atoi(c.Query("limit", "10"))
     sortBy := c.Query("sortBy", "_id")
@@ -139,16 +164,18 @@ func GetAllAlumniServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
     }
     offset := (page - 1) * limit
 
-    alumniList, err := repository.GetAlumniRepo(db, search, sortBy, order, limit, offset)
+    alumniList, err := s.Repo.GetAlumni(c.UserContext(), search, sortBy, order, limit, offset)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetAlumni failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal mendapatkan data alumni: " + err.Error(),
             "success": false,
         })
     }
 
-    total, err := repository.CountAlumniRepo(db, search)
+    total, err := s.Repo.CountAlumni(c.UserContext(), search)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountAlumni failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menghitung total alumni: " + err.Error(),
             "success": false,
@@ -178,9 +205,12 @@ func GetAllAlumniServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func GetAlumniStatsService(c *fiber.Ctx, db *mongo.Database) error {
-    stats, err := repository.GetAlumniStatsByJurusan(db)
+func (s *AlumniService) GetAlumniStats(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.GetAlumniStats")()
+
+    stats, err := s.Repo.GetAlumniStatsByJurusan(c.UserContext())
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetAlumniStatsByJurusan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal mendapatkan statistik: " + err.Error(),
             "success": false,
@@ -192,4 +222,167 @@ func GetAlumniStatsService(c *fiber.Ctx, db *mongo.Database) error {
         "success": true,
         "data":    stats,
     })
-}
\ No newline at end of file
+}
+
+// GetAlumniTimeline returns the career history of an alumni: every pekerjaan
+// in order of tanggal_mulai_kerja with the gaps between them. Users only see
+// the alumni linked to their account.
+func (s *AlumniService) GetAlumniTimeline(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.GetAlumniTimeline")()
+
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    var userID primitive.ObjectID
+    switch v := c.Locals("user_id").(type) {
+    case primitive.ObjectID:
+        userID = v
+    case string:
+        userID, _ = primitive.ObjectIDFromHex(v)
+    }
+    isAdmin := c.Locals("role") == "admin"
+
+    alumni, err := s.Repo.FindAlumniByID(c.UserContext(), id)
+    if err == nil && !isAdmin && (userID.IsZero() || alumni.UserID != userID) {
+        err = repository.ErrNotFoundOrNoAccess
+    }
+    if errors.Is(err, repository.ErrAlumniNotFound) || errors.Is(err, repository.ErrNotFoundOrNoAccess) {
+        return c.Status(404).JSON(fiber.Map{
+            "message": repository.ErrNotFoundOrNoAccess.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindAlumniByID failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan alumni: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    list, err := s.Pekerjaan.FindPekerjaanByAlumniID(c.UserContext(), id)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindPekerjaanByAlumniID failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan data pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan riwayat karier alumni",
+        "success": true,
+        "data":    alumni.ToAlumniTimelineResponse(list, time.Now()),
+    })
+}
+
+// BulkAlumni runs up to model.MaxBulkOperations create, update and delete
+// operations as one bulk write, with the checks of the single-item
+// endpoints and a result per operation. An ordered batch stops at the first
+// failure.
+func (s *AlumniService) BulkAlumni(c *fiber.Ctx) error {
+    defer traceCall(c, "AlumniService.BulkAlumni")()
+
+    var req model.AlumniBulkRequest
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+    ordered, ok, err := bulkOrdered(c, len(req.Operations), req.Ordered)
+    if !ok {
+        return err
+    }
+
+    results := make([]model.BulkItemResult, len(req.Operations))
+    writes := []model.AlumniWrite{}
+    indexes := []int{}
+    stopped := false
+    for i, op := range req.Operations {
+        results[i] = model.BulkItemResult{Index: i, Op: op.Op, ID: op.ID}
+        if stopped {
+            continue
+        }
+
+        w, status, err := alumniBulkWrite(op)
+        results[i].Status = status
+        if err != nil {
+            results[i].Result = model.BulkFailed
+            results[i].Error = err.Error()
+            stopped = ordered
+            continue
+        }
+        results[i].ID = w.ID.Hex()
+        writes = append(writes, w)
+        indexes = append(indexes, i)
+    }
+
+    errs, err := s.Repo.BulkWriteAlumni(c.UserContext(), writes, ordered)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "BulkWriteAlumni failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menjalankan operasi alumni: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return bulkReply(c, results, indexes, errs, ordered)
+}
+
+// alumniBulkWrite checks one operation of BulkAlumni like its single-item
+// endpoint, returning the status that endpoint would reply
+func alumniBulkWrite(op model.AlumniBulkOperation) (model.AlumniWrite, int, error) {
+    w := model.AlumniWrite{Op: op.Op}
+    switch op.Op {
+    case model.BulkCreate:
+        if op.Alumni == nil {
+            return w, 400, errors.New("alumni wajib diisi")
+        }
+        var userID primitive.ObjectID
+        if op.Alumni.UserID != "" {
+            var err error
+            if userID, err = primitive.ObjectIDFromHex(op.Alumni.UserID); err != nil {
+                return w, 400, errors.New("User ID tidak valid")
+            }
+        }
+        w.ID = primitive.NewObjectID()
+        w.Alumni = alumniFromRequest(*op.Alumni)
+        w.Alumni.UserID = userID
+        return w, 201, nil
+    case model.BulkUpdate, model.BulkDelete:
+        id, err := primitive.ObjectIDFromHex(op.ID)
+        if err != nil {
+            return w, 400, errors.New("ID tidak valid")
+        }
+        w.ID = id
+        if op.Op == model.BulkDelete {
+            return w, 200, nil
+        }
+        if op.Alumni == nil {
+            return w, 400, errors.New("alumni wajib diisi")
+        }
+        w.Alumni = alumniFromRequest(*op.Alumni)
+        return w, 200, nil
+    }
+    return w, 400, fmt.Errorf("op %q tidak dikenal", op.Op)
+}
+
+// alumniFromRequest copies the fields every alumni write sets
+func alumniFromRequest(req model.CreateAlumniRequest) model.Alumni {
+    return model.Alumni{
+        NIM:        req.NIM,
+        Nama:       req.Nama,
+        Jurusan:    req.Jurusan,
+        Angkatan:   req.Angkatan,
+        TahunLulus: req.TahunLulus,
+        Email:      req.Email,
+        NoTelepon:  req.NoTelepon,
+        Alamat:     req.Alamat,
+    }
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/cache"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateAlumni(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("POST", "/alumni", s.CreateAlumni, adminCaller)
+
+    userID := primitive.NewObjectID()
+    resp := do(t, app, "POST", "/alumni", model.CreateAlumniRequest{
+        NIM:        "1234567001",
+        Nama:       "John Doe",
+        Jurusan:    "Teknik Informatika",
+        Angkatan:   2018,
+        TahunLulus: 2022,
+        Email:      "john.doe@university.ac.id",
+        NoTelepon:  "081234567001",
+        UserID:     userID.Hex(),
+    })
+    if resp.Status != 201 || !resp.Success {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    var created model.AlumniResponse
+    decodeData(t, resp, &created)
+    if created.ID == "" || created.Nama != "John Doe" || created.UserID != userID.Hex() {
+        t.Errorf("unexpected alumni %+v", created)
+    }
+
+    total, _ := repos.Alumni.CountAlumni(context.Background(), "")
+    if total != 1 {
+        t.Errorf("stored %d alumni, want 1", total)
+    }
+}
+
+func TestCreateAlumniInvalidInput(t *testing.T) {
+    s := NewAlumniService(repository.NewMemoryRepositories().Alumni)
+    app := newTestApp("POST", "/alumni", s.CreateAlumni, adminCaller)
+
+    if resp := do(t, app, "POST", "/alumni", "not an object"); resp.Status != 400 {
+        t.Errorf("bad body: status = %d, want 400", resp.Status)
+    }
+    if resp := do(t, app, "POST", "/alumni", model.CreateAlumniRequest{UserID: "xyz"}); resp.Status != 400 {
+        t.Errorf("bad user id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestUpdateAlumni(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("PUT", "/alumni/:id", s.UpdateAlumni, adminCaller)
+
+    a := newAlumni(t, repos, "1234567001", "John Doe", "Teknik Informatika", primitive.NilObjectID)
+
+    resp := do(t, app, "PUT", "/alumni/"+a.ID.Hex(), model.UpdateAlumniRequest{
+        NIM:        a.NIM,
+        Nama:       "John Updated",
+        Jurusan:    "Sistem Informasi",
+        Angkatan:   2018,
+        TahunLulus: 2022,
+        Email:      a.Email,
+        NoTelepon:  a.NoTelepon,
+    })
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    list, _ := repos.Alumni.GetAlumni(context.Background(), "", "_id", "asc", 10, 0)
+    if len(list) != 1 || list[0].Nama != "John Updated" || list[0].Jurusan != "Sistem Informasi" {
+        t.Errorf("alumni not updated: %+v", list)
+    }
+
+    if resp := do(t, app, "PUT", "/alumni/not-an-id", model.UpdateAlumniRequest{}); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestDeleteAlumni(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("DELETE", "/alumni/:id", s.DeleteAlumni, adminCaller)
+
+    a := newAlumni(t, repos, "1234567001", "John Doe", "Teknik Informatika", primitive.NilObjectID)
+
+    if resp := do(t, app, "DELETE", "/alumni/"+a.ID.Hex(), nil); resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+    if total, _ := repos.Alumni.CountAlumni(context.Background(), ""); total != 0 {
+        t.Errorf("alumni not deleted, %d left", total)
+    }
+    if resp := do(t, app, "DELETE", "/alumni/bad", nil); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestGetAllAlumniDatatable(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("GET", "/alumni", s.GetAllAlumniDatatable, adminCaller)
+
+    newAlumni(t, repos, "1234567001", "Budi", "Teknik Informatika", primitive.NilObjectID)
+    newAlumni(t, repos, "1234567002", "Andi", "Sistem Informasi", primitive.NilObjectID)
+    newAlumni(t, repos, "1234567003", "Citra", "Teknik Informatika", primitive.NilObjectID)
+
+    resp := do(t, app, "GET", "/alumni?page=1&limit=2&sortBy=nama&order=asc", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var list []model.AlumniResponse
+    decodeData(t, resp, &list)
+    if len(list) != 2 || list[0].Nama != "Andi" || list[1].Nama != "Budi" {
+        t.Errorf("unexpected page %+v", list)
+    }
+    if resp.Meta.Total != 3 || resp.Meta.Pages != 2 {
+        t.Errorf("unexpected meta %+v", resp.Meta)
+    }
+
+    resp = do(t, app, "GET", "/alumni?search=citra", nil)
+    decodeData(t, resp, &list)
+    if len(list) != 1 || list[0].Nama != "Citra" || resp.Meta.Total != 1 {
+        t.Errorf("search: got %+v, meta %+v", list, resp.Meta)
+    }
+}
+
+func TestGetAlumniStats(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("GET", "/alumni/stats/jurusan", s.GetAlumniStats, adminCaller)
+
+    newAlumni(t, repos, "1234567001", "Budi", "Teknik Informatika", primitive.NilObjectID)
+    newAlumni(t, repos, "1234567002", "Andi", "Sistem Informasi", primitive.NilObjectID)
+    newAlumni(t, repos, "1234567003", "Citra", "Teknik Informatika", primitive.NilObjectID)
+
+    resp := do(t, app, "GET", "/alumni/stats/jurusan", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var stats []model.AlumniStatsByJurusanResponse
+    decodeData(t, resp, &stats)
+    want := []model.AlumniStatsByJurusanResponse{
+        {Jurusan: "Teknik Informatika", Total: 2},
+        {Jurusan: "Sistem Informasi", Total: 1},
+    }
+    if len(stats) != len(want) || stats[0] != want[0] || stats[1] != want[1] {
+        t.Errorf("stats = %+v, want %+v", stats, want)
+    }
+}
+
+func TestGetAlumniStatsCachedUntilWrite(t *testing.T) {
+    repos := repository.NewCachedRepositories(repository.NewMemoryRepositories(), cache.New(cache.NewLRU(100), time.Minute))
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("GET", "/alumni/stats/jurusan", s.GetAlumniStats, adminCaller)
+    stats := func() []model.AlumniStatsByJurusanResponse {
+        var stats []model.AlumniStatsByJurusanResponse
+        decodeData(t, do(t, app, "GET", "/alumni/stats/jurusan", nil), &stats)
+        return stats
+    }
+
+    newAlumni(t, repos, "1234567001", "Budi", "Teknik Informatika", primitive.NilObjectID)
+    if got := stats(); len(got) != 1 || got[0].Total != 1 {
+        t.Fatalf("stats = %+v", got)
+    }
+
+    // Writes through the cached repository invalidate the stats
+    newAlumni(t, repos, "1234567002", "Citra", "Teknik Informatika", primitive.NilObjectID)
+    if got := stats(); len(got) != 1 || got[0].Total != 2 {
+        t.Errorf("stats after create = %+v, want the new alumni counted", got)
+    }
+}
+
+func TestGetAlumniTimeline(t *testing.T) {
+    repos := repository.NewMemoryRepositories()
+    s := NewAlumniService(repos.Alumni)
+    s.Pekerjaan = repos.Pekerjaan
+
+    owner := caller{userID: primitive.NewObjectID(), role: "user"}
+    alumni := newAlumni(t, repos, "1234567001", "John Doe", "Teknik Informatika", owner.userID)
+    current := newPekerjaan(t, repos, alumni.ID, "PT Digital Indonesia")
+    ended := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+    past, err := repos.Pekerjaan.CreatePekerjaan(context.Background(), model.Pekerjaan{
+        AlumniID:            alumni.ID,
+        NamaPerusahaan:      "PT Startup Indonesia",
+        TanggalMulaiKerja:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+        TanggalSelesaiKerja: &ended,
+        StatusPekerjaan:     model.StatusResign,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    path := "/alumni/" + alumni.ID.Hex() + "/timeline"
+    for _, as := range []caller{adminCaller, owner} {
+        resp := do(t, newTestApp("GET", "/alumni/:id/timeline", s.GetAlumniTimeline, as), "GET", path, nil)
+        if resp.Status != 200 {
+            t.Fatalf("%s: status = %d, body = %+v", as.role, resp.Status, resp)
+        }
+
+        var timeline model.AlumniTimelineResponse
+        decodeData(t, resp, &timeline)
+        types := []string{}
+        for _, entry := range timeline.Entries {
+            types = append(types, entry.Type)
+        }
+        if len(timeline.Entries) != 3 || timeline.Entries[0].Pekerjaan.ID != past.ID.Hex() ||
+            timeline.Entries[1].Type != model.TimelineGap || timeline.Entries[2].Pekerjaan.ID != current.ID.Hex() {
+            t.Errorf("%s: entries %v, want past job, gap, current job", as.role, types)
+        }
+    }
+
+    stranger := caller{userID: primitive.NewObjectID(), role: "user"}
+    app := newTestApp("GET", "/alumni/:id/timeline", s.GetAlumniTimeline, stranger)
+    if resp := do(t, app, "GET", path, nil); resp.Status != 404 {
+        t.Errorf("stranger: status = %d, want 404", resp.Status)
+    }
+    app = newTestApp("GET", "/alumni/:id/timeline", s.GetAlumniTimeline, adminCaller)
+    if resp := do(t, app, "GET", "/alumni/"+primitive.NewObjectID().Hex()+"/timeline", nil); resp.Status != 404 {
+        t.Errorf("unknown alumni: status = %d, want 404", resp.Status)
+    }
+    if resp := do(t, app, "GET", "/alumni/xyz/timeline", nil); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestBulkAlumni(t *testing.T) {
+    repos := repository.NewCachedRepositories(repository.NewMemoryRepositories(), cache.New(cache.NewLRU(100), time.Minute))
+    s := NewAlumniService(repos.Alumni)
+    app := newTestApp("POST", "/alumni/bulk", s.BulkAlumni, adminCaller)
+    ctx := context.Background()
+
+    budi := newAlumni(t, repos, "1234567001", "Budi", "Teknik Informatika", primitive.NilObjectID)
+    citra := newAlumni(t, repos, "1234567002", "Citra", "Tehnik Informatika", primitive.NilObjectID)
+    dewi := newAlumni(t, repos, "1234567003", "Dewi", "Sistem Informasi", primitive.NilObjectID)
+    if stats, _ := repos.Alumni.GetAlumniStatsByJurusan(ctx); len(stats) != 3 {
+        t.Fatalf("stats = %+v", stats)
+    }
+
+    // Correct a misspelt jurusan, add and remove alumni in one request
+    fixed := model.CreateAlumniRequest{
+        NIM:        citra.NIM,
+        Nama:       citra.Nama,
+        Jurusan:    "Teknik Informatika",
+        Angkatan:   citra.Angkatan,
+        TahunLulus: citra.TahunLulus,
+        Email:      citra.Email,
+        NoTelepon:  citra.NoTelepon,
+    }
+    eko := fixed
+    eko.NIM, eko.Nama, eko.Email = "1234567004", "Eko", "eko@university.ac.id"
+    bad := eko
+    bad.UserID = "not-an-id"
+    ordered := false
+    resp := do(t, app, "POST", "/alumni/bulk", model.AlumniBulkRequest{
+        Ordered: &ordered,
+        Operations: []model.AlumniBulkOperation{
+            {Op: model.BulkUpdate, ID: citra.ID.Hex(), Alumni: &fixed},
+            {Op: model.BulkCreate, Alumni: &bad},
+            {Op: model.BulkCreate, Alumni: &eko},
+            {Op: model.BulkDelete, ID: dewi.ID.Hex()},
+            {Op: model.BulkSoftDelete, ID: budi.ID.Hex()},
+            {Op: model.BulkUpdate, ID: budi.ID.Hex()},
+        },
+    })
+    if resp.Status != 200 || resp.Success {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var result model.BulkResponse
+    decodeData(t, resp, &result)
+    want := []string{"ok 200", "failed 400", "ok 201", "ok 200", "failed 400", "failed 400"}
+    for i, r := range result.Results {
+        if got := fmt.Sprintf("%s %d", r.Result, r.Status); i >= len(want) || got != want[i] {
+            t.Errorf("result %d = %q, want %q", i, got, want[i])
+        }
+    }
+
+    // The cached stats see the writes
+    stats, _ := repos.Alumni.GetAlumniStatsByJurusan(ctx)
+    if len(stats) != 1 || stats[0].Jurusan != "Teknik Informatika" || stats[0].Total != 3 {
+        t.Errorf("stats after bulk = %+v", stats)
+    }
+    createdID, _ := primitive.ObjectIDFromHex(result.Results[2].ID)
+    if created, err := repos.Alumni.FindAlumniByID(ctx, createdID); err != nil || created.Nama != "Eko" {
+        t.Errorf("created = %+v, err %v", created, err)
+    }
+}
@@ -1,107 +1,137 @@
 package service
 
 import (
+    "log/slog"
+    "context"
     "errors"
     "strconv"
     "strings"
     
     "go-fiber/app/model"
     "go-fiber/app/repository"
+    "go-fiber/metrics"
+    "go-fiber/tracing"
     "go-fiber/utils"
     
     "github.com/gofiber/fiber/v2"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func LoginService(db *mongo.Database, req model.LoginRequest) (*model.LoginResponse, error) {
-    user, passwordHash, err := repository.FindUserByUsernameOrEmail(db, req.Username)
+// AuthService handles login and user listing
+type AuthService struct {
+    Repo repository.UserRepository
+}
+
+func NewAuthService(repo repository.UserRepository) *AuthService {
+    return &AuthService{Repo: repo}
+}
+
+func (s *AuthService) Login(ctx context.Context, req model.LoginRequest) (*model.LoginResponse, error) {
+    ctx, span := tracing.Start(ctx, "AuthService.Login")
+    defer span.End()
+
+    user, err := s.Repo.FindUserByUsernameOrEmail(ctx, req.Username)
     if err != nil {
+        slog.WarnContext(ctx, "login failed", "identifier", req.Username, "reason", err.Error())
+        if errors.Is(err, repository.ErrUserNotFound) {
+            metrics.Logins.WithLabelValues("unknown_user").Inc()
+        } else {
+            metrics.Logins.WithLabelValues("error").Inc()
+        }
         return nil, errors.New("username atau password salah")
     }
 
-    if !utils.CheckPassword(req.Password, passwordHash) {
+    if !utils.CheckPassword(req.Password, user.PasswordHash) {
+        slog.WarnContext(ctx, "login failed", "identifier", req.Username, "reason", "wrong password")
+        metrics.Logins.WithLabelValues("wrong_password").Inc()
         return nil, errors.New("username atau password salah")
     }
 
     token, err := utils.GenerateToken(*user)
     if err != nil {
+        slog.ErrorContext(ctx, "GenerateToken failed", "error", err)
+        metrics.Logins.WithLabelValues("error").Inc()
         return nil, errors.New("gagal generate token")
     }
 
+    slog.InfoContext(ctx, "login", "user_id", user.ID.Hex(), "role", user.Role)
+    metrics.Logins.WithLabelValues("success").Inc()
+
     return &model.LoginResponse{
         User:  user.ToUserResponse(),
         Token: token,
     }, nil
 }
 
-func GetUsersService(db *mongo.Database) fiber.Handler {
-    return func(c *fiber.Ctx) error {
-        page, _ := strconv.Atoi(c.Query("page", "1"))
-        limit, _ := strconv.Atoi(c.Query("limit", "10"))
-        sortBy := c.Query("sortBy", "id")
-        order := c.Query("order", "asc")
-        search := c.Query("search", "")
-        
-        if page < 1 {
-            page = 1
-        }
-        offset := (page - 1) * limit
-
-        sortByWhitelist := map[string]string{
-            "id":         "id",
-            "username":   "username",
-            "email":      "email",
-            "role":       "role",
-            "created_at": "created_at",
-        }
-        col, ok := sortByWhitelist[sortBy]
-        if !ok {
-            col = "id"
-        }
+func (s *AuthService) GetUsers(c *fiber.Ctx) error {
+    defer traceCall(c, "AuthService.GetUsers")()
 
-        ord := "ASC"
-        if strings.ToLower(order) == "desc" {
-            ord = "DESC"
-        }
+    page, _ := strconv.Atoi(c.Query("page", "1"))
+    limit, _ := strconv.Atoi(c.Query("limit", "10"))
+    sortBy := c.Query("sortBy", "id")
+    order := c.Query("order", "asc")
+    search := c.Query("search", "")
+    
+    if page < 1 {
+        page = 1
+    }
+    offset := (page - 1) * limit
 
-        users, err := repository.GetUsersRepo(db, search, col, ord, limit, offset)
-        if err != nil {
-            return c.Status(500).JSON(fiber.Map{
-                "error":   "Failed to fetch users",
-                "success": false,
-            })
-        }
+    sortByWhitelist := map[string]string{
+        "id":         "id",
+        "username":   "username",
+        "email":      "email",
+        "role":       "role",
+        "created_at": "created_at",
+    }
+    col, ok := sortByWhitelist[sortBy]
+    if !ok {
+        col = "id"
+    }
 
-        total, err := repository.CountUsersRepo(db, search)
-        if err != nil {
-            return c.Status(500).JSON(fiber.Map{
-                "error":   "Failed to count users",
-                "success": false,
-            })
-        }
+    ord := "ASC"
+    if strings.ToLower(order) == "desc" {
+        ord = "DESC"
+    }
 
-        pages := 0
-        if total > 0 {
-            pages = (total + limit - 1) / limit
-        }
+    users, err := s.Repo.GetUsers(c.UserContext(), search, col, ord, limit, offset)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetUsers failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "error":   "Failed to fetch users",
+            "success": false,
+        })
+    }
 
-        responses := make([]model.UserResponse, len(users))
-        for i, user := range users {
-            responses[i] = user.ToUserResponse()
-        }
+    total, err := s.Repo.CountUsers(c.UserContext(), search)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountUsers failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "error":   "Failed to count users",
+            "success": false,
+        })
+    }
 
-        response := model.UserListResponse{
-            Data: responses,
-            Meta: model.MetaInfo{
-                Page:   page,
-                Limit:  limit,
-                Total:  total,
-                Pages:  pages,
-                SortBy: col,
-                Order:  ord,
-                Search: search,
-            },
-        }
-        return c.JSON(response)
+    pages := 0
+    if total > 0 {
+        pages = (total + limit - 1) / limit
+    }
+
+    responses := make([]model.UserResponse, len(users))
+    for i, user := range users {
+        responses[i] = user.ToUserResponse()
+    }
+
+    response := model.UserListResponse{
+        Data: responses,
+        Meta: model.MetaInfo{
+            Page:   page,
+            Limit:  limit,
+            Total:  total,
+            Pages:  pages,
+            SortBy: col,
+            Order:  ord,
+            Search: search,
+        },
     }
+    return c.JSON(response)
 }
\ No newline at end of file
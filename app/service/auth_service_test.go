@@ -0,0 +1,90 @@
+package service
+
+import (
+    "context"
+    "testing"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/config"
+    "go-fiber/utils"
+)
+
+func newAuthFixture(t *testing.T) (*repository.MemoryStore, *AuthService) {
+    cfg := config.Defaults()
+    cfg.JWT.Secret = "test-secret"
+    config.Set(cfg)
+    t.Cleanup(func() { config.Set(config.Defaults()) })
+
+    store := repository.NewMemoryStore()
+    for _, u := range []struct{ username, email, role string }{
+        {"admin", "admin@university.ac.id", "admin"},
+        {"johndoe", "john.doe@university.ac.id", "user"},
+        {"janesmith", "jane.smith@university.ac.id", "user"},
+    } {
+        hash, err := utils.HashPassword(u.username + "123")
+        if err != nil {
+            t.Fatal(err)
+        }
+        store.AddUser(model.User{Username: u.username, Email: u.email, Role: u.role, PasswordHash: hash})
+    }
+
+    return store, NewAuthService(store.Repositories().User)
+}
+
+func TestLogin(t *testing.T) {
+    _, s := newAuthFixture(t)
+
+    for _, identifier := range []string{"johndoe", "john.doe@university.ac.id"} {
+        resp, err := s.Login(context.Background(), model.LoginRequest{Username: identifier, Password: "johndoe123"})
+        if err != nil {
+            t.Fatalf("login %s: %v", identifier, err)
+        }
+        if resp.User.Username != "johndoe" || resp.Token == "" {
+            t.Errorf("unexpected response %+v", resp)
+        }
+
+        claims, err := utils.ParseToken(resp.Token)
+        if err != nil || claims.Role != "user" || claims.UserID != resp.User.ID {
+            t.Errorf("token claims = %+v, err = %v", claims, err)
+        }
+    }
+}
+
+func TestLoginRejectsBadCredentials(t *testing.T) {
+    _, s := newAuthFixture(t)
+
+    for _, req := range []model.LoginRequest{
+        {Username: "johndoe", Password: "wrong"},
+        {Username: "nobody", Password: "johndoe123"},
+    } {
+        if _, err := s.Login(context.Background(), req); err == nil || err.Error() != "username atau password salah" {
+            t.Errorf("login %+v: err = %v", req, err)
+        }
+    }
+}
+
+func TestGetUsers(t *testing.T) {
+    _, s := newAuthFixture(t)
+    app := newTestApp("GET", "/users", s.GetUsers, adminCaller)
+
+    resp := do(t, app, "GET", "/users?sortBy=username&order=desc&limit=2", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var users []model.UserResponse
+    decodeData(t, resp, &users)
+    if len(users) != 2 || users[0].Username != "johndoe" || users[1].Username != "janesmith" {
+        t.Errorf("unexpected users %+v", users)
+    }
+    if resp.Meta.Total != 3 || resp.Meta.Pages != 2 || resp.Meta.Order != "DESC" {
+        t.Errorf("unexpected meta %+v", resp.Meta)
+    }
+
+    resp = do(t, app, "GET", "/users?search=ADMIN", nil)
+    decodeData(t, resp, &users)
+    if len(users) != 1 || users[0].Role != "admin" {
+        t.Errorf("search: %+v", users)
+    }
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "math"
+    "strconv"
+    "strings"
+
+    "github.com/gofiber/fiber/v2"
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CompanyService handles the companies registry endpoints
+type CompanyService struct {
+    Repo repository.CompanyRepository
+}
+
+func NewCompanyService(repo repository.CompanyRepository) *CompanyService {
+    return &CompanyService{Repo: repo}
+}
+
+// nameConflict returns the company other than company.ID already using one
+// of company's names or aliases. The unique index only covers the canonical
+// name, so aliases are checked here.
+func (s *CompanyService) nameConflict(ctx context.Context, company model.Company) (*model.Company, error) {
+    all, err := s.Repo.AllCompanies(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    keys := map[string]bool{}
+    for _, key := range company.Keys() {
+        keys[key] = true
+    }
+    for i := range all {
+        if all[i].ID == company.ID {
+            continue
+        }
+        for _, key := range all[i].Keys() {
+            if keys[key] {
+                return &all[i], nil
+            }
+        }
+    }
+    return nil, nil
+}
+
+// saveCompany checks the names of company and writes it with save, replying
+// with the error when it fails. ok is false when a reply was sent.
+func (s *CompanyService) saveCompany(c *fiber.Ctx, company model.Company, save func() (*model.Company, error)) (*model.Company, bool, error) {
+    if company.NamaKey == "" {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": "Nama perusahaan wajib diisi",
+            "success": false,
+        })
+    }
+
+    conflict, err := s.nameConflict(c.UserContext(), company)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "AllCompanies failed", "error", err)
+        return nil, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal memeriksa nama perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+    if conflict != nil {
+        return nil, false, c.Status(409).JSON(fiber.Map{
+            "message": "Nama atau alias sudah dipakai oleh perusahaan " + conflict.Nama,
+            "success": false,
+        })
+    }
+
+    saved, err := save()
+    switch {
+    case errors.Is(err, repository.ErrCompanyNotFound):
+        return nil, false, c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    case errors.Is(err, repository.ErrCompanyExists):
+        return nil, false, c.Status(409).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    case err != nil:
+        slog.ErrorContext(c.UserContext(), "saving company failed", "error", err)
+        return nil, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menyimpan perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+    return saved, true, nil
+}
+
+func (s *CompanyService) CreateCompany(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.CreateCompany")()
+
+    var req model.CreateCompanyRequest
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    company := model.Company{
+        BidangIndustri: strings.TrimSpace(req.BidangIndustri),
+        Lokasi:         strings.TrimSpace(req.Lokasi),
+    }
+    company.SetNames(req.Nama, req.Aliases)
+
+    created, ok, err := s.saveCompany(c, company, func() (*model.Company, error) {
+        return s.Repo.CreateCompany(c.UserContext(), company)
+    })
+    if !ok {
+        return err
+    }
+
+    return c.Status(201).JSON(fiber.Map{
+        "message": "Perusahaan berhasil ditambahkan",
+        "success": true,
+        "data":    created.ToCompanyResponse(),
+    })
+}
+
+func (s *CompanyService) UpdateCompany(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.UpdateCompany")()
+
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    var req model.UpdateCompanyRequest
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    company := model.Company{
+        ID:             id,
+        BidangIndustri: strings.TrimSpace(req.BidangIndustri),
+        Lokasi:         strings.TrimSpace(req.Lokasi),
+    }
+    company.SetNames(req.Nama, req.Aliases)
+
+    updated, ok, err := s.saveCompany(c, company, func() (*model.Company, error) {
+        return s.Repo.UpdateCompany(c.UserContext(), id, company)
+    })
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Perusahaan berhasil diupdate",
+        "success": true,
+        "data":    updated.ToCompanyResponse(),
+    })
+}
+
+func (s *CompanyService) DeleteCompany(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.DeleteCompany")()
+
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    err = s.Repo.DeleteCompany(c.UserContext(), id)
+    if errors.Is(err, repository.ErrCompanyNotFound) {
+        return c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "DeleteCompany failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghapus perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Perusahaan berhasil dihapus",
+        "success": true,
+    })
+}
+
+// findCompany reads the :id parameter and loads the company, replying with
+// the error when it fails
+func (s *CompanyService) findCompany(c *fiber.Ctx) (*model.Company, error) {
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return nil, c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    company, err := s.Repo.FindCompanyByID(c.UserContext(), id)
+    if errors.Is(err, repository.ErrCompanyNotFound) {
+        return nil, c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindCompanyByID failed", "error", err)
+        return nil, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+    return company, nil
+}
+
+func (s *CompanyService) GetCompany(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.GetCompany")()
+
+    company, err := s.findCompany(c)
+    if company == nil {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan data perusahaan",
+        "success": true,
+        "data":    company.ToCompanyResponse(),
+    })
+}
+
+func (s *CompanyService) GetAllCompaniesDatatable(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.GetAllCompaniesDatatable")()
+
+    page, _ := strconv.Atoi(c.Query("page", "1"))
+    limit, _ := strconv.Atoi(c.Query("limit", "10"))
+    sortBy := c.Query("sortBy", "nama")
+    order := c.Query("order", "asc")
+    search := c.Query("search", "")
+
+    if page < 1 {
+        page = 1
+    }
+    offset := (page - 1) * limit
+
+    list, err := s.Repo.GetCompanies(c.UserContext(), search, sortBy, order, limit, offset)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetCompanies failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan data perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    total, err := s.Repo.CountCompanies(c.UserContext(), search)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountCompanies failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghitung total perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    responses := make([]model.CompanyResponse, len(list))
+    for i, company := range list {
+        responses[i] = company.ToCompanyResponse()
+    }
+
+    meta := model.MetaInfo{
+        Page:   page,
+        Limit:  limit,
+        Total:  total,
+        Pages:  int(math.Ceil(float64(total) / float64(limit))),
+        SortBy: sortBy,
+        Order:  order,
+        Search: search,
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan data perusahaan",
+        "success": true,
+        "data":    responses,
+        "meta":    meta,
+    })
+}
+
+// SuggestCompanies lists the registered companies resembling ?nama=, best
+// match first
+func (s *CompanyService) SuggestCompanies(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.SuggestCompanies")()
+
+    nama := strings.TrimSpace(c.Query("nama"))
+    if nama == "" {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Parameter nama wajib diisi",
+            "success": false,
+        })
+    }
+
+    all, err := s.Repo.AllCompanies(c.UserContext())
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "AllCompanies failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mencari perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan saran perusahaan",
+        "success": true,
+        "data":    model.MatchCompanies(all, nama, 5),
+    })
+}
+
+// GetCompanyAlumni lists everyone with an active pekerjaan at the company
+func (s *CompanyService) GetCompanyAlumni(c *fiber.Ctx) error {
+    defer traceCall(c, "CompanyService.GetCompanyAlumni")()
+
+    company, err := s.findCompany(c)
+    if company == nil {
+        return err
+    }
+
+    list, err := s.Repo.GetCompanyAlumni(c.UserContext(), company.ID)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetCompanyAlumni failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan alumni perusahaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    responses := make([]model.CompanyAlumniResponse, len(list))
+    for i, alumni := range list {
+        responses[i] = alumni.ToCompanyAlumniResponse()
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan alumni yang pernah bekerja di " + company.Nama,
+        "success": true,
+        "data":    responses,
+    })
+}
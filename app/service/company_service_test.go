@@ -0,0 +1,191 @@
+package service
+
+import (
+    "context"
+    "testing"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type companyFixture struct {
+    repos   repository.Repositories
+    service *CompanyService
+    company model.Company
+}
+
+func newCompanyFixture(t *testing.T) companyFixture {
+    repos := repository.NewMemoryRepositories()
+    f := companyFixture{repos: repos, service: NewCompanyService(repos.Company)}
+    f.company = newCompany(t, repos, "PT Digital Indonesia", "DigiIndo")
+    return f
+}
+
+func newCompany(t *testing.T, repos repository.Repositories, nama string, aliases ...string) model.Company {
+    t.Helper()
+    company := model.Company{BidangIndustri: "Technology", Lokasi: "Jakarta"}
+    company.SetNames(nama, aliases)
+    created, err := repos.Company.CreateCompany(context.Background(), company)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return *created
+}
+
+func TestCreateCompany(t *testing.T) {
+    f := newCompanyFixture(t)
+    app := newTestApp("POST", "/companies", f.service.CreateCompany, adminCaller)
+
+    resp := do(t, app, "POST", "/companies", model.CreateCompanyRequest{
+        Nama:    "PT Media Online",
+        Aliases: []string{"Media Online", "MediaOn"},
+        Lokasi:  "Bandung",
+    })
+    if resp.Status != 201 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var created model.CompanyResponse
+    decodeData(t, resp, &created)
+    // "Media Online" is the canonical name once normalized
+    if created.Nama != "PT Media Online" || len(created.Aliases) != 1 || created.Aliases[0] != "MediaOn" {
+        t.Errorf("unexpected company %+v", created)
+    }
+
+    for name, req := range map[string]model.CreateCompanyRequest{
+        "same name":     {Nama: "PT. Digital Indonesia"},
+        "alias as name": {Nama: "Digiindo"},
+        "name as alias": {Nama: "PT Baru", Aliases: []string{"Digital Indonesia"}},
+    } {
+        if resp := do(t, app, "POST", "/companies", req); resp.Status != 409 {
+            t.Errorf("%s: status = %d, want 409", name, resp.Status)
+        }
+    }
+
+    if resp := do(t, app, "POST", "/companies", model.CreateCompanyRequest{Nama: " "}); resp.Status != 400 {
+        t.Errorf("blank name: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestUpdateCompany(t *testing.T) {
+    f := newCompanyFixture(t)
+    other := newCompany(t, f.repos, "PT Media Online")
+    app := newTestApp("PUT", "/companies/:id", f.service.UpdateCompany, adminCaller)
+
+    // Keeping its own names is not a conflict
+    resp := do(t, app, "PUT", "/companies/"+f.company.ID.Hex(), model.UpdateCompanyRequest{
+        Nama: "PT Digital Indonesia Tbk", Aliases: []string{"DigiIndo", "Digital Indo"}, BidangIndustri: "Fintech",
+    })
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    updated, _ := f.repos.Company.FindCompanyByID(context.Background(), f.company.ID)
+    if updated.Nama != "PT Digital Indonesia Tbk" || updated.BidangIndustri != "Fintech" || len(updated.Aliases) != 2 {
+        t.Errorf("company not updated: %+v", updated)
+    }
+
+    resp = do(t, app, "PUT", "/companies/"+other.ID.Hex(), model.UpdateCompanyRequest{Nama: "Media Online", Aliases: []string{"Digital Indo"}})
+    if resp.Status != 409 {
+        t.Errorf("taken alias: status = %d, want 409", resp.Status)
+    }
+
+    if resp := do(t, app, "PUT", "/companies/"+adminCaller.userID.Hex(), model.UpdateCompanyRequest{Nama: "PT Lain"}); resp.Status != 404 {
+        t.Errorf("unknown id: status = %d, want 404", resp.Status)
+    }
+    if resp := do(t, app, "PUT", "/companies/bad", model.UpdateCompanyRequest{Nama: "PT Lain"}); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestDeleteCompanyUnlinksPekerjaan(t *testing.T) {
+    f := newCompanyFixture(t)
+    alumni := newAlumni(t, f.repos, "1234567001", "John Doe", "Teknik Informatika", adminCaller.userID)
+    p := newPekerjaan(t, f.repos, alumni.ID, "PT Digital Indonesia")
+    if _, err := f.repos.Company.LinkPekerjaan(context.Background(), f.company.ID, []primitive.ObjectID{p.ID}); err != nil {
+        t.Fatal(err)
+    }
+
+    app := newTestApp("DELETE", "/companies/:id", f.service.DeleteCompany, adminCaller)
+    if resp := do(t, app, "DELETE", "/companies/"+f.company.ID.Hex(), nil); resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), alumni.ID)
+    if len(list) != 1 || list[0].CompanyID != nil {
+        t.Errorf("pekerjaan still linked: %+v", list)
+    }
+    if resp := do(t, app, "DELETE", "/companies/"+f.company.ID.Hex(), nil); resp.Status != 404 {
+        t.Errorf("second delete: status = %d, want 404", resp.Status)
+    }
+}
+
+func TestGetAllCompaniesDatatable(t *testing.T) {
+    f := newCompanyFixture(t)
+    newCompany(t, f.repos, "PT Media Online")
+    newCompany(t, f.repos, "CV Kopi Nusantara")
+    app := newTestApp("GET", "/companies", f.service.GetAllCompaniesDatatable, adminCaller)
+
+    resp := do(t, app, "GET", "/companies?limit=2", nil)
+    var list []model.CompanyResponse
+    decodeData(t, resp, &list)
+    if resp.Status != 200 || resp.Meta.Total != 3 || len(list) != 2 || list[0].Nama != "CV Kopi Nusantara" {
+        t.Errorf("status = %d, meta = %+v, list = %+v", resp.Status, resp.Meta, list)
+    }
+
+    // Aliases are searched too
+    resp = do(t, app, "GET", "/companies?search=digiindo", nil)
+    decodeData(t, resp, &list)
+    if resp.Meta.Total != 1 || list[0].ID != f.company.ID.Hex() {
+        t.Errorf("alias search: meta = %+v, list = %+v", resp.Meta, list)
+    }
+}
+
+func TestSuggestCompanies(t *testing.T) {
+    f := newCompanyFixture(t)
+    newCompany(t, f.repos, "PT Media Online")
+    app := newTestApp("GET", "/companies/suggest", f.service.SuggestCompanies, adminCaller)
+
+    resp := do(t, app, "GET", "/companies/suggest?nama=Digital+Indonesa", nil)
+    var matches []model.CompanyMatch
+    decodeData(t, resp, &matches)
+    if resp.Status != 200 || len(matches) != 1 || matches[0].Company.ID != f.company.ID.Hex() || matches[0].Exact {
+        t.Errorf("status = %d, matches = %+v", resp.Status, matches)
+    }
+
+    if resp := do(t, app, "GET", "/companies/suggest", nil); resp.Status != 400 {
+        t.Errorf("no nama: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestGetCompanyAlumni(t *testing.T) {
+    f := newCompanyFixture(t)
+    john := newAlumni(t, f.repos, "1234567001", "John Doe", "Teknik Informatika", adminCaller.userID)
+    jane := newAlumni(t, f.repos, "1234567002", "Jane Smith", "Sistem Informasi", adminCaller.userID)
+
+    first := newPekerjaan(t, f.repos, john.ID, "PT Digital Indonesia")
+    second := newPekerjaan(t, f.repos, john.ID, "Digital Indonesia")
+    janes := newPekerjaan(t, f.repos, jane.ID, "PT. Digital Indonesia")
+    trashed := newPekerjaan(t, f.repos, jane.ID, "DigiIndo")
+    f.repos.Pekerjaan.SoftDelete(context.Background(), trashed.ID, adminCaller.userID, true)
+    ids := []primitive.ObjectID{first.ID, second.ID, janes.ID, trashed.ID}
+    if _, err := f.repos.Company.LinkPekerjaan(context.Background(), f.company.ID, ids); err != nil {
+        t.Fatal(err)
+    }
+
+    app := newTestApp("GET", "/companies/:id/alumni", f.service.GetCompanyAlumni, adminCaller)
+    resp := do(t, app, "GET", "/companies/"+f.company.ID.Hex()+"/alumni", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    var list []model.CompanyAlumniResponse
+    decodeData(t, resp, &list)
+    if len(list) != 2 || list[0].Nama != "Jane Smith" || len(list[0].Pekerjaan) != 1 || len(list[1].Pekerjaan) != 2 {
+        t.Errorf("unexpected alumni %+v", list)
+    }
+
+    if resp := do(t, app, "GET", "/companies/"+adminCaller.userID.Hex()+"/alumni", nil); resp.Status != 404 {
+        t.Errorf("unknown company: status = %d, want 404", resp.Status)
+    }
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+    "context"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// readinessTimeout bounds each readiness check, so a hanging database fails
+// the probe instead of stalling it
+const readinessTimeout = 2 * time.Second
+
+// HealthCheck reports whether one dependency can serve requests
+type HealthCheck func(ctx context.Context) error
+
+type namedCheck struct {
+    name  string
+    check HealthCheck
+}
+
+// HealthService answers the liveness and readiness probes
+type HealthService struct {
+    mu           sync.RWMutex
+    checks       []namedCheck
+    shuttingDown atomic.Bool
+}
+
+func NewHealthService() *HealthService {
+    return &HealthService{}
+}
+
+// AddCheck registers a dependency checked by the readiness probe
+func (s *HealthService) AddCheck(name string, check HealthCheck) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.checks = append(s.checks, namedCheck{name: name, check: check})
+}
+
+// ShuttingDown makes the readiness probe fail, so no new traffic is routed
+// here while in-flight requests drain
+func (s *HealthService) ShuttingDown() {
+    s.shuttingDown.Store(true)
+}
+
+// Liveness only reports that the process is serving HTTP
+func (s *HealthService) Liveness(c *fiber.Ctx) error {
+    return c.JSON(fiber.Map{
+        "success": true,
+        "data":    model.HealthResponse{Status: "ok"},
+    })
+}
+
+// Readiness runs every registered check and answers 503 when one fails or
+// the server is shutting down
+func (s *HealthService) Readiness(c *fiber.Ctx) error {
+    if s.shuttingDown.Load() {
+        return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+            "message": "Server sedang dimatikan",
+            "success": false,
+            "data":    model.HealthResponse{Status: "shutting_down"},
+        })
+    }
+
+    s.mu.RLock()
+    checks := s.checks
+    s.mu.RUnlock()
+
+    result := model.HealthResponse{Status: "ok", Checks: map[string]string{}}
+    for _, nc := range checks {
+        ctx, cancel := context.WithTimeout(c.UserContext(), readinessTimeout)
+        err := nc.check(ctx)
+        cancel()
+
+        if err != nil {
+            result.Status = "unavailable"
+            result.Checks[nc.name] = err.Error()
+            continue
+        }
+        result.Checks[nc.name] = "ok"
+    }
+
+    if result.Status != "ok" {
+        return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+            "message": "Layanan belum siap",
+            "success": false,
+            "data":    result,
+        })
+    }
+    return c.JSON(fiber.Map{
+        "success": true,
+        "data":    result,
+    })
+}
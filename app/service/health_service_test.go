@@ -0,0 +1,44 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "go-fiber/app/model"
+)
+
+func TestReadiness(t *testing.T) {
+    s := NewHealthService()
+    s.AddCheck("mongo", func(ctx context.Context) error { return nil })
+    app := newTestApp("GET", "/readyz", s.Readiness, caller{})
+
+    res := do(t, app, "GET", "/readyz", nil)
+    var health model.HealthResponse
+    decodeData(t, res, &health)
+    if res.Status != 200 || health.Status != "ok" || health.Checks["mongo"] != "ok" {
+        t.Fatalf("ready: status = %d, data = %+v", res.Status, health)
+    }
+
+    s.AddCheck("migrations", func(ctx context.Context) error { return errors.New("1 pending") })
+    res = do(t, app, "GET", "/readyz", nil)
+    decodeData(t, res, &health)
+    if res.Status != 503 || res.Success || health.Checks["migrations"] != "1 pending" || health.Checks["mongo"] != "ok" {
+        t.Fatalf("failing check: status = %d, data = %+v", res.Status, health)
+    }
+}
+
+func TestReadinessWhileShuttingDown(t *testing.T) {
+    s := NewHealthService()
+    app := newTestApp("GET", "/readyz", s.Readiness, caller{})
+    live := newTestApp("GET", "/healthz", s.Liveness, caller{})
+
+    s.ShuttingDown()
+
+    if res := do(t, app, "GET", "/readyz", nil); res.Status != 503 {
+        t.Fatalf("readyz: status = %d, want 503", res.Status)
+    }
+    if res := do(t, live, "GET", "/healthz", nil); res.Status != 200 || !res.Success {
+        t.Fatalf("healthz: status = %d, want 200", res.Status)
+    }
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// caller is the authenticated user a test request is made as, standing in for
+// what middleware.AuthRequired stores in the context
+type caller struct {
+    userID primitive.ObjectID
+    role   string
+}
+
+var adminCaller = caller{userID: primitive.NewObjectID(), role: "admin"}
+
+func newTestApp(method, path string, handler fiber.Handler, as caller) *fiber.App {
+    app := fiber.New()
+    app.Add(method, path, func(c *fiber.Ctx) error {
+        c.Locals("user_id", as.userID)
+        c.Locals("role", as.role)
+        return c.Next()
+    }, handler)
+    return app
+}
+
+type testResponse struct {
+    Status  int
+    Success bool                   `json:"success"`
+    Message string                 `json:"message"`
+    Error   string                 `json:"error"`
+    Data    json.RawMessage        `json:"data"`
+    Meta    model.MetaInfo         `json:"meta"`
+}
+
+func do(t *testing.T, app *fiber.App, method, url string, body interface{}) testResponse {
+    t.Helper()
+
+    var reader io.Reader
+    if body != nil {
+        b, err := json.Marshal(body)
+        if err != nil {
+            t.Fatal(err)
+        }
+        reader = bytes.NewReader(b)
+    }
+
+    req := httptest.NewRequest(method, url, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+
+    resp, err := app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+
+    raw, _ := io.ReadAll(resp.Body)
+    out := testResponse{Status: resp.StatusCode}
+    if len(raw) > 0 {
+        if err := json.Unmarshal(raw, &out); err != nil {
+            t.Fatalf("invalid JSON %q: %v", raw, err)
+        }
+    }
+    return out
+}
+
+func decodeData(t *testing.T, r testResponse, v interface{}) {
+    t.Helper()
+    if err := json.Unmarshal(r.Data, v); err != nil {
+        t.Fatalf("decode data %s: %v", r.Data, err)
+    }
+}
+
+func newAlumni(t *testing.T, repos repository.Repositories, nim, nama, jurusan string, userID primitive.ObjectID) model.Alumni {
+    t.Helper()
+    a, err := repos.Alumni.CreateAlumni(context.Background(), model.Alumni{
+        NIM:        nim,
+        Nama:       nama,
+        Jurusan:    jurusan,
+        Angkatan:   2018,
+        TahunLulus: 2022,
+        Email:      nim + "@university.ac.id",
+        NoTelepon:  "0812" + nim,
+        UserID:     userID,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    return *a
+}
+
+func newPekerjaan(t *testing.T, repos repository.Repositories, alumniID primitive.ObjectID, perusahaan string) model.Pekerjaan {
+    t.Helper()
+    gajiMin, gajiMax := int64(8_000_000), int64(12_000_000)
+    p, err := repos.Pekerjaan.CreatePekerjaan(context.Background(), model.Pekerjaan{
+        AlumniID:          alumniID,
+        NamaPerusahaan:    perusahaan,
+        PosisiJabatan:     "Backend Developer",
+        BidangIndustri:    "Technology",
+        LokasiKerja:       "Jakarta",
+        GajiMin:           &gajiMin,
+        GajiMax:           &gajiMax,
+        GajiCurrency:      model.CurrencyIDR,
+        GajiPeriod:        model.GajiMonthly,
+        TanggalMulaiKerja: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+        StatusPekerjaan:   "aktif",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    return *p
+}
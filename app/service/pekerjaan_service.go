@@ -1,17 +1,47 @@
 package service
 
 import (
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
     "math"
     "strconv"
+    "strings"
+    "time"
     
     "github.com/gofiber/fiber/v2"
     "go-fiber/app/model"
     "go-fiber/app/repository"
     "go.mongodb.org/mongo-driver/bson/primitive"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func GetPekerjaanByAlumniIDService(c *fiber.Ctx, db *mongo.Database) error {
+// PekerjaanService handles the pekerjaan and trash endpoints
+type PekerjaanService struct {
+    Repo repository.PekerjaanRepository
+    // Companies links pekerjaan to the companies registry; matching is
+    // skipped when nil
+    Companies repository.CompanyRepository
+    // Alumni resolves the alumni a non-admin caller submits pekerjaan for
+    Alumni repository.AlumniRepository
+    // OverlapPolicy is one of model.OverlapAllow, OverlapActive and
+    // OverlapNone; empty means OverlapActive
+    OverlapPolicy string
+    // TrashRetention is how long pekerjaan stay in the trash before the
+    // scheduled purge; zero keeps them forever
+    TrashRetention time.Duration
+}
+
+// maxTrashBulk caps the ids of one bulk restore or purge
+const maxTrashBulk = 500
+
+func NewPekerjaanService(repo repository.PekerjaanRepository) *PekerjaanService {
+    return &PekerjaanService{Repo: repo}
+}
+
+func (s *PekerjaanService) GetPekerjaanByAlumniID(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.GetPekerjaanByAlumniID")()
+
     alumniIDStr := c.Params("alumni_id")
     alumniID, err := primitive.ObjectIDFromHex(alumniIDStr)
     if err != nil {
@@ -21,9 +51,9 @@ func GetPekerjaanByAlumniIDService(c *fiber.Ctx, db *mongo.Database) error {
         })
     }
 
-    repo := repository.NewPekerjaanRepository(db)
-    pekerjaanList, err := repo.FindPekerjaanByAlumniID(alumniID)
+    pekerjaanList, err := s.Repo.FindPekerjaanByAlumniID(c.UserContext(), alumniID)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindPekerjaanByAlumniID failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal mendapatkan data pekerjaan: " + err.Error(),
             "success": false,
@@ -42,7 +72,9 @@ func GetPekerjaanByAlumniIDService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func CreatePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) CreatePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.CreatePekerjaan")()
+
     var req model.CreatePekerjaanRequest
     if err := c.BodyParser(&req); err != nil {
         return c.Status(400).JSON(fiber.Map{
@@ -51,44 +83,58 @@ func CreatePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
         })
     }
 
-    alumniID, err := primitive.ObjectIDFromHex(req.AlumniID)
+    alumniID, ok, err := s.submitterAlumni(c, req.AlumniID)
+    if !ok {
+        return err
+    }
+    isAdmin := c.Locals("role") == "admin"
+
+    status := model.ModerasiApproved
+    message := "Pekerjaan berhasil ditambahkan"
+    if !isAdmin {
+        status = model.ModerasiPending
+        message = "Pekerjaan berhasil diajukan dan menunggu persetujuan admin"
+    }
+
+    pekerjaan, err := pekerjaanFromRequest(req, alumniID, status)
     if err != nil {
         return c.Status(400).JSON(fiber.Map{
-			"message": "Alumni ID tidak valid",
+            "message": err.Error(),
             "success": false,
         })
     }
 
-    pekerjaan := model.Pekerjaan{
-        AlumniID:            alumniID,
-        NamaPerusahaan:      req.NamaPerusahaan,
-        PosisiJabatan:       req.PosisiJabatan,
-        BidangIndustri:      req.BidangIndustri,
-        LokasiKerja:         req.LokasiKerja,
-        GajiRange:           req.GajiRange,
-        TanggalMulaiKerja:   req.TanggalMulaiKerja,
-        TanggalSelesaiKerja: req.TanggalSelesaiKerja,
-        StatusPekerjaan:     req.StatusPekerjaan,
-        DeskripsiPekerjaan:  req.DeskripsiPekerjaan,
+    if ok, err := s.checkTimeline(c, pekerjaan); !ok {
+        return err
     }
 
-    repo := repository.NewPekerjaanRepository(db)
-    newPekerjaan, err := repo.CreatePekerjaan(pekerjaan)
+    suggestions, err := s.matchCompany(c.UserContext(), &pekerjaan, req.CompanyID)
     if err != nil {
+        return companyMatchError(c, err)
+    }
+
+    newPekerjaan, err := s.Repo.CreatePekerjaan(c.UserContext(), pekerjaan)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CreatePekerjaan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menambahkan pekerjaan: " + err.Error(),
             "success": false,
         })
     }
 
+    response := newPekerjaan.ToPekerjaanResponse()
+    response.CompanySuggestions = suggestions
+
     return c.Status(201).JSON(fiber.Map{
-        "message": "Pekerjaan berhasil ditambahkan",
+        "message": message,
         "success": true,
-        "data":    newPekerjaan.ToPekerjaanResponse(),
+        "data":    response,
     })
 }
 
-func UpdatePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) UpdatePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.UpdatePekerjaan")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -106,44 +152,335 @@ func UpdatePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
         })
     }
 
-    alumniID, err := primitive.ObjectIDFromHex(req.AlumniID)
+    alumniID, ok, err := s.submitterAlumni(c, req.AlumniID)
+    if !ok {
+        return err
+    }
+
+    // Alumni may only edit their own pekerjaan, and every edit goes back to
+    // the moderation queue. Admin edits keep status_moderasi as it is.
+    message := "Pekerjaan berhasil diupdate"
+    status := ""
+    if c.Locals("role") != "admin" {
+        existing, err := s.Repo.FindPekerjaanByID(c.UserContext(), id)
+        if err == nil && existing.AlumniID != alumniID {
+            err = repository.ErrNotFoundOrNoAccess
+        }
+        if errors.Is(err, repository.ErrNotFoundOrNoAccess) {
+            return c.Status(404).JSON(fiber.Map{
+                "message": err.Error(),
+                "success": false,
+            })
+        }
+        if err != nil {
+            slog.ErrorContext(c.UserContext(), "FindPekerjaanByID failed", "error", err)
+            return c.Status(500).JSON(fiber.Map{
+                "message": "Gagal mendapatkan pekerjaan: " + err.Error(),
+                "success": false,
+            })
+        }
+        status = model.ModerasiPending
+        message = "Perubahan pekerjaan berhasil diajukan dan menunggu persetujuan admin"
+    }
+
+    pekerjaan, err := pekerjaanFromRequest(model.CreatePekerjaanRequest(req), alumniID, status)
     if err != nil {
         return c.Status(400).JSON(fiber.Map{
-            "message": "Alumni ID tidak valid",
+            "message": err.Error(),
             "success": false,
         })
     }
 
-    pekerjaan := model.Pekerjaan{
+    pekerjaan.ID = id
+    if ok, err := s.checkTimeline(c, pekerjaan); !ok {
+        return err
+    }
+
+    suggestions, err := s.matchCompany(c.UserContext(), &pekerjaan, req.CompanyID)
+    if err != nil {
+        return companyMatchError(c, err)
+    }
+
+    updatedPekerjaan, err := s.Repo.UpdatePekerjaan(c.UserContext(), id, pekerjaan)
+    if errors.Is(err, repository.ErrNotFoundOrNoAccess) {
+        return c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "UpdatePekerjaan failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal update pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    response := updatedPekerjaan.ToPekerjaanResponse()
+    response.CompanySuggestions = suggestions
+
+    return c.JSON(fiber.Map{
+        "message": message,
+        "success": true,
+        "data":    response,
+    })
+}
+
+// callerOf returns the user id and admin flag the auth middleware stored;
+// the id is zero when missing or malformed
+func callerOf(c *fiber.Ctx) (primitive.ObjectID, bool) {
+    var userID primitive.ObjectID
+    switch v := c.Locals("user_id").(type) {
+    case primitive.ObjectID:
+        userID = v
+    case string:
+        userID, _ = primitive.ObjectIDFromHex(v)
+    }
+    return userID, c.Locals("role") == "admin"
+}
+
+// submitterAlumni resolves the alumni a pekerjaan is written for: the
+// requested alumni_id for admins, the caller's own alumni for everyone
+// else. ok is false when a reply was sent.
+func (s *PekerjaanService) submitterAlumni(c *fiber.Ctx, requested string) (primitive.ObjectID, bool, error) {
+    own, isAdmin, err := s.callerAlumni(c)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindAlumniByUserID failed", "error", err)
+        return primitive.NilObjectID, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan data alumni: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    alumniID, status, err := submitter(isAdmin, own, requested)
+    if err != nil {
+        return alumniID, false, c.Status(status).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    return alumniID, true, nil
+}
+
+// callerAlumni returns the alumni linked to a non-admin caller, nil when
+// there is none or the caller is an admin
+func (s *PekerjaanService) callerAlumni(c *fiber.Ctx) (*model.Alumni, bool, error) {
+    userID, isAdmin := callerOf(c)
+    if isAdmin {
+        return nil, true, nil
+    }
+
+    alumni, err := s.Alumni.FindAlumniByUserID(c.UserContext(), userID)
+    if errors.Is(err, repository.ErrAlumniNotFound) {
+        return nil, false, nil
+    }
+    return alumni, false, err
+}
+
+var (
+    errAlumniID     = errors.New("Alumni ID tidak valid")
+    errNoOwnAlumni  = errors.New("Akun anda belum terhubung dengan data alumni")
+    errNotOwnAlumni = errors.New("Anda hanya dapat mengelola pekerjaan milik anda sendiri")
+)
+
+// submitter picks the alumni of a pekerjaan write given the caller's own
+// alumni, replying status 400 or 403 through the error when it may not
+func submitter(isAdmin bool, own *model.Alumni, requested string) (primitive.ObjectID, int, error) {
+    if isAdmin {
+        alumniID, err := primitive.ObjectIDFromHex(requested)
+        if err != nil {
+            return alumniID, 400, errAlumniID
+        }
+        return alumniID, 0, nil
+    }
+
+    if own == nil {
+        return primitive.NilObjectID, 403, errNoOwnAlumni
+    }
+    if requested != "" && requested != own.ID.Hex() {
+        return primitive.NilObjectID, 403, errNotOwnAlumni
+    }
+    return own.ID, 0, nil
+}
+
+// pekerjaanFromRequest builds the pekerjaan of a create or update request
+// for alumniID; the error is a 400 about the salary
+func pekerjaanFromRequest(req model.CreatePekerjaanRequest, alumniID primitive.ObjectID, status string) (model.Pekerjaan, error) {
+    p := model.Pekerjaan{
         AlumniID:            alumniID,
         NamaPerusahaan:      req.NamaPerusahaan,
         PosisiJabatan:       req.PosisiJabatan,
         BidangIndustri:      req.BidangIndustri,
         LokasiKerja:         req.LokasiKerja,
-        GajiRange:           req.GajiRange,
         TanggalMulaiKerja:   req.TanggalMulaiKerja,
         TanggalSelesaiKerja: req.TanggalSelesaiKerja,
         StatusPekerjaan:     req.StatusPekerjaan,
         DeskripsiPekerjaan:  req.DeskripsiPekerjaan,
+        StatusModerasi:      status,
     }
+    err := setGaji(&p, req.GajiMin, req.GajiMax, req.GajiCurrency, req.GajiPeriod, req.GajiRange)
+    return p, err
+}
 
-    repo := repository.NewPekerjaanRepository(db)
-    updatedPekerjaan, err := repo.UpdatePekerjaan(id, pekerjaan)
-    if err != nil {
-        return c.Status(500).JSON(fiber.Map{
-            "message": "Gagal update pekerjaan: " + err.Error(),
+// checkTimeline enforces the dates and status of p and the overlap policy
+// against the other pekerjaan of its alumni, replying 400 or 409 when they
+// are broken. ok is false when a reply was sent.
+func (s *PekerjaanService) checkTimeline(c *fiber.Ctx, p model.Pekerjaan) (bool, error) {
+    if status, err := s.timelineError(c.UserContext(), p, nil); err != nil {
+        return false, c.Status(status).JSON(fiber.Map{
+            "message": err.Error(),
             "success": false,
         })
     }
+    return true, nil
+}
 
-    return c.JSON(fiber.Map{
-        "message": "Pekerjaan berhasil diupdate",
-        "success": true,
-        "data":    updatedPekerjaan.ToPekerjaanResponse(),
+// timelineError is checkTimeline without the reply: the status is 400, 409
+// or 500. The other pekerjaan of the alumni are taken as batch will leave
+// them once written.
+func (s *PekerjaanService) timelineError(ctx context.Context, p model.Pekerjaan, batch *pekerjaanBatch) (int, error) {
+    if err := p.ValidateTimeline(time.Now()); err != nil {
+        return 400, err
+    }
+    if s.OverlapPolicy == model.OverlapAllow {
+        return 0, nil
+    }
+
+    others, err := s.Repo.FindPekerjaanByAlumniID(ctx, p.AlumniID)
+    if err != nil {
+        slog.ErrorContext(ctx, "FindPekerjaanByAlumniID failed", "error", err)
+        return 500, fmt.Errorf("Gagal memeriksa riwayat pekerjaan: %w", err)
+    }
+    if batch != nil {
+        others = batch.others(p.AlumniID, others)
+    }
+    if err := model.CheckOverlap(p, others, s.OverlapPolicy); err != nil {
+        return 409, err
+    }
+    return 0, nil
+}
+
+var errCompanyID = errors.New("Company ID tidak valid")
+
+// matchCompany links p to the company given in the request or, without one,
+// to the company whose name or an alias equals nama_perusahaan once
+// normalized. When nothing matches exactly the closest companies are
+// returned as suggestions and p stays unlinked.
+func (s *PekerjaanService) matchCompany(ctx context.Context, p *model.Pekerjaan, companyID string) ([]model.CompanyMatch, error) {
+    if s.Companies == nil {
+        return nil, nil
+    }
+
+    if companyID != "" {
+        id, err := primitive.ObjectIDFromHex(companyID)
+        if err != nil {
+            return nil, errCompanyID
+        }
+        if _, err := s.Companies.FindCompanyByID(ctx, id); err != nil {
+            return nil, err
+        }
+        p.CompanyID = &id
+        return nil, nil
+    }
+
+    if strings.TrimSpace(p.NamaPerusahaan) == "" {
+        return nil, nil
+    }
+    all, err := s.Companies.AllCompanies(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    matches := model.MatchCompanies(all, p.NamaPerusahaan, 3)
+    if len(matches) > 0 && matches[0].Exact {
+        id, _ := primitive.ObjectIDFromHex(matches[0].Company.ID)
+        p.CompanyID = &id
+        return nil, nil
+    }
+    return matches, nil
+}
+
+// companyMatchError replies to a failed matchCompany
+func companyMatchError(c *fiber.Ctx, err error) error {
+    status, err := companyMatchStatus(c.UserContext(), err)
+    return c.Status(status).JSON(fiber.Map{
+        "message": err.Error(),
+        "success": false,
     })
 }
 
-func GetAllPekerjaanServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
+// companyMatchStatus is the status and message of a failed matchCompany
+func companyMatchStatus(ctx context.Context, err error) (int, error) {
+    if errors.Is(err, errCompanyID) || errors.Is(err, repository.ErrCompanyNotFound) {
+        return 400, err
+    }
+
+    slog.ErrorContext(ctx, "matching company failed", "error", err)
+    return 500, fmt.Errorf("Gagal mencocokkan perusahaan: %w", err)
+}
+
+// setGaji fills the salary of p from a request. gaji_min and gaji_max win;
+// without them the free-text gaji_range is parsed, as older clients send it.
+func setGaji(p *model.Pekerjaan, min, max *int64, currency, period, text string) error {
+    if min == nil && max == nil {
+        if strings.TrimSpace(text) == "" {
+            return nil
+        }
+        g, err := model.ParseGajiRange(text)
+        if err != nil {
+            return fmt.Errorf("gaji_range %q tidak dikenali, kirim gaji_min dan gaji_max", text)
+        }
+        p.SetGaji(g)
+        return nil
+    }
+
+    g := model.Gaji{Min: min, Max: max, Currency: strings.ToUpper(currency), Period: period}
+    if g.Currency == "" {
+        g.Currency = model.CurrencyIDR
+    }
+    if g.Period == "" {
+        g.Period = model.GajiMonthly
+    }
+    if err := g.Validate(); err != nil {
+        return err
+    }
+    p.SetGaji(g)
+    return nil
+}
+
+// pekerjaanFilter reads the search and salary query parameters of the
+// listing
+func pekerjaanFilter(c *fiber.Ctx, search string) (model.PekerjaanFilter, error) {
+    filter := model.PekerjaanFilter{
+        Search:       search,
+        GajiCurrency: strings.ToUpper(c.Query("gaji_currency")),
+        GajiPeriod:   c.Query("gaji_period"),
+    }
+
+    for name, bound := range map[string]**int64{"gaji_min": &filter.GajiMin, "gaji_max": &filter.GajiMax} {
+        text := c.Query(name)
+        if text == "" {
+            continue
+        }
+        n, err := strconv.ParseInt(text, 10, 64)
+        if err != nil || n < 0 {
+            return filter, fmt.Errorf("%s harus berupa bilangan bulat tidak negatif", name)
+        }
+        *bound = &n
+    }
+
+    if filter.GajiMin != nil && filter.GajiMax != nil && *filter.GajiMin > *filter.GajiMax {
+        return filter, errors.New("gaji_min tidak boleh lebih besar dari gaji_max")
+    }
+    if filter.GajiPeriod != "" && filter.GajiPeriod != model.GajiMonthly && filter.GajiPeriod != model.GajiAnnual {
+        return filter, fmt.Errorf("gaji_period harus %s atau %s", model.GajiMonthly, model.GajiAnnual)
+    }
+    return filter, nil
+}
+
+func (s *PekerjaanService) GetAllPekerjaanDatatable(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.GetAllPekerjaanDatatable")()
+
     page, _ := strconv.Atoi(c.Query("page", "1"))
     limit, _ := strconv.Atoi(c.Query("limit", "10"))
     sortBy := c.Query("sortBy", "_id")
@@ -155,17 +492,26 @@ func GetAllPekerjaanServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
     }
     offset := (page - 1) * limit
 
-    repo := repository.NewPekerjaanRepository(db)
-    list, err := repo.GetPekerjaan(search, sortBy, order, limit, offset)
+    filter, err := pekerjaanFilter(c, search)
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    list, err := s.Repo.GetPekerjaan(c.UserContext(), filter, sortBy, order, limit, offset)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetPekerjaan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal mendapatkan data pekerjaan alumni: " + err.Error(),
             "success": false,
         })
     }
 
-    total, err := repo.CountPekerjaan(search)
+    total, err := s.Repo.CountPekerjaan(c.UserContext(), filter)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountPekerjaan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menghitung total pekerjaan alumni: " + err.Error(),
             "success": false,
@@ -195,7 +541,9 @@ func GetAllPekerjaanServiceDatatable(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func SoftDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) SoftDeletePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.SoftDeletePekerjaan")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -231,9 +579,9 @@ func SoftDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     role := c.Locals("role").(string)
     isAdmin := role == "admin"
 
-    repo := repository.NewPekerjaanRepository(db)
-    err = repo.SoftDelete(id, userID, isAdmin)
+    err = s.Repo.SoftDelete(c.UserContext(), id, userID, isAdmin)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "SoftDelete failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "error":   "Gagal soft delete pekerjaan: " + err.Error(),
             "success": false,
@@ -246,7 +594,9 @@ func SoftDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func GetTrashPekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) GetTrashPekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.GetTrashPekerjaan")()
+
     page, _ := strconv.Atoi(c.Query("page", "1"))
     limit, _ := strconv.Atoi(c.Query("limit", "10"))
     sortBy := c.Query("sortBy", "is_delete")
@@ -278,17 +628,18 @@ func GetTrashPekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     role := c.Locals("role").(string)
     isAdmin := role == "admin"
 
-    repo := repository.NewPekerjaanRepository(db)
-    list, err := repo.GetTrashPekerjaan(userID, isAdmin, search, sortBy, order, limit, offset)
+    list, err := s.Repo.GetTrashPekerjaan(c.UserContext(), userID, isAdmin, search, sortBy, order, limit, offset)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetTrashPekerjaan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal mendapatkan data trash: " + err.Error(),
             "success": false,
         })
     }
 
-    total, err := repo.CountTrashPekerjaan(userID, isAdmin, search)
+    total, err := s.Repo.CountTrashPekerjaan(c.UserContext(), userID, isAdmin, search)
     if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountTrashPekerjaan failed", "error", err)
         return c.Status(500).JSON(fiber.Map{
             "message": "Gagal menghitung total trash: " + err.Error(),
             "success": false,
@@ -318,7 +669,9 @@ func GetTrashPekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func RestorePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) RestorePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.RestorePekerjaan")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -347,8 +700,7 @@ func RestorePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     role := c.Locals("role").(string)
     isAdmin := role == "admin"
 
-    repo := repository.NewPekerjaanRepository(db)
-    err = repo.RestorePekerjaan(id, userID, isAdmin)
+    err = s.Repo.RestorePekerjaan(c.UserContext(), id, userID, isAdmin)
     if err != nil {
         return c.Status(404).JSON(fiber.Map{
             "message": "Data tidak ditemukan atau bukan milik anda",
@@ -362,7 +714,9 @@ func RestorePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     })
 }
 
-func HardDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
+func (s *PekerjaanService) HardDeletePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.HardDeletePekerjaan")()
+
     idStr := c.Params("id")
     id, err := primitive.ObjectIDFromHex(idStr)
     if err != nil {
@@ -391,8 +745,7 @@ func HardDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
     role := c.Locals("role").(string)
     isAdmin := role == "admin"
 
-    repo := repository.NewPekerjaanRepository(db)
-    err = repo.HardDeletePekerjaan(id, userID, isAdmin)
+    err = s.Repo.HardDeletePekerjaan(c.UserContext(), id, userID, isAdmin)
     if err != nil {
         return c.Status(404).JSON(fiber.Map{
             "message": "Data tidak ditemukan atau bukan milik anda",
@@ -404,4 +757,551 @@ func HardDeletePekerjaanService(c *fiber.Ctx, db *mongo.Database) error {
         "message": "Pekerjaan berhasil dihapus permanen",
         "success": true,
     })
-}
\ No newline at end of file
+}
+
+// trashBulkIDs reads the ids of a bulk trash request, dropping duplicates.
+// ok is false when a reply was sent.
+func trashBulkIDs(c *fiber.Ctx) ([]primitive.ObjectID, bool, error) {
+    var req model.TrashBulkRequest
+    if err := c.BodyParser(&req); err != nil {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+    if len(req.IDs) == 0 || len(req.IDs) > maxTrashBulk {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": fmt.Sprintf("ids harus berisi 1 sampai %d ID", maxTrashBulk),
+            "success": false,
+        })
+    }
+
+    seen := map[primitive.ObjectID]bool{}
+    ids := make([]primitive.ObjectID, 0, len(req.IDs))
+    for _, hex := range req.IDs {
+        id, err := primitive.ObjectIDFromHex(hex)
+        if err != nil {
+            return nil, false, c.Status(400).JSON(fiber.Map{
+                "message": fmt.Sprintf("ID %q tidak valid", hex),
+                "success": false,
+            })
+        }
+        if !seen[id] {
+            seen[id] = true
+            ids = append(ids, id)
+        }
+    }
+    return ids, true, nil
+}
+
+// trashBulkResponse splits the requested ids into those the repository
+// changed and the rest
+func trashBulkResponse(ids, done []primitive.ObjectID) model.TrashBulkResponse {
+    changed := map[primitive.ObjectID]bool{}
+    for _, id := range done {
+        changed[id] = true
+    }
+
+    response := model.TrashBulkResponse{Processed: []string{}, Skipped: []string{}}
+    for _, id := range ids {
+        if changed[id] {
+            response.Processed = append(response.Processed, id.Hex())
+        } else {
+            response.Skipped = append(response.Skipped, id.Hex())
+        }
+    }
+    return response
+}
+
+// RestorePekerjaanBulk restores the given pekerjaan from the caller's trash;
+// ids that are not there are reported as skipped
+func (s *PekerjaanService) RestorePekerjaanBulk(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.RestorePekerjaanBulk")()
+
+    ids, ok, err := trashBulkIDs(c)
+    if !ok {
+        return err
+    }
+    userID, isAdmin := callerOf(c)
+
+    restored, err := s.Repo.RestorePekerjaanBulk(c.UserContext(), ids, userID, isAdmin)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "RestorePekerjaanBulk failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mengembalikan pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": fmt.Sprintf("%d pekerjaan berhasil dikembalikan", len(restored)),
+        "success": true,
+        "data":    trashBulkResponse(ids, restored),
+    })
+}
+
+// PurgePekerjaanBulk permanently deletes the given pekerjaan from the
+// caller's trash; ids that are not there are reported as skipped
+func (s *PekerjaanService) PurgePekerjaanBulk(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.PurgePekerjaanBulk")()
+
+    ids, ok, err := trashBulkIDs(c)
+    if !ok {
+        return err
+    }
+    userID, isAdmin := callerOf(c)
+
+    purged, err := s.Repo.HardDeletePekerjaanBulk(c.UserContext(), ids, userID, isAdmin)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "HardDeletePekerjaanBulk failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghapus permanen pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": fmt.Sprintf("%d pekerjaan berhasil dihapus permanen", len(purged)),
+        "success": true,
+        "data":    trashBulkResponse(ids, purged),
+    })
+}
+
+// GetTrashPurgeReport is the dry run of the scheduled purge: the pekerjaan
+// trashed longer than TrashRetention ago, oldest first
+func (s *PekerjaanService) GetTrashPurgeReport(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.GetTrashPurgeReport")()
+
+    limit, _ := strconv.Atoi(c.Query("limit", "100"))
+    if limit < 1 {
+        limit = 100
+    }
+
+    report := model.TrashPurgeReport{Items: []model.PekerjaanTrashResponse{}}
+    if s.TrashRetention <= 0 {
+        return c.JSON(fiber.Map{
+            "message": "Retensi trash tidak diaktifkan, tidak ada yang akan dihapus",
+            "success": true,
+            "data":    report,
+        })
+    }
+
+    cutoff := time.Now().Add(-s.TrashRetention)
+    report.Retention = s.TrashRetention.String()
+    report.Cutoff = &cutoff
+
+    list, err := s.Repo.GetExpiredTrash(c.UserContext(), cutoff, limit)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetExpiredTrash failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan trash kedaluwarsa: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    report.Total, err = s.Repo.CountExpiredTrash(c.UserContext(), cutoff)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountExpiredTrash failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghitung trash kedaluwarsa: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    for _, p := range list {
+        report.Items = append(report.Items, p.ToPekerjaanTrashResponse())
+    }
+
+    return c.JSON(fiber.Map{
+        "message": fmt.Sprintf("%d pekerjaan akan dihapus permanen pada purge berikutnya", report.Total),
+        "success": true,
+        "data":    report,
+    })
+}
+
+// GetModerationPekerjaan lists the pekerjaan waiting for (status=pending,
+// the default) or refused by (status=rejected) an admin. Admins see every
+// alumni's submissions, alumni only their own.
+func (s *PekerjaanService) GetModerationPekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.GetModerationPekerjaan")()
+
+    page, _ := strconv.Atoi(c.Query("page", "1"))
+    limit, _ := strconv.Atoi(c.Query("limit", "10"))
+    sortBy := c.Query("sortBy", "updated_at")
+    order := c.Query("order", "asc")
+    search := c.Query("search", "")
+    status := c.Query("status", model.ModerasiPending)
+
+    if page < 1 {
+        page = 1
+    }
+    offset := (page - 1) * limit
+
+    if status != model.ModerasiPending && status != model.ModerasiRejected {
+        return c.Status(400).JSON(fiber.Map{
+            "message": fmt.Sprintf("status harus %s atau %s", model.ModerasiPending, model.ModerasiRejected),
+            "success": false,
+        })
+    }
+
+    userID, isAdmin := callerOf(c)
+
+    list, err := s.Repo.GetModerationPekerjaan(c.UserContext(), userID, isAdmin, status, search, sortBy, order, limit, offset)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetModerationPekerjaan failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan antrian moderasi: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    total, err := s.Repo.CountModerationPekerjaan(c.UserContext(), userID, isAdmin, status, search)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountModerationPekerjaan failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghitung antrian moderasi: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    responses := make([]model.PekerjaanResponse, len(list))
+    for i, pekerjaan := range list {
+        responses[i] = pekerjaan.ToPekerjaanResponse()
+    }
+
+    meta := model.MetaInfo{
+        Page:   page,
+        Limit:  limit,
+        Total:  total,
+        Pages:  int(math.Ceil(float64(total) / float64(limit))),
+        SortBy: sortBy,
+        Order:  order,
+        Search: search,
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan antrian moderasi",
+        "success": true,
+        "data":    responses,
+        "meta":    meta,
+    })
+}
+
+// ApprovePekerjaan publishes a pending pekerjaan. The timeline rules are
+// checked again, since other pekerjaan may have been approved meanwhile.
+func (s *PekerjaanService) ApprovePekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.ApprovePekerjaan")()
+
+    return s.moderate(c, model.ModerasiApproved, "Pekerjaan berhasil disetujui")
+}
+
+// RejectPekerjaan refuses a pending pekerjaan with an optional catatan for
+// the alumni
+func (s *PekerjaanService) RejectPekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.RejectPekerjaan")()
+
+    return s.moderate(c, model.ModerasiRejected, "Pekerjaan berhasil ditolak")
+}
+
+func (s *PekerjaanService) moderate(c *fiber.Ctx, status, message string) error {
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    var req model.ModerasiRequest
+    if len(c.Body()) > 0 {
+        if err := c.BodyParser(&req); err != nil {
+            return c.Status(400).JSON(fiber.Map{
+                "message": "Input tidak valid: " + err.Error(),
+                "success": false,
+            })
+        }
+    }
+
+    pekerjaan, err := s.Repo.FindPekerjaanByID(c.UserContext(), id)
+    if errors.Is(err, repository.ErrNotFoundOrNoAccess) {
+        return c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindPekerjaanByID failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+    if pekerjaan.StatusModerasi != model.ModerasiPending {
+        return c.Status(409).JSON(fiber.Map{
+            "message": "Pekerjaan tidak sedang menunggu persetujuan",
+            "success": false,
+        })
+    }
+
+    if status == model.ModerasiApproved {
+        if ok, err := s.checkTimeline(c, *pekerjaan); !ok {
+            return err
+        }
+    }
+
+    moderatorID, _ := callerOf(c)
+    err = s.Repo.ModeratePekerjaan(c.UserContext(), id, status, strings.TrimSpace(req.Catatan), moderatorID)
+    if errors.Is(err, repository.ErrNotFoundOrNoAccess) {
+        // Moderated or deleted since it was read
+        return c.Status(409).JSON(fiber.Map{
+            "message": "Pekerjaan tidak sedang menunggu persetujuan",
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "ModeratePekerjaan failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal memoderasi pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    pekerjaan, err = s.Repo.FindPekerjaanByID(c.UserContext(), id)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindPekerjaanByID failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "message": message,
+        "success": true,
+        "data":    pekerjaan.ToPekerjaanResponse(),
+    })
+}
+
+// BulkPekerjaan runs up to model.MaxBulkOperations create, update,
+// soft_delete and delete operations as one bulk write. Every operation gets
+// the validation and permission checks of its single-item endpoint, later
+// ones seeing what earlier ones change, and a result of its own. An ordered
+// batch stops at the first failure.
+func (s *PekerjaanService) BulkPekerjaan(c *fiber.Ctx) error {
+    defer traceCall(c, "PekerjaanService.BulkPekerjaan")()
+
+    var req model.PekerjaanBulkRequest
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+    ordered, ok, err := bulkOrdered(c, len(req.Operations), req.Ordered)
+    if !ok {
+        return err
+    }
+
+    own, isAdmin, err := s.callerAlumni(c)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindAlumniByUserID failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan data alumni: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    batch, err := s.newPekerjaanBatch(c.UserContext(), req.Operations)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindPekerjaanByIDs failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    results := make([]model.BulkItemResult, len(req.Operations))
+    writes := []model.PekerjaanWrite{}
+    indexes := []int{}
+    stopped := false
+    for i, op := range req.Operations {
+        results[i] = model.BulkItemResult{Index: i, Op: op.Op, ID: op.ID}
+        if stopped {
+            continue
+        }
+
+        w, status, err := s.pekerjaanBulkWrite(c.UserContext(), op, isAdmin, own, batch)
+        results[i].Status = status
+        if err != nil {
+            results[i].Result = model.BulkFailed
+            results[i].Error = err.Error()
+            stopped = ordered
+            continue
+        }
+        results[i].ID = w.ID.Hex()
+        writes = append(writes, w)
+        indexes = append(indexes, i)
+    }
+
+    errs, err := s.Repo.BulkWritePekerjaan(c.UserContext(), writes, ordered)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "BulkWritePekerjaan failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menjalankan operasi pekerjaan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    return bulkReply(c, results, indexes, errs, ordered)
+}
+
+// pekerjaanBatch holds the pekerjaan a bulk request refers to as its
+// accepted operations will leave them; touched lists, in order, the ids
+// those operations changed
+type pekerjaanBatch struct {
+    found   map[primitive.ObjectID]model.Pekerjaan
+    touched []primitive.ObjectID
+}
+
+// newPekerjaanBatch reads the pekerjaan the operations name by id
+func (s *PekerjaanService) newPekerjaanBatch(ctx context.Context, ops []model.PekerjaanBulkOperation) (*pekerjaanBatch, error) {
+    batch := &pekerjaanBatch{found: map[primitive.ObjectID]model.Pekerjaan{}}
+
+    ids := []primitive.ObjectID{}
+    for _, op := range ops {
+        if id, err := primitive.ObjectIDFromHex(op.ID); err == nil {
+            ids = append(ids, id)
+        }
+    }
+    if len(ids) == 0 {
+        return batch, nil
+    }
+
+    list, err := s.Repo.FindPekerjaanByIDs(ctx, ids)
+    if err != nil {
+        return nil, err
+    }
+    for _, p := range list {
+        batch.found[p.ID] = p
+    }
+    return batch, nil
+}
+
+// set records p as an accepted operation leaves it; a nil p was deleted
+func (b *pekerjaanBatch) set(id primitive.ObjectID, p *model.Pekerjaan) {
+    if p != nil {
+        b.found[id] = *p
+    } else {
+        delete(b.found, id)
+    }
+    b.touched = append(b.touched, id)
+}
+
+// others replaces the pekerjaan of list, those of alumniID in the timeline,
+// with what the batch made of them and adds the ones it created
+func (b *pekerjaanBatch) others(alumniID primitive.ObjectID, list []model.Pekerjaan) []model.Pekerjaan {
+    touched := map[primitive.ObjectID]bool{}
+    merged := []model.Pekerjaan{}
+    for _, id := range b.touched {
+        if touched[id] {
+            continue
+        }
+        touched[id] = true
+        if p, ok := b.found[id]; ok && p.AlumniID == alumniID && p.IsDelete == nil && p.Approved() {
+            merged = append(merged, p)
+        }
+    }
+    for _, p := range list {
+        if !touched[p.ID] {
+            merged = append(merged, p)
+        }
+    }
+    return merged
+}
+
+// owns reports whether the caller may touch p; admins may touch any
+func owns(isAdmin bool, own *model.Alumni, p model.Pekerjaan) bool {
+    return isAdmin || (own != nil && p.AlumniID == own.ID)
+}
+
+// pekerjaanBulkWrite checks one operation of BulkPekerjaan like its
+// single-item endpoint, returning the status that endpoint would reply
+func (s *PekerjaanService) pekerjaanBulkWrite(ctx context.Context, op model.PekerjaanBulkOperation, isAdmin bool, own *model.Alumni, batch *pekerjaanBatch) (model.PekerjaanWrite, int, error) {
+    w := model.PekerjaanWrite{Op: op.Op}
+    switch op.Op {
+    case model.BulkCreate:
+        w.ID = primitive.NewObjectID()
+    case model.BulkUpdate, model.BulkSoftDelete, model.BulkDelete:
+        id, err := primitive.ObjectIDFromHex(op.ID)
+        if err != nil {
+            return w, 400, errors.New("ID tidak valid")
+        }
+        w.ID = id
+    default:
+        return w, 400, fmt.Errorf("op %q tidak dikenal", op.Op)
+    }
+    existing, found := batch.found[w.ID]
+
+    switch op.Op {
+    case model.BulkSoftDelete:
+        if !found || existing.IsDelete != nil || !owns(isAdmin, own, existing) {
+            return w, 404, repository.ErrNotFoundOrNoAccess
+        }
+        now := time.Now()
+        existing.IsDelete = &now
+        batch.set(w.ID, &existing)
+        return w, 200, nil
+    case model.BulkDelete:
+        // Like DELETE /pekerjaan/trash/:id only trashed pekerjaan go
+        if !found || existing.IsDelete == nil || !owns(isAdmin, own, existing) {
+            return w, 404, repository.ErrNotFoundOrNoAccess
+        }
+        batch.set(w.ID, nil)
+        return w, 200, nil
+    }
+
+    if op.Pekerjaan == nil {
+        return w, 400, errors.New("pekerjaan wajib diisi")
+    }
+    alumniID, status, err := submitter(isAdmin, own, op.Pekerjaan.AlumniID)
+    if err != nil {
+        return w, status, err
+    }
+
+    moderasi := model.ModerasiApproved
+    if op.Op == model.BulkUpdate {
+        if !found || (!isAdmin && (existing.IsDelete != nil || existing.AlumniID != alumniID)) {
+            return w, 404, repository.ErrNotFoundOrNoAccess
+        }
+        moderasi = ""
+    }
+    if !isAdmin {
+        moderasi = model.ModerasiPending
+    }
+
+    p, err := pekerjaanFromRequest(*op.Pekerjaan, alumniID, moderasi)
+    if err != nil {
+        return w, 400, err
+    }
+    p.ID = w.ID
+    if status, err := s.timelineError(ctx, p, batch); err != nil {
+        return w, status, err
+    }
+    if _, err := s.matchCompany(ctx, &p, op.Pekerjaan.CompanyID); err != nil {
+        status, err := companyMatchStatus(ctx, err)
+        return w, status, err
+    }
+    w.Pekerjaan = p
+
+    after, status := p, 201
+    if op.Op == model.BulkUpdate {
+        // Admin updates keep status_moderasi, so does the batch
+        after.IsDelete = existing.IsDelete
+        if after.StatusModerasi == "" {
+            after.StatusModerasi = existing.StatusModerasi
+        }
+        status = 200
+    }
+    batch.set(w.ID, &after)
+    return w, status, nil
+}
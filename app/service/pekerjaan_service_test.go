@@ -0,0 +1,878 @@
+package service
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pekerjaanFixture has one alumni owned by owner with one pekerjaan, and one
+// alumni owned by someone else
+type pekerjaanFixture struct {
+    repos     repository.Repositories
+    service   *PekerjaanService
+    owner     caller
+    stranger  caller
+    alumni    model.Alumni
+    pekerjaan model.Pekerjaan
+    other     model.Pekerjaan
+}
+
+func newPekerjaanFixture(t *testing.T) pekerjaanFixture {
+    repos := repository.NewMemoryRepositories()
+    f := pekerjaanFixture{
+        repos:    repos,
+        service:  NewPekerjaanService(repos.Pekerjaan),
+        owner:    caller{userID: primitive.NewObjectID(), role: "user"},
+        stranger: caller{userID: primitive.NewObjectID(), role: "user"},
+    }
+    f.service.Alumni = repos.Alumni
+
+    f.alumni = newAlumni(t, repos, "1234567001", "John Doe", "Teknik Informatika", f.owner.userID)
+    otherAlumni := newAlumni(t, repos, "1234567002", "Jane Smith", "Sistem Informasi", f.stranger.userID)
+
+    f.pekerjaan = newPekerjaan(t, repos, f.alumni.ID, "PT Digital Indonesia")
+    f.other = newPekerjaan(t, repos, otherAlumni.ID, "PT Media Online")
+    return f
+}
+
+func TestGetPekerjaanByAlumniID(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("GET", "/pekerjaan/alumni/:alumni_id", f.service.GetPekerjaanByAlumniID, adminCaller)
+
+    resp := do(t, app, "GET", "/pekerjaan/alumni/"+f.alumni.ID.Hex(), nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var list []model.PekerjaanResponse
+    decodeData(t, resp, &list)
+    if len(list) != 1 || list[0].ID != f.pekerjaan.ID.Hex() {
+        t.Errorf("unexpected list %+v", list)
+    }
+
+    if resp := do(t, app, "GET", "/pekerjaan/alumni/xyz", nil); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestCreatePekerjaan(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, adminCaller)
+
+    resp := do(t, app, "POST", "/pekerjaan", model.CreatePekerjaanRequest{
+        AlumniID:            f.alumni.ID.Hex(),
+        NamaPerusahaan:      "PT Startup Indonesia",
+        PosisiJabatan:       "Full Stack Developer",
+        BidangIndustri:      "E-commerce",
+        LokasiKerja:         "Jakarta",
+        TanggalMulaiKerja:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+        TanggalSelesaiKerja: ptr(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)),
+        StatusPekerjaan:     "resign",
+    })
+    if resp.Status != 201 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    var created model.PekerjaanResponse
+    decodeData(t, resp, &created)
+    if created.AlumniID != f.alumni.ID.Hex() || created.NamaPerusahaan != "PT Startup Indonesia" {
+        t.Errorf("unexpected pekerjaan %+v", created)
+    }
+
+    if resp := do(t, app, "POST", "/pekerjaan", model.CreatePekerjaanRequest{AlumniID: "bad"}); resp.Status != 400 {
+        t.Errorf("bad alumni id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestCreatePekerjaanGaji(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, adminCaller)
+
+    request := func(gaji func(*model.CreatePekerjaanRequest)) model.CreatePekerjaanRequest {
+        req := model.CreatePekerjaanRequest{
+            AlumniID:            f.alumni.ID.Hex(),
+            NamaPerusahaan:      "PT Startup Indonesia",
+            PosisiJabatan:       "Full Stack Developer",
+            BidangIndustri:      "E-commerce",
+            LokasiKerja:         "Jakarta",
+            TanggalMulaiKerja:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+            TanggalSelesaiKerja: ptr(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)),
+            StatusPekerjaan:     "resign",
+        }
+        gaji(&req)
+        return req
+    }
+
+    // Older clients still send the free text
+    resp := do(t, app, "POST", "/pekerjaan", request(func(r *model.CreatePekerjaanRequest) { r.GajiRange = "Rp 7,5 - 10 jt/bulan" }))
+    if resp.Status != 201 {
+        t.Fatalf("gaji_range: status = %d, body = %+v", resp.Status, resp)
+    }
+    var created model.PekerjaanResponse
+    decodeData(t, resp, &created)
+    if created.GajiMin == nil || *created.GajiMin != 7_500_000 || *created.GajiMax != 10_000_000 ||
+        created.GajiCurrency != "IDR" || created.GajiPeriod != "monthly" || created.GajiRange != "7,5-10 juta" {
+        t.Errorf("gaji_range not structured: %+v", created)
+    }
+
+    gajiMin := int64(150_000_000)
+    resp = do(t, app, "POST", "/pekerjaan", request(func(r *model.CreatePekerjaanRequest) {
+        r.GajiMin, r.GajiPeriod = &gajiMin, model.GajiAnnual
+    }))
+    decodeData(t, resp, &created)
+    if resp.Status != 201 || created.GajiMax != nil || created.GajiRange != "mulai 150 juta per tahun" {
+        t.Errorf("open range: status %d, pekerjaan %+v", resp.Status, created)
+    }
+
+    gajiMax := int64(100)
+    for name, gaji := range map[string]func(*model.CreatePekerjaanRequest){
+        "unparseable":   func(r *model.CreatePekerjaanRequest) { r.GajiRange = "negotiable" },
+        "min above max": func(r *model.CreatePekerjaanRequest) { r.GajiMin, r.GajiMax = &gajiMin, &gajiMax },
+        "bad period":    func(r *model.CreatePekerjaanRequest) { r.GajiMin, r.GajiPeriod = &gajiMin, "weekly" },
+    } {
+        if resp := do(t, app, "POST", "/pekerjaan", request(gaji)); resp.Status != 400 {
+            t.Errorf("%s: status = %d, want 400", name, resp.Status)
+        }
+    }
+}
+
+func TestCreatePekerjaanMatchesCompany(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    f.service.Companies = f.repos.Company
+    company := model.Company{}
+    company.SetNames("PT Digital Indonesia", []string{"DigiIndo"})
+    registered, err := f.repos.Company.CreateCompany(context.Background(), company)
+    if err != nil {
+        t.Fatal(err)
+    }
+    app := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, adminCaller)
+
+    request := func(perusahaan, companyID string) model.CreatePekerjaanRequest {
+        return model.CreatePekerjaanRequest{
+            AlumniID:            f.alumni.ID.Hex(),
+            NamaPerusahaan:      perusahaan,
+            CompanyID:           companyID,
+            PosisiJabatan:       "Data Engineer",
+            BidangIndustri:      "Technology",
+            LokasiKerja:         "Jakarta",
+            TanggalMulaiKerja:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+            TanggalSelesaiKerja: ptr(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)),
+            StatusPekerjaan:     "resign",
+        }
+    }
+
+    // Same name once normalized: linked
+    resp := do(t, app, "POST", "/pekerjaan", request("PT. Digital Indonesia Tbk", ""))
+    var created model.PekerjaanResponse
+    decodeData(t, resp, &created)
+    if resp.Status != 201 || created.CompanyID != registered.ID.Hex() || len(created.CompanySuggestions) != 0 {
+        t.Errorf("exact name: status = %d, pekerjaan = %+v", resp.Status, created)
+    }
+
+    // Close name: suggested, not linked
+    resp = do(t, app, "POST", "/pekerjaan", request("PT Digital Indonesa", ""))
+    created = model.PekerjaanResponse{}
+    decodeData(t, resp, &created)
+    if resp.Status != 201 || created.CompanyID != "" || len(created.CompanySuggestions) != 1 ||
+        created.CompanySuggestions[0].Company.ID != registered.ID.Hex() {
+        t.Errorf("close name: status = %d, pekerjaan = %+v", resp.Status, created)
+    }
+
+    // An explicit company_id wins over the name
+    resp = do(t, app, "POST", "/pekerjaan", request("Kantor Lama", registered.ID.Hex()))
+    created = model.PekerjaanResponse{}
+    decodeData(t, resp, &created)
+    if resp.Status != 201 || created.CompanyID != registered.ID.Hex() {
+        t.Errorf("company_id: status = %d, pekerjaan = %+v", resp.Status, created)
+    }
+
+    for name, companyID := range map[string]string{"bad": "xyz", "unknown": f.alumni.ID.Hex()} {
+        if resp := do(t, app, "POST", "/pekerjaan", request("PT Lain", companyID)); resp.Status != 400 {
+            t.Errorf("%s company_id: status = %d, want 400", name, resp.Status)
+        }
+    }
+}
+
+func TestUpdatePekerjaan(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("PUT", "/pekerjaan/:id", f.service.UpdatePekerjaan, adminCaller)
+
+    end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    resp := do(t, app, "PUT", "/pekerjaan/"+f.pekerjaan.ID.Hex(), model.UpdatePekerjaanRequest{
+        AlumniID:            f.alumni.ID.Hex(),
+        NamaPerusahaan:      f.pekerjaan.NamaPerusahaan,
+        PosisiJabatan:       "Senior Backend Engineer",
+        BidangIndustri:      f.pekerjaan.BidangIndustri,
+        LokasiKerja:         f.pekerjaan.LokasiKerja,
+        TanggalMulaiKerja:   f.pekerjaan.TanggalMulaiKerja,
+        TanggalSelesaiKerja: &end,
+        StatusPekerjaan:     "resign",
+    })
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), f.alumni.ID)
+    if len(list) != 1 || list[0].PosisiJabatan != "Senior Backend Engineer" || list[0].StatusPekerjaan != "resign" {
+        t.Errorf("pekerjaan not updated: %+v", list)
+    }
+
+    if resp := do(t, app, "PUT", "/pekerjaan/bad", model.UpdatePekerjaanRequest{}); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+
+    missing := primitive.NewObjectID()
+    if resp := do(t, app, "PUT", "/pekerjaan/"+missing.Hex(), model.UpdatePekerjaanRequest(pastJob(f.alumni.ID.Hex()))); resp.Status != 404 {
+        t.Errorf("unknown id: status = %d, want 404", resp.Status)
+    }
+    if _, err := f.repos.Pekerjaan.FindPekerjaanByID(context.Background(), missing); err == nil {
+        t.Error("updating an unknown id created it")
+    }
+}
+
+// pastJob is a finished pekerjaan that overlaps nothing in the fixture
+func pastJob(alumniID string) model.CreatePekerjaanRequest {
+    return model.CreatePekerjaanRequest{
+        AlumniID:            alumniID,
+        NamaPerusahaan:      "PT Startup Indonesia",
+        PosisiJabatan:       "Junior Developer",
+        BidangIndustri:      "E-commerce",
+        LokasiKerja:         "Jakarta",
+        TanggalMulaiKerja:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+        TanggalSelesaiKerja: ptr(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)),
+        StatusPekerjaan:     "resign",
+    }
+}
+
+func TestCreatePekerjaanByOwnerIsPending(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, f.owner)
+
+    // alumni_id may be left out, it defaults to the caller's own alumni
+    resp := do(t, app, "POST", "/pekerjaan", pastJob(""))
+    if resp.Status != 201 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var created model.PekerjaanResponse
+    decodeData(t, resp, &created)
+    if created.AlumniID != f.alumni.ID.Hex() || created.StatusModerasi != model.ModerasiPending {
+        t.Errorf("created = %+v", created)
+    }
+
+    // Not listed until approved
+    list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), f.alumni.ID)
+    if len(list) != 1 {
+        t.Errorf("pending pekerjaan listed: %+v", list)
+    }
+    if n, _ := f.repos.Pekerjaan.CountPekerjaan(context.Background(), model.PekerjaanFilter{}); n != 2 {
+        t.Errorf("CountPekerjaan = %d, want 2", n)
+    }
+
+    // Someone else's alumni
+    if resp := do(t, app, "POST", "/pekerjaan", pastJob(f.other.AlumniID.Hex())); resp.Status != 403 {
+        t.Errorf("other alumni: status = %d, want 403", resp.Status)
+    }
+
+    // An account without alumni
+    lone := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, caller{userID: primitive.NewObjectID(), role: "user"})
+    if resp := do(t, lone, "POST", "/pekerjaan", pastJob("")); resp.Status != 403 {
+        t.Errorf("no alumni: status = %d, want 403", resp.Status)
+    }
+}
+
+func TestUpdatePekerjaanByOwner(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("PUT", "/pekerjaan/:id", f.service.UpdatePekerjaan, f.owner)
+
+    req := model.UpdatePekerjaanRequest(pastJob(""))
+    if resp := do(t, app, "PUT", "/pekerjaan/"+f.other.ID.Hex(), req); resp.Status != 404 {
+        t.Errorf("stranger's pekerjaan: status = %d, want 404", resp.Status)
+    }
+
+    req.TanggalSelesaiKerja = nil
+    req.StatusPekerjaan = "aktif"
+    req.TanggalMulaiKerja = f.pekerjaan.TanggalMulaiKerja
+    resp := do(t, app, "PUT", "/pekerjaan/"+f.pekerjaan.ID.Hex(), req)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+
+    p, _ := f.repos.Pekerjaan.FindPekerjaanByID(context.Background(), f.pekerjaan.ID)
+    if p.StatusModerasi != model.ModerasiPending || p.PosisiJabatan != "Junior Developer" {
+        t.Errorf("edit should wait for approval: %+v", p)
+    }
+}
+
+func TestModeratePekerjaan(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    submit := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, f.owner)
+    queue := newTestApp("GET", "/pekerjaan/moderation", f.service.GetModerationPekerjaan, adminCaller)
+    ownQueue := newTestApp("GET", "/pekerjaan/moderation", f.service.GetModerationPekerjaan, f.stranger)
+    approve := newTestApp("PUT", "/pekerjaan/moderation/approve/:id", f.service.ApprovePekerjaan, adminCaller)
+    reject := newTestApp("PUT", "/pekerjaan/moderation/reject/:id", f.service.RejectPekerjaan, adminCaller)
+
+    var first, second model.PekerjaanResponse
+    decodeData(t, do(t, submit, "POST", "/pekerjaan", pastJob("")), &first)
+    req := pastJob("")
+    req.TanggalMulaiKerja, req.TanggalSelesaiKerja = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), ptr(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+    decodeData(t, do(t, submit, "POST", "/pekerjaan", req), &second)
+
+    resp := do(t, queue, "GET", "/pekerjaan/moderation", nil)
+    if resp.Status != 200 || resp.Meta.Total != 2 {
+        t.Fatalf("queue: status = %d, total = %d", resp.Status, resp.Meta.Total)
+    }
+    if resp := do(t, ownQueue, "GET", "/pekerjaan/moderation", nil); resp.Meta.Total != 0 {
+        t.Errorf("stranger sees %d submissions", resp.Meta.Total)
+    }
+    if resp := do(t, queue, "GET", "/pekerjaan/moderation?status=approved", nil); resp.Status != 400 {
+        t.Errorf("status=approved: status = %d, want 400", resp.Status)
+    }
+
+    if resp := do(t, approve, "PUT", "/pekerjaan/moderation/approve/"+first.ID, nil); resp.Status != 200 {
+        t.Fatalf("approve: status = %d, body = %+v", resp.Status, resp)
+    }
+    if resp := do(t, approve, "PUT", "/pekerjaan/moderation/approve/"+first.ID, nil); resp.Status != 409 {
+        t.Errorf("approve twice: status = %d, want 409", resp.Status)
+    }
+    if resp := do(t, approve, "PUT", "/pekerjaan/moderation/approve/"+f.other.ID.Hex(), nil); resp.Status != 409 {
+        t.Errorf("approve an approved pekerjaan: status = %d, want 409", resp.Status)
+    }
+
+    resp = do(t, reject, "PUT", "/pekerjaan/moderation/reject/"+second.ID, model.ModerasiRequest{Catatan: "Tanggal tidak sesuai"})
+    if resp.Status != 200 {
+        t.Fatalf("reject: status = %d, body = %+v", resp.Status, resp)
+    }
+    var rejected model.PekerjaanResponse
+    decodeData(t, resp, &rejected)
+    if rejected.StatusModerasi != model.ModerasiRejected || rejected.CatatanModerasi != "Tanggal tidak sesuai" || rejected.ModeratedAt == nil {
+        t.Errorf("rejected = %+v", rejected)
+    }
+
+    list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), f.alumni.ID)
+    if len(list) != 2 {
+        t.Errorf("listed %d pekerjaan, want the fixture's and the approved one", len(list))
+    }
+    if resp := do(t, queue, "GET", "/pekerjaan/moderation?status=rejected", nil); resp.Meta.Total != 1 {
+        t.Errorf("rejected total = %d, want 1", resp.Meta.Total)
+    }
+}
+
+func TestCreatePekerjaanTimelineRules(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan", f.service.CreatePekerjaan, adminCaller)
+
+    request := func(start time.Time, end *time.Time, status string) model.CreatePekerjaanRequest {
+        return model.CreatePekerjaanRequest{
+            AlumniID:            f.alumni.ID.Hex(),
+            NamaPerusahaan:      "PT Startup Indonesia",
+            PosisiJabatan:       "Full Stack Developer",
+            TanggalMulaiKerja:   start,
+            TanggalSelesaiKerja: end,
+            StatusPekerjaan:     status,
+        }
+    }
+    start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+
+    for name, req := range map[string]model.CreatePekerjaanRequest{
+        "end before start":   request(start, ptr(start.AddDate(0, -1, 0)), "resign"),
+        "aktif already over": request(start, ptr(start.AddDate(1, 0, 0)), "aktif"),
+        "resign without end": request(start, nil, "resign"),
+    } {
+        if resp := do(t, app, "POST", "/pekerjaan", req); resp.Status != 400 {
+            t.Errorf("%s: status = %d, want 400", name, resp.Status)
+        }
+    }
+
+    // The fixture pekerjaan is still aktif
+    second := request(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), nil, "aktif")
+    if resp := do(t, app, "POST", "/pekerjaan", second); resp.Status != 409 {
+        t.Errorf("second aktif: status = %d, want 409", resp.Status)
+    }
+
+    // A finished side job during it is fine unless no overlap is allowed
+    side := request(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), ptr(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)), "resign")
+    f.service.OverlapPolicy = model.OverlapNone
+    if resp := do(t, app, "POST", "/pekerjaan", side); resp.Status != 409 {
+        t.Errorf("side job, policy none: status = %d, want 409", resp.Status)
+    }
+    f.service.OverlapPolicy = model.OverlapActive
+    if resp := do(t, app, "POST", "/pekerjaan", side); resp.Status != 201 {
+        t.Errorf("side job, policy active: status = %d, want 201", resp.Status)
+    }
+
+    f.service.OverlapPolicy = model.OverlapAllow
+    if resp := do(t, app, "POST", "/pekerjaan", second); resp.Status != 201 {
+        t.Errorf("second aktif, policy allow: status = %d, want 201", resp.Status)
+    }
+}
+
+func TestExpireContracts(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    ctx := context.Background()
+
+    // Was valid when written, the contract has run out since
+    ended := f.pekerjaan
+    ended.TanggalSelesaiKerja = ptr(time.Now().Add(-time.Hour))
+    if _, err := f.repos.Pekerjaan.UpdatePekerjaan(ctx, f.pekerjaan.ID, ended); err != nil {
+        t.Fatal(err)
+    }
+
+    n, err := f.repos.Pekerjaan.ExpireContracts(ctx, time.Now())
+    if err != nil || n != 1 {
+        t.Fatalf("expired %d, err %v; want 1", n, err)
+    }
+    list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(ctx, f.alumni.ID)
+    if len(list) != 1 || list[0].StatusPekerjaan != model.StatusKontrakHabis {
+        t.Errorf("pekerjaan = %+v, want kontrak_habis", list)
+    }
+
+    // The other pekerjaan has no end date
+    if n, _ := f.repos.Pekerjaan.ExpireContracts(ctx, time.Now()); n != 0 {
+        t.Errorf("second run expired %d, want 0", n)
+    }
+}
+
+func TestGetAllPekerjaanDatatableHidesTrash(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("GET", "/pekerjaan", f.service.GetAllPekerjaanDatatable, adminCaller)
+
+    if err := f.repos.Pekerjaan.SoftDelete(context.Background(), f.other.ID, adminCaller.userID, true); err != nil {
+        t.Fatal(err)
+    }
+
+    resp := do(t, app, "GET", "/pekerjaan", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var list []model.PekerjaanResponse
+    decodeData(t, resp, &list)
+    if len(list) != 1 || list[0].ID != f.pekerjaan.ID.Hex() || resp.Meta.Total != 1 {
+        t.Errorf("unexpected list %+v, meta %+v", list, resp.Meta)
+    }
+
+    resp = do(t, app, "GET", "/pekerjaan?search=media", nil)
+    decodeData(t, resp, &list)
+    if len(list) != 0 {
+        t.Errorf("search returned deleted pekerjaan: %+v", list)
+    }
+}
+
+func TestGetAllPekerjaanDatatableGajiFilter(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("GET", "/pekerjaan", f.service.GetAllPekerjaanDatatable, adminCaller)
+
+    // f.pekerjaan and f.other pay 8-12 juta; add 15-20 juta and one without
+    senior := f.pekerjaan
+    senior.SetGaji(model.Gaji{Min: ptr(int64(15_000_000)), Max: ptr(int64(20_000_000)), Currency: "IDR", Period: "monthly"})
+    senior = createPekerjaan(t, f, senior)
+    unknown := f.pekerjaan
+    unknown.GajiMin, unknown.GajiMax, unknown.GajiRange, unknown.GajiReview = nil, nil, "negotiable", true
+    createPekerjaan(t, f, unknown)
+
+    for query, want := range map[string]int{
+        "":                                     4,
+        "?gaji_min=13000000":                   1,
+        "?gaji_max=12000000":                   2,
+        "?gaji_min=10000000&gaji_max=16000000": 3,
+        "?gaji_currency=idr":                   3,
+        "?gaji_period=annual":                  0,
+    } {
+        resp := do(t, app, "GET", "/pekerjaan"+query, nil)
+        if resp.Status != 200 || resp.Meta.Total != want {
+            t.Errorf("%q: status %d, total %d, want %d", query, resp.Status, resp.Meta.Total, want)
+        }
+    }
+
+    resp := do(t, app, "GET", "/pekerjaan?sortBy=gaji_min&order=desc&limit=1", nil)
+    var list []model.PekerjaanResponse
+    decodeData(t, resp, &list)
+    if len(list) != 1 || list[0].ID != senior.ID.Hex() {
+        t.Errorf("sort by gaji_min desc: %+v", list)
+    }
+
+    for _, query := range []string{"?gaji_min=abc", "?gaji_min=-1", "?gaji_min=5&gaji_max=1", "?gaji_period=weekly"} {
+        if resp := do(t, app, "GET", "/pekerjaan"+query, nil); resp.Status != 400 {
+            t.Errorf("%q: status = %d, want 400", query, resp.Status)
+        }
+    }
+}
+
+func createPekerjaan(t *testing.T, f pekerjaanFixture, p model.Pekerjaan) model.Pekerjaan {
+    t.Helper()
+    created, err := f.repos.Pekerjaan.CreatePekerjaan(context.Background(), p)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return *created
+}
+
+func ptr[T any](v T) *T {
+    return &v
+}
+
+func TestSoftDeletePekerjaanOwnership(t *testing.T) {
+    f := newPekerjaanFixture(t)
+
+    // A stranger cannot delete someone else's pekerjaan
+    app := newTestApp("DELETE", "/pekerjaan/:id", f.service.SoftDeletePekerjaan, f.stranger)
+    if resp := do(t, app, "DELETE", "/pekerjaan/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 500 || resp.Success {
+        t.Errorf("stranger delete: status = %d, want 500", resp.Status)
+    }
+
+    // A user without alumni record cannot delete anything
+    app = newTestApp("DELETE", "/pekerjaan/:id", f.service.SoftDeletePekerjaan, caller{userID: primitive.NewObjectID(), role: "user"})
+    if resp := do(t, app, "DELETE", "/pekerjaan/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 500 {
+        t.Errorf("no alumni delete: status = %d, want 500", resp.Status)
+    }
+
+    app = newTestApp("DELETE", "/pekerjaan/:id", f.service.SoftDeletePekerjaan, f.owner)
+    if resp := do(t, app, "DELETE", "/pekerjaan/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 200 {
+        t.Fatalf("owner delete: status = %d", resp.Status)
+    }
+
+    if list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), f.alumni.ID); len(list) != 0 {
+        t.Errorf("soft-deleted pekerjaan still listed: %+v", list)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(context.Background(), f.owner.userID, false, ""); total != 1 {
+        t.Errorf("trash has %d items, want 1", total)
+    }
+
+    if resp := do(t, app, "DELETE", "/pekerjaan/bad", nil); resp.Status != 400 {
+        t.Errorf("bad id: status = %d, want 400", resp.Status)
+    }
+}
+
+func TestGetTrashPekerjaanScopedToOwner(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    for _, p := range []model.Pekerjaan{f.pekerjaan, f.other} {
+        if err := f.repos.Pekerjaan.SoftDelete(context.Background(), p.ID, adminCaller.userID, true); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    app := newTestApp("GET", "/pekerjaan/trash", f.service.GetTrashPekerjaan, f.owner)
+    resp := do(t, app, "GET", "/pekerjaan/trash", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+
+    var list []model.PekerjaanTrashResponse
+    decodeData(t, resp, &list)
+    if len(list) != 1 || list[0].ID != f.pekerjaan.ID.Hex() || list[0].DeletedAt.IsZero() {
+        t.Errorf("owner trash = %+v", list)
+    }
+
+    app = newTestApp("GET", "/pekerjaan/trash", f.service.GetTrashPekerjaan, adminCaller)
+    resp = do(t, app, "GET", "/pekerjaan/trash", nil)
+    if resp.Meta.Total != 2 {
+        t.Errorf("admin trash total = %d, want 2", resp.Meta.Total)
+    }
+}
+
+func TestRestorePekerjaan(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    if err := f.repos.Pekerjaan.SoftDelete(context.Background(), f.pekerjaan.ID, f.owner.userID, false); err != nil {
+        t.Fatal(err)
+    }
+
+    app := newTestApp("PUT", "/pekerjaan/trash/restore/:id", f.service.RestorePekerjaan, f.stranger)
+    if resp := do(t, app, "PUT", "/pekerjaan/trash/restore/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 404 {
+        t.Errorf("stranger restore: status = %d, want 404", resp.Status)
+    }
+
+    app = newTestApp("PUT", "/pekerjaan/trash/restore/:id", f.service.RestorePekerjaan, f.owner)
+    if resp := do(t, app, "PUT", "/pekerjaan/trash/restore/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 200 {
+        t.Fatalf("owner restore: status = %d", resp.Status)
+    }
+    if list, _ := f.repos.Pekerjaan.FindPekerjaanByAlumniID(context.Background(), f.alumni.ID); len(list) != 1 {
+        t.Errorf("restored pekerjaan not listed")
+    }
+
+    // Restoring something that is not in the trash fails
+    if resp := do(t, app, "PUT", "/pekerjaan/trash/restore/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 404 {
+        t.Errorf("restore active: status = %d, want 404", resp.Status)
+    }
+}
+
+func TestHardDeletePekerjaan(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("DELETE", "/pekerjaan/trash/:id", f.service.HardDeletePekerjaan, f.owner)
+
+    // Only trashed pekerjaan can be deleted permanently
+    if resp := do(t, app, "DELETE", "/pekerjaan/trash/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 404 {
+        t.Errorf("hard delete active: status = %d, want 404", resp.Status)
+    }
+
+    if err := f.repos.Pekerjaan.SoftDelete(context.Background(), f.pekerjaan.ID, f.owner.userID, false); err != nil {
+        t.Fatal(err)
+    }
+    if resp := do(t, app, "DELETE", "/pekerjaan/trash/"+f.pekerjaan.ID.Hex(), nil); resp.Status != 200 {
+        t.Fatalf("hard delete: status = %d", resp.Status)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(context.Background(), adminCaller.userID, true, ""); total != 0 {
+        t.Errorf("trash has %d items, want 0", total)
+    }
+}
+
+func TestRestorePekerjaanBulk(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan/trash/restore", f.service.RestorePekerjaanBulk, f.owner)
+    ctx := context.Background()
+
+    second := createPekerjaan(t, f, f.pekerjaan)
+    for _, id := range []primitive.ObjectID{f.pekerjaan.ID, second.ID, f.other.ID} {
+        if err := f.repos.Pekerjaan.SoftDelete(ctx, id, adminCaller.userID, true); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    // The stranger's pekerjaan is skipped, duplicates count once
+    ids := []string{f.pekerjaan.ID.Hex(), second.ID.Hex(), f.other.ID.Hex(), f.pekerjaan.ID.Hex()}
+    resp := do(t, app, "POST", "/pekerjaan/trash/restore", model.TrashBulkRequest{IDs: ids})
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var result model.TrashBulkResponse
+    decodeData(t, resp, &result)
+    if len(result.Processed) != 2 || len(result.Skipped) != 1 || result.Skipped[0] != f.other.ID.Hex() {
+        t.Errorf("result = %+v", result)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(ctx, adminCaller.userID, true, ""); total != 1 {
+        t.Errorf("trash has %d items, want 1", total)
+    }
+
+    for name, body := range map[string]interface{}{
+        "no ids":     model.TrashBulkRequest{},
+        "invalid id": model.TrashBulkRequest{IDs: []string{"xyz"}},
+    } {
+        if resp := do(t, app, "POST", "/pekerjaan/trash/restore", body); resp.Status != 400 {
+            t.Errorf("%s: status = %d, want 400", name, resp.Status)
+        }
+    }
+}
+
+func TestPurgePekerjaanBulk(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan/trash/purge", f.service.PurgePekerjaanBulk, adminCaller)
+    ctx := context.Background()
+
+    if err := f.repos.Pekerjaan.SoftDelete(ctx, f.other.ID, adminCaller.userID, true); err != nil {
+        t.Fatal(err)
+    }
+
+    // Active pekerjaan are never purged
+    resp := do(t, app, "POST", "/pekerjaan/trash/purge", model.TrashBulkRequest{IDs: []string{f.pekerjaan.ID.Hex(), f.other.ID.Hex()}})
+    var result model.TrashBulkResponse
+    decodeData(t, resp, &result)
+    if len(result.Processed) != 1 || result.Processed[0] != f.other.ID.Hex() || len(result.Skipped) != 1 {
+        t.Errorf("result = %+v", result)
+    }
+    if n, _ := f.repos.Pekerjaan.CountPekerjaan(ctx, model.PekerjaanFilter{}); n != 1 {
+        t.Errorf("%d active pekerjaan left, want 1", n)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(ctx, adminCaller.userID, true, ""); total != 0 {
+        t.Errorf("trash has %d items, want 0", total)
+    }
+}
+
+func TestTrashRetention(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("GET", "/pekerjaan/trash/purge/preview", f.service.GetTrashPurgeReport, adminCaller)
+    ctx := context.Background()
+
+    // Retention off: nothing is due
+    var report model.TrashPurgeReport
+    decodeData(t, do(t, app, "GET", "/pekerjaan/trash/purge/preview", nil), &report)
+    if report.Cutoff != nil || report.Total != 0 {
+        t.Errorf("retention off: report = %+v", report)
+    }
+
+    f.service.TrashRetention = 30 * 24 * time.Hour
+    old, recent := f.pekerjaan, f.pekerjaan
+    old.IsDelete = ptr(time.Now().Add(-40 * 24 * time.Hour))
+    recent.IsDelete = ptr(time.Now().Add(-time.Hour))
+    old = createPekerjaan(t, f, old)
+    createPekerjaan(t, f, recent)
+
+    resp := do(t, app, "GET", "/pekerjaan/trash/purge/preview", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d", resp.Status)
+    }
+    decodeData(t, resp, &report)
+    if report.Total != 1 || len(report.Items) != 1 || report.Items[0].ID != old.ID.Hex() || report.Cutoff == nil {
+        t.Errorf("report = %+v", report)
+    }
+
+    // The scheduled purge removes exactly what the report listed
+    n, err := f.repos.Pekerjaan.PurgeTrash(ctx, *report.Cutoff)
+    if err != nil || n != 1 {
+        t.Fatalf("purged %d, err %v; want 1", n, err)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(ctx, adminCaller.userID, true, ""); total != 1 {
+        t.Errorf("trash has %d items, want the recent one", total)
+    }
+}
+
+func bulkResponse(t *testing.T, resp testResponse) model.BulkResponse {
+    t.Helper()
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var result model.BulkResponse
+    decodeData(t, resp, &result)
+    return result
+}
+
+func bulkOutcomes(result model.BulkResponse) []string {
+    outcomes := make([]string, len(result.Results))
+    for i, r := range result.Results {
+        outcomes[i] = fmt.Sprintf("%s %d", r.Result, r.Status)
+    }
+    return outcomes
+}
+
+func TestBulkPekerjaanOrdered(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan/bulk", f.service.BulkPekerjaan, adminCaller)
+    ctx := context.Background()
+
+    update := pastJob(f.other.AlumniID.Hex())
+    update.PosisiJabatan = "Senior Developer"
+    resp := do(t, app, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{
+        Operations: []model.PekerjaanBulkOperation{
+            {Op: model.BulkCreate, Pekerjaan: ptr(pastJob(f.alumni.ID.Hex()))},
+            {Op: model.BulkUpdate, ID: f.other.ID.Hex(), Pekerjaan: &update},
+            {Op: model.BulkSoftDelete, ID: "xyz"},
+            {Op: model.BulkSoftDelete, ID: f.pekerjaan.ID.Hex()},
+        },
+    })
+    result := bulkResponse(t, resp)
+    want := []string{"ok 201", "ok 200", "failed 400", "skipped 0"}
+    if got := bulkOutcomes(result); fmt.Sprint(got) != fmt.Sprint(want) || !result.Ordered || resp.Success {
+        t.Fatalf("outcomes = %v, want %v (response %+v)", got, want, result)
+    }
+    if result.Succeeded != 2 || result.Failed != 1 || result.Skipped != 1 {
+        t.Errorf("counts = %+v", result)
+    }
+
+    // The created pekerjaan carries the reported id; the skipped delete did not run
+    createdID, _ := primitive.ObjectIDFromHex(result.Results[0].ID)
+    if created, err := f.repos.Pekerjaan.FindPekerjaanByID(ctx, createdID); err != nil || created.StatusModerasi != model.ModerasiApproved {
+        t.Errorf("created = %+v, err %v", created, err)
+    }
+    if updated, _ := f.repos.Pekerjaan.FindPekerjaanByID(ctx, f.other.ID); updated.PosisiJabatan != "Senior Developer" {
+        t.Errorf("updated = %+v", updated)
+    }
+    if _, err := f.repos.Pekerjaan.FindPekerjaanByID(ctx, f.pekerjaan.ID); err != nil {
+        t.Errorf("skipped soft delete ran: %v", err)
+    }
+
+    // Unordered, the same batch runs past the failure
+    ordered := false
+    resp = do(t, app, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{
+        Ordered: &ordered,
+        Operations: []model.PekerjaanBulkOperation{
+            {Op: model.BulkSoftDelete, ID: "xyz"},
+            {Op: "archive", ID: f.pekerjaan.ID.Hex()},
+            {Op: model.BulkSoftDelete, ID: f.pekerjaan.ID.Hex()},
+        },
+    })
+    result = bulkResponse(t, resp)
+    want = []string{"failed 400", "failed 400", "ok 200"}
+    if got := bulkOutcomes(result); fmt.Sprint(got) != fmt.Sprint(want) || result.Ordered {
+        t.Errorf("unordered outcomes = %v, want %v", got, want)
+    }
+    if total, _ := f.repos.Pekerjaan.CountTrashPekerjaan(ctx, adminCaller.userID, true, ""); total != 1 {
+        t.Errorf("trash has %d items, want 1", total)
+    }
+
+    for _, ops := range [][]model.PekerjaanBulkOperation{nil, make([]model.PekerjaanBulkOperation, model.MaxBulkOperations+1)} {
+        if resp := do(t, app, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{Operations: ops}); resp.Status != 400 {
+            t.Errorf("%d operations: status = %d, want 400", len(ops), resp.Status)
+        }
+    }
+}
+
+func TestBulkPekerjaanByOwner(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan/bulk", f.service.BulkPekerjaan, f.owner)
+    ctx := context.Background()
+
+    // Later operations see earlier ones: the delete finds the pekerjaan the
+    // soft_delete before it trashed
+    ordered := false
+    resp := do(t, app, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{
+        Ordered: &ordered,
+        Operations: []model.PekerjaanBulkOperation{
+            {Op: model.BulkCreate, Pekerjaan: ptr(pastJob(""))},
+            {Op: model.BulkCreate, Pekerjaan: ptr(pastJob(f.other.AlumniID.Hex()))},
+            {Op: model.BulkUpdate, ID: f.other.ID.Hex(), Pekerjaan: ptr(pastJob(""))},
+            {Op: model.BulkSoftDelete, ID: f.other.ID.Hex()},
+            {Op: model.BulkDelete, ID: f.pekerjaan.ID.Hex()},
+            {Op: model.BulkSoftDelete, ID: f.pekerjaan.ID.Hex()},
+            {Op: model.BulkDelete, ID: f.pekerjaan.ID.Hex()},
+        },
+    })
+    result := bulkResponse(t, resp)
+    want := []string{"ok 201", "failed 403", "failed 404", "failed 404", "failed 404", "ok 200", "ok 200"}
+    if got := bulkOutcomes(result); fmt.Sprint(got) != fmt.Sprint(want) {
+        t.Fatalf("outcomes = %v, want %v", got, want)
+    }
+
+    // Submissions wait for approval like POST /pekerjaan
+    createdID, _ := primitive.ObjectIDFromHex(result.Results[0].ID)
+    if created, err := f.repos.Pekerjaan.FindPekerjaanByID(ctx, createdID); err != nil || created.StatusModerasi != model.ModerasiPending {
+        t.Errorf("created = %+v, err %v", created, err)
+    }
+    if found, _ := f.repos.Pekerjaan.FindPekerjaanByIDs(ctx, []primitive.ObjectID{f.pekerjaan.ID, f.other.ID}); len(found) != 1 || found[0].ID != f.other.ID {
+        t.Errorf("left = %+v, want only the stranger's pekerjaan", found)
+    }
+
+    // An account without alumni may not write anything
+    lone := newTestApp("POST", "/pekerjaan/bulk", f.service.BulkPekerjaan, caller{userID: primitive.NewObjectID(), role: "user"})
+    result = bulkResponse(t, do(t, lone, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{
+        Operations: []model.PekerjaanBulkOperation{{Op: model.BulkCreate, Pekerjaan: ptr(pastJob(""))}},
+    }))
+    if got := bulkOutcomes(result); fmt.Sprint(got) != "[failed 403]" {
+        t.Errorf("no alumni: outcomes = %v", got)
+    }
+}
+
+func TestBulkPekerjaanTimeline(t *testing.T) {
+    f := newPekerjaanFixture(t)
+    app := newTestApp("POST", "/pekerjaan/bulk", f.service.BulkPekerjaan, adminCaller)
+
+    // f.pekerjaan is aktif; once trashed in the batch a new aktif one fits,
+    // but a second one overlaps the first
+    aktif := model.CreatePekerjaanRequest{
+        AlumniID:          f.alumni.ID.Hex(),
+        NamaPerusahaan:    "PT Startup Indonesia",
+        PosisiJabatan:     "Tech Lead",
+        BidangIndustri:    "E-commerce",
+        LokasiKerja:       "Jakarta",
+        TanggalMulaiKerja: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+        StatusPekerjaan:   model.StatusAktif,
+    }
+    ordered := false
+    result := bulkResponse(t, do(t, app, "POST", "/pekerjaan/bulk", model.PekerjaanBulkRequest{
+        Ordered: &ordered,
+        Operations: []model.PekerjaanBulkOperation{
+            {Op: model.BulkCreate, Pekerjaan: &aktif},
+            {Op: model.BulkSoftDelete, ID: f.pekerjaan.ID.Hex()},
+            {Op: model.BulkCreate, Pekerjaan: &aktif},
+            {Op: model.BulkCreate, Pekerjaan: &aktif},
+        },
+    }))
+    want := []string{"failed 409", "ok 200", "ok 201", "failed 409"}
+    if got := bulkOutcomes(result); fmt.Sprint(got) != fmt.Sprint(want) {
+        t.Errorf("outcomes = %v, want %v", got, want)
+    }
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
+    "math"
+    "slices"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/report"
+    "go-fiber/tracing"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Defaults of the ReportService settings left zero
+const (
+    defaultReportWorkers = 2
+    defaultReportTimeout = 5 * time.Minute
+)
+
+// StaleReportMessage is the error of a job no server finished in time
+const StaleReportMessage = "laporan tidak selesai dibuat, server berhenti atau waktu habis"
+
+// ReportService handles the accreditation report jobs under /reports. A job
+// is stored as pending and generated in the background, at most Workers at
+// once; its files are kept for download once it is done.
+type ReportService struct {
+    Repo  repository.ReportRepository
+    Stats repository.StatsRepository
+    // MinGroupSize as in StatsService
+    MinGroupSize int
+    Workers      int
+    // Timeout bounds a job from its request, waiting for a worker included
+    Timeout time.Duration
+    // Jobs is the context the generations run under; cancelling it fails
+    // those still running. context.Background when nil.
+    Jobs context.Context
+
+    once    sync.Once
+    slots   chan struct{}
+    running sync.WaitGroup
+}
+
+func NewReportService(repo repository.ReportRepository, stats repository.StatsRepository) *ReportService {
+    return &ReportService{Repo: repo, Stats: stats}
+}
+
+func (s *ReportService) minGroupSize() int {
+    if s.MinGroupSize > 0 {
+        return s.MinGroupSize
+    }
+    return model.DefaultMinGroupSize
+}
+
+func (s *ReportService) timeout() time.Duration {
+    if s.Timeout > 0 {
+        return s.Timeout
+    }
+    return defaultReportTimeout
+}
+
+// Wait blocks until every generation started has finished
+func (s *ReportService) Wait() {
+    s.running.Wait()
+}
+
+// FailStale fails the jobs requested longer than Timeout ago that are still
+// pending or running, left behind by a server that stopped
+func (s *ReportService) FailStale(ctx context.Context) (int, error) {
+    return s.Repo.FailStaleReports(ctx, time.Now().Add(-s.timeout()), StaleReportMessage)
+}
+
+// reportFormats checks the requested formats, every one of
+// model.ReportFormats when none are given
+func reportFormats(requested []string) ([]string, error) {
+    if len(requested) == 0 {
+        return model.ReportFormats, nil
+    }
+
+    var formats []string
+    for _, format := range requested {
+        format = strings.ToLower(strings.TrimSpace(format))
+        if !slices.Contains(model.ReportFormats, format) {
+            return nil, fmt.Errorf("format %q tidak didukung, gunakan %s", format, strings.Join(model.ReportFormats, " atau "))
+        }
+        if !slices.Contains(formats, format) {
+            formats = append(formats, format)
+        }
+    }
+    return formats, nil
+}
+
+// CreateReport queues the accreditation report of a jurusan and tahun_lulus
+// and replies 202 with the job to poll
+func (s *ReportService) CreateReport(c *fiber.Ctx) error {
+    defer traceCall(c, "ReportService.CreateReport")()
+
+    var req model.CreateReportRequest
+    if err := c.BodyParser(&req); err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": "Input tidak valid: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    req.Jurusan = strings.TrimSpace(req.Jurusan)
+    formats, err := reportFormats(req.Formats)
+    switch {
+    case req.Jurusan == "":
+        err = errors.New("jurusan wajib diisi")
+    case req.TahunLulus < 1900 || req.TahunLulus > 2100:
+        err = errors.New("tahun_lulus harus antara 1900 dan 2100")
+    }
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    userID, _ := callerOf(c)
+    job, err := s.Repo.CreateReport(c.UserContext(), model.Report{
+        Jurusan:     req.Jurusan,
+        TahunLulus:  req.TahunLulus,
+        Formats:     formats,
+        Status:      model.ReportPending,
+        RequestedBy: userID,
+        CreatedAt:   time.Now(),
+    })
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CreateReport failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal membuat laporan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    s.start(*job)
+
+    return c.Status(202).JSON(fiber.Map{
+        "message": "Laporan sedang dibuat",
+        "success": true,
+        "data":    job.ToReportResponse(),
+    })
+}
+
+// start generates job in the background, detached from the request
+func (s *ReportService) start(job model.Report) {
+    s.once.Do(func() {
+        workers := s.Workers
+        if workers < 1 {
+            workers = defaultReportWorkers
+        }
+        s.slots = make(chan struct{}, workers)
+    })
+
+    s.running.Add(1)
+    go s.run(job)
+}
+
+// run waits for a worker, generates job and writes how it ended
+func (s *ReportService) run(job model.Report) {
+    defer s.running.Done()
+
+    base := s.Jobs
+    if base == nil {
+        base = context.Background()
+    }
+    ctx, cancel := context.WithDeadline(base, job.CreatedAt.Add(s.timeout()))
+    defer cancel()
+    ctx, span := tracing.Start(ctx, "ReportService.run")
+    defer span.End()
+
+    var err error
+    select {
+    case s.slots <- struct{}{}:
+        job, err = s.generate(ctx, job)
+        <-s.slots
+    case <-ctx.Done():
+        err = ctx.Err()
+    }
+
+    finished := time.Now()
+    job.FinishedAt = &finished
+    job.Status = model.ReportDone
+    switch {
+    case errors.Is(err, context.DeadlineExceeded):
+        job.Status, job.Error = model.ReportFailed, "waktu pembuatan laporan habis"
+    case errors.Is(err, context.Canceled):
+        job.Status, job.Error = model.ReportFailed, "server berhenti sebelum laporan selesai"
+    case err != nil:
+        job.Status, job.Error = model.ReportFailed, err.Error()
+    }
+    if job.Status == model.ReportFailed {
+        job.Files = nil
+        slog.ErrorContext(ctx, "report generation failed", "report_id", job.ID.Hex(), "error", err)
+    }
+
+    // ctx may be over by now, the outcome is written regardless
+    writeCtx, writeCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+    defer writeCancel()
+    if err := s.Repo.UpdateReport(writeCtx, job); err != nil {
+        slog.ErrorContext(ctx, "UpdateReport failed", "report_id", job.ID.Hex(), "error", err)
+    }
+}
+
+// generate reads the cohort of job, renders each of its formats and stores
+// the files
+func (s *ReportService) generate(ctx context.Context, job model.Report) (model.Report, error) {
+    started := time.Now()
+    job.Status, job.StartedAt = model.ReportRunning, &started
+    if err := s.Repo.UpdateReport(ctx, job); err != nil {
+        return job, err
+    }
+
+    filter := model.CohortFilter{Jurusan: job.Jurusan, TahunLulusFrom: job.TahunLulus, TahunLulusTo: job.TahunLulus}
+    cohorts, err := s.Stats.GetCohorts(ctx, filter)
+    if err != nil {
+        return job, err
+    }
+    if len(cohorts) == 0 {
+        return job, fmt.Errorf("tidak ada alumni %s yang lulus tahun %d", job.Jurusan, job.TahunLulus)
+    }
+    facts, err := s.Stats.GetPekerjaanFacts(ctx, filter)
+    if err != nil {
+        return job, err
+    }
+
+    data := model.Accreditation(cohorts[0], facts, s.minGroupSize(), time.Now())
+    for _, format := range job.Formats {
+        content, err := report.Render(format, data)
+        if err != nil {
+            return job, err
+        }
+        if err := s.Repo.SaveReportFile(ctx, model.ReportContent{ReportID: job.ID, Format: format, Data: content}); err != nil {
+            return job, err
+        }
+        job.Files = append(job.Files, model.ReportFile{
+            Format:      format,
+            Name:        job.FileName(format),
+            ContentType: model.ReportContentType(format),
+            Size:        len(content),
+        })
+    }
+    return job, ctx.Err()
+}
+
+// findReport reads the :id parameter and loads the job. ok is false when a
+// reply was sent.
+func (s *ReportService) findReport(c *fiber.Ctx) (*model.Report, bool, error) {
+    id, err := primitive.ObjectIDFromHex(c.Params("id"))
+    if err != nil {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": "ID tidak valid",
+            "success": false,
+        })
+    }
+
+    job, err := s.Repo.FindReportByID(c.UserContext(), id)
+    if errors.Is(err, repository.ErrReportNotFound) {
+        return nil, false, c.Status(404).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "FindReportByID failed", "error", err)
+        return nil, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan laporan: " + err.Error(),
+            "success": false,
+        })
+    }
+    return job, true, nil
+}
+
+// GetReport returns a job, polled until its status is done or failed
+func (s *ReportService) GetReport(c *fiber.Ctx) error {
+    defer traceCall(c, "ReportService.GetReport")()
+
+    job, ok, err := s.findReport(c)
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan status laporan",
+        "success": true,
+        "data":    job.ToReportResponse(),
+    })
+}
+
+// GetReports lists the jobs, newest first
+func (s *ReportService) GetReports(c *fiber.Ctx) error {
+    defer traceCall(c, "ReportService.GetReports")()
+
+    page, _ := strconv.Atoi(c.Query("page", "1"))
+    limit, _ := strconv.Atoi(c.Query("limit", "10"))
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 {
+        limit = 10
+    }
+    offset := (page - 1) * limit
+
+    list, err := s.Repo.GetReports(c.UserContext(), limit, offset)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetReports failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan data laporan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    total, err := s.Repo.CountReports(c.UserContext())
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "CountReports failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal menghitung total laporan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    responses := make([]model.ReportResponse, len(list))
+    for i := range list {
+        responses[i] = list[i].ToReportResponse()
+    }
+
+    meta := model.MetaInfo{
+        Page:   page,
+        Limit:  limit,
+        Total:  total,
+        Pages:  int(math.Ceil(float64(total) / float64(limit))),
+        SortBy: "created_at",
+        Order:  "desc",
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan data laporan",
+        "success": true,
+        "data":    responses,
+        "meta":    meta,
+    })
+}
+
+// DownloadReport sends the generated file of a done job in :format
+func (s *ReportService) DownloadReport(c *fiber.Ctx) error {
+    defer traceCall(c, "ReportService.DownloadReport")()
+
+    job, ok, err := s.findReport(c)
+    if !ok {
+        return err
+    }
+
+    switch job.Status {
+    case model.ReportFailed:
+        return c.Status(409).JSON(fiber.Map{
+            "message": "Pembuatan laporan gagal: " + job.Error,
+            "success": false,
+        })
+    case model.ReportPending, model.ReportRunning:
+        return c.Status(409).JSON(fiber.Map{
+            "message": "Laporan belum selesai dibuat",
+            "success": false,
+        })
+    }
+
+    format := strings.ToLower(c.Params("format"))
+    file, found := job.File(format)
+    if !found {
+        return c.Status(404).JSON(fiber.Map{
+            "message": fmt.Sprintf("Laporan tidak dibuat dalam format %s", format),
+            "success": false,
+        })
+    }
+
+    content, err := s.Repo.GetReportFile(c.UserContext(), job.ID, format)
+    if errors.Is(err, repository.ErrReportNotFound) {
+        return c.Status(404).JSON(fiber.Map{
+            "message": "File laporan tidak ditemukan",
+            "success": false,
+        })
+    }
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetReportFile failed", "error", err)
+        return c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mengunduh laporan: " + err.Error(),
+            "success": false,
+        })
+    }
+
+    c.Attachment(file.Name)
+    c.Set(fiber.HeaderContentType, file.ContentType)
+    return c.Send(content.Data)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+func newReportApp(s *ReportService) *fiber.App {
+    app := newTestApp("POST", "/reports", s.CreateReport, adminCaller)
+    app.Get("/reports", s.GetReports)
+    app.Get("/reports/:id", s.GetReport)
+    app.Get("/reports/:id/download/:format", s.DownloadReport)
+    return app
+}
+
+func TestReportLifecycle(t *testing.T) {
+    repos, _ := newStatsFixture(t)
+    s := NewReportService(repos.Report, repos.Stats)
+    app := newReportApp(s)
+
+    resp := do(t, app, "POST", "/reports", fiber.Map{"jurusan": "Teknik Informatika", "tahun_lulus": 2022})
+    if resp.Status != 202 {
+        t.Fatalf("status = %d: %s", resp.Status, resp.Message)
+    }
+    var job model.ReportResponse
+    decodeData(t, resp, &job)
+    if job.Status != model.ReportPending || len(job.Formats) != 2 {
+        t.Errorf("queued job = %+v", job)
+    }
+
+    s.Wait()
+    decodeData(t, do(t, app, "GET", "/reports/"+job.ID, nil), &job)
+    if job.Status != model.ReportDone || len(job.Files) != 2 || job.StartedAt == nil || job.FinishedAt == nil {
+        t.Fatalf("finished job = %+v", job)
+    }
+    if job.Files[0].URL != "/reports/"+job.ID+"/download/pdf" {
+        t.Errorf("url = %q", job.Files[0].URL)
+    }
+
+    res, err := app.Test(httptest.NewRequest("GET", job.Files[0].URL, nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer res.Body.Close()
+    body, _ := io.ReadAll(res.Body)
+    if res.StatusCode != 200 || res.Header.Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(body, []byte("%PDF")) {
+        t.Errorf("download = %d %q", res.StatusCode, res.Header.Get("Content-Type"))
+    }
+    if disposition := res.Header.Get("Content-Disposition"); !strings.Contains(disposition, "tracer-study-teknik-informatika-2022.pdf") {
+        t.Errorf("Content-Disposition = %q", disposition)
+    }
+    if len(body) != job.Files[0].Size {
+        t.Errorf("size = %d, want %d", len(body), job.Files[0].Size)
+    }
+
+    list := do(t, app, "GET", "/reports", nil)
+    if list.Status != 200 || list.Meta.Total != 1 {
+        t.Errorf("list = %d, total %d", list.Status, list.Meta.Total)
+    }
+}
+
+func TestReportFormats(t *testing.T) {
+    repos, _ := newStatsFixture(t)
+    s := NewReportService(repos.Report, repos.Stats)
+    app := newReportApp(s)
+
+    tests := []struct {
+        body   fiber.Map
+        status int
+    }{
+        {fiber.Map{"tahun_lulus": 2022}, 400},
+        {fiber.Map{"jurusan": "Teknik Informatika", "tahun_lulus": 22}, 400},
+        {fiber.Map{"jurusan": "Teknik Informatika", "tahun_lulus": 2022, "formats": []string{"docx"}}, 400},
+        {fiber.Map{"jurusan": "Teknik Informatika", "tahun_lulus": 2022, "formats": []string{"XLSX", "xlsx"}}, 202},
+    }
+    for _, tt := range tests {
+        if resp := do(t, app, "POST", "/reports", tt.body); resp.Status != tt.status {
+            t.Errorf("POST %v = %d, want %d: %s", tt.body, resp.Status, tt.status, resp.Message)
+        }
+    }
+    s.Wait()
+
+    list, err := repos.Report.GetReports(context.Background(), 10, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(list) != 1 || len(list[0].Files) != 1 || list[0].Files[0].Format != model.ReportXLSX {
+        t.Fatalf("reports = %+v", list)
+    }
+    if resp := do(t, app, "GET", "/reports/"+list[0].ID.Hex()+"/download/pdf", nil); resp.Status != 404 {
+        t.Errorf("pdf of an xlsx report = %d, want 404", resp.Status)
+    }
+}
+
+func TestReportFailed(t *testing.T) {
+    repos, _ := newStatsFixture(t)
+    s := NewReportService(repos.Report, repos.Stats)
+    app := newReportApp(s)
+
+    var job model.ReportResponse
+    decodeData(t, do(t, app, "POST", "/reports", fiber.Map{"jurusan": "Teknik Sipil", "tahun_lulus": 2022}), &job)
+    s.Wait()
+
+    decodeData(t, do(t, app, "GET", "/reports/"+job.ID, nil), &job)
+    if job.Status != model.ReportFailed || !strings.Contains(job.Error, "Teknik Sipil") || len(job.Files) != 0 {
+        t.Errorf("job = %+v", job)
+    }
+    if resp := do(t, app, "GET", "/reports/"+job.ID+"/download/pdf", nil); resp.Status != 409 {
+        t.Errorf("download of a failed report = %d, want 409", resp.Status)
+    }
+}
+
+func TestReportPending(t *testing.T) {
+    repos, _ := newStatsFixture(t)
+    s := NewReportService(repos.Report, repos.Stats)
+    app := newReportApp(s)
+    ctx := context.Background()
+
+    stale, err := repos.Report.CreateReport(ctx, model.Report{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022, Formats: model.ReportFormats,
+        Status: model.ReportRunning, CreatedAt: time.Now().Add(-time.Hour),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    fresh, err := repos.Report.CreateReport(ctx, model.Report{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022, Formats: model.ReportFormats,
+        Status: model.ReportPending, CreatedAt: time.Now(),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if resp := do(t, app, "GET", "/reports/"+fresh.ID.Hex()+"/download/xlsx", nil); resp.Status != 409 {
+        t.Errorf("download of a pending report = %d, want 409", resp.Status)
+    }
+
+    if n, err := s.FailStale(ctx); err != nil || n != 1 {
+        t.Fatalf("FailStale = %d, %v", n, err)
+    }
+    found, err := repos.Report.FindReportByID(ctx, stale.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if found.Status != model.ReportFailed || found.Error != StaleReportMessage || found.FinishedAt == nil {
+        t.Errorf("stale report = %+v", found)
+    }
+    if found, _ := repos.Report.FindReportByID(ctx, fresh.ID); found.Status != model.ReportPending {
+        t.Errorf("fresh report = %s, want pending", found.Status)
+    }
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+    "errors"
+    "fmt"
+    "log/slog"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/tracing"
+
+    "github.com/gofiber/fiber/v2"
+    "go.opentelemetry.io/otel/codes"
+)
+
+// Services groups the HTTP services wired to one set of repositories
+type Services struct {
+    Alumni    *AlumniService
+    Pekerjaan *PekerjaanService
+    Company   *CompanyService
+    Stats     *StatsService
+    Reports   *ReportService
+    Auth      *AuthService
+    Health    *HealthService
+}
+
+func NewServices(repos repository.Repositories) *Services {
+    pekerjaan := NewPekerjaanService(repos.Pekerjaan)
+    pekerjaan.Companies = repos.Company
+    pekerjaan.Alumni = repos.Alumni
+    alumni := NewAlumniService(repos.Alumni)
+    alumni.Pekerjaan = repos.Pekerjaan
+
+    return &Services{
+        Alumni:    alumni,
+        Pekerjaan: pekerjaan,
+        Company:   NewCompanyService(repos.Company),
+        Stats:     NewStatsService(repos.Stats),
+        Reports:   NewReportService(repos.Report, repos.Stats),
+        Auth:      NewAuthService(repos.User),
+        Health:    NewHealthService(),
+    }
+}
+
+// traceCall starts the span of a service call, making it the parent of the
+// repository calls made with c.UserContext(); use as
+// defer traceCall(c, name)()
+func traceCall(c *fiber.Ctx, name string) func() {
+    ctx, span := tracing.Start(c.UserContext(), name)
+    c.SetUserContext(ctx)
+
+    return func() {
+        if status := c.Response().StatusCode(); status >= 500 {
+            span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+        }
+        span.End()
+    }
+}
+
+// bulkOrdered checks the number of operations of a bulk request and returns
+// whether it runs ordered, the default. ok is false when a reply was sent.
+func bulkOrdered(c *fiber.Ctx, n int, ordered *bool) (bool, bool, error) {
+    if n == 0 || n > model.MaxBulkOperations {
+        return false, false, c.Status(400).JSON(fiber.Map{
+            "message": fmt.Sprintf("operations harus berisi 1 sampai %d operasi", model.MaxBulkOperations),
+            "success": false,
+        })
+    }
+    return ordered == nil || *ordered, true, nil
+}
+
+// bulkReply completes the results of a bulk request with the errors of its
+// writes, results[indexes[i]] being that of errs[i], and replies with the
+// whole batch. Once an ordered batch failed every later operation counts as
+// skipped, whether it was checked or written.
+func bulkReply(c *fiber.Ctx, results []model.BulkItemResult, indexes []int, errs []error, ordered bool) error {
+    for i, err := range errs {
+        result := &results[indexes[i]]
+        switch {
+        case err == nil:
+            result.Result = model.BulkOK
+        case errors.Is(err, repository.ErrBulkSkipped):
+            result.Result = model.BulkSkipped
+        case errors.Is(err, repository.ErrNotFoundOrNoAccess):
+            result.Result, result.Status, result.Error = model.BulkFailed, 404, err.Error()
+        default:
+            slog.ErrorContext(c.UserContext(), "bulk write failed", "index", result.Index, "error", err)
+            result.Result, result.Status, result.Error = model.BulkFailed, 500, err.Error()
+        }
+    }
+
+    response := model.BulkResponse{Ordered: ordered, Results: results}
+    failed := false
+    for i := range results {
+        result := &results[i]
+        if (failed && ordered) || result.Result == model.BulkSkipped {
+            *result = model.BulkItemResult{
+                Index:  result.Index,
+                Op:     result.Op,
+                ID:     result.ID,
+                Result: model.BulkSkipped,
+                Error:  repository.ErrBulkSkipped.Error(),
+            }
+        }
+
+        switch result.Result {
+        case model.BulkOK:
+            response.Succeeded++
+        case model.BulkFailed:
+            response.Failed++
+            failed = true
+        default:
+            response.Skipped++
+        }
+    }
+
+    return c.JSON(fiber.Map{
+        "message": fmt.Sprintf("%d operasi berhasil, %d gagal, %d dilewati", response.Succeeded, response.Failed, response.Skipped),
+        "success": response.Failed == 0 && response.Skipped == 0,
+        "data":    response,
+    })
+}
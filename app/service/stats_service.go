@@ -0,0 +1,307 @@
+package service
+
+import (
+    "fmt"
+    "log/slog"
+    "strconv"
+    "strings"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// maxTopIndustries caps the top query parameter of the /stats endpoints
+const maxTopIndustries = 20
+
+// maxCareerPaths caps the paths query parameter of GET /stats/career-paths
+const maxCareerPaths = 50
+
+// StatsService handles the alumni analytics endpoints under /stats
+type StatsService struct {
+    Repo repository.StatsRepository
+    // MinGroupSize is the smallest group reported on its own; zero means
+    // model.DefaultMinGroupSize
+    MinGroupSize int
+}
+
+func NewStatsService(repo repository.StatsRepository) *StatsService {
+    return &StatsService{Repo: repo}
+}
+
+// positiveQuery reads an optional positive integer query parameter, def when
+// it is missing
+func positiveQuery(c *fiber.Ctx, name string, def int) (int, error) {
+    text := c.Query(name)
+    if text == "" {
+        return def, nil
+    }
+    n, err := strconv.Atoi(text)
+    if err != nil || n < 1 {
+        return 0, fmt.Errorf("%s harus berupa bilangan bulat positif", name)
+    }
+    return n, nil
+}
+
+// topQuery reads the number of industries to list, 3 by default
+func topQuery(c *fiber.Ctx) (int, error) {
+    top, err := positiveQuery(c, "top", 3)
+    if err == nil && top > maxTopIndustries {
+        err = fmt.Errorf("top maksimal %d", maxTopIndustries)
+    }
+    return top, err
+}
+
+// cohortFilter reads the jurusan, angkatan and tahun_lulus range of the
+// analytics
+func cohortFilter(c *fiber.Ctx) (model.CohortFilter, error) {
+    filter := model.CohortFilter{Jurusan: strings.TrimSpace(c.Query("jurusan"))}
+
+    var err error
+    if filter.Angkatan, err = positiveQuery(c, "angkatan", 0); err != nil {
+        return filter, err
+    }
+    if filter.TahunLulusFrom, err = positiveQuery(c, "tahun_lulus_from", 0); err != nil {
+        return filter, err
+    }
+    if filter.TahunLulusTo, err = positiveQuery(c, "tahun_lulus_to", 0); err != nil {
+        return filter, err
+    }
+    if filter.TahunLulusFrom != 0 && filter.TahunLulusTo != 0 && filter.TahunLulusFrom > filter.TahunLulusTo {
+        return filter, fmt.Errorf("tahun_lulus_from tidak boleh lebih besar dari tahun_lulus_to")
+    }
+    return filter, nil
+}
+
+// cohorts runs the cohort aggregation of the request. ok is false when a
+// reply was sent.
+func (s *StatsService) cohorts(c *fiber.Ctx) ([]model.CohortAggregate, bool, error) {
+    filter, err := cohortFilter(c)
+    if err != nil {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    cohorts, err := s.Repo.GetCohorts(c.UserContext(), filter)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetCohorts failed", "error", err)
+        return nil, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan statistik: " + err.Error(),
+            "success": false,
+        })
+    }
+    return cohorts, true, nil
+}
+
+func (s *StatsService) minGroupSize() int {
+    if s.MinGroupSize > 0 {
+        return s.MinGroupSize
+    }
+    return model.DefaultMinGroupSize
+}
+
+// currencyQuery reads the currency the salaries are reported in, IDR by
+// default
+func currencyQuery(c *fiber.Ctx) (string, error) {
+    currency := strings.ToUpper(c.Query("currency", model.CurrencyIDR))
+    if !model.ValidCurrency(currency) {
+        return "", fmt.Errorf("mata uang %q tidak valid, gunakan kode ISO seperti IDR", currency)
+    }
+    return currency, nil
+}
+
+// facts reads the pekerjaan of the cohorts of the request. ok is false when
+// a reply was sent.
+func (s *StatsService) facts(c *fiber.Ctx) ([]model.PekerjaanFact, bool, error) {
+    filter, err := cohortFilter(c)
+    if err != nil {
+        return nil, false, c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    facts, err := s.Repo.GetPekerjaanFacts(c.UserContext(), filter)
+    if err != nil {
+        slog.ErrorContext(c.UserContext(), "GetPekerjaanFacts failed", "error", err)
+        return nil, false, c.Status(500).JSON(fiber.Map{
+            "message": "Gagal mendapatkan statistik: " + err.Error(),
+            "success": false,
+        })
+    }
+    return facts, true, nil
+}
+
+// GetCohortStats returns per jurusan and tahun_lulus the employment rate,
+// the median waiting time for the first pekerjaan, the share still aktif,
+// the job churn and the most common industries. Cohorts under the minimum
+// size are suppressed.
+func (s *StatsService) GetCohortStats(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetCohortStats")()
+
+    top, err := topQuery(c)
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    cohorts, ok, err := s.cohorts(c)
+    if !ok {
+        return err
+    }
+
+    stats := make([]model.CohortStats, len(cohorts))
+    for i, cohort := range cohorts {
+        stats[i] = cohort.Stats(top, s.minGroupSize())
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan statistik angkatan",
+        "success": true,
+        "data":    stats,
+    })
+}
+
+// GetWaitingTime returns per jurusan and tahun_lulus how long the employed
+// alumni waited for their first pekerjaan, in model.WaitingBands. Cohorts
+// with fewer employed alumni than the minimum size are suppressed.
+func (s *StatsService) GetWaitingTime(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetWaitingTime")()
+
+    cohorts, ok, err := s.cohorts(c)
+    if !ok {
+        return err
+    }
+
+    stats := make([]model.WaitingTimeStats, len(cohorts))
+    for i, cohort := range cohorts {
+        stats[i] = cohort.WaitingTime(s.minGroupSize())
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan statistik masa tunggu",
+        "success": true,
+        "data":    stats,
+    })
+}
+
+// GetDistribution counts the pekerjaan per bidang_industri, lokasi_kerja or
+// posisi_jabatan, merging the groups under the minimum size
+func (s *StatsService) GetDistribution(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetDistribution")()
+
+    by := c.Query("by", model.DimensionIndustri)
+    if by != model.DimensionIndustri && by != model.DimensionLokasi && by != model.DimensionPosisi {
+        return c.Status(400).JSON(fiber.Map{
+            "message": fmt.Sprintf("by harus %s, %s atau %s", model.DimensionIndustri, model.DimensionLokasi, model.DimensionPosisi),
+            "success": false,
+        })
+    }
+
+    facts, ok, err := s.facts(c)
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan sebaran pekerjaan",
+        "success": true,
+        "data":    model.Distribution(facts, by, s.minGroupSize()),
+    })
+}
+
+// GetSalary returns the monthly salary percentiles per jurusan or angkatan,
+// suppressed for groups under the minimum size
+func (s *StatsService) GetSalary(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetSalary")()
+
+    by := c.Query("by", model.SalaryByJurusan)
+    if by != model.SalaryByJurusan && by != model.SalaryByAngkatan {
+        return c.Status(400).JSON(fiber.Map{
+            "message": fmt.Sprintf("by harus %s atau %s", model.SalaryByJurusan, model.SalaryByAngkatan),
+            "success": false,
+        })
+    }
+    currency, err := currencyQuery(c)
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    facts, ok, err := s.facts(c)
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan statistik gaji",
+        "success": true,
+        "data":    model.Salary(facts, by, currency, s.minGroupSize()),
+    })
+}
+
+// GetTrends follows the pekerjaan, median salary and top industries per year
+// of tanggal_mulai_kerja
+func (s *StatsService) GetTrends(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetTrends")()
+
+    top, err := topQuery(c)
+    var currency string
+    if err == nil {
+        currency, err = currencyQuery(c)
+    }
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    facts, ok, err := s.facts(c)
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan tren pekerjaan",
+        "success": true,
+        "data":    model.Trends(facts, currency, top, s.minGroupSize()),
+    })
+}
+
+// GetCareerPaths follows the alumni from one pekerjaan to the next: the
+// transitions between industries and position levels, how long pekerjaan
+// last and the most common paths
+func (s *StatsService) GetCareerPaths(c *fiber.Ctx) error {
+    defer traceCall(c, "StatsService.GetCareerPaths")()
+
+    paths, err := positiveQuery(c, "paths", 10)
+    if err == nil && paths > maxCareerPaths {
+        err = fmt.Errorf("paths maksimal %d", maxCareerPaths)
+    }
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "message": err.Error(),
+            "success": false,
+        })
+    }
+
+    facts, ok, err := s.facts(c)
+    if !ok {
+        return err
+    }
+
+    return c.JSON(fiber.Map{
+        "message": "Berhasil mendapatkan jalur karier alumni",
+        "success": true,
+        "data":    model.Careers(facts, time.Now(), paths, s.minGroupSize()),
+    })
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+    "context"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newStatsFixture has three Teknik Informatika alumni of 2022, one of them
+// without pekerjaan, and one Sistem Informasi alumni of 2021
+func newStatsFixture(t *testing.T) (repository.Repositories, *StatsService) {
+    repos := repository.NewMemoryRepositories()
+    ctx := context.Background()
+
+    job := func(alumniID primitive.ObjectID, industri string, start time.Time, status string) *model.Pekerjaan {
+        p := newPekerjaan(t, repos, alumniID, "PT "+industri)
+        p.BidangIndustri, p.TanggalMulaiKerja, p.StatusPekerjaan = industri, start, status
+        if _, err := repos.Pekerjaan.UpdatePekerjaan(ctx, p.ID, p); err != nil {
+            t.Fatal(err)
+        }
+        return &p
+    }
+
+    first := newAlumni(t, repos, "1234567001", "John Doe", "Teknik Informatika", primitive.NilObjectID)
+    job(first.ID, "Technology", time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC), "resign")
+    job(first.ID, "Banking", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), "aktif")
+
+    second := newAlumni(t, repos, "1234567002", "Jane Smith", "Teknik Informatika", primitive.NilObjectID)
+    job(second.ID, "Technology", time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), "aktif")
+    // Neither trashed nor pending pekerjaan count
+    trashed := job(second.ID, "Retail", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "resign")
+    if err := repos.Pekerjaan.SoftDelete(ctx, trashed.ID, primitive.NilObjectID, true); err != nil {
+        t.Fatal(err)
+    }
+    pending := job(second.ID, "Retail", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "resign")
+    pending.StatusModerasi = model.ModerasiPending
+    if _, err := repos.Pekerjaan.UpdatePekerjaan(ctx, pending.ID, *pending); err != nil {
+        t.Fatal(err)
+    }
+
+    newAlumni(t, repos, "1234567003", "Budi Santoso", "Teknik Informatika", primitive.NilObjectID)
+
+    other := newAlumni(t, repos, "1234567004", "Siti Aminah", "Sistem Informasi", primitive.NilObjectID)
+    other.TahunLulus = 2021
+    if _, err := repos.Alumni.UpdateAlumni(ctx, other.ID, other); err != nil {
+        t.Fatal(err)
+    }
+    job(other.ID, "Government", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), "aktif")
+
+    return repos, NewStatsService(repos.Stats)
+}
+
+func TestGetCohortStats(t *testing.T) {
+    _, s := newStatsFixture(t)
+    s.MinGroupSize = 2
+    app := newTestApp("GET", "/stats/cohorts", s.GetCohortStats, adminCaller)
+
+    resp := do(t, app, "GET", "/stats/cohorts?top=1", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var stats []model.CohortStats
+    decodeData(t, resp, &stats)
+    if len(stats) != 2 || stats[0].Jurusan != "Sistem Informasi" || stats[1].Jurusan != "Teknik Informatika" {
+        t.Fatalf("cohorts = %+v", stats)
+    }
+    // One alumni is under the minimum size
+    if si := stats[0]; !si.Suppressed || si.Alumni != 1 || si.Employed != 0 || si.EmploymentRate != 0 {
+        t.Errorf("Sistem Informasi = %+v", si)
+    }
+
+    ti := stats[1]
+    if ti.Alumni != 3 || ti.Employed != 2 || ti.EmploymentRate != 66.67 || ti.AktifRate != 66.67 || ti.ChurnRate != 50 || ti.AvgPekerjaan != 1.5 {
+        t.Errorf("Teknik Informatika = %+v", ti)
+    }
+    // First pekerjaan in April 2022 and September 2023, 3 and 20 months
+    if ti.MedianWaitingMonths == nil || *ti.MedianWaitingMonths != 11.5 {
+        t.Errorf("median waiting = %v, want 11.5", ti.MedianWaitingMonths)
+    }
+    if len(ti.TopIndustries) != 1 || ti.TopIndustries[0] != (model.IndustryCount{BidangIndustri: "Technology", Total: 2, Alumni: 2}) {
+        t.Errorf("top industries = %v", ti.TopIndustries)
+    }
+
+    s.MinGroupSize = 1
+    resp = do(t, app, "GET", "/stats/cohorts?jurusan=Sistem+Informasi&tahun_lulus_from=2021&tahun_lulus_to=2021", nil)
+    decodeData(t, resp, &stats)
+    if len(stats) != 1 || stats[0].Jurusan != "Sistem Informasi" || stats[0].Suppressed || stats[0].EmploymentRate != 100 {
+        t.Errorf("filtered cohorts = %+v", stats)
+    }
+
+    for _, query := range []string{"top=0", "top=21", "angkatan=abc", "tahun_lulus_from=2023&tahun_lulus_to=2022"} {
+        if resp := do(t, app, "GET", "/stats/cohorts?"+query, nil); resp.Status != 400 {
+            t.Errorf("%s: status = %d, want 400", query, resp.Status)
+        }
+    }
+}
+
+func TestGetWaitingTime(t *testing.T) {
+    _, s := newStatsFixture(t)
+    s.MinGroupSize = 2
+    app := newTestApp("GET", "/stats/waiting-time", s.GetWaitingTime, adminCaller)
+
+    resp := do(t, app, "GET", "/stats/waiting-time?jurusan=Teknik+Informatika", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var stats []model.WaitingTimeStats
+    decodeData(t, resp, &stats)
+    if len(stats) != 1 || stats[0].Employed != 2 {
+        t.Fatalf("waiting time = %+v", stats)
+    }
+    bands := stats[0].Bands
+    if bands[0].Total != 1 || bands[1].Total != 0 || bands[2].Total != 1 || bands[0].Percent != 50 {
+        t.Errorf("bands = %+v", bands)
+    }
+
+    s.MinGroupSize = 3
+    decodeData(t, do(t, app, "GET", "/stats/waiting-time?jurusan=Teknik+Informatika", nil), &stats)
+    if len(stats) != 1 || !stats[0].Suppressed || stats[0].MedianWaitingMonths != nil || len(stats[0].Bands) != 0 {
+        t.Errorf("under the minimum size = %+v", stats)
+    }
+}
+
+func TestGetPekerjaanAnalytics(t *testing.T) {
+    _, s := newStatsFixture(t)
+    s.MinGroupSize = 2
+
+    app := newTestApp("GET", "/stats/distribution", s.GetDistribution, adminCaller)
+    resp := do(t, app, "GET", "/stats/distribution?jurusan=Teknik+Informatika", nil)
+    var distribution model.DistributionStats
+    decodeData(t, resp, &distribution)
+    want := []model.DistributionGroup{{Value: "Technology", Total: 2, Alumni: 2, Percent: 66.67}, {Value: model.OtherGroup, Total: 1, Alumni: 1, Percent: 33.33}}
+    if resp.Status != 200 || distribution.Total != 3 || !reflect.DeepEqual(distribution.Groups, want) {
+        t.Errorf("distribution: status = %d, data = %+v", resp.Status, distribution)
+    }
+
+    app = newTestApp("GET", "/stats/salary", s.GetSalary, adminCaller)
+    resp = do(t, app, "GET", "/stats/salary", nil)
+    var salary model.SalaryStats
+    decodeData(t, resp, &salary)
+    if resp.Status != 200 || len(salary.Groups) != 2 || !salary.Groups[0].Suppressed || salary.Groups[1].Percentiles == nil ||
+        salary.Groups[1].Percentiles.P50 != 10_000_000 {
+        t.Errorf("salary: status = %d, data = %+v", resp.Status, salary)
+    }
+
+    app = newTestApp("GET", "/stats/trends", s.GetTrends, adminCaller)
+    resp = do(t, app, "GET", "/stats/trends", nil)
+    var trends model.TrendStats
+    decodeData(t, resp, &trends)
+    if resp.Status != 200 || len(trends.Years) != 3 || trends.Years[2].Tahun != 2023 || trends.Years[2].Pekerjaan != 2 {
+        t.Errorf("trends: status = %d, data = %+v", resp.Status, trends)
+    }
+
+    for _, url := range []string{"/stats/distribution?by=gaji", "/stats/salary?by=tahun_lulus", "/stats/salary?currency=rupiah", "/stats/trends?top=0"} {
+        path, _, _ := strings.Cut(url, "?")
+        handler := map[string]fiber.Handler{"/stats/distribution": s.GetDistribution, "/stats/salary": s.GetSalary, "/stats/trends": s.GetTrends}[path]
+        if resp := do(t, newTestApp("GET", path, handler, adminCaller), "GET", url, nil); resp.Status != 400 {
+            t.Errorf("%s: status = %d, want 400", url, resp.Status)
+        }
+    }
+}
+
+func TestGetCareerPaths(t *testing.T) {
+    _, s := newStatsFixture(t)
+    s.MinGroupSize = 1
+    app := newTestApp("GET", "/stats/career-paths", s.GetCareerPaths, adminCaller)
+
+    resp := do(t, app, "GET", "/stats/career-paths?jurusan=Teknik+Informatika&angkatan=2018", nil)
+    if resp.Status != 200 {
+        t.Fatalf("status = %d, body = %+v", resp.Status, resp)
+    }
+    var stats model.CareerStats
+    decodeData(t, resp, &stats)
+    want := []model.CareerPath{{Steps: []string{"Technology", "Banking"}, Total: 1}}
+    if stats.Alumni != 2 || stats.Transitions != 1 || !reflect.DeepEqual(stats.IndustryPaths, want) {
+        t.Errorf("career paths = %+v", stats)
+    }
+
+    if resp := do(t, app, "GET", "/stats/career-paths?paths=51", nil); resp.Status != 400 {
+        t.Errorf("paths=51: status = %d, want 400", resp.Status)
+    }
+}
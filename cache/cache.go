@@ -0,0 +1,128 @@
+package cache
+
+import (
+    "context"
+    "encoding/json"
+    "log/slog"
+    "strconv"
+    "time"
+
+    "go-fiber/metrics"
+)
+
+// Store holds the cached values. A ttl of zero keeps the value until it is
+// evicted.
+type Store interface {
+    Get(ctx context.Context, key string) ([]byte, bool, error)
+    Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Cache stores query results by namespace, e.g. "alumni". Every namespace has
+// a version, the time of its last change, that is part of the keys of its
+// entries: Invalidate moves the version forward, so entries cached before a
+// write are never read again and expire on their own.
+type Cache struct {
+    store Store
+    ttl   time.Duration
+    now   func() time.Time
+}
+
+// New returns a cache keeping entries in store for ttl
+func New(store Store, ttl time.Duration) *Cache {
+    return &Cache{store: store, ttl: ttl, now: time.Now}
+}
+
+func versionKey(namespace string) string {
+    return "version:" + namespace
+}
+
+// Version returns the time namespace last changed. A namespace without a
+// recorded change starts now, as nothing is known about earlier writes.
+func (c *Cache) Version(ctx context.Context, namespace string) (time.Time, error) {
+    raw, ok, err := c.store.Get(ctx, versionKey(namespace))
+    if err != nil {
+        return time.Time{}, err
+    }
+    if ok {
+        if unix, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+            return time.Unix(unix, 0), nil
+        }
+    }
+    return c.bump(ctx, namespace, time.Time{})
+}
+
+// Local reports whether the versions are kept in this process only, as with
+// an LRU, and miss the writes made by other replicas
+func (c *Cache) Local() bool {
+    _, ok := c.store.(*LRU)
+    return ok
+}
+
+// Invalidate records a change of every namespace
+func (c *Cache) Invalidate(ctx context.Context, namespaces ...string) error {
+    for _, namespace := range namespaces {
+        previous, err := c.Version(ctx, namespace)
+        if err != nil {
+            return err
+        }
+        if _, err := c.bump(ctx, namespace, previous); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// bump stores a version later than previous. Versions are whole seconds, the
+// precision of Last-Modified, so they are at least a second apart: a client
+// holding the previous version never mistakes the new one for it.
+func (c *Cache) bump(ctx context.Context, namespace string, previous time.Time) (time.Time, error) {
+    version := c.now().Truncate(time.Second).Add(time.Second)
+    if !version.After(previous) {
+        version = previous.Add(time.Second)
+    }
+    err := c.store.Set(ctx, versionKey(namespace), []byte(strconv.FormatInt(version.Unix(), 10)), 0)
+    return version, err
+}
+
+// Remember returns the value cached under key in namespace, or loads, caches
+// and returns it. Cache failures are logged and fall back to load.
+func Remember[T any](ctx context.Context, c *Cache, namespace, key string, load func() (T, error)) (T, error) {
+    version, err := c.Version(ctx, namespace)
+    if err != nil {
+        slog.WarnContext(ctx, "cache unavailable", "namespace", namespace, "error", err)
+        return load()
+    }
+    entryKey := namespace + ":" + strconv.FormatInt(version.Unix(), 10) + ":" + key
+
+    var value T
+    if raw, ok, err := c.store.Get(ctx, entryKey); err != nil {
+        slog.WarnContext(ctx, "cache read failed", "key", entryKey, "error", err)
+    } else if ok && json.Unmarshal(raw, &value) == nil {
+        metrics.CacheRequests.WithLabelValues(namespace, "hit").Inc()
+        return value, nil
+    }
+    metrics.CacheRequests.WithLabelValues(namespace, "miss").Inc()
+
+    value, err = load()
+    if err != nil {
+        return value, err
+    }
+    if raw, err := json.Marshal(value); err == nil {
+        if err := c.store.Set(ctx, entryKey, raw, c.ttl); err != nil {
+            slog.WarnContext(ctx, "cache write failed", "key", entryKey, "error", err)
+        }
+    }
+    return value, nil
+}
+
+var defaultCache *Cache
+
+// SetDefault makes c the cache used for conditional requests
+func SetDefault(c *Cache) {
+    defaultCache = c
+}
+
+// Default returns the cache set with SetDefault, or nil when caching is off
+func Default() *Cache {
+    return defaultCache
+}
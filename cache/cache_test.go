@@ -0,0 +1,120 @@
+package cache
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+    ctx := context.Background()
+    lru := NewLRU(2)
+    lru.Set(ctx, "a", []byte("1"), 0)
+    lru.Set(ctx, "b", []byte("2"), 0)
+    lru.Get(ctx, "a")
+    lru.Set(ctx, "c", []byte("3"), 0)
+
+    if _, ok, _ := lru.Get(ctx, "b"); ok {
+        t.Error("b was used least recently and should be evicted")
+    }
+    for _, key := range []string{"a", "c"} {
+        if _, ok, _ := lru.Get(ctx, key); !ok {
+            t.Errorf("%s was evicted", key)
+        }
+    }
+    if lru.Len() != 2 {
+        t.Errorf("len = %d, want 2", lru.Len())
+    }
+}
+
+func TestLRUExpires(t *testing.T) {
+    ctx := context.Background()
+    now := time.Unix(1700000000, 0)
+    lru := NewLRU(10)
+    lru.now = func() time.Time { return now }
+
+    lru.Set(ctx, "short", []byte("1"), time.Minute)
+    lru.Set(ctx, "forever", []byte("2"), 0)
+    now = now.Add(time.Minute)
+
+    if _, ok, _ := lru.Get(ctx, "short"); ok {
+        t.Error("expired value returned")
+    }
+    if _, ok, _ := lru.Get(ctx, "forever"); !ok {
+        t.Error("value without ttl expired")
+    }
+}
+
+func TestRememberUntilInvalidated(t *testing.T) {
+    ctx := context.Background()
+    now := time.Unix(1700000000, 0)
+    c := New(NewLRU(10), time.Minute)
+    c.now = func() time.Time { return now }
+
+    loads := 0
+    load := func() ([]string, error) {
+        loads++
+        return []string{"Informatika"}, nil
+    }
+
+    for i := 0; i < 3; i++ {
+        got, err := Remember(ctx, c, "alumni", "stats", load)
+        if err != nil || len(got) != 1 || got[0] != "Informatika" {
+            t.Fatalf("Remember = %v, %v", got, err)
+        }
+    }
+    if loads != 1 {
+        t.Fatalf("loads = %d, want 1", loads)
+    }
+
+    if err := c.Invalidate(ctx, "alumni"); err != nil {
+        t.Fatal(err)
+    }
+    Remember(ctx, c, "alumni", "stats", load)
+    if loads != 2 {
+        t.Errorf("loads = %d, want a reload after Invalidate", loads)
+    }
+
+    Remember(ctx, c, "pekerjaan", "stats", load)
+    c.Invalidate(ctx, "alumni")
+    Remember(ctx, c, "pekerjaan", "stats", load)
+    if loads != 3 {
+        t.Errorf("loads = %d, other namespaces should stay cached", loads)
+    }
+}
+
+func TestRememberDoesNotCacheErrors(t *testing.T) {
+    ctx := context.Background()
+    c := New(NewLRU(10), time.Minute)
+
+    fail := errors.New("database down")
+    if _, err := Remember(ctx, c, "alumni", "count", func() (int, error) { return 0, fail }); err != fail {
+        t.Fatalf("err = %v, want %v", err, fail)
+    }
+    got, err := Remember(ctx, c, "alumni", "count", func() (int, error) { return 7, nil })
+    if err != nil || got != 7 {
+        t.Errorf("Remember = %d, %v, want 7", got, err)
+    }
+}
+
+func TestVersionsAreWholeSecondsApart(t *testing.T) {
+    ctx := context.Background()
+    now := time.Unix(1700000000, 400_000_000)
+    c := New(NewLRU(10), time.Minute)
+    c.now = func() time.Time { return now }
+
+    first, _ := c.Version(ctx, "alumni")
+    if first.Nanosecond() != 0 || first.Before(now) {
+        t.Fatalf("version = %s, want a whole second not before %s", first, now)
+    }
+
+    // Writes within the same second still move the version forward
+    c.Invalidate(ctx, "alumni")
+    second, _ := c.Version(ctx, "alumni")
+    c.Invalidate(ctx, "alumni")
+    third, _ := c.Version(ctx, "alumni")
+    if second.Sub(first) < time.Second || third.Sub(second) < time.Second {
+        t.Errorf("versions %s, %s, %s should be at least a second apart", first, second, third)
+    }
+}
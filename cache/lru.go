@@ -0,0 +1,77 @@
+package cache
+
+import (
+    "container/list"
+    "context"
+    "sync"
+    "time"
+)
+
+// LRU keeps up to size values in this process, evicting the least recently
+// used. Every replica caches on its own and only sees its own writes, so use
+// a shared store when running more than one.
+type LRU struct {
+    mu      sync.Mutex
+    size    int
+    order   *list.List // front is the most recently used
+    entries map[string]*list.Element
+    now     func() time.Time
+}
+
+type lruEntry struct {
+    key     string
+    value   []byte
+    expires time.Time // zero for no expiry
+}
+
+func NewLRU(size int) *LRU {
+    return &LRU{size: size, order: list.New(), entries: map[string]*list.Element{}, now: time.Now}
+}
+
+func (l *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    element, ok := l.entries[key]
+    if !ok {
+        return nil, false, nil
+    }
+    entry := element.Value.(*lruEntry)
+    if !entry.expires.IsZero() && !l.now().Before(entry.expires) {
+        l.order.Remove(element)
+        delete(l.entries, key)
+        return nil, false, nil
+    }
+    l.order.MoveToFront(element)
+    return entry.value, true, nil
+}
+
+func (l *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    entry := &lruEntry{key: key, value: value}
+    if ttl > 0 {
+        entry.expires = l.now().Add(ttl)
+    }
+
+    if element, ok := l.entries[key]; ok {
+        element.Value = entry
+        l.order.MoveToFront(element)
+        return nil
+    }
+    l.entries[key] = l.order.PushFront(entry)
+    for l.order.Len() > l.size {
+        oldest := l.order.Back()
+        l.order.Remove(oldest)
+        delete(l.entries, oldest.Value.(*lruEntry).key)
+    }
+    return nil
+}
+
+// Len returns the number of values held, expired ones included
+func (l *LRU) Len() int {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.order.Len()
+}
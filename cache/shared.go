@@ -0,0 +1,114 @@
+package cache
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "log/slog"
+    "sync"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sweepInterval is how often the PostgreSQL store deletes expired values
+const sweepInterval = time.Minute
+
+// MongoStore shares the cache between replicas in a MongoDB collection, one
+// document per key. A TTL index on expires_at removes expired values; until
+// it runs, Get skips them.
+type MongoStore struct {
+    coll *mongo.Collection
+}
+
+func NewMongoStore(coll *mongo.Collection) *MongoStore {
+    return &MongoStore{coll: coll}
+}
+
+func (s *MongoStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+    var doc struct {
+        Value     []byte     `bson:"value"`
+        ExpiresAt *time.Time `bson:"expires_at"`
+    }
+    err := s.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+    if errors.Is(err, mongo.ErrNoDocuments) {
+        return nil, false, nil
+    }
+    if err != nil {
+        return nil, false, err
+    }
+    if doc.ExpiresAt != nil && !time.Now().Before(*doc.ExpiresAt) {
+        return nil, false, nil
+    }
+    return doc.Value, true, nil
+}
+
+func (s *MongoStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+    set := bson.M{"value": value}
+    update := bson.M{"$set": set}
+    if ttl > 0 {
+        set["expires_at"] = time.Now().Add(ttl)
+    } else {
+        update["$unset"] = bson.M{"expires_at": ""}
+    }
+    _, err := s.coll.UpdateByID(ctx, key, update, options.Update().SetUpsert(true))
+    return err
+}
+
+// PostgresStore shares the cache between replicas in the cache_entries
+// table. Expired rows are deleted once per sweepInterval.
+type PostgresStore struct {
+    db *sql.DB
+
+    mu    sync.Mutex
+    swept time.Time
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+    return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+    var value []byte
+    err := s.db.QueryRowContext(ctx, `
+        SELECT value FROM cache_entries
+        WHERE key = $1 AND (expires_at IS NULL OR expires_at > now())`, key).Scan(&value)
+    if errors.Is(err, sql.ErrNoRows) {
+        return nil, false, nil
+    }
+    if err != nil {
+        return nil, false, err
+    }
+    return value, true, nil
+}
+
+func (s *PostgresStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+    s.sweep(ctx)
+
+    var expiresAt *time.Time
+    if ttl > 0 {
+        t := time.Now().Add(ttl)
+        expiresAt = &t
+    }
+    _, err := s.db.ExecContext(ctx, `
+        INSERT INTO cache_entries (key, value, expires_at) VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`,
+        key, value, expiresAt)
+    return err
+}
+
+func (s *PostgresStore) sweep(ctx context.Context) {
+    s.mu.Lock()
+    if time.Since(s.swept) < sweepInterval {
+        s.mu.Unlock()
+        return
+    }
+    s.swept = time.Now()
+    s.mu.Unlock()
+
+    if _, err := s.db.ExecContext(ctx, `DELETE FROM cache_entries WHERE expires_at < now()`); err != nil {
+        slog.WarnContext(ctx, "sweeping cache failed", "error", err)
+    }
+}
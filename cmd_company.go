@@ -0,0 +1,112 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// cmdCompaniesBackfill links the pekerjaan written before the companies
+// registry existed. A nama_perusahaan equal to a company name or alias once
+// normalized is linked; close matches are only reported, since "PT Digital
+// Indonesia" and "PT Digital Indonesiana" may well be different employers.
+// With --create, names matching nothing become new companies.
+func cmdCompaniesBackfill(c *cli, args []string) error {
+    fs := flag.NewFlagSet("companies backfill", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "Only report what would be linked")
+    create := fs.Bool("create", false, "Register a company for every name matching none")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+
+    repos := c.repositories()
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+    defer cancel()
+
+    companies, err := repos.Company.AllCompanies(ctx)
+    if err != nil {
+        return err
+    }
+    unlinked, err := repos.Company.UnlinkedPekerjaan(ctx)
+    if err != nil {
+        return err
+    }
+
+    log.Printf("🏢 %d unlinked pekerjaan, %d registered companies", len(unlinked), len(companies))
+
+    links := map[primitive.ObjectID][]primitive.ObjectID{}
+    var order []primitive.ObjectID
+    created, review, unmatched := 0, 0, 0
+    for _, p := range unlinked {
+        if strings.TrimSpace(p.NamaPerusahaan) == "" {
+            unmatched++
+            continue
+        }
+
+        matches := model.MatchCompanies(companies, p.NamaPerusahaan, 1)
+        var companyID primitive.ObjectID
+        switch {
+        case len(matches) > 0 && matches[0].Exact:
+            companyID, _ = primitive.ObjectIDFromHex(matches[0].Company.ID)
+        case *create:
+            company := model.Company{BidangIndustri: p.BidangIndustri, Lokasi: p.LokasiKerja}
+            company.SetNames(p.NamaPerusahaan, nil)
+            if *dryRun {
+                company.ID = primitive.NewObjectID()
+            } else {
+                saved, err := repos.Company.CreateCompany(ctx, company)
+                if err != nil {
+                    return fmt.Errorf("create company %q: %w", company.Nama, err)
+                }
+                company = *saved
+            }
+            log.Printf("  + %s", company.Nama)
+            companies = append(companies, company)
+            companyID = company.ID
+            created++
+        case len(matches) > 0:
+            log.Printf("  ? Pekerjaan %s: %q resembles %q (%.2f), link it by hand", p.ID.Hex(), p.NamaPerusahaan, matches[0].Company.Nama, matches[0].Score)
+            review++
+            continue
+        default:
+            unmatched++
+            continue
+        }
+
+        if _, ok := links[companyID]; !ok {
+            order = append(order, companyID)
+        }
+        links[companyID] = append(links[companyID], p.ID)
+    }
+
+    linked := 0
+    for _, companyID := range order {
+        if *dryRun {
+            linked += len(links[companyID])
+            continue
+        }
+        n, err := repos.Company.LinkPekerjaan(ctx, companyID, links[companyID])
+        if err != nil {
+            return err
+        }
+        linked += n
+    }
+
+    verb := "linked"
+    if *dryRun {
+        verb = "would be linked"
+    }
+    log.Printf("✅ %d pekerjaan %s, %d companies created, %d need review, %d unmatched", linked, verb, created, review, unmatched)
+    return nil
+}
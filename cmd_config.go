@@ -0,0 +1,39 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "os"
+)
+
+func cmdConfigPrint(c *cli, args []string) error {
+    fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+    redacted := fs.Bool("redacted", false, "Mask secrets and connection string passwords")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+
+    cfg := c.cfg
+    if *redacted {
+        cfg = cfg.Redacted()
+    }
+
+    out, err := cfg.YAML()
+    if err != nil {
+        return err
+    }
+    os.Stdout.Write(out)
+
+    // Printing works with an incomplete configuration, but scripts can use the
+    // exit code to check it
+    if err := c.cfg.Validate(); err != nil {
+        log.Printf("⚠️  Invalid configuration:\n%v", err)
+        return err
+    }
+    return nil
+}
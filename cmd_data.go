@@ -0,0 +1,317 @@
+package main
+
+import (
+    "context"
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exportPageSize is how many documents export reads per query
+const exportPageSize = 500
+
+// alumniColumns are the CSV columns read by alumni import; user_id and alamat
+// may be empty. Export writes them too, so its output can be imported again.
+var alumniColumns = []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telepon", "alamat", "user_id"}
+
+func cmdAlumniImport(c *cli, args []string) error {
+    fs := flag.NewFlagSet("alumni import", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "Only validate the file")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) != 1 {
+        return fmt.Errorf("%w: expected one CSV file", errUsage)
+    }
+
+    file, err := os.Open(positional[0])
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    reader := csv.NewReader(file)
+    reader.TrimLeadingSpace = true
+
+    header, err := reader.Read()
+    if err != nil {
+        return fmt.Errorf("read header: %w", err)
+    }
+    index := map[string]int{}
+    for i, name := range header {
+        index[strings.ToLower(strings.TrimSpace(name))] = i
+    }
+    for _, name := range alumniColumns[:7] {
+        if _, ok := index[name]; !ok {
+            return fmt.Errorf("missing column %q", name)
+        }
+    }
+
+    var rows []model.Alumni
+    var lines []int
+    failed := 0
+    for line := 2; ; line++ {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+
+        alumni, err := parseAlumniRow(record, index)
+        if err != nil {
+            log.Printf("  ✗ line %d: %v", line, err)
+            failed++
+            continue
+        }
+        rows = append(rows, alumni)
+        lines = append(lines, line)
+    }
+
+    if *dryRun {
+        log.Printf("✅ %d row(s) valid, %d invalid", len(rows), failed)
+        if failed > 0 {
+            return fmt.Errorf("%d invalid row(s)", failed)
+        }
+        return nil
+    }
+
+    repos := c.repositories()
+    imported := 0
+    for i, alumni := range rows {
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        _, err := repos.Alumni.CreateAlumni(ctx, alumni)
+        cancel()
+        if err != nil {
+            log.Printf("  ✗ line %d (%s): %v", lines[i], alumni.NIM, err)
+            failed++
+            continue
+        }
+        imported++
+    }
+
+    log.Printf("✅ %d alumni imported, %d failed", imported, failed)
+    if failed > 0 {
+        return fmt.Errorf("%d row(s) failed", failed)
+    }
+    return nil
+}
+
+func parseAlumniRow(record []string, index map[string]int) (model.Alumni, error) {
+    get := func(name string) string {
+        i, ok := index[name]
+        if !ok || i >= len(record) {
+            return ""
+        }
+        return strings.TrimSpace(record[i])
+    }
+
+    for _, name := range alumniColumns[:7] {
+        if get(name) == "" {
+            return model.Alumni{}, fmt.Errorf("%s is required", name)
+        }
+    }
+
+    angkatan, err := strconv.Atoi(get("angkatan"))
+    if err != nil {
+        return model.Alumni{}, errors.New("angkatan must be a number")
+    }
+    tahunLulus, err := strconv.Atoi(get("tahun_lulus"))
+    if err != nil {
+        return model.Alumni{}, errors.New("tahun_lulus must be a number")
+    }
+
+    var userID primitive.ObjectID
+    if hex := get("user_id"); hex != "" {
+        if userID, err = primitive.ObjectIDFromHex(hex); err != nil {
+            return model.Alumni{}, errors.New("user_id is not a valid id")
+        }
+    }
+
+    return model.Alumni{
+        NIM:        get("nim"),
+        Nama:       get("nama"),
+        Jurusan:    get("jurusan"),
+        Angkatan:   angkatan,
+        TahunLulus: tahunLulus,
+        Email:      get("email"),
+        NoTelepon:  get("no_telepon"),
+        Alamat:     get("alamat"),
+        UserID:     userID,
+    }, nil
+}
+
+func cmdExport(c *cli, args []string) error {
+    fs := flag.NewFlagSet("export", flag.ContinueOnError)
+    format := fs.String("format", "csv", "Output format: csv or json")
+    out := fs.String("out", "", "Output file, stdout when empty")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) != 1 {
+        return fmt.Errorf("%w: expected alumni, pekerjaan or users", errUsage)
+    }
+    if *format != "csv" && *format != "json" {
+        return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+    }
+
+    var export func(context.Context, repository.Repositories) ([]string, [][]string, []interface{}, error)
+    switch positional[0] {
+    case "alumni":
+        export = exportAlumni
+    case "pekerjaan":
+        export = exportPekerjaan
+    case "users":
+        export = exportUsers
+    default:
+        return fmt.Errorf("%w: cannot export %q", errUsage, positional[0])
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+    defer cancel()
+
+    header, rows, items, err := export(ctx, c.repositories())
+    if err != nil {
+        return err
+    }
+
+    w := io.Writer(os.Stdout)
+    if *out != "" {
+        file, err := os.Create(*out)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        w = file
+    }
+
+    if *format == "json" {
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        if err := encoder.Encode(items); err != nil {
+            return err
+        }
+    } else {
+        writer := csv.NewWriter(w)
+        writer.Write(header)
+        writer.WriteAll(rows)
+        if err := writer.Error(); err != nil {
+            return err
+        }
+    }
+
+    log.Printf("✅ Exported %d %s", len(items), positional[0])
+    return nil
+}
+
+func exportAlumni(ctx context.Context, repos repository.Repositories) ([]string, [][]string, []interface{}, error) {
+    header := append([]string{"id"}, alumniColumns...)
+    header = append(header, "created_at", "updated_at")
+
+    var rows [][]string
+    items := []interface{}{}
+    for offset := 0; ; offset += exportPageSize {
+        page, err := repos.Alumni.GetAlumni(ctx, "", "", "asc", exportPageSize, offset)
+        if err != nil {
+            return nil, nil, nil, err
+        }
+
+        for _, a := range page {
+            items = append(items, a.ToAlumniResponse())
+
+            userID := ""
+            if !a.UserID.IsZero() {
+                userID = a.UserID.Hex()
+            }
+            rows = append(rows, []string{
+                a.ID.Hex(), a.NIM, a.Nama, a.Jurusan, strconv.Itoa(a.Angkatan), strconv.Itoa(a.TahunLulus),
+                a.Email, a.NoTelepon, a.Alamat, userID,
+                a.CreatedAt.Format(time.RFC3339), a.UpdatedAt.Format(time.RFC3339),
+            })
+        }
+        if len(page) < exportPageSize {
+            return header, rows, items, nil
+        }
+    }
+}
+
+func exportPekerjaan(ctx context.Context, repos repository.Repositories) ([]string, [][]string, []interface{}, error) {
+    header := []string{
+        "id", "alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja",
+        "gaji_min", "gaji_max", "gaji_currency", "gaji_period", "gaji_range",
+        "tanggal_mulai_kerja", "tanggal_selesai_kerja", "status_pekerjaan", "deskripsi_pekerjaan", "created_at", "updated_at",
+    }
+
+    var rows [][]string
+    items := []interface{}{}
+    for offset := 0; ; offset += exportPageSize {
+        page, err := repos.Pekerjaan.GetPekerjaan(ctx, model.PekerjaanFilter{}, "", "asc", exportPageSize, offset)
+        if err != nil {
+            return nil, nil, nil, err
+        }
+
+        for _, p := range page {
+            items = append(items, p.ToPekerjaanResponse())
+
+            selesai := ""
+            if p.TanggalSelesaiKerja != nil {
+                selesai = p.TanggalSelesaiKerja.Format("2006-01-02")
+            }
+            rows = append(rows, []string{
+                p.ID.Hex(), p.AlumniID.Hex(), p.NamaPerusahaan, p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja,
+                formatBound(p.GajiMin), formatBound(p.GajiMax), p.GajiCurrency, p.GajiPeriod, p.GajiText(),
+                p.TanggalMulaiKerja.Format("2006-01-02"), selesai, p.StatusPekerjaan, p.DeskripsiPekerjaan,
+                p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339),
+            })
+        }
+        if len(page) < exportPageSize {
+            return header, rows, items, nil
+        }
+    }
+}
+
+// formatBound writes a salary bound, empty for an open end
+func formatBound(n *int64) string {
+    if n == nil {
+        return ""
+    }
+    return strconv.FormatInt(*n, 10)
+}
+
+func exportUsers(ctx context.Context, repos repository.Repositories) ([]string, [][]string, []interface{}, error) {
+    header := []string{"id", "username", "email", "role"}
+
+    var rows [][]string
+    items := []interface{}{}
+    for offset := 0; ; offset += exportPageSize {
+        page, err := repos.User.GetUsers(ctx, "", "", "asc", exportPageSize, offset)
+        if err != nil {
+            return nil, nil, nil, err
+        }
+
+        for _, u := range page {
+            response := u.ToUserResponse()
+            items = append(items, response)
+            rows = append(rows, []string{response.ID, response.Username, response.Email, response.Role})
+        }
+        if len(page) < exportPageSize {
+            return header, rows, items, nil
+        }
+    }
+}
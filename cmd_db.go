@@ -0,0 +1,215 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "text/tabwriter"
+    "time"
+
+    "go-fiber/database"
+)
+
+// migrationTimeout bounds migrate, schema and reset commands
+const migrationTimeout = 5 * time.Minute
+
+// countArg parses the optional [n] of migrate up and migrate down
+func (c *cli) countArg(name string, args []string, def int) (int, error) {
+    fs := flag.NewFlagSet(name, flag.ContinueOnError)
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return 0, err
+    }
+
+    switch len(positional) {
+    case 0:
+        return def, nil
+    case 1:
+        n, err := strconv.Atoi(positional[0])
+        if err != nil || n < 1 {
+            return 0, fmt.Errorf("%w: invalid count %q", errUsage, positional[0])
+        }
+        return n, nil
+    }
+    return 0, fmt.Errorf("%w: unexpected argument %q", errUsage, positional[1])
+}
+
+func cmdMigrateStatus(c *cli, args []string) error {
+    if err := c.noArgs("migrate status", args); err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    statuses, err := c.migrator().Status(ctx)
+    if err != nil {
+        return err
+    }
+
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+    for _, status := range statuses {
+        appliedAt := "-"
+        if status.AppliedAt != nil {
+            appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+        }
+        fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+    }
+    return w.Flush()
+}
+
+func cmdMigrateUp(c *cli, args []string) error {
+    n, err := c.countArg("migrate up", args, 0)
+    if err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    return c.migrator().Up(ctx, n)
+}
+
+func cmdMigrateDown(c *cli, args []string) error {
+    n, err := c.countArg("migrate down", args, 1)
+    if err != nil {
+        return err
+    }
+    if err := c.confirm(fmt.Sprintf("Rolling back %d migration(s) drops their collections, tables or indexes", n)); err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    return c.migrator().Down(ctx, n)
+}
+
+func cmdMigrateRedo(c *cli, args []string) error {
+    if err := c.noArgs("migrate redo", args); err != nil {
+        return err
+    }
+    if err := c.confirm("Redoing the newest migration drops what it created"); err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    return c.migrator().Redo(ctx)
+}
+
+func cmdSchemaSync(c *cli, args []string) error {
+    fs := flag.NewFlagSet("schema sync", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "Only report the drift")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+
+    db, err := c.mongo("schema sync")
+    if err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    drift, err := database.DiffSchema(ctx, db)
+    if err != nil {
+        return err
+    }
+    if len(drift) == 0 {
+        fmt.Println("Schema is up to date")
+        return nil
+    }
+    for _, d := range drift {
+        fmt.Println(d)
+    }
+    if *dryRun {
+        fmt.Printf("%d difference(s), run without --dry-run to apply\n", len(drift))
+        return nil
+    }
+
+    if err := c.confirm("Syncing the schema updates validators and drops undeclared indexes"); err != nil {
+        return err
+    }
+    drift, err = database.SyncSchema(ctx, db)
+    if err != nil {
+        return err
+    }
+    fmt.Printf("%d difference(s) applied\n", len(drift))
+    return nil
+}
+
+func cmdSeed(c *cli, args []string) error {
+    if err := c.noArgs("seed", args); err != nil {
+        return err
+    }
+
+    db, err := c.mongo("seed")
+    if err != nil {
+        return err
+    }
+
+    log.Println("🌱 Seeding database...")
+    if err := database.SeedData(db); err != nil {
+        return err
+    }
+    return database.SeedSummary(db)
+}
+
+func cmdDBSummary(c *cli, args []string) error {
+    if err := c.noArgs("db summary", args); err != nil {
+        return err
+    }
+
+    db, err := c.mongo("db summary")
+    if err != nil {
+        return err
+    }
+    return database.SeedSummary(db)
+}
+
+func cmdDBReset(c *cli, args []string) error {
+    if err := c.noArgs("db reset", args); err != nil {
+        return err
+    }
+    if err := c.confirm("Resetting deletes ALL data"); err != nil {
+        return err
+    }
+
+    migrator := c.migrator()
+    ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+    defer cancel()
+
+    log.Println("⚠️  Resetting database...")
+    if err := migrator.Down(ctx, 0); err != nil {
+        return err
+    }
+    if err := migrator.Up(ctx, 0); err != nil {
+        return err
+    }
+    log.Println("✅ Database reset completed")
+    return nil
+}
+
+func cmdCopyToPostgres(c *cli, args []string) error {
+    if err := c.noArgs("db copy-to-postgres", args); err != nil {
+        return err
+    }
+
+    mdb := database.ConnectDB()
+    pg := database.ConnectPostgres()
+    if err := database.RunPostgresMigrations(pg); err != nil {
+        return err
+    }
+    return database.CopyMongoToPostgres(mdb, pg)
+}
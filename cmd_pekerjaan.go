@@ -0,0 +1,135 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "flag"
+    "fmt"
+    "log"
+    "time"
+
+    "go-fiber/app/repository"
+)
+
+// cmdPekerjaanExpire moves the aktif pekerjaan past their
+// tanggal_selesai_kerja to kontrak_habis once, as serve does every
+// PEKERJAAN_EXPIRE_INTERVAL
+func cmdPekerjaanExpire(c *cli, args []string) error {
+    if err := c.noArgs("pekerjaan expire-contracts", args); err != nil {
+        return err
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+    defer cancel()
+
+    n, err := c.repositories().Pekerjaan.ExpireContracts(ctx, time.Now())
+    if err != nil {
+        return err
+    }
+    log.Printf("✅ %d contracts moved to kontrak_habis", n)
+    return nil
+}
+
+// expireContracts runs ExpireContracts every interval until ctx is done. The
+// update only touches pekerjaan still aktif, so replicas running it at the
+// same time do no harm.
+func expireContracts(ctx context.Context, repo repository.PekerjaanRepository, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        n, err := repo.ExpireContracts(ctx, time.Now())
+        switch {
+        case err != nil && ctx.Err() == nil:
+            log.Printf("⚠️  Expiring contracts failed: %v", err)
+        case n > 0:
+            log.Printf("📅 %d contracts moved to kontrak_habis", n)
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// cmdPekerjaanPurgeTrash permanently deletes the pekerjaan trashed longer
+// than PEKERJAAN_TRASH_RETENTION ago once, as serve does every
+// PEKERJAAN_PURGE_INTERVAL. With --dry-run it only lists them; otherwise it
+// asks first unless --yes is given.
+func cmdPekerjaanPurgeTrash(c *cli, args []string) error {
+    fs := flag.NewFlagSet("pekerjaan purge-trash", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "Only report what would be purged")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+
+    retention := c.cfg.Pekerjaan.TrashRetention
+    if retention <= 0 {
+        return errors.New("PEKERJAAN_TRASH_RETENTION is 0, the trash is kept forever")
+    }
+
+    repo := c.repositories().Pekerjaan
+    cutoff := time.Now().Add(-retention)
+
+    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+    expired, err := repo.GetExpiredTrash(ctx, cutoff, 0)
+    cancel()
+    if err != nil {
+        return err
+    }
+
+    if *dryRun {
+        for _, p := range expired {
+            log.Printf("  %s  %s, %s (trashed %s)", p.ID.Hex(), p.NamaPerusahaan, p.PosisiJabatan, p.IsDelete.Format(time.DateOnly))
+        }
+        log.Printf("🔍 Dry run: %d pekerjaan trashed before %s would be purged", len(expired), cutoff.Format(time.DateTime))
+        return nil
+    }
+    if len(expired) == 0 {
+        log.Printf("✅ No pekerjaan trashed before %s", cutoff.Format(time.DateTime))
+        return nil
+    }
+    if err := c.confirm(fmt.Sprintf("permanently delete %d trashed pekerjaan", len(expired))); err != nil {
+        return err
+    }
+
+    // The prompt may have taken a while, the purge gets its own timeout
+    ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+    defer cancel()
+
+    n, err := repo.PurgeTrash(ctx, cutoff)
+    if err != nil {
+        return err
+    }
+    log.Printf("✅ %d pekerjaan purged from the trash", n)
+    return nil
+}
+
+// purgeTrash runs PurgeTrash every interval until ctx is done, deleting what
+// was trashed more than retention ago
+func purgeTrash(ctx context.Context, repo repository.PekerjaanRepository, retention, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        n, err := repo.PurgeTrash(ctx, time.Now().Add(-retention))
+        switch {
+        case err != nil && ctx.Err() == nil:
+            log.Printf("⚠️  Purging the trash failed: %v", err)
+        case n > 0:
+            log.Printf("🗑️  %d pekerjaan purged from the trash", n)
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+    "context"
+    "crypto/tls"
+    "log"
+    "net"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/app/service"
+    "go-fiber/cache"
+    "go-fiber/config"
+    "go-fiber/database"
+    "go-fiber/metrics"
+    "go-fiber/middleware"
+    "go-fiber/ratelimit"
+    "go-fiber/routes"
+    "go-fiber/tlsreload"
+    "go-fiber/tracing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func cmdServe(c *cli, args []string) error {
+    if err := c.noArgs("serve", args); err != nil {
+        return err
+    }
+
+    log.Println("🚀 Starting application...")
+
+    flushTraces, err := tracing.Setup(context.Background(), c.cfg.Tracing)
+    if err != nil {
+        return err
+    }
+    defer func() {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if err := flushTraces(ctx); err != nil {
+            log.Printf("⚠️  Failed to flush traces: %v", err)
+        }
+    }()
+
+    if database.Driver() == database.DriverPostgres {
+        pg := database.ConnectPostgres()
+
+        if c.cfg.AutoMigrate {
+            if err := database.RunPostgresMigrations(pg); err != nil {
+                log.Printf("⚠️  Auto-migration failed: %v", err)
+            }
+        }
+
+        repos := repository.NewPostgresRepositories(pg)
+        metrics.RegisterPostgresPool(pg)
+        if c.cfg.RateLimit.Store == config.RateLimitShared {
+            ratelimit.SetDefault(ratelimit.NewPostgresStore(pg))
+        }
+        metrics.RegisterBusiness(businessCounts(repos))
+        repos = cachedRepositories(c.cfg.Cache, repos, func() cache.Store { return cache.NewPostgresStore(pg) })
+        services := service.NewServices(repos)
+        services.Health.AddCheck("postgres", pg.PingContext)
+        services.Health.AddCheck("migrations", database.NewPostgresMigrator(pg).Ready)
+
+        return serve(c.cfg, nil, services, func(ctx context.Context) error {
+            log.Println("🔌 Closing PostgreSQL connections")
+            return pg.Close()
+        })
+    }
+
+    db := database.ConnectDB()
+
+    // Run migrations automatically on startup (optional)
+    if c.cfg.AutoMigrate {
+        if err := database.RunMigrations(db); err != nil {
+            log.Printf("⚠️  Auto-migration failed: %v", err)
+        }
+    }
+
+    // Report validator and index drift, fixing it with SCHEMA_SYNC=true.
+    // Drift left afterwards fails the schema readiness check.
+    ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeouts.SchemaCheck)
+    if err := database.ReconcileSchema(ctx, db, c.cfg.SchemaSync); err != nil {
+        log.Printf("⚠️  Schema check failed: %v", err)
+    }
+    cancel()
+
+    repos := repository.NewMongoRepositories(db)
+    metrics.RegisterBusiness(businessCounts(repos))
+    if c.cfg.RateLimit.Store == config.RateLimitShared {
+        ratelimit.SetDefault(ratelimit.NewMongoStore(db.Collection(database.RateLimitsCollection)))
+    }
+    repos = cachedRepositories(c.cfg.Cache, repos, func() cache.Store {
+        return cache.NewMongoStore(db.Collection(database.CacheCollection))
+    })
+    services := service.NewServices(repos)
+    services.Health.AddCheck("mongo", func(ctx context.Context) error {
+        return db.Client().Ping(ctx, readpref.Primary())
+    })
+    services.Health.AddCheck("migrations", database.NewMongoMigrator(db).Ready)
+    services.Health.AddCheck("schema", database.SchemaReady(db))
+
+    return serve(c.cfg, db, services, func(ctx context.Context) error {
+        log.Println("🔌 Disconnecting MongoDB")
+        return db.Client().Disconnect(ctx)
+    })
+}
+
+// cachedRepositories caches the queries of repos as configured, in an LRU
+// or in the store made by shared, and sets the cache used by ConditionalGet
+func cachedRepositories(cfg config.CacheConfig, repos repository.Repositories, shared func() cache.Store) repository.Repositories {
+    if !cfg.Enabled {
+        return repos
+    }
+
+    var store cache.Store = cache.NewLRU(cfg.Size)
+    if cfg.Store == config.CacheShared {
+        store = shared()
+    }
+    c := cache.New(store, cfg.TTL)
+    cache.SetDefault(c)
+    return repository.NewCachedRepositories(repos, c)
+}
+
+// serve runs the HTTP server until SIGINT or SIGTERM, then stops accepting
+// connections, lets in-flight requests finish within Timeouts.Shutdown and
+// calls closeDB
+func serve(cfg *config.Config, db *mongo.Database, services *service.Services, closeDB func(context.Context) error) error {
+    // Request contexts outlive the start of the shutdown and are only
+    // cancelled once draining is over
+    requests, stopRequests := context.WithCancel(context.Background())
+    defer stopRequests()
+
+    // Create Fiber app
+    app := config.NewApp(db, middleware.RequestID(requests), middleware.Tracing(), middleware.Logger(), middleware.Metrics(), middleware.Recover())
+
+    jobs, stopJobs := context.WithCancel(context.Background())
+    defer stopJobs()
+
+    // Register routes
+    services.Pekerjaan.OverlapPolicy = cfg.Pekerjaan.OverlapPolicy
+    services.Pekerjaan.TrashRetention = cfg.Pekerjaan.TrashRetention
+    services.Stats.MinGroupSize = cfg.Stats.MinGroupSize
+    services.Reports.MinGroupSize = cfg.Stats.MinGroupSize
+    services.Reports.Workers = cfg.Reports.Workers
+    services.Reports.Timeout = cfg.Reports.Timeout
+    services.Reports.Jobs = jobs
+    routes.RegisterRoutes(app, services)
+
+    // Every origin used to be allowed; a frontend on another origin breaks
+    // until it is listed
+    if cfg.CORS.AllowOrigins == "" {
+        log.Println("⚠️  CORS_ALLOW_ORIGINS is not set, cross-origin requests are refused")
+    }
+
+    ln, err := listen(cfg)
+    if err != nil {
+        return err
+    }
+
+    quit := make(chan os.Signal, 1)
+    signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+    defer signal.Stop(quit)
+
+    // Start server
+    listenErr := make(chan error, 1)
+    go func() {
+        scheme := "http"
+        if cfg.TLS.Enabled() {
+            scheme = "https"
+        }
+        log.Printf("🌐 Server running on %s://localhost:%s", scheme, cfg.Port)
+        listenErr <- app.Listener(ln)
+    }()
+
+    if cfg.Pekerjaan.ExpireInterval > 0 {
+        go expireContracts(jobs, services.Pekerjaan.Repo, cfg.Pekerjaan.ExpireInterval)
+    }
+    if cfg.Pekerjaan.TrashRetention > 0 && cfg.Pekerjaan.PurgeInterval > 0 {
+        go purgeTrash(jobs, services.Pekerjaan.Repo, cfg.Pekerjaan.TrashRetention, cfg.Pekerjaan.PurgeInterval)
+    }
+    go failStaleReports(jobs, services.Reports, cfg.Reports.Timeout)
+
+    select {
+    case err := <-listenErr:
+        return err
+    case sig := <-quit:
+        log.Printf("🛑 Received %s, shutting down (draining up to %s)", sig, cfg.Timeouts.Shutdown)
+    }
+
+    // Fail readiness first and keep serving for the grace period, so load
+    // balancers take this replica out before it stops accepting connections
+    services.Health.ShuttingDown()
+    stopJobs()
+    if cfg.Timeouts.ShutdownGrace > 0 {
+        log.Printf("⏳ Readiness failing, waiting %s before closing the listener", cfg.Timeouts.ShutdownGrace)
+        select {
+        case <-time.After(cfg.Timeouts.ShutdownGrace):
+        case sig := <-quit:
+            log.Printf("🛑 Received %s again, skipping the grace period", sig)
+        }
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Shutdown)
+    defer cancel()
+
+    shutdownErr := app.ShutdownWithContext(ctx)
+    if shutdownErr != nil {
+        log.Printf("⚠️  In-flight requests did not finish in time: %v", shutdownErr)
+    }
+    // Requests still running past the drain deadline are cancelled now
+    stopRequests()
+    // Reports still generating were cancelled by stopJobs and only need to
+    // record that they failed
+    services.Reports.Wait()
+
+    // Give the database its own deadline, the drain may have used up ctx
+    closeCtx, closeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer closeCancel()
+    if err := closeDB(closeCtx); err != nil {
+        log.Printf("⚠️  Failed to close the database: %v", err)
+        return err
+    }
+
+    log.Println("👋 Server stopped")
+    return shutdownErr
+}
+
+// listen opens the server socket, with TLS when a certificate is configured.
+// The certificate files are watched and reloaded when they change.
+func listen(cfg *config.Config) (net.Listener, error) {
+    ln, err := net.Listen("tcp", ":"+cfg.Port)
+    if err != nil || !cfg.TLS.Enabled() {
+        return ln, err
+    }
+
+    certs, err := tlsreload.New(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+    if err != nil {
+        ln.Close()
+        return nil, err
+    }
+    // Runs for the life of the process
+    go certs.Watch(context.Background(), cfg.TLS.ReloadInterval)
+    log.Printf("🔒 TLS enabled with %s", cfg.TLS.CertFile)
+    return tls.NewListener(ln, certs.Config()), nil
+}
+
+// failStaleReports fails the report jobs past their timeout every timeout
+// until ctx is done, so jobs of a server that stopped do not stay pending
+func failStaleReports(ctx context.Context, s *service.ReportService, timeout time.Duration) {
+    ticker := time.NewTicker(timeout)
+    defer ticker.Stop()
+
+    for {
+        n, err := s.FailStale(ctx)
+        switch {
+        case err != nil && ctx.Err() == nil:
+            log.Printf("⚠️  Failing stale reports failed: %v", err)
+        case n > 0:
+            log.Printf("📄 %d unfinished reports marked failed", n)
+        }
+
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// businessCounts reads the totals exported as business gauges
+func businessCounts(repos repository.Repositories) func(context.Context) (metrics.BusinessCounts, error) {
+    return func(ctx context.Context) (metrics.BusinessCounts, error) {
+        var counts metrics.BusinessCounts
+        var err error
+
+        if counts.Alumni, err = repos.Alumni.CountAlumni(ctx, ""); err != nil {
+            return counts, err
+        }
+        if counts.ActivePekerjaan, err = repos.Pekerjaan.CountPekerjaanByStatus(ctx, model.StatusAktif); err != nil {
+            return counts, err
+        }
+        counts.TrashPekerjaan, err = repos.Pekerjaan.CountTrashPekerjaan(ctx, primitive.NilObjectID, true, "")
+        return counts, err
+    }
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "strings"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/utils"
+)
+
+const minPasswordLength = 8
+
+// readPassword reads the password from stdin with --password-stdin, or asks
+// for it twice on a terminal
+func (c *cli) readPassword(fromStdin bool) (string, error) {
+    readLine := func() string {
+        line, _ := c.in.ReadString('\n')
+        return strings.TrimRight(line, "\r\n")
+    }
+
+    var password string
+    if fromStdin {
+        password = readLine()
+    } else {
+        if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+            return "", fmt.Errorf("%w: use --password-stdin when not run interactively", errUsage)
+        }
+
+        fmt.Fprint(os.Stderr, "Password: ")
+        password = readLine()
+        fmt.Fprint(os.Stderr, "Repeat password: ")
+        if readLine() != password {
+            return "", errors.New("passwords do not match")
+        }
+    }
+
+    if len(password) < minPasswordLength {
+        return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+    }
+    return password, nil
+}
+
+func cmdUserCreateAdmin(c *cli, args []string) error {
+    fs := flag.NewFlagSet("user create-admin", flag.ContinueOnError)
+    username := fs.String("username", "", "Username, 3 to 50 characters")
+    email := fs.String("email", "", "Email address")
+    passwordStdin := fs.Bool("password-stdin", false, "Read the password from stdin")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+    if len(*username) < 3 || len(*username) > 50 || !strings.Contains(*email, "@") {
+        return fmt.Errorf("%w: --username (3 to 50 characters) and a valid --email are required", errUsage)
+    }
+
+    password, err := c.readPassword(*passwordStdin)
+    if err != nil {
+        return err
+    }
+    hash, err := utils.HashPassword(password)
+    if err != nil {
+        return err
+    }
+
+    repos := c.repositories()
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    user, err := repos.User.CreateUser(ctx, model.User{
+        Username:     *username,
+        Email:        *email,
+        PasswordHash: hash,
+        Role:         "admin",
+    })
+    if err != nil {
+        return err
+    }
+
+    log.Printf("✅ Admin %s created with id %s", user.Username, user.ID.Hex())
+    return nil
+}
+
+func cmdUserSetPassword(c *cli, args []string) error {
+    fs := flag.NewFlagSet("user set-password", flag.ContinueOnError)
+    passwordStdin := fs.Bool("password-stdin", false, "Read the password from stdin")
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
+    }
+    if len(positional) != 1 {
+        return fmt.Errorf("%w: expected one username or email", errUsage)
+    }
+
+    password, err := c.readPassword(*passwordStdin)
+    if err != nil {
+        return err
+    }
+    hash, err := utils.HashPassword(password)
+    if err != nil {
+        return err
+    }
+
+    repos := c.repositories()
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    user, err := repos.User.FindUserByUsernameOrEmail(ctx, positional[0])
+    if err != nil {
+        return err
+    }
+
+    if err := repos.User.UpdatePassword(ctx, user.ID, hash); err != nil {
+        return err
+    }
+
+    log.Printf("✅ Password of %s updated", user.Username)
+    return nil
+}
@@ -1,14 +1,24 @@
 package config
 
 import (
+    "strings"
+
     "github.com/gofiber/fiber/v2"
     "github.com/gofiber/fiber/v2/middleware/cors"
-    "github.com/gofiber/fiber/v2/middleware/logger"
+    "github.com/gofiber/fiber/v2/middleware/helmet"
     "go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewApp(db *mongo.Database) *fiber.App {
+// NewApp creates the app with the shared error handler, the configured body
+// limit, trusted proxies, security headers and CORS policy. handlers run
+// first on every request, before the headers are set.
+func NewApp(db *mongo.Database, handlers ...fiber.Handler) *fiber.App {
     app := fiber.New(fiber.Config{
+        BodyLimit:               int(current.BodyLimits.Max()),
+        ProxyHeader:             current.Proxy.Header,
+        EnableTrustedProxyCheck: true,
+        TrustedProxies:          current.Proxy.TrustedProxyList(),
+        EnableIPValidation:      true,
         ErrorHandler: func(c *fiber.Ctx, err error) error {
             code := fiber.StatusInternalServerError
             if e, ok := err.(*fiber.Error); ok {
@@ -21,8 +31,37 @@ func NewApp(db *mongo.Database) *fiber.App {
         },
     })
 
-    app.Use(logger.New())
-    app.Use(cors.New())
+    for _, handler := range handlers {
+        app.Use(handler)
+    }
+    origins := current.CORS.AllowOrigins
+    app.Use(securityHeaders(current.Headers, origins != ""))
+    if origins != "" {
+        app.Use(cors.New(cors.Config{
+            AllowOrigins:     origins,
+            AllowMethods:     current.CORS.AllowMethods,
+            AllowHeaders:     current.CORS.AllowHeaders,
+            ExposeHeaders:    current.CORS.ExposeHeaders,
+            AllowCredentials: current.CORS.AllowCredentials,
+            MaxAge:           int(current.CORS.MaxAge.Seconds()),
+        }))
+    }
 
     return app
-}
\ No newline at end of file
+}
+
+// securityHeaders sets the configured headers on top of the helmet
+// defaults; routes may replace the Content-Security-Policy, as the docs page
+// does. Resources are opened to other origins when CORS allows them.
+func securityHeaders(cfg HeadersConfig, crossOrigin bool) fiber.Handler {
+    resourcePolicy := "same-origin"
+    if crossOrigin {
+        resourcePolicy = "cross-origin"
+    }
+    return helmet.New(helmet.Config{
+        XFrameOptions:             strings.ToUpper(cfg.FrameOptions),
+        HSTSMaxAge:                int(cfg.HSTSMaxAge.Seconds()),
+        ContentSecurityPolicy:     cfg.ContentSecurityPolicy,
+        CrossOriginResourcePolicy: resourcePolicy,
+    })
+}
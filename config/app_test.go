@@ -0,0 +1,94 @@
+package config
+
+import (
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+func TestNewAppSecurityHeaders(t *testing.T) {
+    app := NewApp(nil)
+    app.Get("/alumni", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/alumni", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]string{
+        "X-Content-Type-Options":      "nosniff",
+        "X-Frame-Options":             "DENY",
+        "Content-Security-Policy":     Defaults().Headers.ContentSecurityPolicy,
+        "Access-Control-Allow-Origin": "",
+        // HSTS is only sent over HTTPS
+        "Strict-Transport-Security": "",
+    }
+    for header, value := range want {
+        if got := resp.Header.Get(header); got != value {
+            t.Errorf("%s = %q, want %q", header, got, value)
+        }
+    }
+}
+
+func TestNewAppCORS(t *testing.T) {
+    cfg := Defaults()
+    cfg.CORS.AllowOrigins = "https://alumni.example.ac.id"
+    Set(cfg)
+    t.Cleanup(func() { Set(Defaults()) })
+
+    app := NewApp(nil)
+    app.Get("/alumni", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+    preflight := func(origin string) http.Header {
+        req := httptest.NewRequest("OPTIONS", "/alumni", nil)
+        req.Header.Set("Origin", origin)
+        req.Header.Set("Access-Control-Request-Method", "GET")
+        resp, err := app.Test(req)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return resp.Header
+    }
+
+    allowed := preflight("https://alumni.example.ac.id")
+    if got := allowed.Get("Access-Control-Allow-Origin"); got != "https://alumni.example.ac.id" {
+        t.Errorf("allowed origin: Access-Control-Allow-Origin = %q", got)
+    }
+    if got := allowed.Get("Access-Control-Allow-Methods"); !strings.Contains(got, "PUT") || strings.Contains(got, "PATCH") {
+        t.Errorf("Access-Control-Allow-Methods = %q", got)
+    }
+    if got := preflight("https://evil.example.com").Get("Access-Control-Allow-Origin"); got != "" {
+        t.Errorf("other origin: Access-Control-Allow-Origin = %q, want none", got)
+    }
+}
+
+func TestNewAppTrustedProxies(t *testing.T) {
+    ip := func(trusted string) string {
+        cfg := Defaults()
+        cfg.Proxy = ProxyConfig{Header: "X-Real-IP", TrustedProxies: trusted}
+        Set(cfg)
+        t.Cleanup(func() { Set(Defaults()) })
+
+        app := NewApp(nil)
+        app.Get("/", func(c *fiber.Ctx) error { return c.SendString(c.IP()) })
+        req := httptest.NewRequest("GET", "/", nil)
+        req.Header.Set("X-Real-IP", "203.0.113.7")
+        resp, err := app.Test(req)
+        if err != nil {
+            t.Fatal(err)
+        }
+        body, _ := io.ReadAll(resp.Body)
+        return string(body)
+    }
+
+    // app.Test connects from 0.0.0.0
+    if got := ip("0.0.0.0/32"); got != "203.0.113.7" {
+        t.Errorf("from a trusted proxy: IP = %q, want the header", got)
+    }
+    if got := ip("10.0.0.1"); got != "0.0.0.0" {
+        t.Errorf("from another peer: IP = %q, want the peer address", got)
+    }
+}
@@ -0,0 +1,699 @@
+package config
+
+import (
+    "encoding"
+    "errors"
+    "fmt"
+    "io"
+    "io/fs"
+    "net"
+    "net/url"
+    "os"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+
+    "go-fiber/logger"
+    "go-fiber/ratelimit"
+
+    "github.com/joho/godotenv"
+    "gopkg.in/yaml.v3"
+)
+
+// MinJWTSecretLength is 256 bits, the key size of HS256
+const MinJWTSecretLength = 32
+
+// Config is the typed application configuration. Each field is read from,
+// in order of precedence: the process environment, .env.<APP_ENV> and .env
+// (both optional), the YAML file named by --config or CONFIG_FILE, and the
+// defaults from Defaults. The env tag names the variable, the yaml tag the
+// YAML key. Secret fields are hidden by Redacted; secret:"url" only hides the
+// password inside a connection string, shown as xxxxx.
+type Config struct {
+    Env         string          `yaml:"env" env:"APP_ENV"`
+    Port        string          `yaml:"port" env:"APP_PORT"`
+    AutoMigrate bool            `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+    SchemaSync  bool            `yaml:"schema_sync" env:"SCHEMA_SYNC"`
+    Database    DatabaseConfig  `yaml:"database"`
+    JWT         JWTConfig       `yaml:"jwt"`
+    Timeouts    TimeoutConfig   `yaml:"timeouts"`
+    Log         LogConfig       `yaml:"log"`
+    Tracing     TracingConfig   `yaml:"tracing"`
+    RateLimit   RateLimitConfig `yaml:"rate_limit"`
+    Cache       CacheConfig     `yaml:"cache"`
+    CORS        CORSConfig      `yaml:"cors"`
+    Headers     HeadersConfig   `yaml:"headers"`
+    Proxy       ProxyConfig     `yaml:"proxy"`
+    BodyLimits  BodyLimitConfig `yaml:"body_limits"`
+    TLS         TLSConfig       `yaml:"tls"`
+    Pekerjaan   PekerjaanConfig `yaml:"pekerjaan"`
+    Stats       StatsConfig     `yaml:"stats"`
+    Reports     ReportsConfig   `yaml:"reports"`
+}
+
+type DatabaseConfig struct {
+    Driver             string        `yaml:"driver" env:"DB_DRIVER"`
+    MongoURI           string        `yaml:"mongodb_uri" env:"MONGODB_URI" secret:"url"`
+    MongoDatabase      string        `yaml:"mongodb_database" env:"MONGODB_DATABASE"`
+    PostgresURL        string        `yaml:"postgres_url" env:"POSTGRES_URL" secret:"url"`
+    SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"SLOW_QUERY_THRESHOLD"`
+}
+
+type JWTConfig struct {
+    Secret string        `yaml:"secret" env:"JWT_SECRET" secret:"true"`
+    TTL    time.Duration `yaml:"ttl" env:"JWT_TTL"`
+}
+
+// TimeoutConfig holds the request deadlines. Routes overrides Request per
+// route group and is read from REQUEST_TIMEOUT_<GROUP> variables. After
+// SIGTERM the readiness probe fails for ShutdownGrace while new connections
+// are still accepted, so load balancers stop routing here, and then
+// in-flight requests may drain for up to Shutdown.
+type TimeoutConfig struct {
+    Request       time.Duration            `yaml:"request" env:"REQUEST_TIMEOUT"`
+    Routes        map[string]time.Duration `yaml:"routes"`
+    Shutdown      time.Duration            `yaml:"shutdown" env:"SHUTDOWN_TIMEOUT"`
+    ShutdownGrace time.Duration            `yaml:"shutdown_grace" env:"SHUTDOWN_GRACE_PERIOD"`
+    SchemaCheck   time.Duration            `yaml:"schema_check" env:"SCHEMA_CHECK_TIMEOUT"`
+}
+
+// LogConfig selects the log level (debug, info, warn, error) and format
+// (auto, json, pretty); auto writes JSON when APP_ENV is production
+type LogConfig struct {
+    Level  string `yaml:"level" env:"LOG_LEVEL"`
+    Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig selects where OpenTelemetry spans go: none, otlp (OTLP over
+// HTTP to Endpoint, e.g. http://localhost:4318 for a local collector) or
+// stdout. SampleRatio is the share of new traces recorded; traces started
+// upstream follow the caller's decision.
+type TracingConfig struct {
+    Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+    Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+    ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+    SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// RateLimitConfig holds the token bucket policy of each route group, written
+// like "10/1m burst=5 key=ip" (see ratelimit.ParsePolicy) and read from
+// RATE_LIMIT_<GROUP> variables. Groups without a policy are not limited.
+// Store is memory, counting per replica, or shared, keeping the buckets in
+// the configured database. APIKeys lists the comma separated keys that
+// policies keyed by api_key count separately; other keys count by IP.
+type RateLimitConfig struct {
+    Enabled  bool              `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+    Store    string            `yaml:"store" env:"RATE_LIMIT_STORE"`
+    APIKeys  string            `yaml:"api_keys" env:"RATE_LIMIT_API_KEYS" secret:"true"`
+    Policies map[string]string `yaml:"policies"`
+}
+
+// Rate limit stores selectable with RATE_LIMIT_STORE
+const (
+    RateLimitMemory = "memory"
+    RateLimitShared = "shared"
+)
+
+// Cache stores selectable with CACHE_STORE
+const (
+    CacheMemory = "memory"
+    CacheShared = "shared"
+)
+
+// CacheConfig sets up the cache of stats and list queries, off by default.
+// Store is memory, an LRU of Size entries per replica, or shared, keeping the
+// entries in the configured database. A memory cache only sees the writes of
+// its own replica, so other replicas serve stale entries for up to TTL.
+type CacheConfig struct {
+    Enabled bool          `yaml:"enabled" env:"CACHE_ENABLED"`
+    Store   string        `yaml:"store" env:"CACHE_STORE"`
+    Size    int           `yaml:"size" env:"CACHE_SIZE"`
+    TTL     time.Duration `yaml:"ttl" env:"CACHE_TTL"`
+}
+
+// CORSConfig lists the comma separated origins, methods and headers allowed
+// in cross-origin requests. Without AllowOrigins only same-origin requests
+// work; "*" allows every origin and rules out AllowCredentials.
+type CORSConfig struct {
+    AllowOrigins     string        `yaml:"allow_origins" env:"CORS_ALLOW_ORIGINS"`
+    AllowMethods     string        `yaml:"allow_methods" env:"CORS_ALLOW_METHODS"`
+    AllowHeaders     string        `yaml:"allow_headers" env:"CORS_ALLOW_HEADERS"`
+    ExposeHeaders    string        `yaml:"expose_headers" env:"CORS_EXPOSE_HEADERS"`
+    AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+    MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"`
+}
+
+// HeadersConfig holds the security headers sent with every response. HSTS
+// is only sent over HTTPS; a zero HSTSMaxAge turns it off.
+type HeadersConfig struct {
+    HSTSMaxAge            time.Duration `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+    ContentSecurityPolicy string        `yaml:"content_security_policy" env:"CONTENT_SECURITY_POLICY"`
+    FrameOptions          string        `yaml:"frame_options" env:"FRAME_OPTIONS"`
+}
+
+// ProxyConfig tells the client IP apart behind a reverse proxy. Header, e.g.
+// X-Real-IP, is only read from requests whose peer is one of the comma
+// separated TrustedProxies (IPs or CIDR ranges); other requests use the peer
+// address. The proxy must overwrite the header: with X-Forwarded-For the
+// first address is used, which a client can forge when the proxy appends.
+type ProxyConfig struct {
+    Header         string `yaml:"header" env:"PROXY_HEADER"`
+    TrustedProxies string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+// TrustedProxyList returns the entries of TrustedProxies
+func (p ProxyConfig) TrustedProxyList() []string {
+    var proxies []string
+    for _, proxy := range strings.Split(p.TrustedProxies, ",") {
+        if proxy = strings.TrimSpace(proxy); proxy != "" {
+            proxies = append(proxies, proxy)
+        }
+    }
+    return proxies
+}
+
+// BodyLimitConfig bounds request bodies. Routes overrides Default per route
+// group and is read from BODY_LIMIT_<GROUP> variables, e.g.
+// BODY_LIMIT_AUTH=16KB.
+type BodyLimitConfig struct {
+    Default ByteSize            `yaml:"default" env:"BODY_LIMIT"`
+    Routes  map[string]ByteSize `yaml:"routes"`
+}
+
+// TLSConfig turns on HTTPS when both files are set. The certificate is
+// reloaded when the files change, checked every ReloadInterval.
+type TLSConfig struct {
+    CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE"`
+    KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE"`
+    ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL"`
+}
+
+// Enabled reports whether the server listens with TLS
+func (t TLSConfig) Enabled() bool {
+    return t.CertFile != "" && t.KeyFile != ""
+}
+
+// Overlap policies selectable with PEKERJAAN_OVERLAP_POLICY
+const (
+    OverlapAllow  = "allow"
+    OverlapActive = "active"
+    OverlapNone   = "none"
+)
+
+// PekerjaanConfig holds the employment timeline rules. OverlapPolicy decides
+// which pekerjaan of one alumni may run at once: allow any, only one aktif
+// (active), or no overlapping periods at all (none). Aktif contracts past
+// their end date become kontrak_habis every ExpireInterval; zero turns that
+// off. Every PurgeInterval, trash older than TrashRetention is deleted for
+// good; the retention is zero by default, keeping the trash forever until an
+// operator opts in (see config.example.yaml).
+type PekerjaanConfig struct {
+    OverlapPolicy  string        `yaml:"overlap_policy" env:"PEKERJAAN_OVERLAP_POLICY"`
+    ExpireInterval time.Duration `yaml:"expire_interval" env:"PEKERJAAN_EXPIRE_INTERVAL"`
+    TrashRetention time.Duration `yaml:"trash_retention" env:"PEKERJAAN_TRASH_RETENTION"`
+    PurgeInterval  time.Duration `yaml:"purge_interval" env:"PEKERJAAN_PURGE_INTERVAL"`
+}
+
+// StatsConfig holds the analytics settings. Groups of fewer than
+// MinGroupSize alumni are merged or suppressed.
+type StatsConfig struct {
+    MinGroupSize int `yaml:"min_group_size" env:"STATS_MIN_GROUP_SIZE"`
+}
+
+// ReportsConfig holds the accreditation report jobs. At most Workers
+// reports are generated at once; a job not finished within Timeout of its
+// request fails, and so does one left behind by a stopped server.
+type ReportsConfig struct {
+    Workers int           `yaml:"workers" env:"REPORT_WORKERS"`
+    Timeout time.Duration `yaml:"timeout" env:"REPORT_TIMEOUT"`
+}
+
+// Span exporters selectable with TRACING_EXPORTER
+const (
+    TracingNone   = "none"
+    TracingOTLP   = "otlp"
+    TracingStdout = "stdout"
+)
+
+// Storage drivers selectable with DB_DRIVER
+const (
+    DriverMongo    = "mongo"
+    DriverPostgres = "postgres"
+)
+
+// Defaults returns the configuration used for everything left unset
+func Defaults() *Config {
+    return &Config{
+        Env:  "development",
+        Port: "3000",
+        Database: DatabaseConfig{
+            Driver:             DriverMongo,
+            SlowQueryThreshold: 500 * time.Millisecond,
+        },
+        JWT: JWTConfig{
+            TTL: 24 * time.Hour,
+        },
+        Timeouts: TimeoutConfig{
+            Request:       10 * time.Second,
+            Routes:        map[string]time.Duration{},
+            Shutdown:      15 * time.Second,
+            ShutdownGrace: 5 * time.Second,
+            SchemaCheck:   time.Minute,
+        },
+        Log: LogConfig{
+            Level:  "info",
+            Format: logger.FormatAuto,
+        },
+        Tracing: TracingConfig{
+            Exporter:    TracingNone,
+            ServiceName: "alumni-api",
+            SampleRatio: 1,
+        },
+        RateLimit: RateLimitConfig{
+            Enabled: true,
+            Store:   RateLimitMemory,
+            Policies: map[string]string{
+                "auth":      "10/1m burst=10 key=ip",
+                "alumni":    "300/1m burst=60 key=user",
+                "pekerjaan": "300/1m burst=60 key=user",
+                "users":     "120/1m burst=30 key=user",
+                "companies": "300/1m burst=60 key=user",
+                "stats":     "60/1m burst=20 key=user",
+                "reports":   "30/1m burst=10 key=user",
+            },
+        },
+        Cache: CacheConfig{
+            Store:   CacheMemory,
+            Size:    1000,
+            TTL:     5 * time.Minute,
+        },
+        CORS: CORSConfig{
+            AllowMethods:  "GET,HEAD,POST,PUT,DELETE",
+            AllowHeaders:  "Authorization,Content-Type,X-Request-ID,X-API-Key,If-None-Match,If-Modified-Since",
+            ExposeHeaders: "X-Request-ID,ETag,Last-Modified,Retry-After,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy",
+            MaxAge:        10 * time.Minute,
+        },
+        Headers: HeadersConfig{
+            HSTSMaxAge:            180 * 24 * time.Hour,
+            ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+            FrameOptions:          "DENY",
+        },
+        BodyLimits: BodyLimitConfig{
+            Default: 1 * MB,
+            Routes:  map[string]ByteSize{"auth": 16 * KB},
+        },
+        TLS: TLSConfig{
+            ReloadInterval: 30 * time.Second,
+        },
+        Pekerjaan: PekerjaanConfig{
+            OverlapPolicy:  OverlapActive,
+            ExpireInterval: time.Hour,
+            PurgeInterval:  time.Hour,
+        },
+        Stats: StatsConfig{
+            MinGroupSize: 5,
+        },
+        Reports: ReportsConfig{
+            Workers: 2,
+            Timeout: 5 * time.Minute,
+        },
+    }
+}
+
+var current = Defaults()
+
+// Get returns the configuration loaded at startup, or the defaults
+func Get() *Config {
+    return current
+}
+
+// Set makes cfg the configuration returned by Get
+func Set(cfg *Config) {
+    current = cfg
+}
+
+// Load reads the configuration for the named environment. With an env name
+// .env.<env> is loaded before .env, so it wins over the shared file. file is
+// the YAML file to read; when empty CONFIG_FILE is used, and without either
+// no YAML is read. Load does not validate, see Validate.
+func Load(env, file string) (*Config, error) {
+    if env != "" && os.Getenv("APP_ENV") == "" {
+        os.Setenv("APP_ENV", env)
+    }
+    if env != "" {
+        if err := godotenv.Load(".env." + env); err != nil {
+            return nil, fmt.Errorf("load .env.%s: %w", env, err)
+        }
+    }
+    if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+        return nil, fmt.Errorf("load .env: %w", err)
+    }
+
+    cfg := Defaults()
+
+    if file == "" {
+        file = os.Getenv("CONFIG_FILE")
+    }
+    if file != "" {
+        data, err := os.ReadFile(file)
+        if err != nil {
+            return nil, err
+        }
+        if err := yaml.Unmarshal(data, cfg); err != nil {
+            return nil, fmt.Errorf("parse %s: %w", file, err)
+        }
+    }
+
+    if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+        return nil, err
+    }
+
+    if cfg.Timeouts.Routes == nil {
+        cfg.Timeouts.Routes = map[string]time.Duration{}
+    }
+    if cfg.RateLimit.Policies == nil {
+        cfg.RateLimit.Policies = map[string]string{}
+    }
+    if cfg.BodyLimits.Routes == nil {
+        cfg.BodyLimits.Routes = map[string]ByteSize{}
+    }
+    for _, pair := range os.Environ() {
+        key, value, _ := strings.Cut(pair, "=")
+        if value == "" {
+            continue
+        }
+
+        if group, ok := strings.CutPrefix(key, "REQUEST_TIMEOUT_"); ok {
+            d, err := time.ParseDuration(value)
+            if err != nil {
+                return nil, fmt.Errorf("%s: %w", key, err)
+            }
+            cfg.Timeouts.Routes[strings.ToLower(group)] = d
+        }
+        if group, ok := strings.CutPrefix(key, "RATE_LIMIT_"); ok && group != "ENABLED" && group != "STORE" && group != "API_KEYS" {
+            cfg.RateLimit.Policies[strings.ToLower(group)] = value
+        }
+        if group, ok := strings.CutPrefix(key, "BODY_LIMIT_"); ok {
+            size, err := ParseByteSize(value)
+            if err != nil {
+                return nil, fmt.Errorf("%s: %w", key, err)
+            }
+            cfg.BodyLimits.Routes[strings.ToLower(group)] = size
+        }
+    }
+
+    return cfg, nil
+}
+
+// applyEnv overwrites every field with an env tag whose variable is set
+func applyEnv(v reflect.Value) error {
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        value := v.Field(i)
+
+        if field.Type.Kind() == reflect.Struct {
+            if err := applyEnv(value); err != nil {
+                return err
+            }
+            continue
+        }
+
+        key := field.Tag.Get("env")
+        raw, ok := os.LookupEnv(key)
+        if key == "" || !ok || raw == "" {
+            continue
+        }
+
+        if u, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+            if err := u.UnmarshalText([]byte(raw)); err != nil {
+                return fmt.Errorf("%s: %w", key, err)
+            }
+            continue
+        }
+
+        switch value.Interface().(type) {
+        case string:
+            value.SetString(raw)
+        case bool:
+            b, err := strconv.ParseBool(raw)
+            if err != nil {
+                return fmt.Errorf("%s: %w", key, err)
+            }
+            value.SetBool(b)
+        case time.Duration:
+            d, err := time.ParseDuration(raw)
+            if err != nil {
+                return fmt.Errorf("%s: %w", key, err)
+            }
+            value.SetInt(int64(d))
+        case int:
+            n, err := strconv.Atoi(raw)
+            if err != nil {
+                return fmt.Errorf("%s: %w", key, err)
+            }
+            value.SetInt(int64(n))
+        case float64:
+            f, err := strconv.ParseFloat(raw, 64)
+            if err != nil {
+                return fmt.Errorf("%s: %w", key, err)
+            }
+            value.SetFloat(f)
+        }
+    }
+    return nil
+}
+
+// Validate checks everything needed to serve requests
+func (c *Config) Validate() error {
+    errs := []error{c.ValidateDatabase(), c.ValidateLog()}
+
+    switch c.Tracing.Exporter {
+    case TracingNone, TracingOTLP, TracingStdout:
+    default:
+        errs = append(errs, fmt.Errorf("TRACING_EXPORTER %q is not one of %s, %s, %s", c.Tracing.Exporter, TracingNone, TracingOTLP, TracingStdout))
+    }
+    if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+        errs = append(errs, errors.New("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+    }
+    if c.RateLimit.Store != RateLimitMemory && c.RateLimit.Store != RateLimitShared {
+        errs = append(errs, fmt.Errorf("RATE_LIMIT_STORE %q is not one of %s, %s", c.RateLimit.Store, RateLimitMemory, RateLimitShared))
+    }
+    for group, spec := range c.RateLimit.Policies {
+        if _, err := ratelimit.ParsePolicy(spec); err != nil {
+            errs = append(errs, fmt.Errorf("RATE_LIMIT_%s: %w", strings.ToUpper(group), err))
+        }
+    }
+    if c.Cache.Store != CacheMemory && c.Cache.Store != CacheShared {
+        errs = append(errs, fmt.Errorf("CACHE_STORE %q is not one of %s, %s", c.Cache.Store, CacheMemory, CacheShared))
+    }
+    if c.Cache.Enabled && c.Cache.Store == CacheMemory && c.Cache.Size <= 0 {
+        errs = append(errs, errors.New("CACHE_SIZE must be positive"))
+    }
+    if c.Cache.TTL < 0 {
+        errs = append(errs, errors.New("CACHE_TTL must not be negative"))
+    }
+    if c.CORS.AllowCredentials && strings.Contains(c.CORS.AllowOrigins, "*") {
+        errs = append(errs, errors.New("CORS_ALLOW_CREDENTIALS cannot be used with CORS_ALLOW_ORIGINS=*"))
+    }
+    if c.Proxy.Header != "" && len(c.Proxy.TrustedProxyList()) == 0 {
+        errs = append(errs, errors.New("PROXY_HEADER needs TRUSTED_PROXIES, otherwise any client can set its own IP"))
+    }
+    for _, proxy := range c.Proxy.TrustedProxyList() {
+        if net.ParseIP(proxy) == nil {
+            if _, _, err := net.ParseCIDR(proxy); err != nil {
+                errs = append(errs, fmt.Errorf("TRUSTED_PROXIES: %q is not an IP or CIDR range", proxy))
+            }
+        }
+    }
+    if c.BodyLimits.Default <= 0 {
+        errs = append(errs, errors.New("BODY_LIMIT must be positive"))
+    }
+    for group, size := range c.BodyLimits.Routes {
+        if size <= 0 {
+            errs = append(errs, fmt.Errorf("BODY_LIMIT_%s must be positive", strings.ToUpper(group)))
+        }
+    }
+    if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+        errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+    }
+    if c.TLS.Enabled() && c.TLS.ReloadInterval <= 0 {
+        errs = append(errs, errors.New("TLS_RELOAD_INTERVAL must be positive"))
+    }
+    switch c.Pekerjaan.OverlapPolicy {
+    case OverlapAllow, OverlapActive, OverlapNone:
+    default:
+        errs = append(errs, fmt.Errorf("PEKERJAAN_OVERLAP_POLICY %q is not one of %s, %s, %s", c.Pekerjaan.OverlapPolicy, OverlapAllow, OverlapActive, OverlapNone))
+    }
+    if c.Pekerjaan.ExpireInterval < 0 {
+        errs = append(errs, errors.New("PEKERJAAN_EXPIRE_INTERVAL must not be negative"))
+    }
+    if c.Pekerjaan.TrashRetention < 0 {
+        errs = append(errs, errors.New("PEKERJAAN_TRASH_RETENTION must not be negative"))
+    }
+    if c.Pekerjaan.PurgeInterval < 0 {
+        errs = append(errs, errors.New("PEKERJAAN_PURGE_INTERVAL must not be negative"))
+    }
+    if c.Stats.MinGroupSize < 1 {
+        errs = append(errs, errors.New("STATS_MIN_GROUP_SIZE must be positive"))
+    }
+    if c.Reports.Workers < 1 {
+        errs = append(errs, errors.New("REPORT_WORKERS must be positive"))
+    }
+    if c.Reports.Timeout <= 0 {
+        errs = append(errs, errors.New("REPORT_TIMEOUT must be positive"))
+    }
+
+    if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+        errs = append(errs, fmt.Errorf("APP_PORT %q is not a valid port", c.Port))
+    }
+    if len(c.JWT.Secret) < MinJWTSecretLength {
+        errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d characters", MinJWTSecretLength))
+    }
+    if c.JWT.TTL <= 0 {
+        errs = append(errs, errors.New("JWT_TTL must be positive"))
+    }
+    if c.Timeouts.Request <= 0 {
+        errs = append(errs, errors.New("REQUEST_TIMEOUT must be positive"))
+    }
+    if c.Timeouts.Shutdown <= 0 {
+        errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+    }
+    if c.Timeouts.ShutdownGrace < 0 {
+        errs = append(errs, errors.New("SHUTDOWN_GRACE_PERIOD must not be negative"))
+    }
+    if c.Timeouts.SchemaCheck <= 0 {
+        errs = append(errs, errors.New("SCHEMA_CHECK_TIMEOUT must be positive"))
+    }
+    for group, d := range c.Timeouts.Routes {
+        if d <= 0 {
+            errs = append(errs, fmt.Errorf("REQUEST_TIMEOUT_%s must be positive", strings.ToUpper(group)))
+        }
+    }
+
+    return errors.Join(errs...)
+}
+
+// ValidateDatabase checks the settings of the configured storage driver,
+// which is all the database commands need
+func (c *Config) ValidateDatabase() error {
+    switch c.Database.Driver {
+    case DriverMongo:
+        var errs []error
+        if c.Database.MongoURI == "" {
+            errs = append(errs, errors.New("MONGODB_URI is required"))
+        }
+        if c.Database.MongoDatabase == "" {
+            errs = append(errs, errors.New("MONGODB_DATABASE is required"))
+        }
+        return errors.Join(errs...)
+    case DriverPostgres:
+        if c.Database.PostgresURL == "" {
+            return errors.New("POSTGRES_URL is required")
+        }
+        return nil
+    }
+    return fmt.Errorf("DB_DRIVER %q is not one of %s, %s", c.Database.Driver, DriverMongo, DriverPostgres)
+}
+
+// ValidateLog checks the log level and format
+func (c *Config) ValidateLog() error {
+    _, err := logger.New(io.Discard, c.Log.Level, c.Log.Format, c.Env)
+    return err
+}
+
+// RequestTimeout returns the deadline for a route group, e.g. "stats" uses
+// REQUEST_TIMEOUT_STATS and falls back to REQUEST_TIMEOUT
+func (c *Config) RequestTimeout(group string) time.Duration {
+    if d, ok := c.Timeouts.Routes[group]; ok {
+        return d
+    }
+    return c.Timeouts.Request
+}
+
+// BodyLimit returns the request body limit of a route group, e.g. "auth"
+// uses BODY_LIMIT_AUTH and falls back to BODY_LIMIT
+func (c *Config) BodyLimit(group string) ByteSize {
+    if size, ok := c.BodyLimits.Routes[group]; ok {
+        return size
+    }
+    return c.BodyLimits.Default
+}
+
+// RateLimitPolicy returns the rate limit policy of a route group, or "" when the
+// group is not limited
+func (c *Config) RateLimitPolicy(group string) string {
+    if !c.RateLimit.Enabled {
+        return ""
+    }
+    return c.RateLimit.Policies[group]
+}
+
+// RequestTimeout returns the deadline of a route group from the loaded
+// configuration
+func RequestTimeout(group string) time.Duration {
+    return current.RequestTimeout(group)
+}
+
+// BodyLimit returns the request body limit of a route group from the loaded
+// configuration
+func BodyLimit(group string) ByteSize {
+    return current.BodyLimit(group)
+}
+
+// RateLimitPolicy returns the rate limit policy of a route group from the
+// loaded configuration
+func RateLimitPolicy(group string) string {
+    return current.RateLimitPolicy(group)
+}
+
+// Redacted returns a copy with secrets masked, for printing
+func (c *Config) Redacted() *Config {
+    copied := *c
+    copied.Timeouts.Routes = map[string]time.Duration{}
+    for group, d := range c.Timeouts.Routes {
+        copied.Timeouts.Routes[group] = d
+    }
+    copied.BodyLimits.Routes = map[string]ByteSize{}
+    for group, size := range c.BodyLimits.Routes {
+        copied.BodyLimits.Routes[group] = size
+    }
+    copied.RateLimit.Policies = map[string]string{}
+    for group, spec := range c.RateLimit.Policies {
+        copied.RateLimit.Policies[group] = spec
+    }
+    redact(reflect.ValueOf(&copied).Elem())
+    return &copied
+}
+
+func redact(v reflect.Value) {
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        value := v.Field(i)
+        if value.Kind() == reflect.Struct {
+            redact(value)
+            continue
+        }
+        if value.Kind() != reflect.String || value.String() == "" {
+            continue
+        }
+
+        switch t.Field(i).Tag.Get("secret") {
+        case "true":
+            value.SetString("********")
+        case "url":
+            u, err := url.Parse(value.String())
+            if err != nil {
+                value.SetString("********")
+            } else {
+                value.SetString(u.Redacted())
+            }
+        }
+    }
+}
+
+// YAML renders the configuration in the format Load reads
+func (c *Config) YAML() ([]byte, error) {
+    return yaml.Marshal(c)
+}
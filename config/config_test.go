@@ -0,0 +1,226 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+    dir := t.TempDir()
+    wd, _ := os.Getwd()
+    os.Chdir(dir)
+    t.Cleanup(func() { os.Chdir(wd) })
+
+    file := filepath.Join(dir, "config.yaml")
+    os.WriteFile(file, []byte(`
+port: "4000"
+database:
+  mongodb_uri: mongodb://yaml:27017
+  mongodb_database: from_yaml
+jwt:
+  ttl: 2h
+timeouts:
+  routes:
+    stats: 45s
+`), 0o600)
+    os.WriteFile(filepath.Join(dir, ".env"), []byte("MONGODB_DATABASE=from_dotenv\nAPP_PORT=5000\n"), 0o600)
+
+    t.Setenv("APP_PORT", "6000")
+    t.Setenv("REQUEST_TIMEOUT_AUTH", "3s")
+    t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+    t.Setenv("RATE_LIMIT_AUTH", "5/1m key=ip")
+    t.Setenv("CACHE_SIZE", "50")
+    t.Setenv("BODY_LIMIT", "2MB")
+    t.Setenv("BODY_LIMIT_PEKERJAAN", "64KB")
+    // godotenv writes into the process environment; undo it after the test
+    t.Setenv("MONGODB_DATABASE", "")
+    os.Unsetenv("MONGODB_DATABASE")
+
+    cfg, err := Load("", file)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if cfg.Port != "6000" {
+        t.Errorf("port = %q, process environment should win", cfg.Port)
+    }
+    if cfg.Database.MongoDatabase != "from_dotenv" {
+        t.Errorf("database = %q, .env should win over YAML", cfg.Database.MongoDatabase)
+    }
+    if cfg.Database.MongoURI != "mongodb://yaml:27017" {
+        t.Errorf("uri = %q, YAML should win over defaults", cfg.Database.MongoURI)
+    }
+    if cfg.JWT.TTL != 2*time.Hour || cfg.Database.SlowQueryThreshold != 500*time.Millisecond {
+        t.Errorf("ttl = %s, slow query = %s", cfg.JWT.TTL, cfg.Database.SlowQueryThreshold)
+    }
+    if cfg.Tracing.SampleRatio != 0.25 || cfg.Tracing.Exporter != TracingNone {
+        t.Errorf("tracing = %+v", cfg.Tracing)
+    }
+    if cfg.RequestTimeout("stats") != 45*time.Second || cfg.RequestTimeout("auth") != 3*time.Second || cfg.RequestTimeout("alumni") != 10*time.Second {
+        t.Errorf("timeouts = %v", cfg.Timeouts)
+    }
+    if cfg.RateLimitPolicy("auth") != "5/1m key=ip" || cfg.RateLimitPolicy("alumni") == "" {
+        t.Errorf("rate limits = %v", cfg.RateLimit.Policies)
+    }
+    if cfg.Cache.Size != 50 || cfg.Cache.TTL != 5*time.Minute {
+        t.Errorf("cache = %+v", cfg.Cache)
+    }
+    if cfg.BodyLimit("pekerjaan") != 64*KB || cfg.BodyLimit("auth") != 16*KB || cfg.BodyLimit("alumni") != 2*MB || cfg.BodyLimits.Max() != 2*MB {
+        t.Errorf("body limits = %+v", cfg.BodyLimits)
+    }
+}
+
+func TestValidate(t *testing.T) {
+    cfg := Defaults()
+    cfg.Database.MongoURI = "mongodb://localhost:27017"
+    cfg.Database.MongoDatabase = "alumni"
+    cfg.JWT.Secret = "short"
+
+    err := cfg.Validate()
+    if err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+        t.Fatalf("short secret: err = %v", err)
+    }
+    if err := cfg.ValidateDatabase(); err != nil {
+        t.Fatalf("database only: %v", err)
+    }
+
+    cfg.JWT.Secret = strings.Repeat("x", MinJWTSecretLength)
+    if err := cfg.Validate(); err != nil {
+        t.Fatalf("valid config: %v", err)
+    }
+
+    cfg.RateLimit.Policies["auth"] = "10 per minute"
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_AUTH") {
+        t.Fatalf("invalid rate limit: err = %v", err)
+    }
+    cfg.RateLimit.Policies["auth"] = "10/1m"
+
+    cfg.CORS.AllowOrigins = "*"
+    cfg.CORS.AllowCredentials = true
+    cfg.TLS.CertFile = "server.crt"
+    err = cfg.Validate()
+    if err == nil || !strings.Contains(err.Error(), "CORS_ALLOW_CREDENTIALS") || !strings.Contains(err.Error(), "TLS_KEY_FILE") {
+        t.Fatalf("credentials with any origin, cert without key: err = %v", err)
+    }
+    cfg.CORS = Defaults().CORS
+    cfg.TLS = Defaults().TLS
+
+    cfg.Pekerjaan.OverlapPolicy = "strict"
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "PEKERJAAN_OVERLAP_POLICY") {
+        t.Fatalf("unknown overlap policy: err = %v", err)
+    }
+    cfg.Pekerjaan = Defaults().Pekerjaan
+
+    cfg.Timeouts.ShutdownGrace = -time.Second
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "SHUTDOWN_GRACE_PERIOD") {
+        t.Fatalf("negative grace period: err = %v", err)
+    }
+    cfg.Timeouts = Defaults().Timeouts
+
+    cfg.Timeouts.SchemaCheck = 0
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "SCHEMA_CHECK_TIMEOUT") {
+        t.Fatalf("no schema check timeout: err = %v", err)
+    }
+    cfg.Timeouts = Defaults().Timeouts
+
+    cfg.Proxy.Header = "X-Real-IP"
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "TRUSTED_PROXIES") {
+        t.Fatalf("proxy header without trusted proxies: err = %v", err)
+    }
+    cfg.Proxy.TrustedProxies = "10.0.0.1, 172.16.0.0/12, proxy.local"
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), `"proxy.local"`) {
+        t.Fatalf("invalid trusted proxy: err = %v", err)
+    }
+    cfg.Proxy.TrustedProxies = "10.0.0.1, 172.16.0.0/12"
+    if err := cfg.Validate(); err != nil {
+        t.Fatalf("trusted proxies: %v", err)
+    }
+    cfg.Proxy = Defaults().Proxy
+
+    cfg.Pekerjaan.TrashRetention = -time.Hour
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "PEKERJAAN_TRASH_RETENTION") {
+        t.Fatalf("negative retention: err = %v", err)
+    }
+    cfg.Pekerjaan = Defaults().Pekerjaan
+    cfg.Stats.MinGroupSize = 0
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "STATS_MIN_GROUP_SIZE") {
+        t.Fatalf("zero min group size: err = %v", err)
+    }
+    cfg.Stats = Defaults().Stats
+    cfg.Reports.Workers = 0
+    if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "REPORT_WORKERS") {
+        t.Fatalf("no report workers: err = %v", err)
+    }
+    cfg.Reports = Defaults().Reports
+
+    cfg.Database.Driver = "mysql"
+    if err := cfg.Validate(); err == nil {
+        t.Fatal("unknown driver should fail")
+    }
+}
+
+func TestRedacted(t *testing.T) {
+    cfg := Defaults()
+    cfg.JWT.Secret = "super-secret-value"
+    cfg.Database.MongoURI = "mongodb://app:hunter2@db:27017/?authSource=admin"
+
+    out, err := cfg.Redacted().YAML()
+    if err != nil {
+        t.Fatal(err)
+    }
+    text := string(out)
+    if strings.Contains(text, "super-secret-value") || strings.Contains(text, "hunter2") {
+        t.Fatalf("secrets leaked:\n%s", text)
+    }
+    if !strings.Contains(text, "mongodb://app:xxxxx@db:27017/?authSource=admin") {
+        t.Fatalf("connection string not kept:\n%s", text)
+    }
+    if cfg.JWT.Secret != "super-secret-value" {
+        t.Fatal("Redacted modified the original")
+    }
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+    cfg := Defaults()
+    cfg.BodyLimits.Routes["pekerjaan"] = 64 * KB
+    out, err := cfg.YAML()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(string(out), "pekerjaan: 64KB") {
+        t.Errorf("sizes should print with units:\n%s", out)
+    }
+
+    file := filepath.Join(t.TempDir(), "config.yaml")
+    os.WriteFile(file, out, 0o600)
+    loaded, err := Load("", file)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if loaded.BodyLimit("pekerjaan") != 64*KB || loaded.BodyLimits.Default != MB {
+        t.Errorf("body limits = %+v", loaded.BodyLimits)
+    }
+}
+
+func TestExampleConfig(t *testing.T) {
+    if Defaults().Pekerjaan.TrashRetention != 0 {
+        t.Error("trash purging should be off without configuration")
+    }
+
+    cfg, err := Load("", "../config.example.yaml")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if cfg.Pekerjaan.TrashRetention != 30*24*time.Hour || cfg.Pekerjaan.PurgeInterval != time.Hour {
+        t.Errorf("pekerjaan = %+v", cfg.Pekerjaan)
+    }
+    if cfg.CORS.AllowOrigins == "" || !cfg.Cache.Enabled {
+        t.Errorf("cors = %+v, cache = %+v", cfg.CORS, cfg.Cache)
+    }
+    if cfg.Proxy.Header == "" || len(cfg.Proxy.TrustedProxyList()) != 2 {
+        t.Errorf("proxy = %+v", cfg.Proxy)
+    }
+}
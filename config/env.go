@@ -1,12 +0,0 @@
-package config
-
-import (
-    "log"
-    "github.com/joho/godotenv"
-)
-
-func LoadEnv() {
-    if err := godotenv.Load(); err != nil {
-        log.Fatal("Error loading .env file")
-    }
-}
@@ -0,0 +1,73 @@
+package config
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// ByteSize is a size in bytes, written like 512KB, 4MB or 1048576. Units are
+// powers of 1024.
+type ByteSize int64
+
+const (
+    B  ByteSize = 1
+    KB          = 1024 * B
+    MB          = 1024 * KB
+    GB          = 1024 * MB
+)
+
+var byteUnits = []struct {
+    suffix string
+    size   ByteSize
+}{{"GB", GB}, {"MB", MB}, {"KB", KB}, {"B", B}}
+
+// ParseByteSize reads a size written with an optional B, KB, MB or GB suffix
+func ParseByteSize(s string) (ByteSize, error) {
+    text := strings.ToUpper(strings.TrimSpace(s))
+    unit := B
+    for _, u := range byteUnits {
+        if number, ok := strings.CutSuffix(text, u.suffix); ok {
+            text, unit = strings.TrimSpace(number), u.size
+            break
+        }
+    }
+
+    n, err := strconv.ParseInt(text, 10, 64)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("invalid size %q, expected e.g. 512KB or 4MB", s)
+    }
+    return ByteSize(n) * unit, nil
+}
+
+// String writes the size in the largest unit that divides it
+func (b ByteSize) String() string {
+    for _, u := range byteUnits {
+        if b != 0 && b%u.size == 0 {
+            return strconv.FormatInt(int64(b/u.size), 10) + u.suffix
+        }
+    }
+    return "0B"
+}
+
+func (b ByteSize) MarshalText() ([]byte, error) {
+    return []byte(b.String()), nil
+}
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+    size, err := ParseByteSize(string(text))
+    if err != nil {
+        return err
+    }
+    *b = size
+    return nil
+}
+
+// Max is the largest body any route accepts, the limit of the server itself
+func (l BodyLimitConfig) Max() ByteSize {
+    largest := l.Default
+    for _, size := range l.Routes {
+        largest = max(largest, size)
+    }
+    return largest
+}
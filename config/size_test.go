@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+    tests := []struct {
+        in   string
+        want ByteSize
+        err  bool
+    }{
+        {"1048576", 1048576, false},
+        {"512B", 512, false},
+        {"16KB", 16 * KB, false},
+        {"4mb", 4 * MB, false},
+        {" 1 GB ", GB, false},
+        {"", 0, true},
+        {"MB", 0, true},
+        {"1.5MB", 0, true},
+        {"-1KB", 0, true},
+        {"4 megabytes", 0, true},
+    }
+    for _, tt := range tests {
+        got, err := ParseByteSize(tt.in)
+        if (err != nil) != tt.err || got != tt.want {
+            t.Errorf("ParseByteSize(%q) = %d, %v", tt.in, got, err)
+        }
+    }
+}
+
+func TestByteSizeString(t *testing.T) {
+    for size, want := range map[ByteSize]string{0: "0B", 100: "100B", 16 * KB: "16KB", 1536: "1536B", 4 * MB: "4MB", 2 * GB: "2GB"} {
+        if got := size.String(); got != want {
+            t.Errorf("%d.String() = %q, want %q", int64(size), got, want)
+        }
+    }
+}
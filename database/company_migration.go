@@ -0,0 +1,24 @@
+package database
+
+import (
+    "context"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// dropCompanies unlinks every pekerjaan, restores the pekerjaan validator of
+// structure_gaji_range and drops the registry
+func dropCompanies(ctx context.Context, db *mongo.Database) error {
+    coll := db.Collection(PekerjaanCollection)
+    if _, err := coll.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"company_id": ""}}); err != nil {
+        return err
+    }
+    if err := dropIndex(ctx, coll, "idx_company_id"); err != nil {
+        return err
+    }
+    if err := setValidator(ctx, db, PekerjaanCollection, pekerjaanValidatorV2); err != nil {
+        return err
+    }
+    return dropCollection(CompaniesCollection)(ctx, db)
+}
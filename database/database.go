@@ -3,9 +3,12 @@ package database
 import (
     "context"
     "log"
-    "os"
     "time"
-    
+
+    "go-fiber/config"
+    "go-fiber/metrics"
+    "go-fiber/tracing"
+
     "go.mongodb.org/mongo-driver/mongo"
     "go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -16,10 +19,10 @@ func ConnectDB() *mongo.Database {
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
 
-    mongoURI := os.Getenv("MONGODB_URI")
-    dbName := os.Getenv("MONGODB_DATABASE")
+    mongoURI := config.Get().Database.MongoURI
+    dbName := config.Get().Database.MongoDatabase
     
-    client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+    client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).SetPoolMonitor(metrics.MongoPoolMonitor()).SetMonitor(tracing.MongoMonitor()))
     if err != nil {
         log.Fatal("Failed to connect to MongoDB:", err)
     }
@@ -0,0 +1,226 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "log"
+
+    "go-fiber/app/model"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// structureGaji parses a legacy gaji_range. ok is false when the text has to
+// be reviewed by hand; it is then kept as it is.
+func structureGaji(text string) (model.Gaji, bool) {
+    g, err := model.ParseGajiRange(text)
+    return g, err == nil
+}
+
+// legacyGajiText is the gaji_range a rollback restores: the original text
+// while the salary is still what it was parsed into, otherwise the salary
+// rendered as text
+func legacyGajiText(legacy string, g model.Gaji) string {
+    if parsed, ok := structureGaji(legacy); ok && parsed.Range() == g.Range() {
+        return legacy
+    }
+    return g.Range()
+}
+
+// structureGajiRange converts the gaji_range strings written before gaji_min
+// and gaji_max existed, keeping the original in gaji_range_legacy for the
+// rollback. Strings the parser does not understand stay in gaji_range and
+// get gaji_review set.
+//
+// MongoDB has no transaction here, so each document is converted by a single
+// update that only matches it while it is still unconverted. A run that
+// stopped part way can be run again: it picks up where it left off.
+func structureGajiRange(ctx context.Context, db *mongo.Database) error {
+    coll := db.Collection(PekerjaanCollection)
+
+    cursor, err := coll.Find(ctx, bson.M{
+        "gaji_range": bson.M{"$type": "string", "$ne": ""},
+        "gaji_min":   nil,
+        "gaji_max":   nil,
+    })
+    if err != nil {
+        return err
+    }
+
+    var docs []struct {
+        ID        primitive.ObjectID `bson:"_id"`
+        GajiRange string             `bson:"gaji_range"`
+    }
+    if err := cursor.All(ctx, &docs); err != nil {
+        return err
+    }
+
+    converted, flagged := 0, 0
+    for _, doc := range docs {
+        g, ok := structureGaji(doc.GajiRange)
+        update := bson.M{"$set": bson.M{"gaji_review": true}}
+        if ok {
+            update = bson.M{
+                "$set": bson.M{
+                    "gaji_min":          g.Min,
+                    "gaji_max":          g.Max,
+                    "gaji_currency":     g.Currency,
+                    "gaji_period":       g.Period,
+                    "gaji_range_legacy": doc.GajiRange,
+                },
+                "$unset": bson.M{"gaji_range": "", "gaji_review": ""},
+            }
+        }
+
+        // Skip documents edited since they were read
+        filter := bson.M{"_id": doc.ID, "gaji_range": doc.GajiRange, "gaji_min": nil, "gaji_max": nil}
+        result, err := coll.UpdateOne(ctx, filter, update)
+        if err != nil {
+            return err
+        }
+        switch {
+        case result.MatchedCount == 0:
+        case ok:
+            converted++
+        default:
+            log.Printf("  ⚠️  Pekerjaan %s: gaji_range %q needs review", doc.ID.Hex(), doc.GajiRange)
+            flagged++
+        }
+    }
+
+    log.Printf("  ✓ gaji_range structured (%d converted, %d flagged for review)", converted, flagged)
+    return nil
+}
+
+// unstructureGajiRange restores gaji_range from gaji_range_legacy, or
+// renders it from the structured fields, and restores the validator of
+// create_pekerjaan_collection
+func unstructureGajiRange(ctx context.Context, db *mongo.Database) error {
+    coll := db.Collection(PekerjaanCollection)
+    cursor, err := coll.Find(ctx, bson.M{"$or": []bson.M{
+        {"gaji_min": bson.M{"$ne": nil}},
+        {"gaji_max": bson.M{"$ne": nil}},
+    }})
+    if err != nil {
+        return err
+    }
+
+    var list []struct {
+        model.Pekerjaan `bson:",inline"`
+        GajiRangeLegacy string `bson:"gaji_range_legacy"`
+    }
+    if err := cursor.All(ctx, &list); err != nil {
+        return err
+    }
+    for _, p := range list {
+        text := legacyGajiText(p.GajiRangeLegacy, p.Gaji())
+        if _, err := coll.UpdateByID(ctx, p.ID, bson.M{"$set": bson.M{"gaji_range": text}}); err != nil {
+            return err
+        }
+    }
+
+    _, err = coll.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{
+        "gaji_min": "", "gaji_max": "", "gaji_currency": "", "gaji_period": "", "gaji_review": "", "gaji_range_legacy": "",
+    }})
+    if err != nil {
+        return err
+    }
+    if err := setValidator(ctx, db, PekerjaanCollection, pekerjaanValidatorV1); err != nil {
+        return err
+    }
+    return dropIndex(ctx, coll, "idx_gaji")
+}
+
+// structureGajiRangeRows is structureGajiRange for PostgreSQL, run after the
+// columns are added. It adds gaji_range_legacy itself, as the statement of
+// the migration is checksummed; the transaction makes it all or nothing.
+func structureGajiRangeRows(ctx context.Context, tx *sql.Tx) error {
+    if _, err := tx.ExecContext(ctx, `ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_range_legacy TEXT`); err != nil {
+        return err
+    }
+
+    rows, err := tx.QueryContext(ctx, `
+        SELECT id, gaji_range FROM pekerjaan_alumni
+        WHERE gaji_range <> '' AND gaji_min IS NULL AND gaji_max IS NULL`)
+    if err != nil {
+        return err
+    }
+    texts := map[string]string{}
+    for rows.Next() {
+        var id, text string
+        if err := rows.Scan(&id, &text); err != nil {
+            rows.Close()
+            return err
+        }
+        texts[id] = text
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return err
+    }
+
+    converted, flagged := 0, 0
+    for id, text := range texts {
+        g, ok := structureGaji(text)
+        if !ok {
+            log.Printf("  ⚠️  Pekerjaan %s: gaji_range %q needs review", id, text)
+            flagged++
+            if _, err := tx.ExecContext(ctx, `UPDATE pekerjaan_alumni SET gaji_review = true WHERE id = $1`, id); err != nil {
+                return err
+            }
+            continue
+        }
+        _, err := tx.ExecContext(ctx, `
+            UPDATE pekerjaan_alumni
+            SET gaji_min = $2, gaji_max = $3, gaji_currency = $4, gaji_period = $5, gaji_range_legacy = gaji_range, gaji_range = NULL
+            WHERE id = $1`, id, g.Min, g.Max, g.Currency, g.Period)
+        if err != nil {
+            return err
+        }
+        converted++
+    }
+
+    log.Printf("  ✓ gaji_range structured (%d converted, %d flagged for review)", converted, flagged)
+    return nil
+}
+
+// unstructureGajiRangeRows restores gaji_range like unstructureGajiRange
+// before the columns are dropped. Databases migrated before
+// gaji_range_legacy existed get the column empty.
+func unstructureGajiRangeRows(ctx context.Context, tx *sql.Tx) error {
+    if _, err := tx.ExecContext(ctx, `ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_range_legacy TEXT`); err != nil {
+        return err
+    }
+
+    rows, err := tx.QueryContext(ctx, `
+        SELECT id, gaji_min, gaji_max, COALESCE(gaji_currency, 'IDR'), COALESCE(gaji_period, 'monthly'), COALESCE(gaji_range_legacy, '')
+        FROM pekerjaan_alumni
+        WHERE gaji_min IS NOT NULL OR gaji_max IS NOT NULL`)
+    if err != nil {
+        return err
+    }
+    texts := map[string]string{}
+    for rows.Next() {
+        var id, legacy string
+        var g model.Gaji
+        if err := rows.Scan(&id, &g.Min, &g.Max, &g.Currency, &g.Period, &legacy); err != nil {
+            rows.Close()
+            return err
+        }
+        texts[id] = legacyGajiText(legacy, g)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return err
+    }
+
+    for id, text := range texts {
+        if _, err := tx.ExecContext(ctx, `UPDATE pekerjaan_alumni SET gaji_range = $2 WHERE id = $1`, id, text); err != nil {
+            return err
+        }
+    }
+    _, err = tx.ExecContext(ctx, `ALTER TABLE pekerjaan_alumni DROP COLUMN gaji_range_legacy`)
+    return err
+}
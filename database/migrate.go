@@ -0,0 +1,273 @@
+package database
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Migration states reported by Migrator.Status
+const (
+    MigrationApplied  = "applied"
+    MigrationPending  = "pending"
+    MigrationModified = "modified" // applied, but the definition changed since
+    MigrationUnknown  = "unknown"  // recorded in the database, missing from the code
+)
+
+// migrationLockTTL bounds how long a crashed run can keep others out
+const migrationLockTTL = 10 * time.Minute
+
+var (
+    ErrMigrationLocked   = errors.New("another migration run holds the lock")
+    ErrMigrationChecksum = errors.New("applied migrations were modified")
+)
+
+// MigrationDef is one numbered migration known to the code
+type MigrationDef struct {
+    Version  int
+    Name     string
+    Checksum string
+}
+
+// MigrationRecord is one applied migration as stored in the database
+type MigrationRecord struct {
+    Version   int
+    Name      string
+    Checksum  string
+    AppliedAt time.Time
+}
+
+// MigrationStatus pairs a migration with its state
+type MigrationStatus struct {
+    Version   int
+    Name      string
+    State     string
+    AppliedAt *time.Time
+}
+
+// migrationSource is a storage backend the Migrator drives. Up and Down run
+// a single migration and add or remove its record.
+type migrationSource interface {
+    Definitions() []MigrationDef
+    Lock(ctx context.Context) (unlock func(), err error)
+    Applied(ctx context.Context) ([]MigrationRecord, error)
+    Up(ctx context.Context, version int) error
+    Down(ctx context.Context, version int) error
+}
+
+// Migrator applies and rolls back numbered migrations in version order
+type Migrator struct {
+    source migrationSource
+}
+
+// Status lists every known migration followed by unknown recorded ones
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+    applied, err := m.source.Applied(ctx)
+    if err != nil {
+        return nil, err
+    }
+    return buildStatus(m.source.Definitions(), applied), nil
+}
+
+// Ready returns an error while any migration is not applied as defined, so
+// the readiness probe fails until the database matches the code
+func (m *Migrator) Ready(ctx context.Context) error {
+    statuses, err := m.Status(ctx)
+    if err != nil {
+        return err
+    }
+
+    counts := map[string]int{}
+    for _, status := range statuses {
+        if status.State != MigrationApplied {
+            counts[status.State]++
+        }
+    }
+
+    var problems []string
+    for _, state := range []string{MigrationPending, MigrationModified, MigrationUnknown} {
+        if counts[state] > 0 {
+            problems = append(problems, fmt.Sprintf("%d %s", counts[state], state))
+        }
+    }
+    if len(problems) > 0 {
+        return fmt.Errorf("migrations not up to date: %s", strings.Join(problems, ", "))
+    }
+    return nil
+}
+
+// Up applies up to n pending migrations, all of them when n <= 0
+func (m *Migrator) Up(ctx context.Context, n int) error {
+    return m.locked(ctx, func(statuses []MigrationStatus) error {
+        count := 0
+        for _, status := range statuses {
+            if status.State != MigrationPending {
+                continue
+            }
+            if n > 0 && count == n {
+                break
+            }
+
+            log.Printf("▶️  Applying migration %03d_%s", status.Version, status.Name)
+            if err := m.source.Up(ctx, status.Version); err != nil {
+                log.Printf("❌ Migration %03d_%s failed: %v", status.Version, status.Name, err)
+                return err
+            }
+            log.Printf("✅ Migration %03d_%s applied", status.Version, status.Name)
+            count++
+        }
+
+        if count == 0 {
+            log.Println("⏭️  No pending migrations")
+        }
+        return nil
+    })
+}
+
+// Down rolls back up to n applied migrations, newest first, all of them when
+// n <= 0
+func (m *Migrator) Down(ctx context.Context, n int) error {
+    return m.locked(ctx, func(statuses []MigrationStatus) error {
+        return m.down(ctx, statuses, n)
+    })
+}
+
+// Redo rolls back the newest applied migration and applies it again
+func (m *Migrator) Redo(ctx context.Context) error {
+    return m.locked(ctx, func(statuses []MigrationStatus) error {
+        last := lastApplied(statuses, 1)
+        if len(last) == 0 {
+            log.Println("⏭️  No applied migrations")
+            return nil
+        }
+
+        if err := m.down(ctx, statuses, 1); err != nil {
+            return err
+        }
+
+        log.Printf("▶️  Applying migration %03d_%s", last[0].Version, last[0].Name)
+        if err := m.source.Up(ctx, last[0].Version); err != nil {
+            return err
+        }
+        log.Printf("✅ Migration %03d_%s applied", last[0].Version, last[0].Name)
+        return nil
+    })
+}
+
+func (m *Migrator) down(ctx context.Context, statuses []MigrationStatus, n int) error {
+    targets := lastApplied(statuses, n)
+    if len(targets) == 0 {
+        log.Println("⏭️  No applied migrations")
+        return nil
+    }
+
+    for _, status := range targets {
+        log.Printf("◀️  Rolling back migration %03d_%s", status.Version, status.Name)
+        if err := m.source.Down(ctx, status.Version); err != nil {
+            log.Printf("❌ Rollback of %03d_%s failed: %v", status.Version, status.Name, err)
+            return err
+        }
+        log.Printf("✅ Migration %03d_%s rolled back", status.Version, status.Name)
+    }
+    return nil
+}
+
+// locked takes the migration lock and refuses to run when an applied
+// migration was edited or the database holds migrations the code lacks
+func (m *Migrator) locked(ctx context.Context, fn func([]MigrationStatus) error) error {
+    unlock, err := m.source.Lock(ctx)
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    statuses, err := m.Status(ctx)
+    if err != nil {
+        return err
+    }
+
+    var bad []string
+    for _, status := range statuses {
+        if status.State == MigrationModified || status.State == MigrationUnknown {
+            bad = append(bad, fmt.Sprintf("%03d_%s (%s)", status.Version, status.Name, status.State))
+        }
+    }
+    if len(bad) > 0 {
+        return fmt.Errorf("%w: %s", ErrMigrationChecksum, strings.Join(bad, ", "))
+    }
+
+    return fn(statuses)
+}
+
+func buildStatus(defs []MigrationDef, applied []MigrationRecord) []MigrationStatus {
+    records := map[int]MigrationRecord{}
+    for _, record := range applied {
+        records[record.Version] = record
+    }
+
+    statuses := make([]MigrationStatus, 0, len(defs))
+    known := map[int]bool{}
+    for _, def := range defs {
+        known[def.Version] = true
+        status := MigrationStatus{Version: def.Version, Name: def.Name, State: MigrationPending}
+
+        if record, ok := records[def.Version]; ok {
+            appliedAt := record.AppliedAt
+            status.AppliedAt = &appliedAt
+            status.State = MigrationApplied
+            if record.Checksum != def.Checksum {
+                status.State = MigrationModified
+            }
+        }
+        statuses = append(statuses, status)
+    }
+
+    for _, record := range applied {
+        if known[record.Version] {
+            continue
+        }
+        appliedAt := record.AppliedAt
+        statuses = append(statuses, MigrationStatus{
+            Version:   record.Version,
+            Name:      record.Name,
+            State:     MigrationUnknown,
+            AppliedAt: &appliedAt,
+        })
+    }
+
+    sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+    return statuses
+}
+
+// lastApplied returns up to n applied migrations, newest first
+func lastApplied(statuses []MigrationStatus, n int) []MigrationStatus {
+    var result []MigrationStatus
+    for i := len(statuses) - 1; i >= 0; i-- {
+        if n > 0 && len(result) == n {
+            break
+        }
+        if statuses[i].State == MigrationApplied {
+            result = append(result, statuses[i])
+        }
+    }
+    return result
+}
+
+// checksum fingerprints a migration definition
+func checksum(parts ...string) string {
+    sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+    return hex.EncodeToString(sum[:])
+}
+
+// lockOwner identifies this process in lock documents and log lines
+func lockOwner() string {
+    host, _ := os.Hostname()
+    return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
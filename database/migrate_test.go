@@ -0,0 +1,267 @@
+package database
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+)
+
+// fakeSource records the order migrations run in
+type fakeSource struct {
+    defs    []MigrationDef
+    applied map[int]MigrationRecord
+    calls   []string
+    locked  bool
+}
+
+func newFakeSource(n int) *fakeSource {
+    s := &fakeSource{applied: map[int]MigrationRecord{}}
+    for v := 1; v <= n; v++ {
+        s.defs = append(s.defs, MigrationDef{Version: v, Name: "m", Checksum: checksum("m", string(rune('0'+v)))})
+    }
+    return s
+}
+
+func (s *fakeSource) Definitions() []MigrationDef { return s.defs }
+
+func (s *fakeSource) Lock(ctx context.Context) (func(), error) {
+    if s.locked {
+        return nil, ErrMigrationLocked
+    }
+    s.locked = true
+    return func() { s.locked = false }, nil
+}
+
+func (s *fakeSource) Applied(ctx context.Context) ([]MigrationRecord, error) {
+    var records []MigrationRecord
+    for _, record := range s.applied {
+        records = append(records, record)
+    }
+    return records, nil
+}
+
+func (s *fakeSource) Up(ctx context.Context, version int) error {
+    s.calls = append(s.calls, "up"+string(rune('0'+version)))
+    s.applied[version] = MigrationRecord{Version: version, Name: "m", Checksum: s.defs[version-1].Checksum, AppliedAt: time.Now()}
+    return nil
+}
+
+func (s *fakeSource) Down(ctx context.Context, version int) error {
+    s.calls = append(s.calls, "down"+string(rune('0'+version)))
+    delete(s.applied, version)
+    return nil
+}
+
+func states(t *testing.T, m *Migrator) []string {
+    t.Helper()
+    statuses, err := m.Status(context.Background())
+    if err != nil {
+        t.Fatal(err)
+    }
+    var result []string
+    for _, status := range statuses {
+        result = append(result, status.State)
+    }
+    return result
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+    ctx := context.Background()
+    source := newFakeSource(4)
+    m := &Migrator{source: source}
+
+    if err := m.Up(ctx, 2); err != nil {
+        t.Fatal(err)
+    }
+    if err := m.Up(ctx, 0); err != nil {
+        t.Fatal(err)
+    }
+    if err := m.Down(ctx, 3); err != nil {
+        t.Fatal(err)
+    }
+    if err := m.Redo(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    want := []string{"up1", "up2", "up3", "up4", "down4", "down3", "down2", "down1", "up1"}
+    if !reflect.DeepEqual(source.calls, want) {
+        t.Fatalf("calls = %v, want %v", source.calls, want)
+    }
+
+    got := states(t, m)
+    wantStates := []string{MigrationApplied, MigrationPending, MigrationPending, MigrationPending}
+    if !reflect.DeepEqual(got, wantStates) {
+        t.Fatalf("states = %v, want %v", got, wantStates)
+    }
+    if source.locked {
+        t.Fatal("lock was not released")
+    }
+}
+
+func TestMigratorRefusesModifiedMigrations(t *testing.T) {
+    ctx := context.Background()
+    source := newFakeSource(2)
+    m := &Migrator{source: source}
+
+    if err := m.Up(ctx, 1); err != nil {
+        t.Fatal(err)
+    }
+    source.defs[0].Checksum = "edited"
+    source.applied[9] = MigrationRecord{Version: 9, Name: "removed"}
+
+    got := states(t, m)
+    want := []string{MigrationModified, MigrationPending, MigrationUnknown}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("states = %v, want %v", got, want)
+    }
+
+    if err := m.Up(ctx, 0); !errors.Is(err, ErrMigrationChecksum) {
+        t.Fatalf("err = %v, want %v", err, ErrMigrationChecksum)
+    }
+    if len(source.calls) != 1 {
+        t.Fatalf("calls = %v, want only the first up", source.calls)
+    }
+}
+
+func TestMigratorLocked(t *testing.T) {
+    source := newFakeSource(1)
+    source.locked = true
+
+    err := (&Migrator{source: source}).Up(context.Background(), 0)
+    if !errors.Is(err, ErrMigrationLocked) {
+        t.Fatalf("err = %v, want %v", err, ErrMigrationLocked)
+    }
+    if len(source.calls) != 0 {
+        t.Fatalf("calls = %v, want none", source.calls)
+    }
+}
+
+func TestMigratorReady(t *testing.T) {
+    ctx := context.Background()
+    source := newFakeSource(3)
+    m := &Migrator{source: source}
+
+    if err := m.Ready(ctx); err == nil || err.Error() != "migrations not up to date: 3 pending" {
+        t.Fatalf("err = %v", err)
+    }
+
+    if err := m.Up(ctx, 0); err != nil {
+        t.Fatal(err)
+    }
+    if err := m.Ready(ctx); err != nil {
+        t.Fatalf("all applied: err = %v", err)
+    }
+
+    source.applied[9] = MigrationRecord{Version: 9, Name: "gone"}
+    if err := m.Ready(ctx); err == nil || err.Error() != "migrations not up to date: 1 unknown" {
+        t.Fatalf("err = %v", err)
+    }
+}
+
+func TestMigrationDefinitions(t *testing.T) {
+    for name, defs := range map[string][]MigrationDef{
+        "mongo":    (&mongoMigrationSource{}).Definitions(),
+        "postgres": (&postgresMigrationSource{}).Definitions(),
+    } {
+        for i, def := range defs {
+            if def.Version != i+1 {
+                t.Errorf("%s: migration %s has version %d, want %d", name, def.Name, def.Version, i+1)
+            }
+            if def.Checksum == "" {
+                t.Errorf("%s: migration %s has no checksum", name, def.Name)
+            }
+        }
+    }
+}
+
+// TestMongoMigrationChecksums pins the released migrations. A failure means
+// an applied migration changed: put the change in a new migration instead.
+func TestMongoMigrationChecksums(t *testing.T) {
+    want := []struct {
+        checksum, legacy string
+    }{
+        {"737611cfac7f79047b4b2a781b1ffcf2e3972c4711d01d4ee6627b0d04d558bd", "a8867cba9383f9b38f63f2b404d8def39bcf1e51f61e63561389cc30bcf2c148"},
+        {"a23d4ef76f4e6769049ddee69d633ba4fcd2d5cf6eb543ca452fa4a83d75556b", "b02b43fe81484cc0ae9f80a1b79aaa2786ae50a300876c7534baa9cc365c11cd"},
+        {"c18f3372cfad5510c5235cb438206c8a033bcef676c8f44f9cd8cc7dad7f4aa5", "ca882d0ff33629b6fe34bdc3aa629f1e6b913e3daf85936e3f04971c2d93d7ff"},
+        {"8f7ae125a86a5d6bba82ba5c8a167fbcd853fef18d9963d01710e164d9a30d5d", "d603689214a56228385ad1b30351fc928059de6e03537ac9c173df1e2f33c140"},
+        {"af2a8aae3acc61fc115fdd0849aeb9b209d7b2f43af3a6c8c0dbd858e757c6af", "d62fe72c0cf0ac507e593c9339f09be9fe0c6cb7fb205ec2fea6cdcbd8b20c3b"},
+        {"6c59461adcb894630591f478ae4c6f4e86c373b6109a438b2609b778357b9fc5", "911e4da840911cb3a6914c5c076ec220f7e36a4e2f18c9409500957b79e8a24e"},
+        {"917bc60edac411a3e95772dc2a84638814ecd4484a1a9578bca8cd9aed0c0c75", "eeda73694b77b40d094b8065ad572b517604a9bcbe6998c8eb217e91b5880626"},
+        {"dbf863ca1aafa9b22e7ad0c514982f5abc6a07c86172e33997ea943e477afd90", "475e5a748643530addffef50154a254324c7e37ae85e00478567bf6c5590b188"},
+        {"dad06aa7df860212695bcb84d4210feee3e367dea8579d39688ad399bdd40210", "c800cd88eb0b75251d76bed27208b97060e24fbc1a50a7f88d424d99a2f05138"},
+        {"9da810648180c734beffa72e64045c15c33c594b2a1cb7da7412d3391a2dd64f", "17f925ae8b2fdbf9fae07ac22e683acb8c213bf819e10bc5f0aa3ed5bebe742a"},
+    }
+
+    for i, w := range want {
+        m := mongoMigrations[i]
+        if got := m.checksum(); got != w.checksum {
+            t.Errorf("%03d_%s: checksum = %s, want %s", m.version, m.name, got, w.checksum)
+        }
+        if got := m.legacyChecksum(); got != w.legacy {
+            t.Errorf("%03d_%s: legacy checksum = %s, want %s", m.version, m.name, got, w.legacy)
+        }
+    }
+}
+
+// TestMigrationsBuildCollections applies the migration schemas in order and
+// checks that an empty database ends up with Collections, so a fresh install
+// has no drift
+func TestMigrationsBuildCollections(t *testing.T) {
+    built := map[string]*CollectionSchema{}
+    for _, m := range mongoMigrations {
+        for _, schema := range m.schema {
+            b, ok := built[schema.Name]
+            if !ok {
+                b = &CollectionSchema{Name: schema.Name}
+                built[schema.Name] = b
+            }
+            if schema.Validator != nil {
+                b.Validator = schema.Validator
+            }
+            b.Indexes = append(b.Indexes, schema.Indexes...)
+        }
+    }
+
+    if len(built) != len(Collections) {
+        t.Errorf("migrations build %d collections, Collections declares %d", len(built), len(Collections))
+    }
+    for _, want := range Collections {
+        got, ok := built[want.Name]
+        if !ok {
+            t.Errorf("%s: no migration creates it", want.Name)
+            continue
+        }
+        if !sameDocument(got.Validator, want.Validator) {
+            t.Errorf("%s: validator differs from Collections", want.Name)
+        }
+        if !reflect.DeepEqual(indexesByName(*got), indexesByName(want)) {
+            t.Errorf("%s: indexes = %v, want %v", want.Name, indexesByName(*got), indexesByName(want))
+        }
+    }
+}
+
+func indexesByName(schema CollectionSchema) map[string]indexSpec {
+    indexes := map[string]indexSpec{}
+    for _, index := range schemaSpecs([]CollectionSchema{schema})[0].Indexes {
+        indexes[index.Name] = index
+    }
+    return indexes
+}
+
+func TestLegacyGajiText(t *testing.T) {
+    g, _ := structureGaji("Rp 8.000.000 - 12.000.000")
+    if got := legacyGajiText("Rp 8.000.000 - 12.000.000", g); got != "Rp 8.000.000 - 12.000.000" {
+        t.Errorf("unchanged salary = %q, want the original text", got)
+    }
+
+    edited := int64(15_000_000)
+    g.Max = &edited
+    if got := legacyGajiText("Rp 8.000.000 - 12.000.000", g); got != g.Range() {
+        t.Errorf("edited salary = %q, want %q", got, g.Range())
+    }
+    if got := legacyGajiText("", g); got != g.Range() {
+        t.Errorf("without legacy text = %q, want %q", got, g.Range())
+    }
+}
@@ -2,7 +2,11 @@ package database
 
 import (
     "context"
+    "encoding/json"
+    "errors"
+    "fmt"
     "log"
+    "strconv"
     "time"
 
     "go.mongodb.org/mongo-driver/bson"
@@ -12,407 +16,362 @@ import (
 
 // Collection names
 const (
-    UsersCollection      = "users"
-    AlumniCollection     = "alumni"
-    PekerjaanCollection  = "pekerjaan_alumni"
-    MigrationsCollection = "migrations"
+    UsersCollection       = "users"
+    AlumniCollection      = "alumni"
+    PekerjaanCollection   = "pekerjaan_alumni"
+    MigrationsCollection  = "migrations"
+    RateLimitsCollection  = "rate_limits"
+    CacheCollection       = "cache"
+    CompaniesCollection   = "companies"
+    ReportsCollection     = "reports"
+    ReportFilesCollection = "report_files"
 )
 
-// Migration represents a database migration
-type Migration struct {
+// migrationsLockCollection holds the single lock document of a migration run
+const migrationsLockCollection = "migrations_lock"
+
+// migrationLockRenewal is how often a running migration extends its lock
+const migrationLockRenewal = migrationLockTTL / 4
+
+// mongoMigration is one numbered MongoDB migration. Before up runs, the
+// validators in schema are set and its indexes created. The checksum covers
+// schema and revision: bump revision when up or down change in a way the
+// schema does not show.
+type mongoMigration struct {
+    version  int
+    revision int
+    name     string
+    schema   []CollectionSchema
+    up       func(context.Context, *mongo.Database) error
+    down     func(context.Context, *mongo.Database) error
+}
+
+var mongoMigrations = []mongoMigration{
+    {1, 1, "create_users_collection", []CollectionSchema{{Name: UsersCollection, Validator: usersValidator}}, nil, dropCollection(UsersCollection)},
+    {2, 1, "create_alumni_collection", []CollectionSchema{{Name: AlumniCollection, Validator: alumniValidator}}, nil, dropCollection(AlumniCollection)},
+    {3, 1, "create_pekerjaan_collection", []CollectionSchema{{Name: PekerjaanCollection, Validator: pekerjaanValidatorV1}}, nil, dropCollection(PekerjaanCollection)},
+    {4, 1, "create_indexes", createIndexesV1, nil, dropIndexes(createIndexesV1)},
+    {5, 1, "create_rate_limits_collection", []CollectionSchema{{Name: RateLimitsCollection, Validator: rateLimitsValidator, Indexes: rateLimitsIndexes}}, nil, dropCollection(RateLimitsCollection)},
+    {6, 1, "create_cache_collection", []CollectionSchema{{Name: CacheCollection, Validator: cacheValidator, Indexes: cacheIndexes}}, nil, dropCollection(CacheCollection)},
+    {7, 1, "structure_gaji_range", structureGajiSchema, structureGajiRange, unstructureGajiRange},
+    {8, 1, "create_companies_collection", companiesSchema, nil, dropCompanies},
+    {9, 1, "add_pekerjaan_moderation", moderationSchema, addPekerjaanModeration, dropPekerjaanModeration},
+    {10, 1, "create_reports_collections", []CollectionSchema{
+        {Name: ReportsCollection, Validator: reportsValidator, Indexes: reportsIndexes},
+        {Name: ReportFilesCollection, Validator: reportFilesValidator, Indexes: reportFilesIndexes},
+    }, nil, dropReports},
+}
+
+// legacyMongoSpecs are the hand-written specs checksummed before checksums
+// covered the schema. Applied re-stamps records still carrying one.
+var legacyMongoSpecs = map[int]string{
+    1:  UsersCollection,
+    2:  AlumniCollection,
+    3:  PekerjaanCollection,
+    4:  "indexes",
+    5:  RateLimitsCollection,
+    6:  CacheCollection,
+    7:  "gaji_min gaji_max gaji_currency gaji_period",
+    8:  CompaniesCollection + " company_id",
+    9:  "status_moderasi catatan_moderasi moderated_by moderated_at",
+    10: ReportsCollection + " " + ReportFilesCollection,
+}
+
+// migrationDoc is an applied migration in the migrations collection. Records
+// written before versioning only carry name and applied_at.
+type migrationDoc struct {
+    Version   int       `bson:"version"`
     Name      string    `bson:"name"`
+    Checksum  string    `bson:"checksum"`
     AppliedAt time.Time `bson:"applied_at"`
 }
 
-// RunMigrations executes all database migrations
+// NewMongoMigrator returns the migrator for the MongoDB schema
+func NewMongoMigrator(db *mongo.Database) *Migrator {
+    return &Migrator{source: &mongoMigrationSource{db: db}}
+}
+
+// RunMigrations applies all pending MongoDB migrations
 func RunMigrations(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
     defer cancel()
 
     log.Println("🔄 Starting migrations...")
+    if err := NewMongoMigrator(db).Up(ctx, 0); err != nil {
+        return err
+    }
+    log.Println("✅ All migrations completed successfully!")
+    return nil
+}
 
-    // Check if migrations collection exists
-    migrationsColl := db.Collection(MigrationsCollection)
-
-    migrations := []struct {
-        name string
-        fn   func(*mongo.Database) error
-    }{
-        {"create_users_collection", createUsersCollection},
-        {"create_alumni_collection", createAlumniCollection},
-        {"create_pekerjaan_collection", createPekerjaanCollection},
-        {"create_indexes", createAllIndexes},
+type mongoMigrationSource struct {
+    db *mongo.Database
+}
+
+func (s *mongoMigrationSource) Definitions() []MigrationDef {
+    defs := make([]MigrationDef, 0, len(mongoMigrations))
+    for _, m := range mongoMigrations {
+        defs = append(defs, MigrationDef{Version: m.version, Name: m.name, Checksum: m.checksum()})
     }
+    return defs
+}
 
-    for _, migration := range migrations {
-        // Check if migration already applied
-        count, err := migrationsColl.CountDocuments(ctx, bson.M{"name": migration.name})
-        if err != nil {
-            return err
-        }
+func (m mongoMigration) checksum() string {
+    spec, err := json.Marshal(schemaSpecs(m.schema))
+    if err != nil {
+        // Schemas are package level literals, this only fails on a programming error
+        panic(fmt.Sprintf("migration %s: %v", m.name, err))
+    }
+    return checksum(m.name, strconv.Itoa(m.revision), string(spec))
+}
 
-        if count > 0 {
-            log.Printf("⏭️  Migration '%s' already applied, skipping...", migration.name)
-            continue
-        }
+// legacyChecksum is the checksum the migration had before it covered the
+// schema
+func (m mongoMigration) legacyChecksum() string {
+    spec, _ := json.Marshal(legacyMongoSpecs[m.version])
+    return checksum(m.name, string(spec))
+}
 
-        // Run migration
-        log.Printf("▶️  Running migration: %s", migration.name)
-        if err := migration.fn(db); err != nil {
-            log.Printf("❌ Migration '%s' failed: %v", migration.name, err)
-            return err
+// apply sets the validators and creates the indexes of the migration, then
+// runs its up step
+func (m mongoMigration) apply(ctx context.Context, db *mongo.Database) error {
+    for _, schema := range m.schema {
+        if schema.Validator != nil {
+            if err := createCollection(schema)(ctx, db); err != nil {
+                return err
+            }
         }
-
-        // Record migration
-        _, err = migrationsColl.InsertOne(ctx, Migration{
-            Name:      migration.name,
-            AppliedAt: time.Now(),
-        })
-        if err != nil {
-            return err
+        if len(schema.Indexes) > 0 {
+            if _, err := db.Collection(schema.Name).Indexes().CreateMany(ctx, schema.Indexes); err != nil {
+                return err
+            }
+            log.Printf("  ✓ %s indexes created", schema.Name)
         }
+    }
+    if m.up == nil {
+        return nil
+    }
+    return m.up(ctx, db)
+}
 
-        log.Printf("✅ Migration '%s' completed", migration.name)
+// Lock upserts the lock document. The filter only matches an expired lock,
+// so while another run holds a live one the upsert hits the unique _id.
+func (s *mongoMigrationSource) Lock(ctx context.Context) (func(), error) {
+    coll := s.db.Collection(migrationsLockCollection)
+    owner := lockOwner()
+    now := time.Now()
+
+    _, err := coll.UpdateOne(ctx,
+        bson.M{"_id": "migrate", "expires_at": bson.M{"$lt": now}},
+        bson.M{"$set": bson.M{"owner": owner, "locked_at": now, "expires_at": now.Add(migrationLockTTL)}},
+        options.Update().SetUpsert(true),
+    )
+    if mongo.IsDuplicateKeyError(err) {
+        var holder struct {
+            Owner    string    `bson:"owner"`
+            LockedAt time.Time `bson:"locked_at"`
+        }
+        coll.FindOne(ctx, bson.M{"_id": "migrate"}).Decode(&holder)
+        return nil, fmt.Errorf("%w: %s since %s", ErrMigrationLocked, holder.Owner, holder.LockedAt.Format(time.RFC3339))
+    }
+    if err != nil {
+        return nil, err
     }
 
-    log.Println("✅ All migrations completed successfully!")
-    return nil
+    stop, stopped := make(chan struct{}), make(chan struct{})
+    go func() {
+        defer close(stopped)
+        renewLock(coll, owner, stop)
+    }()
+
+    return func() {
+        close(stop)
+        <-stopped
+
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        defer cancel()
+        if _, err := coll.DeleteOne(ctx, bson.M{"_id": "migrate", "owner": owner}); err != nil {
+            log.Printf("⚠️  Failed to release migration lock: %v", err)
+        }
+    }, nil
 }
 
-// createUsersCollection creates users collection with validation
-func createUsersCollection(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// renewLock pushes expires_at forward until stop is closed, so a migration
+// running longer than migrationLockTTL keeps the lock
+func renewLock(coll *mongo.Collection, owner string, stop <-chan struct{}) {
+    ticker := time.NewTicker(migrationLockRenewal)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-stop:
+            return
+        case <-ticker.C:
+        }
 
-    // Create collection with schema validation
-    validator := bson.M{
-        "$jsonSchema": bson.M{
-            "bsonType": "object",
-            "required": []string{"username", "email", "password_hash", "role", "created_at"},
-            "properties": bson.M{
-                "username": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                    "minLength":   3,
-                    "maxLength":   50,
-                },
-                "email": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and match email pattern",
-                    "pattern":     "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
-                },
-                "password_hash": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "role": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be either admin or user",
-                    "enum":        []string{"admin", "user"},
-                },
-                "created_at": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-            },
-        },
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        result, err := coll.UpdateOne(ctx,
+            bson.M{"_id": "migrate", "owner": owner},
+            bson.M{"$set": bson.M{"expires_at": time.Now().Add(migrationLockTTL)}},
+        )
+        cancel()
+        switch {
+        case err != nil:
+            log.Printf("⚠️  Failed to renew migration lock: %v", err)
+        case result.MatchedCount == 0:
+            log.Println("⚠️  Migration lock expired and is no longer held by this run")
+        }
     }
-
-    opts := options.CreateCollection().SetValidator(validator)
-    return db.CreateCollection(ctx, UsersCollection, opts)
 }
 
-// createAlumniCollection creates alumni collection with validation
-func createAlumniCollection(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// Applied reads the migration records, giving legacy name-only records the
+// version and checksum of the matching migration and re-stamping records
+// whose checksum predates the schema checksums
+func (s *mongoMigrationSource) Applied(ctx context.Context) ([]MigrationRecord, error) {
+    coll := s.db.Collection(MigrationsCollection)
 
-    validator := bson.M{
-        "$jsonSchema": bson.M{
-            "bsonType": "object",
-            "required": []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telepon", "created_at", "updated_at"},
-            "properties": bson.M{
-                "nim": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                    "minLength":   5,
-                    "maxLength":   20,
-                },
-                "nama": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                    "minLength":   3,
-                    "maxLength":   100,
-                },
-                "jurusan": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "angkatan": bson.M{
-                    "bsonType":    "int",
-                    "description": "must be an integer and is required",
-                    "minimum":     1900,
-                    "maximum":     2100,
-                },
-                "tahun_lulus": bson.M{
-                    "bsonType":    "int",
-                    "description": "must be an integer and is required",
-                    "minimum":     1900,
-                    "maximum":     2100,
-                },
-                "email": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and match email pattern",
-                    "pattern":     "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
-                },
-                "no_telepon": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "alamat": bson.M{
-                    "bsonType":    []string{"string", "null"},
-                    "description": "must be a string or null",
-                },
-                "user_id": bson.M{
-                    "bsonType":    []string{"objectId", "null"},
-                    "description": "must be an objectId reference to users or null",
-                },
-                "created_at": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-                "updated_at": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-            },
-        },
+    cursor, err := coll.Find(ctx, bson.M{})
+    if err != nil {
+        return nil, err
     }
+    var docs []migrationDoc
+    if err := cursor.All(ctx, &docs); err != nil {
+        return nil, err
+    }
+
+    records := make([]MigrationRecord, 0, len(docs))
+    for _, doc := range docs {
+        if doc.Version == 0 {
+            m, ok := findMongoMigrationByName(doc.Name)
+            if !ok {
+                continue
+            }
+            doc.Version = m.version
+            doc.Checksum = m.checksum()
+            _, err := coll.UpdateOne(ctx,
+                bson.M{"name": doc.Name, "version": bson.M{"$exists": false}},
+                bson.M{"$set": bson.M{"version": doc.Version, "checksum": doc.Checksum}},
+            )
+            if err != nil {
+                return nil, err
+            }
+            log.Printf("🔖 Versioned legacy migration record %03d_%s", doc.Version, doc.Name)
+        } else if m, ok := findMongoMigration(doc.Version); ok && doc.Checksum != m.checksum() && doc.Checksum == m.legacyChecksum() {
+            _, err := coll.UpdateOne(ctx,
+                bson.M{"version": doc.Version, "checksum": doc.Checksum},
+                bson.M{"$set": bson.M{"checksum": m.checksum()}},
+            )
+            if err != nil {
+                return nil, err
+            }
+            doc.Checksum = m.checksum()
+            log.Printf("🔖 Re-stamped checksum of migration %03d_%s", doc.Version, doc.Name)
+        }
 
-    opts := options.CreateCollection().SetValidator(validator)
-    return db.CreateCollection(ctx, AlumniCollection, opts)
+        records = append(records, MigrationRecord{
+            Version:   doc.Version,
+            Name:      doc.Name,
+            Checksum:  doc.Checksum,
+            AppliedAt: doc.AppliedAt,
+        })
+    }
+    return records, nil
 }
 
-func createPekerjaanCollection(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+func (s *mongoMigrationSource) Up(ctx context.Context, version int) error {
+    m, ok := findMongoMigration(version)
+    if !ok {
+        return fmt.Errorf("migration %d not found", version)
+    }
 
-    validator := bson.M{
-        "$jsonSchema": bson.M{
-            "bsonType": "object",
-            "required": []string{"alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "tanggal_mulai_kerja", "status_pekerjaan", "created_at", "updated_at"},
-            "properties": bson.M{
-                "alumni_id": bson.M{
-                    "bsonType":    "objectId",
-                    "description": "must be an objectId reference to alumni and is required",
-                },
-                "nama_perusahaan": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                    "minLength":   2,
-                    "maxLength":   200,
-                },
-                "posisi_jabatan": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "bidang_industri": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "lokasi_kerja": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                },
-                "gaji_range": bson.M{
-                    "bsonType":    []string{"string", "null"},
-                    "description": "must be a string or null",
-                },
-                "tanggal_mulai_kerja": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-                "tanggal_selesai_kerja": bson.M{
-                    "bsonType":    []string{"date", "null"}, // Allow null values
-                    "description": "must be a date or null",
-                },
-                "status_pekerjaan": bson.M{
-                    "bsonType":    "string",
-                    "description": "must be a string and is required",
-                    "enum":        []string{"aktif", "resign", "kontrak_habis"},
-                },
-                "deskripsi_pekerjaan": bson.M{
-                    "bsonType":    []string{"string", "null"},
-                    "description": "must be a string or null",
-                },
-                "is_delete": bson.M{
-                    "bsonType":    []string{"date", "null"}, // Allow null values
-                    "description": "soft delete timestamp",
-                },
-                "created_at": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-                "updated_at": bson.M{
-                    "bsonType":    "date",
-                    "description": "must be a date and is required",
-                },
-            },
-        },
+    if err := m.apply(ctx, s.db); err != nil {
+        return err
     }
 
-    opts := options.CreateCollection().SetValidator(validator)
-    return db.CreateCollection(ctx, PekerjaanCollection, opts)
+    _, err := s.db.Collection(MigrationsCollection).InsertOne(ctx, migrationDoc{
+        Version:   m.version,
+        Name:      m.name,
+        Checksum:  m.checksum(),
+        AppliedAt: time.Now(),
+    })
+    return err
 }
 
-// createAllIndexes creates all necessary indexes
-func createAllIndexes(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-    defer cancel()
-
-    // Users indexes
-    userCollection := db.Collection(UsersCollection)
-    userIndexes := []mongo.IndexModel{
-        {
-            Keys:    bson.D{{Key: "username", Value: 1}},
-            Options: options.Index().SetUnique(true).SetName("idx_username"),
-        },
-        {
-            Keys:    bson.D{{Key: "email", Value: 1}},
-            Options: options.Index().SetUnique(true).SetName("idx_email"),
-        },
-        {
-            Keys:    bson.D{{Key: "role", Value: 1}},
-            Options: options.Index().SetName("idx_role"),
-        },
+func (s *mongoMigrationSource) Down(ctx context.Context, version int) error {
+    m, ok := findMongoMigration(version)
+    if !ok {
+        return fmt.Errorf("migration %d not found", version)
     }
 
-    _, err := userCollection.Indexes().CreateMany(ctx, userIndexes)
-    if err != nil {
+    if err := m.down(ctx, s.db); err != nil {
         return err
     }
-    log.Println("  ✓ Users indexes created")
-
-    // Alumni indexes
-    alumniCollection := db.Collection(AlumniCollection)
-    alumniIndexes := []mongo.IndexModel{
-        {
-            Keys:    bson.D{{Key: "nim", Value: 1}},
-            Options: options.Index().SetUnique(true).SetName("idx_nim"),
-        },
-        {
-            Keys:    bson.D{{Key: "email", Value: 1}},
-            Options: options.Index().SetName("idx_alumni_email"),
-        },
-        {
-            Keys:    bson.D{{Key: "nama", Value: 1}},
-            Options: options.Index().SetName("idx_nama"),
-        },
-        {
-            Keys:    bson.D{{Key: "jurusan", Value: 1}},
-            Options: options.Index().SetName("idx_jurusan"),
-        },
-        {
-            Keys:    bson.D{{Key: "angkatan", Value: 1}},
-            Options: options.Index().SetName("idx_angkatan"),
-        },
-        {
-            Keys:    bson.D{{Key: "tahun_lulus", Value: 1}},
-            Options: options.Index().SetName("idx_tahun_lulus"),
-        },
-        {
-            Keys:    bson.D{{Key: "user_id", Value: 1}},
-            Options: options.Index().SetName("idx_user_id"),
-        },
-        {
-            Keys: bson.D{
-                {Key: "nama", Value: "text"},
-                {Key: "nim", Value: "text"},
-                {Key: "email", Value: "text"},
-            },
-            Options: options.Index().SetName("idx_text_search"),
-        },
-    }
 
-    _, err = alumniCollection.Indexes().CreateMany(ctx, alumniIndexes)
-    if err != nil {
-        return err
+    _, err := s.db.Collection(MigrationsCollection).DeleteOne(ctx, bson.M{"version": version})
+    return err
+}
+
+func findMongoMigration(version int) (mongoMigration, bool) {
+    for _, m := range mongoMigrations {
+        if m.version == version {
+            return m, true
+        }
     }
-    log.Println("  ✓ Alumni indexes created")
-
-    // Pekerjaan indexes
-    pekerjaanCollection := db.Collection(PekerjaanCollection)
-    pekerjaanIndexes := []mongo.IndexModel{
-        {
-            Keys:    bson.D{{Key: "alumni_id", Value: 1}},
-            Options: options.Index().SetName("idx_alumni_id"),
-        },
-        {
-            Keys:    bson.D{{Key: "nama_perusahaan", Value: 1}},
-            Options: options.Index().SetName("idx_nama_perusahaan"),
-        },
-        {
-            Keys:    bson.D{{Key: "bidang_industri", Value: 1}},
-            Options: options.Index().SetName("idx_bidang_industri"),
-        },
-        {
-            Keys:    bson.D{{Key: "status_pekerjaan", Value: 1}},
-            Options: options.Index().SetName("idx_status_pekerjaan"),
-        },
-        {
-            Keys:    bson.D{{Key: "is_delete", Value: 1}},
-            Options: options.Index().SetName("idx_is_delete"),
-        },
-        {
-            Keys:    bson.D{{Key: "tanggal_mulai_kerja", Value: -1}},
-            Options: options.Index().SetName("idx_tanggal_mulai_kerja"),
-        },
-        {
-            Keys: bson.D{
-                {Key: "nama_perusahaan", Value: "text"},
-                {Key: "posisi_jabatan", Value: "text"},
-                {Key: "lokasi_kerja", Value: "text"},
-            },
-            Options: options.Index().SetName("idx_pekerjaan_text_search"),
-        },
-        // Compound index for common queries
-        {
-            Keys: bson.D{
-                {Key: "alumni_id", Value: 1},
-                {Key: "is_delete", Value: 1},
-            },
-            Options: options.Index().SetName("idx_alumni_active"),
-        },
+    return mongoMigration{}, false
+}
+
+func findMongoMigrationByName(name string) (mongoMigration, bool) {
+    for _, m := range mongoMigrations {
+        if m.name == name {
+            return m, true
+        }
     }
+    return mongoMigration{}, false
+}
 
-    _, err = pekerjaanCollection.Indexes().CreateMany(ctx, pekerjaanIndexes)
-    if err != nil {
+// createCollection creates the collection with the validator of schema. A
+// collection that already exists only gets the validator updated.
+func createCollection(schema CollectionSchema) func(context.Context, *mongo.Database) error {
+    return func(ctx context.Context, db *mongo.Database) error {
+        opts := options.CreateCollection().SetValidator(schema.Validator)
+        err := db.CreateCollection(ctx, schema.Name, opts)
+
+        // NamespaceExists
+        var cmdErr mongo.CommandError
+        if errors.As(err, &cmdErr) && cmdErr.Code == 48 {
+            return setValidator(ctx, db, schema.Name, schema.Validator)
+        }
         return err
     }
-    log.Println("  ✓ Pekerjaan indexes created")
-
-    return nil
 }
 
-// DropAllCollections drops all collections (for testing/reset)
-func DropAllCollections(db *mongo.Database) error {
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+// setValidator replaces the validator of an existing collection
+func setValidator(ctx context.Context, db *mongo.Database, name string, validator bson.M) error {
+    return db.RunCommand(ctx, bson.D{
+        {Key: "collMod", Value: name},
+        {Key: "validator", Value: validator},
+    }).Err()
+}
 
-    collections := []string{
-        UsersCollection,
-        AlumniCollection,
-        PekerjaanCollection,
-        MigrationsCollection,
+// dropCollection is the down step of a create collection migration
+func dropCollection(name string) func(context.Context, *mongo.Database) error {
+    return func(ctx context.Context, db *mongo.Database) error {
+        return db.Collection(name).Drop(ctx)
     }
+}
 
-    for _, coll := range collections {
-        if err := db.Collection(coll).Drop(ctx); err != nil {
-            log.Printf("Warning: Failed to drop collection %s: %v", coll, err)
-        } else {
-            log.Printf("✓ Dropped collection: %s", coll)
+// dropIndexes drops the indexes of schemas. Indexes or collections that are
+// already gone are skipped.
+func dropIndexes(schemas []CollectionSchema) func(context.Context, *mongo.Database) error {
+    return func(ctx context.Context, db *mongo.Database) error {
+        for _, schema := range schemas {
+            for _, index := range schema.Indexes {
+                if err := dropIndex(ctx, db.Collection(schema.Name), *index.Options.Name); err != nil {
+                    return err
+                }
+            }
+            log.Printf("  ✓ %s indexes dropped", schema.Name)
         }
+        return nil
     }
-
-    return nil
-}
\ No newline at end of file
+}
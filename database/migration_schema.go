@@ -0,0 +1,334 @@
+package database
+
+import (
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The schemas applied by the MongoDB migrations. They are checksummed, so
+// once released they never change: a schema change is a new migration whose
+// schema brings the database in line with Collections again.
+
+// pekerjaanValidatorV1 is the pekerjaan_alumni validator before the
+// structured salary fields
+var pekerjaanValidatorV1 = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "tanggal_mulai_kerja", "status_pekerjaan", "created_at", "updated_at"},
+        "properties": bson.M{
+            "alumni_id": bson.M{
+                "bsonType":    "objectId",
+                "description": "must be an objectId reference to alumni and is required",
+            },
+            "nama_perusahaan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "minLength":   2,
+                "maxLength":   200,
+            },
+            "posisi_jabatan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "bidang_industri": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "lokasi_kerja": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "gaji_range": bson.M{
+                "bsonType":    []string{"string", "null"},
+                "description": "must be a string or null",
+            },
+            "tanggal_mulai_kerja": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "tanggal_selesai_kerja": bson.M{
+                "bsonType":    []string{"date", "null"}, // Allow null values
+                "description": "must be a date or null",
+            },
+            "status_pekerjaan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "enum":        []string{"aktif", "resign", "kontrak_habis"},
+            },
+            "deskripsi_pekerjaan": bson.M{
+                "bsonType":    []string{"string", "null"},
+                "description": "must be a string or null",
+            },
+            "is_delete": bson.M{
+                "bsonType":    []string{"date", "null"}, // Allow null values
+                "description": "soft delete timestamp",
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "updated_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+// pekerjaanValidatorV2 adds the structured salary fields
+var pekerjaanValidatorV2 = withProperties(pekerjaanValidatorV1, bson.M{
+    "gaji_min": bson.M{
+        "bsonType":    []string{"long", "int", "null"},
+        "minimum":     0,
+        "description": "lower salary bound, must be a non-negative integer or null",
+    },
+    "gaji_max": bson.M{
+        "bsonType":    []string{"long", "int", "null"},
+        "minimum":     0,
+        "description": "upper salary bound, must be a non-negative integer or null",
+    },
+    "gaji_currency": bson.M{
+        "bsonType":    "string",
+        "pattern":     "^[A-Z]{3}$",
+        "description": "must be an ISO 4217 currency code",
+    },
+    "gaji_period": bson.M{
+        "bsonType":    "string",
+        "description": "must be monthly or annual",
+        "enum":        []string{"monthly", "annual"},
+    },
+    "gaji_range": bson.M{
+        "bsonType":    []string{"string", "null"},
+        "description": "free-text salary kept when it could not be parsed, must be a string or null",
+    },
+    "gaji_review": bson.M{
+        "bsonType":    "bool",
+        "description": "set when gaji_range could not be parsed",
+    },
+})
+
+// pekerjaanValidatorV3 adds the link to the companies registry
+var pekerjaanValidatorV3 = withProperties(pekerjaanValidatorV2, bson.M{
+    "company_id": bson.M{
+        "bsonType":    []string{"objectId", "null"},
+        "description": "must be an objectId reference to companies or null",
+    },
+})
+
+// pekerjaanValidatorV4 adds the moderation fields
+var pekerjaanValidatorV4 = withProperties(pekerjaanValidatorV3, bson.M{
+    "status_moderasi": bson.M{
+        "bsonType":    "string",
+        "description": "must be pending, approved or rejected",
+        "enum":        []string{"pending", "approved", "rejected"},
+    },
+    "catatan_moderasi": bson.M{
+        "bsonType":    "string",
+        "description": "must be a string",
+    },
+    "moderated_by": bson.M{
+        "bsonType":    []string{"objectId", "null"},
+        "description": "must be an objectId reference to users or null",
+    },
+    "moderated_at": bson.M{
+        "bsonType":    []string{"date", "null"},
+        "description": "must be a date or null",
+    },
+})
+
+// createIndexesV1 is the index set of create_indexes
+var createIndexesV1 = []CollectionSchema{
+    {
+        Name: UsersCollection,
+        Indexes: []mongo.IndexModel{
+            {
+                Keys:    bson.D{{Key: "username", Value: 1}},
+                Options: options.Index().SetUnique(true).SetName("idx_username"),
+            },
+            {
+                Keys:    bson.D{{Key: "email", Value: 1}},
+                Options: options.Index().SetUnique(true).SetName("idx_email"),
+            },
+            {
+                Keys:    bson.D{{Key: "role", Value: 1}},
+                Options: options.Index().SetName("idx_role"),
+            },
+        },
+    },
+    {
+        Name: AlumniCollection,
+        Indexes: []mongo.IndexModel{
+            {
+                Keys:    bson.D{{Key: "nim", Value: 1}},
+                Options: options.Index().SetUnique(true).SetName("idx_nim"),
+            },
+            {
+                Keys:    bson.D{{Key: "email", Value: 1}},
+                Options: options.Index().SetName("idx_alumni_email"),
+            },
+            {
+                Keys:    bson.D{{Key: "nama", Value: 1}},
+                Options: options.Index().SetName("idx_nama"),
+            },
+            {
+                Keys:    bson.D{{Key: "jurusan", Value: 1}},
+                Options: options.Index().SetName("idx_jurusan"),
+            },
+            {
+                Keys:    bson.D{{Key: "angkatan", Value: 1}},
+                Options: options.Index().SetName("idx_angkatan"),
+            },
+            {
+                Keys:    bson.D{{Key: "tahun_lulus", Value: 1}},
+                Options: options.Index().SetName("idx_tahun_lulus"),
+            },
+            {
+                Keys:    bson.D{{Key: "user_id", Value: 1}},
+                Options: options.Index().SetName("idx_user_id"),
+            },
+            {
+                Keys: bson.D{
+                    {Key: "nama", Value: "text"},
+                    {Key: "nim", Value: "text"},
+                    {Key: "email", Value: "text"},
+                },
+                Options: options.Index().SetName("idx_text_search"),
+            },
+        },
+    },
+    {
+        Name: PekerjaanCollection,
+        Indexes: []mongo.IndexModel{
+            {
+                Keys:    bson.D{{Key: "alumni_id", Value: 1}},
+                Options: options.Index().SetName("idx_alumni_id"),
+            },
+            {
+                Keys:    bson.D{{Key: "nama_perusahaan", Value: 1}},
+                Options: options.Index().SetName("idx_nama_perusahaan"),
+            },
+            {
+                Keys:    bson.D{{Key: "bidang_industri", Value: 1}},
+                Options: options.Index().SetName("idx_bidang_industri"),
+            },
+            {
+                Keys:    bson.D{{Key: "status_pekerjaan", Value: 1}},
+                Options: options.Index().SetName("idx_status_pekerjaan"),
+            },
+            {
+                Keys:    bson.D{{Key: "is_delete", Value: 1}},
+                Options: options.Index().SetName("idx_is_delete"),
+            },
+            {
+                Keys:    bson.D{{Key: "tanggal_mulai_kerja", Value: -1}},
+                Options: options.Index().SetName("idx_tanggal_mulai_kerja"),
+            },
+            {
+                Keys: bson.D{
+                    {Key: "nama_perusahaan", Value: "text"},
+                    {Key: "posisi_jabatan", Value: "text"},
+                    {Key: "lokasi_kerja", Value: "text"},
+                },
+                Options: options.Index().SetName("idx_pekerjaan_text_search"),
+            },
+            // Compound index for common queries
+            {
+                Keys: bson.D{
+                    {Key: "alumni_id", Value: 1},
+                    {Key: "is_delete", Value: 1},
+                },
+                Options: options.Index().SetName("idx_alumni_active"),
+            },
+        },
+    },
+}
+
+// structureGajiSchema is the schema of structure_gaji_range
+var structureGajiSchema = []CollectionSchema{{
+    Name:      PekerjaanCollection,
+    Validator: pekerjaanValidatorV2,
+    Indexes: []mongo.IndexModel{{
+        Keys: bson.D{
+            {Key: "gaji_min", Value: 1},
+            {Key: "gaji_max", Value: 1},
+        },
+        Options: options.Index().SetName("idx_gaji"),
+    }},
+}}
+
+// companiesSchema is the schema of create_companies_collection. Existing
+// pekerjaan are linked by the companies backfill command, not the migration.
+var companiesSchema = []CollectionSchema{
+    {Name: CompaniesCollection, Validator: companiesValidator, Indexes: companiesIndexes},
+    {
+        Name:      PekerjaanCollection,
+        Validator: pekerjaanValidatorV3,
+        Indexes: []mongo.IndexModel{{
+            Keys:    bson.D{{Key: "company_id", Value: 1}},
+            Options: options.Index().SetName("idx_company_id"),
+        }},
+    },
+}
+
+// moderationSchema is the schema of add_pekerjaan_moderation
+var moderationSchema = []CollectionSchema{{
+    Name:      PekerjaanCollection,
+    Validator: pekerjaanValidatorV4,
+    Indexes: []mongo.IndexModel{{
+        Keys:    bson.D{{Key: "status_moderasi", Value: 1}},
+        Options: options.Index().SetName("idx_status_moderasi"),
+    }},
+}}
+
+// withProperties returns a copy of validator with properties added or
+// replaced
+func withProperties(validator bson.M, properties bson.M) bson.M {
+    schema := bson.M{}
+    for key, value := range validator["$jsonSchema"].(bson.M) {
+        schema[key] = value
+    }
+
+    merged := bson.M{}
+    for key, value := range schema["properties"].(bson.M) {
+        merged[key] = value
+    }
+    for key, value := range properties {
+        merged[key] = value
+    }
+    schema["properties"] = merged
+    return bson.M{"$jsonSchema": schema}
+}
+
+// schemaSpec is the checksummed form of a CollectionSchema. It lists the
+// index options the code uses rather than encoding mongo.IndexModel, whose
+// fields change with the driver.
+type schemaSpec struct {
+    Collection string      `json:"collection"`
+    Validator  bson.M      `json:"validator,omitempty"`
+    Indexes    []indexSpec `json:"indexes,omitempty"`
+}
+
+type indexSpec struct {
+    Name   string `json:"name"`
+    Keys   bson.D `json:"keys"`
+    Unique bool   `json:"unique,omitempty"`
+    TTL    *int32 `json:"ttl,omitempty"`
+}
+
+func schemaSpecs(schemas []CollectionSchema) []schemaSpec {
+    specs := make([]schemaSpec, 0, len(schemas))
+    for _, schema := range schemas {
+        spec := schemaSpec{Collection: schema.Name, Validator: schema.Validator}
+        for _, model := range schema.Indexes {
+            spec.Indexes = append(spec.Indexes, indexSpec{
+                Name:   *model.Options.Name,
+                Keys:   model.Keys.(bson.D),
+                Unique: model.Options.Unique != nil && *model.Options.Unique,
+                TTL:    model.Options.ExpireAfterSeconds,
+            })
+        }
+        specs = append(specs, spec)
+    }
+    return specs
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+    "context"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// addPekerjaanModeration marks every existing pekerjaan approved, since they
+// were all written by admins
+func addPekerjaanModeration(ctx context.Context, db *mongo.Database) error {
+    coll := db.Collection(PekerjaanCollection)
+    _, err := coll.UpdateMany(ctx,
+        bson.M{"status_moderasi": bson.M{"$exists": false}},
+        bson.M{"$set": bson.M{"status_moderasi": "approved"}})
+    return err
+}
+
+// dropPekerjaanModeration removes the moderation fields. Pending and rejected
+// pekerjaan stay and become visible like any other. The validator of
+// create_companies_collection is restored.
+func dropPekerjaanModeration(ctx context.Context, db *mongo.Database) error {
+    coll := db.Collection(PekerjaanCollection)
+    _, err := coll.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{
+        "status_moderasi":  "",
+        "catatan_moderasi": "",
+        "moderated_by":     "",
+        "moderated_at":     "",
+    }})
+    if err != nil {
+        return err
+    }
+    if err := setValidator(ctx, db, PekerjaanCollection, pekerjaanValidatorV3); err != nil {
+        return err
+    }
+    return dropIndex(ctx, coll, "idx_status_moderasi")
+}
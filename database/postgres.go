@@ -0,0 +1,43 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "log"
+    "time"
+
+    "go-fiber/config"
+
+    _ "github.com/lib/pq"
+)
+
+// Storage drivers selectable with DB_DRIVER
+const (
+    DriverMongo    = config.DriverMongo
+    DriverPostgres = config.DriverPostgres
+)
+
+// Driver returns the configured storage driver, defaulting to MongoDB
+func Driver() string {
+    return config.Get().Database.Driver
+}
+
+var PG *sql.DB
+
+func ConnectPostgres() *sql.DB {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    db, err := sql.Open("postgres", config.Get().Database.PostgresURL)
+    if err != nil {
+        log.Fatal("Failed to open PostgreSQL:", err)
+    }
+
+    if err = db.PingContext(ctx); err != nil {
+        log.Fatal("Database tidak connect:", err)
+    }
+
+    log.Println("PostgreSQL Connected ✅")
+    PG = db
+    return PG
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "log"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/lib/pq"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// CopyMongoToPostgres copies users, alumni, companies, pekerjaan and the
+// report jobs with their generated files from MongoDB into the migrated
+// PostgreSQL schema, keeping the ObjectIDs. Rows that already exist are
+// skipped, so the copy can be re-run after a partial failure.
+func CopyMongoToPostgres(mdb *mongo.Database, pg *sql.DB) error {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+    defer cancel()
+
+    log.Println("📦 Copying MongoDB data to PostgreSQL...")
+
+    tx, err := pg.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var users []model.User
+    if err := findAll(ctx, mdb.Collection(UsersCollection), &users); err != nil {
+        return err
+    }
+    for _, u := range users {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO users (id, username, email, password_hash, role, created_at)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            ON CONFLICT (id) DO NOTHING`,
+            u.ID.Hex(), u.Username, u.Email, u.PasswordHash, u.Role, u.CreatedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy user %s: %v", u.ID.Hex(), err)
+            return err
+        }
+    }
+    log.Printf("  ✓ Users copied (%d)", len(users))
+
+    var alumni []model.Alumni
+    if err := findAll(ctx, mdb.Collection(AlumniCollection), &alumni); err != nil {
+        return err
+    }
+    for _, a := range alumni {
+        var userID interface{}
+        if !a.UserID.IsZero() {
+            userID = a.UserID.Hex()
+        }
+
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO alumni (id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, user_id, created_at, updated_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+            ON CONFLICT (id) DO NOTHING`,
+            a.ID.Hex(), a.NIM, a.Nama, a.Jurusan, a.Angkatan, a.TahunLulus, a.Email, a.NoTelepon,
+            a.Alamat, userID, a.CreatedAt, a.UpdatedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy alumni %s: %v", a.ID.Hex(), err)
+            return err
+        }
+    }
+    log.Printf("  ✓ Alumni copied (%d)", len(alumni))
+
+    var companies []model.Company
+    if err := findAll(ctx, mdb.Collection(CompaniesCollection), &companies); err != nil {
+        return err
+    }
+    for _, c := range companies {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO companies (id, nama, nama_key, aliases, alias_keys, bidang_industri, lokasi, created_at, updated_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            ON CONFLICT (id) DO NOTHING`,
+            c.ID.Hex(), c.Nama, c.NamaKey, pq.Array(c.Aliases), pq.Array(c.AliasKeys),
+            c.BidangIndustri, c.Lokasi, c.CreatedAt, c.UpdatedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy company %s: %v", c.ID.Hex(), err)
+            return err
+        }
+    }
+    log.Printf("  ✓ Companies copied (%d)", len(companies))
+
+    var pekerjaan []model.Pekerjaan
+    if err := findAll(ctx, mdb.Collection(PekerjaanCollection), &pekerjaan); err != nil {
+        return err
+    }
+    for _, p := range pekerjaan {
+        var moderatedBy string
+        if p.ModeratedBy != nil {
+            moderatedBy = p.ModeratedBy.Hex()
+        }
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO pekerjaan_alumni (id, alumni_id, nama_perusahaan, company_id, posisi_jabatan, bidang_industri, lokasi_kerja,
+                gaji_min, gaji_max, gaji_currency, gaji_period, gaji_range, gaji_review,
+                tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, deskripsi_pekerjaan,
+                is_delete, created_at, updated_at, status_moderasi, catatan_moderasi, moderated_by, moderated_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+            ON CONFLICT (id) DO NOTHING`,
+            p.ID.Hex(), p.AlumniID.Hex(), p.NamaPerusahaan, nullIfEmpty(p.CompanyHex()), p.PosisiJabatan, p.BidangIndustri, p.LokasiKerja,
+            p.GajiMin, p.GajiMax, nullIfEmpty(p.GajiCurrency), nullIfEmpty(p.GajiPeriod), p.GajiRange, p.GajiReview,
+            p.TanggalMulaiKerja, p.TanggalSelesaiKerja, p.StatusPekerjaan, p.DeskripsiPekerjaan,
+            p.IsDelete, p.CreatedAt, p.UpdatedAt, p.ModerationStatus(), nullIfEmpty(p.CatatanModerasi), nullIfEmpty(moderatedBy), p.ModeratedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy pekerjaan %s: %v", p.ID.Hex(), err)
+            return err
+        }
+    }
+    log.Printf("  ✓ Pekerjaan copied (%d)", len(pekerjaan))
+
+    var reports []model.Report
+    if err := findAll(ctx, mdb.Collection(ReportsCollection), &reports); err != nil {
+        return err
+    }
+    for _, r := range reports {
+        if r.Files == nil {
+            r.Files = []model.ReportFile{}
+        }
+        files, err := json.Marshal(r.Files)
+        if err != nil {
+            return err
+        }
+        _, err = tx.ExecContext(ctx, `
+            INSERT INTO reports (id, jurusan, tahun_lulus, formats, status, error, files, requested_by, created_at, started_at, finished_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+            ON CONFLICT (id) DO NOTHING`,
+            r.ID.Hex(), r.Jurusan, r.TahunLulus, pq.Array(r.Formats), r.Status, nullIfEmpty(r.Error),
+            files, r.RequestedBy.Hex(), r.CreatedAt, r.StartedAt, r.FinishedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy report %s: %v", r.ID.Hex(), err)
+            return err
+        }
+    }
+    log.Printf("  ✓ Reports copied (%d)", len(reports))
+
+    // Generated files can be large, so they are read one at a time
+    cursor, err := mdb.Collection(ReportFilesCollection).Find(ctx, bson.M{})
+    if err != nil {
+        return err
+    }
+    defer cursor.Close(ctx)
+
+    copied := 0
+    for cursor.Next(ctx) {
+        var f model.ReportContent
+        if err := cursor.Decode(&f); err != nil {
+            return err
+        }
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO report_files (report_id, format, data, created_at)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (report_id, format) DO NOTHING`,
+            f.ReportID.Hex(), f.Format, f.Data, f.CreatedAt)
+        if err != nil {
+            log.Printf("❌ Failed to copy %s file of report %s: %v", f.Format, f.ReportID.Hex(), err)
+            return err
+        }
+        copied++
+    }
+    if err := cursor.Err(); err != nil {
+        return err
+    }
+    log.Printf("  ✓ Report files copied (%d)", copied)
+
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+
+    log.Println("✅ Copy completed successfully!")
+    return nil
+}
+
+func findAll(ctx context.Context, coll *mongo.Collection, out interface{}) error {
+    cursor, err := coll.Find(ctx, bson.M{})
+    if err != nil {
+        return err
+    }
+    defer cursor.Close(ctx)
+
+    return cursor.All(ctx, out)
+}
+
+// nullIfEmpty copies an empty string as NULL, which the CHECK constraints
+// accept
+func nullIfEmpty(s string) interface{} {
+    if s == "" {
+        return nil
+    }
+    return s
+}
@@ -0,0 +1,357 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "log"
+    "time"
+)
+
+// postgresMigrations mirror RunMigrations: the CHECK constraints follow the
+// $jsonSchema validators and the indexes follow the Mongo migration schemas.
+// Like the Mongo collections there are no foreign keys, so deleting an alumni
+// leaves its pekerjaan in place.
+var postgresMigrations = []postgresMigration{
+    {1, "create_users_table", `
+        CREATE TABLE users (
+            id            CHAR(24) PRIMARY KEY,
+            username      VARCHAR(50) NOT NULL CHECK (char_length(username) >= 3),
+            email         TEXT NOT NULL CHECK (email ~ '^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$'),
+            password_hash TEXT NOT NULL,
+            role          TEXT NOT NULL CHECK (role IN ('admin', 'user')),
+            created_at    TIMESTAMPTZ NOT NULL
+        )`, `DROP TABLE users`},
+    {2, "create_alumni_table", `
+        CREATE TABLE alumni (
+            id          CHAR(24) PRIMARY KEY,
+            nim         VARCHAR(20) NOT NULL CHECK (char_length(nim) >= 5),
+            nama        VARCHAR(100) NOT NULL CHECK (char_length(nama) >= 3),
+            jurusan     TEXT NOT NULL,
+            angkatan    INTEGER NOT NULL CHECK (angkatan BETWEEN 1900 AND 2100),
+            tahun_lulus INTEGER NOT NULL CHECK (tahun_lulus BETWEEN 1900 AND 2100),
+            email       TEXT NOT NULL CHECK (email ~ '^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$'),
+            no_telepon  TEXT NOT NULL,
+            alamat      TEXT,
+            user_id     CHAR(24),
+            created_at  TIMESTAMPTZ NOT NULL,
+            updated_at  TIMESTAMPTZ NOT NULL
+        )`, `DROP TABLE alumni`},
+    {3, "create_pekerjaan_table", `
+        CREATE TABLE pekerjaan_alumni (
+            id                    CHAR(24) PRIMARY KEY,
+            alumni_id             CHAR(24) NOT NULL,
+            nama_perusahaan       VARCHAR(200) NOT NULL CHECK (char_length(nama_perusahaan) >= 2),
+            posisi_jabatan        TEXT NOT NULL,
+            bidang_industri       TEXT NOT NULL,
+            lokasi_kerja          TEXT NOT NULL,
+            gaji_range            TEXT,
+            tanggal_mulai_kerja   TIMESTAMPTZ NOT NULL,
+            tanggal_selesai_kerja TIMESTAMPTZ,
+            status_pekerjaan      TEXT NOT NULL CHECK (status_pekerjaan IN ('aktif', 'resign', 'kontrak_habis')),
+            deskripsi_pekerjaan   TEXT,
+            is_delete             TIMESTAMPTZ,
+            created_at            TIMESTAMPTZ NOT NULL,
+            updated_at            TIMESTAMPTZ NOT NULL
+        )`, `DROP TABLE pekerjaan_alumni`},
+    {4, "create_indexes", `
+        CREATE UNIQUE INDEX idx_username ON users (username);
+        CREATE UNIQUE INDEX idx_email ON users (email);
+        CREATE INDEX idx_role ON users (role);
+
+        CREATE UNIQUE INDEX idx_nim ON alumni (nim);
+        CREATE INDEX idx_alumni_email ON alumni (email);
+        CREATE INDEX idx_nama ON alumni (nama);
+        CREATE INDEX idx_jurusan ON alumni (jurusan);
+        CREATE INDEX idx_angkatan ON alumni (angkatan);
+        CREATE INDEX idx_tahun_lulus ON alumni (tahun_lulus);
+        CREATE INDEX idx_user_id ON alumni (user_id);
+
+        CREATE INDEX idx_alumni_id ON pekerjaan_alumni (alumni_id);
+        CREATE INDEX idx_nama_perusahaan ON pekerjaan_alumni (nama_perusahaan);
+        CREATE INDEX idx_bidang_industri ON pekerjaan_alumni (bidang_industri);
+        CREATE INDEX idx_status_pekerjaan ON pekerjaan_alumni (status_pekerjaan);
+        CREATE INDEX idx_is_delete ON pekerjaan_alumni (is_delete);
+        CREATE INDEX idx_tanggal_mulai_kerja ON pekerjaan_alumni (tanggal_mulai_kerja DESC);
+        CREATE INDEX idx_alumni_active ON pekerjaan_alumni (alumni_id) WHERE is_delete IS NULL`, `
+        DROP INDEX idx_username, idx_email, idx_role,
+            idx_nim, idx_alumni_email, idx_nama, idx_jurusan, idx_angkatan, idx_tahun_lulus, idx_user_id,
+            idx_alumni_id, idx_nama_perusahaan, idx_bidang_industri, idx_status_pekerjaan, idx_is_delete,
+            idx_tanggal_mulai_kerja, idx_alumni_active`},
+    {5, "create_rate_limits_table", `
+        CREATE TABLE rate_limits (
+            key        TEXT PRIMARY KEY,
+            tokens     DOUBLE PRECISION NOT NULL,
+            allowed    BOOLEAN NOT NULL,
+            updated_at TIMESTAMPTZ NOT NULL,
+            expires_at TIMESTAMPTZ NOT NULL
+        );
+        CREATE INDEX idx_rate_limits_expires_at ON rate_limits (expires_at)`, `DROP TABLE rate_limits`},
+    {6, "create_cache_entries_table", `
+        CREATE TABLE cache_entries (
+            key        TEXT PRIMARY KEY,
+            value      BYTEA NOT NULL,
+            expires_at TIMESTAMPTZ
+        );
+        CREATE INDEX idx_cache_entries_expires_at ON cache_entries (expires_at)`, `DROP TABLE cache_entries`},
+    {7, "structure_gaji_range", `
+        ALTER TABLE pekerjaan_alumni
+            ADD COLUMN gaji_min      BIGINT CHECK (gaji_min >= 0),
+            ADD COLUMN gaji_max      BIGINT CHECK (gaji_max >= 0),
+            ADD COLUMN gaji_currency TEXT CHECK (gaji_currency ~ '^[A-Z]{3}$'),
+            ADD COLUMN gaji_period   TEXT CHECK (gaji_period IN ('monthly', 'annual')),
+            ADD COLUMN gaji_review   BOOLEAN NOT NULL DEFAULT false,
+            ADD CONSTRAINT pekerjaan_gaji_bounds CHECK (gaji_min <= gaji_max);
+        CREATE INDEX idx_gaji ON pekerjaan_alumni (gaji_min, gaji_max)`, `
+        DROP INDEX idx_gaji;
+        ALTER TABLE pekerjaan_alumni
+            DROP COLUMN gaji_min, DROP COLUMN gaji_max, DROP COLUMN gaji_currency,
+            DROP COLUMN gaji_period, DROP COLUMN gaji_review`},
+    {8, "create_companies_table", `
+        CREATE TABLE companies (
+            id              CHAR(24) PRIMARY KEY,
+            nama            VARCHAR(200) NOT NULL CHECK (char_length(nama) >= 1),
+            nama_key        TEXT NOT NULL CHECK (nama_key <> ''),
+            aliases         TEXT[] NOT NULL DEFAULT '{}',
+            alias_keys      TEXT[] NOT NULL DEFAULT '{}',
+            bidang_industri TEXT,
+            lokasi          TEXT,
+            created_at      TIMESTAMPTZ NOT NULL,
+            updated_at      TIMESTAMPTZ NOT NULL
+        );
+        CREATE UNIQUE INDEX idx_company_nama_key ON companies (nama_key);
+        CREATE INDEX idx_company_alias_keys ON companies USING GIN (alias_keys);
+        CREATE INDEX idx_company_nama ON companies (nama);
+        ALTER TABLE pekerjaan_alumni ADD COLUMN company_id CHAR(24);
+        CREATE INDEX idx_company_id ON pekerjaan_alumni (company_id)`, `
+        DROP INDEX idx_company_id;
+        ALTER TABLE pekerjaan_alumni DROP COLUMN company_id;
+        DROP TABLE companies`},
+    {9, "add_pekerjaan_moderation", `
+        ALTER TABLE pekerjaan_alumni
+            ADD COLUMN status_moderasi  TEXT NOT NULL DEFAULT 'approved'
+                CHECK (status_moderasi IN ('pending', 'approved', 'rejected')),
+            ADD COLUMN catatan_moderasi TEXT,
+            ADD COLUMN moderated_by     CHAR(24),
+            ADD COLUMN moderated_at     TIMESTAMPTZ;
+        CREATE INDEX idx_status_moderasi ON pekerjaan_alumni (status_moderasi)`, `
+        DROP INDEX idx_status_moderasi;
+        ALTER TABLE pekerjaan_alumni
+            DROP COLUMN status_moderasi, DROP COLUMN catatan_moderasi,
+            DROP COLUMN moderated_by, DROP COLUMN moderated_at`},
+    {10, "create_reports_tables", `
+        CREATE TABLE reports (
+            id           CHAR(24) PRIMARY KEY,
+            jurusan      TEXT NOT NULL,
+            tahun_lulus  INTEGER NOT NULL CHECK (tahun_lulus BETWEEN 1900 AND 2100),
+            formats      TEXT[] NOT NULL,
+            status       TEXT NOT NULL CHECK (status IN ('pending', 'running', 'done', 'failed')),
+            error        TEXT,
+            files        JSONB NOT NULL DEFAULT '[]',
+            requested_by CHAR(24),
+            created_at   TIMESTAMPTZ NOT NULL,
+            started_at   TIMESTAMPTZ,
+            finished_at  TIMESTAMPTZ
+        );
+        CREATE INDEX idx_report_created_at ON reports (created_at DESC);
+        CREATE INDEX idx_report_status ON reports (status, created_at);
+        CREATE TABLE report_files (
+            report_id  CHAR(24) NOT NULL,
+            format     TEXT NOT NULL CHECK (format IN ('pdf', 'xlsx')),
+            data       BYTEA NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL,
+            PRIMARY KEY (report_id, format)
+        )`, `
+        DROP TABLE report_files;
+        DROP TABLE reports`},
+}
+
+// postgresDataSteps are the Go parts of migrations that cannot be written in
+// SQL. up runs after the migration's statement and down before it, in the
+// same transaction.
+var postgresDataSteps = map[int]struct {
+    up, down func(context.Context, *sql.Tx) error
+}{
+    7: {structureGajiRangeRows, unstructureGajiRangeRows},
+}
+
+// postgresMigrationLockKey is the pg_advisory_lock key of a migration run
+const postgresMigrationLockKey = 4242030
+
+// postgresMigration is one numbered PostgreSQL migration. The checksum covers
+// both statements.
+type postgresMigration struct {
+    version int
+    name    string
+    up      string
+    down    string
+}
+
+// NewPostgresMigrator returns the migrator for the PostgreSQL schema
+func NewPostgresMigrator(db *sql.DB) *Migrator {
+    return &Migrator{source: &postgresMigrationSource{db: db}}
+}
+
+// RunPostgresMigrations applies all pending PostgreSQL migrations
+func RunPostgresMigrations(db *sql.DB) error {
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+    defer cancel()
+
+    log.Println("🔄 Starting PostgreSQL migrations...")
+    if err := NewPostgresMigrator(db).Up(ctx, 0); err != nil {
+        return err
+    }
+    log.Println("✅ All PostgreSQL migrations completed successfully!")
+    return nil
+}
+
+type postgresMigrationSource struct {
+    db *sql.DB
+}
+
+func (s *postgresMigrationSource) Definitions() []MigrationDef {
+    defs := make([]MigrationDef, 0, len(postgresMigrations))
+    for _, m := range postgresMigrations {
+        defs = append(defs, MigrationDef{Version: m.version, Name: m.name, Checksum: m.checksum()})
+    }
+    return defs
+}
+
+func (m postgresMigration) checksum() string {
+    return checksum(m.name, m.up, m.down)
+}
+
+// Lock takes a session level advisory lock on a dedicated connection, so it
+// is released with the connection even when the process dies
+func (s *postgresMigrationSource) Lock(ctx context.Context) (func(), error) {
+    conn, err := s.db.Conn(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    var locked bool
+    if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, postgresMigrationLockKey).Scan(&locked); err != nil {
+        conn.Close()
+        return nil, err
+    }
+    if !locked {
+        conn.Close()
+        return nil, ErrMigrationLocked
+    }
+
+    return func() {
+        ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+        defer cancel()
+        if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, postgresMigrationLockKey); err != nil {
+            log.Printf("⚠️  Failed to release migration lock: %v", err)
+        }
+        conn.Close()
+    }, nil
+}
+
+// Applied reads schema_migrations, adding the version and checksum columns
+// and filling them for rows written before versioning
+func (s *postgresMigrationSource) Applied(ctx context.Context) ([]MigrationRecord, error) {
+    _, err := s.db.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            name       TEXT PRIMARY KEY,
+            applied_at TIMESTAMPTZ NOT NULL
+        );
+        ALTER TABLE schema_migrations
+            ADD COLUMN IF NOT EXISTS version INTEGER,
+            ADD COLUMN IF NOT EXISTS checksum TEXT`)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, m := range postgresMigrations {
+        _, err := s.db.ExecContext(ctx,
+            `UPDATE schema_migrations SET version = $1, checksum = $2 WHERE name = $3 AND version IS NULL`,
+            m.version, m.checksum(), m.name)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    rows, err := s.db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations WHERE version IS NOT NULL`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var records []MigrationRecord
+    for rows.Next() {
+        var record MigrationRecord
+        if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.AppliedAt); err != nil {
+            return nil, err
+        }
+        records = append(records, record)
+    }
+    return records, rows.Err()
+}
+
+// Up runs the migration and its record in one transaction
+func (s *postgresMigrationSource) Up(ctx context.Context, version int) error {
+    m, ok := findPostgresMigration(version)
+    if !ok {
+        return fmt.Errorf("migration %d not found", version)
+    }
+
+    return s.inTx(ctx, func(tx *sql.Tx) error {
+        if _, err := tx.ExecContext(ctx, m.up); err != nil {
+            return err
+        }
+        if step, ok := postgresDataSteps[version]; ok {
+            return step.up(ctx, tx)
+        }
+        return nil
+    }, `INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)`,
+        m.version, m.name, m.checksum(), time.Now())
+}
+
+// Down reverts the migration and removes its record in one transaction
+func (s *postgresMigrationSource) Down(ctx context.Context, version int) error {
+    m, ok := findPostgresMigration(version)
+    if !ok {
+        return fmt.Errorf("migration %d not found", version)
+    }
+
+    return s.inTx(ctx, func(tx *sql.Tx) error {
+        if step, ok := postgresDataSteps[version]; ok {
+            if err := step.down(ctx, tx); err != nil {
+                return err
+            }
+        }
+        _, err := tx.ExecContext(ctx, m.down)
+        return err
+    }, `DELETE FROM schema_migrations WHERE version = $1`, m.version)
+}
+
+// inTx runs migrate and writes the schema_migrations record in one
+// transaction
+func (s *postgresMigrationSource) inTx(ctx context.Context, migrate func(*sql.Tx) error, record string, args ...interface{}) error {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+
+    if err := migrate(tx); err != nil {
+        tx.Rollback()
+        return err
+    }
+    if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+        tx.Rollback()
+        return err
+    }
+
+    return tx.Commit()
+}
+
+func findPostgresMigration(version int) (postgresMigration, bool) {
+    for _, m := range postgresMigrations {
+        if m.version == version {
+            return m, true
+        }
+    }
+    return postgresMigration{}, false
+}
@@ -0,0 +1,15 @@
+package database
+
+import (
+    "context"
+
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// dropReports drops the jobs together with every generated file
+func dropReports(ctx context.Context, db *mongo.Database) error {
+    if err := dropCollection(ReportFilesCollection)(ctx, db); err != nil {
+        return err
+    }
+    return dropCollection(ReportsCollection)(ctx, db)
+}
@@ -0,0 +1,555 @@
+package database
+
+import (
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionSchema declares the validator and indexes of one collection
+type CollectionSchema struct {
+    Name      string
+    Validator bson.M
+    Indexes   []mongo.IndexModel
+}
+
+// Collections is the single source of truth for the MongoDB schema. A change
+// here needs a migration that applies it on an empty database, which
+// TestMigrationsBuildCollections checks; SyncSchema reconciles a database
+// that drifted from it.
+var Collections = []CollectionSchema{
+    {Name: UsersCollection, Validator: usersValidator, Indexes: usersIndexes},
+    {Name: AlumniCollection, Validator: alumniValidator, Indexes: alumniIndexes},
+    {Name: PekerjaanCollection, Validator: pekerjaanValidator, Indexes: pekerjaanIndexes},
+    {Name: RateLimitsCollection, Validator: rateLimitsValidator, Indexes: rateLimitsIndexes},
+    {Name: CacheCollection, Validator: cacheValidator, Indexes: cacheIndexes},
+    {Name: CompaniesCollection, Validator: companiesValidator, Indexes: companiesIndexes},
+    {Name: ReportsCollection, Validator: reportsValidator, Indexes: reportsIndexes},
+    {Name: ReportFilesCollection, Validator: reportFilesValidator, Indexes: reportFilesIndexes},
+}
+
+// collectionSchema returns the declared schema of the named collection
+func collectionSchema(name string) (CollectionSchema, bool) {
+    for _, schema := range Collections {
+        if schema.Name == name {
+            return schema, true
+        }
+    }
+    return CollectionSchema{}, false
+}
+
+// usersValidator is the $jsonSchema of the users collection
+var usersValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"username", "email", "password_hash", "role", "created_at"},
+        "properties": bson.M{
+            "username": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "minLength":   3,
+                "maxLength":   50,
+            },
+            "email": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and match email pattern",
+                "pattern":     "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
+            },
+            "password_hash": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "role": bson.M{
+                "bsonType":    "string",
+                "description": "must be either admin or user",
+                "enum":        []string{"admin", "user"},
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+var usersIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "username", Value: 1}},
+        Options: options.Index().SetUnique(true).SetName("idx_username"),
+    },
+    {
+        Keys:    bson.D{{Key: "email", Value: 1}},
+        Options: options.Index().SetUnique(true).SetName("idx_email"),
+    },
+    {
+        Keys:    bson.D{{Key: "role", Value: 1}},
+        Options: options.Index().SetName("idx_role"),
+    },
+}
+
+// alumniValidator is the $jsonSchema of the alumni collection
+var alumniValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"nim", "nama", "jurusan", "angkatan", "tahun_lulus", "email", "no_telepon", "created_at", "updated_at"},
+        "properties": bson.M{
+            "nim": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "minLength":   5,
+                "maxLength":   20,
+            },
+            "nama": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "minLength":   3,
+                "maxLength":   100,
+            },
+            "jurusan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "angkatan": bson.M{
+                "bsonType":    "int",
+                "description": "must be an integer and is required",
+                "minimum":     1900,
+                "maximum":     2100,
+            },
+            "tahun_lulus": bson.M{
+                "bsonType":    "int",
+                "description": "must be an integer and is required",
+                "minimum":     1900,
+                "maximum":     2100,
+            },
+            "email": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and match email pattern",
+                "pattern":     "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
+            },
+            "no_telepon": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "alamat": bson.M{
+                "bsonType":    []string{"string", "null"},
+                "description": "must be a string or null",
+            },
+            "user_id": bson.M{
+                "bsonType":    []string{"objectId", "null"},
+                "description": "must be an objectId reference to users or null",
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "updated_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+var alumniIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "nim", Value: 1}},
+        Options: options.Index().SetUnique(true).SetName("idx_nim"),
+    },
+    {
+        Keys:    bson.D{{Key: "email", Value: 1}},
+        Options: options.Index().SetName("idx_alumni_email"),
+    },
+    {
+        Keys:    bson.D{{Key: "nama", Value: 1}},
+        Options: options.Index().SetName("idx_nama"),
+    },
+    {
+        Keys:    bson.D{{Key: "jurusan", Value: 1}},
+        Options: options.Index().SetName("idx_jurusan"),
+    },
+    {
+        Keys:    bson.D{{Key: "angkatan", Value: 1}},
+        Options: options.Index().SetName("idx_angkatan"),
+    },
+    {
+        Keys:    bson.D{{Key: "tahun_lulus", Value: 1}},
+        Options: options.Index().SetName("idx_tahun_lulus"),
+    },
+    {
+        Keys:    bson.D{{Key: "user_id", Value: 1}},
+        Options: options.Index().SetName("idx_user_id"),
+    },
+    {
+        Keys: bson.D{
+            {Key: "nama", Value: "text"},
+            {Key: "nim", Value: "text"},
+            {Key: "email", Value: "text"},
+        },
+        Options: options.Index().SetName("idx_text_search"),
+    },
+}
+
+// pekerjaanValidator is the $jsonSchema of the pekerjaan_alumni collection
+var pekerjaanValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"alumni_id", "nama_perusahaan", "posisi_jabatan", "bidang_industri", "lokasi_kerja", "tanggal_mulai_kerja", "status_pekerjaan", "created_at", "updated_at"},
+        "properties": bson.M{
+            "alumni_id": bson.M{
+                "bsonType":    "objectId",
+                "description": "must be an objectId reference to alumni and is required",
+            },
+            "nama_perusahaan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "minLength":   2,
+                "maxLength":   200,
+            },
+            "company_id": bson.M{
+                "bsonType":    []string{"objectId", "null"},
+                "description": "must be an objectId reference to companies or null",
+            },
+            "posisi_jabatan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "bidang_industri": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "lokasi_kerja": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "gaji_min": bson.M{
+                "bsonType":    []string{"long", "int", "null"},
+                "minimum":     0,
+                "description": "lower salary bound, must be a non-negative integer or null",
+            },
+            "gaji_max": bson.M{
+                "bsonType":    []string{"long", "int", "null"},
+                "minimum":     0,
+                "description": "upper salary bound, must be a non-negative integer or null",
+            },
+            "gaji_currency": bson.M{
+                "bsonType":    "string",
+                "pattern":     "^[A-Z]{3}$",
+                "description": "must be an ISO 4217 currency code",
+            },
+            "gaji_period": bson.M{
+                "bsonType":    "string",
+                "description": "must be monthly or annual",
+                "enum":        []string{"monthly", "annual"},
+            },
+            "gaji_range": bson.M{
+                "bsonType":    []string{"string", "null"},
+                "description": "free-text salary kept when it could not be parsed, must be a string or null",
+            },
+            "gaji_review": bson.M{
+                "bsonType":    "bool",
+                "description": "set when gaji_range could not be parsed",
+            },
+            "tanggal_mulai_kerja": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "tanggal_selesai_kerja": bson.M{
+                "bsonType":    []string{"date", "null"}, // Allow null values
+                "description": "must be a date or null",
+            },
+            "status_pekerjaan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+                "enum":        []string{"aktif", "resign", "kontrak_habis"},
+            },
+            "deskripsi_pekerjaan": bson.M{
+                "bsonType":    []string{"string", "null"},
+                "description": "must be a string or null",
+            },
+            "status_moderasi": bson.M{
+                "bsonType":    "string",
+                "description": "must be pending, approved or rejected",
+                "enum":        []string{"pending", "approved", "rejected"},
+            },
+            "catatan_moderasi": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string",
+            },
+            "moderated_by": bson.M{
+                "bsonType":    []string{"objectId", "null"},
+                "description": "must be an objectId reference to users or null",
+            },
+            "moderated_at": bson.M{
+                "bsonType":    []string{"date", "null"},
+                "description": "must be a date or null",
+            },
+            "is_delete": bson.M{
+                "bsonType":    []string{"date", "null"}, // Allow null values
+                "description": "soft delete timestamp",
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "updated_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+var pekerjaanIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "alumni_id", Value: 1}},
+        Options: options.Index().SetName("idx_alumni_id"),
+    },
+    {
+        Keys:    bson.D{{Key: "nama_perusahaan", Value: 1}},
+        Options: options.Index().SetName("idx_nama_perusahaan"),
+    },
+    {
+        Keys:    bson.D{{Key: "company_id", Value: 1}},
+        Options: options.Index().SetName("idx_company_id"),
+    },
+    {
+        Keys:    bson.D{{Key: "bidang_industri", Value: 1}},
+        Options: options.Index().SetName("idx_bidang_industri"),
+    },
+    {
+        Keys:    bson.D{{Key: "status_pekerjaan", Value: 1}},
+        Options: options.Index().SetName("idx_status_pekerjaan"),
+    },
+    {
+        Keys:    bson.D{{Key: "is_delete", Value: 1}},
+        Options: options.Index().SetName("idx_is_delete"),
+    },
+    {
+        Keys:    bson.D{{Key: "status_moderasi", Value: 1}},
+        Options: options.Index().SetName("idx_status_moderasi"),
+    },
+    {
+        Keys:    bson.D{{Key: "tanggal_mulai_kerja", Value: -1}},
+        Options: options.Index().SetName("idx_tanggal_mulai_kerja"),
+    },
+    {
+        Keys: bson.D{
+            {Key: "gaji_min", Value: 1},
+            {Key: "gaji_max", Value: 1},
+        },
+        Options: options.Index().SetName("idx_gaji"),
+    },
+    {
+        Keys: bson.D{
+            {Key: "nama_perusahaan", Value: "text"},
+            {Key: "posisi_jabatan", Value: "text"},
+            {Key: "lokasi_kerja", Value: "text"},
+        },
+        Options: options.Index().SetName("idx_pekerjaan_text_search"),
+    },
+    // Compound index for common queries
+    {
+        Keys: bson.D{
+            {Key: "alumni_id", Value: 1},
+            {Key: "is_delete", Value: 1},
+        },
+        Options: options.Index().SetName("idx_alumni_active"),
+    },
+}
+
+// rateLimitsValidator is the $jsonSchema of the rate limit buckets written by
+// ratelimit.MongoStore; _id is the bucket key
+var rateLimitsValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"tokens", "allowed", "updated_at", "expires_at"},
+        "properties": bson.M{
+            "tokens": bson.M{
+                "bsonType":    "double",
+                "description": "tokens left in the bucket",
+            },
+            "allowed": bson.M{
+                "bsonType":    "bool",
+                "description": "whether the last request was allowed",
+            },
+            "updated_at": bson.M{
+                "bsonType":    "date",
+                "description": "last refill",
+            },
+            "expires_at": bson.M{
+                "bsonType":    "date",
+                "description": "when the bucket is full again and may be removed",
+            },
+        },
+    },
+}
+
+var rateLimitsIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "expires_at", Value: 1}},
+        Options: options.Index().SetName("idx_rate_limits_ttl").SetExpireAfterSeconds(0),
+    },
+}
+
+// cacheValidator is the $jsonSchema of the values written by
+// cache.MongoStore; _id is the cache key
+var cacheValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"value"},
+        "properties": bson.M{
+            "value": bson.M{
+                "bsonType":    "binData",
+                "description": "cached value, JSON encoded",
+            },
+            "expires_at": bson.M{
+                "bsonType":    "date",
+                "description": "when the value may be removed; absent for namespace versions",
+            },
+        },
+    },
+}
+
+var cacheIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "expires_at", Value: 1}},
+        Options: options.Index().SetName("idx_cache_ttl").SetExpireAfterSeconds(0),
+    },
+}
+
+// companiesValidator is the $jsonSchema of the companies registry
+var companiesValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"nama", "nama_key", "aliases", "alias_keys", "created_at", "updated_at"},
+        "properties": bson.M{
+            "nama": bson.M{
+                "bsonType":    "string",
+                "description": "canonical company name, must be a string and is required",
+                "minLength":   1,
+                "maxLength":   200,
+            },
+            "nama_key": bson.M{
+                "bsonType":    "string",
+                "description": "normalized nama, must be a string and is required",
+                "minLength":   1,
+            },
+            "aliases": bson.M{
+                "bsonType":    "array",
+                "description": "other names of the company",
+                "items":       bson.M{"bsonType": "string"},
+            },
+            "alias_keys": bson.M{
+                "bsonType":    "array",
+                "description": "normalized aliases",
+                "items":       bson.M{"bsonType": "string"},
+            },
+            "bidang_industri": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string",
+            },
+            "lokasi": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string",
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+            "updated_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+var companiesIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "nama_key", Value: 1}},
+        Options: options.Index().SetUnique(true).SetName("idx_company_nama_key"),
+    },
+    {
+        Keys:    bson.D{{Key: "alias_keys", Value: 1}},
+        Options: options.Index().SetName("idx_company_alias_keys"),
+    },
+    {
+        Keys:    bson.D{{Key: "nama", Value: 1}},
+        Options: options.Index().SetName("idx_company_nama"),
+    },
+}
+
+// reportsValidator is the $jsonSchema of the report jobs
+var reportsValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"jurusan", "tahun_lulus", "formats", "status", "created_at"},
+        "properties": bson.M{
+            "jurusan": bson.M{
+                "bsonType":    "string",
+                "description": "must be a string and is required",
+            },
+            "tahun_lulus": bson.M{
+                "bsonType":    "int",
+                "description": "must be an integer and is required",
+                "minimum":     1900,
+                "maximum":     2100,
+            },
+            "formats": bson.M{
+                "bsonType":    "array",
+                "description": "formats to generate",
+                "items":       bson.M{"enum": []string{"pdf", "xlsx"}},
+            },
+            "status": bson.M{
+                "bsonType":    "string",
+                "description": "must be pending, running, done or failed",
+                "enum":        []string{"pending", "running", "done", "failed"},
+            },
+            "created_at": bson.M{
+                "bsonType":    "date",
+                "description": "must be a date and is required",
+            },
+        },
+    },
+}
+
+var reportsIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "created_at", Value: -1}},
+        Options: options.Index().SetName("idx_report_created_at"),
+    },
+    {
+        Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+        Options: options.Index().SetName("idx_report_status"),
+    },
+}
+
+// reportFilesValidator is the $jsonSchema of the generated report files
+var reportFilesValidator = bson.M{
+    "$jsonSchema": bson.M{
+        "bsonType": "object",
+        "required": []string{"report_id", "format", "data"},
+        "properties": bson.M{
+            "report_id": bson.M{
+                "bsonType":    "objectId",
+                "description": "the report the file belongs to",
+            },
+            "format": bson.M{
+                "bsonType":    "string",
+                "description": "must be pdf or xlsx",
+                "enum":        []string{"pdf", "xlsx"},
+            },
+            "data": bson.M{
+                "bsonType":    "binData",
+                "description": "the file content",
+            },
+        },
+    },
+}
+
+var reportFilesIndexes = []mongo.IndexModel{
+    {
+        Keys:    bson.D{{Key: "report_id", Value: 1}, {Key: "format", Value: 1}},
+        Options: options.Index().SetUnique(true).SetName("idx_report_file"),
+    },
+}
@@ -0,0 +1,342 @@
+package database
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "reflect"
+    "sort"
+    "strings"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// Drift kinds reported by DiffSchema
+const (
+    DriftMissingCollection = "missing_collection"
+    DriftValidator         = "validator"
+    DriftMissingIndex      = "missing_index"
+    DriftChangedIndex      = "changed_index"
+    DriftExtraIndex        = "extra_index"
+)
+
+// SchemaDrift is one difference between Collections and the live database
+type SchemaDrift struct {
+    Collection string
+    Kind       string
+    Index      string
+    Detail     string
+}
+
+func (d SchemaDrift) String() string {
+    s := d.Collection + ": " + d.Kind
+    if d.Index != "" {
+        s += " " + d.Index
+    }
+    if d.Detail != "" {
+        s += " (" + d.Detail + ")"
+    }
+    return s
+}
+
+// liveIndex is one entry of listIndexes
+type liveIndex struct {
+    Name    string `bson:"name"`
+    Key     bson.D `bson:"key"`
+    Unique  bool   `bson:"unique"`
+    Weights bson.M `bson:"weights"`
+    TTL     *int32 `bson:"expireAfterSeconds"`
+}
+
+// DiffSchema compares the live database with Collections. Indexes are
+// matched by name; a text index is compared by its fields since the server
+// stores its key as _fts/_ftsx.
+func DiffSchema(ctx context.Context, db *mongo.Database) ([]SchemaDrift, error) {
+    specs, err := db.ListCollectionSpecifications(ctx, bson.M{})
+    if err != nil {
+        return nil, err
+    }
+    live := map[string]*mongo.CollectionSpecification{}
+    for _, spec := range specs {
+        live[spec.Name] = spec
+    }
+
+    var drift []SchemaDrift
+    for _, schema := range Collections {
+        spec, ok := live[schema.Name]
+        if !ok {
+            drift = append(drift, SchemaDrift{Collection: schema.Name, Kind: DriftMissingCollection})
+            continue
+        }
+
+        var options struct {
+            Validator bson.M `bson:"validator"`
+        }
+        if len(spec.Options) > 0 {
+            if err := bson.Unmarshal(spec.Options, &options); err != nil {
+                return nil, err
+            }
+        }
+        if !sameDocument(options.Validator, schema.Validator) {
+            drift = append(drift, SchemaDrift{Collection: schema.Name, Kind: DriftValidator})
+        }
+
+        indexDrift, err := diffIndexes(ctx, db.Collection(schema.Name), schema)
+        if err != nil {
+            return nil, err
+        }
+        drift = append(drift, indexDrift...)
+    }
+    return drift, nil
+}
+
+func diffIndexes(ctx context.Context, coll *mongo.Collection, schema CollectionSchema) ([]SchemaDrift, error) {
+    cursor, err := coll.Indexes().List(ctx)
+    if err != nil {
+        return nil, err
+    }
+    var indexes []liveIndex
+    if err := cursor.All(ctx, &indexes); err != nil {
+        return nil, err
+    }
+
+    live := map[string]liveIndex{}
+    for _, index := range indexes {
+        live[index.Name] = index
+    }
+
+    var drift []SchemaDrift
+    declared := map[string]bool{"_id_": true}
+    for _, model := range schema.Indexes {
+        name := *model.Options.Name
+        declared[name] = true
+
+        index, ok := live[name]
+        if !ok {
+            drift = append(drift, SchemaDrift{Collection: schema.Name, Kind: DriftMissingIndex, Index: name})
+            continue
+        }
+        if detail := indexDifference(model, index); detail != "" {
+            drift = append(drift, SchemaDrift{Collection: schema.Name, Kind: DriftChangedIndex, Index: name, Detail: detail})
+        }
+    }
+
+    var extra []string
+    for name := range live {
+        if !declared[name] {
+            extra = append(extra, name)
+        }
+    }
+    sort.Strings(extra)
+    for _, name := range extra {
+        drift = append(drift, SchemaDrift{Collection: schema.Name, Kind: DriftExtraIndex, Index: name})
+    }
+    return drift, nil
+}
+
+// indexDifference describes how the live index differs from the model, or
+// returns "" when they match
+func indexDifference(model mongo.IndexModel, index liveIndex) string {
+    keys := model.Keys.(bson.D)
+
+    var textFields []string
+    for _, key := range keys {
+        if key.Value == "text" {
+            textFields = append(textFields, key.Key)
+        }
+    }
+
+    if len(textFields) > 0 {
+        var liveFields []string
+        for field := range index.Weights {
+            liveFields = append(liveFields, field)
+        }
+        sort.Strings(textFields)
+        sort.Strings(liveFields)
+        if !reflect.DeepEqual(textFields, liveFields) {
+            return "text fields " + strings.Join(liveFields, ",")
+        }
+    } else if !sameKeys(keys, index.Key) {
+        return "keys"
+    }
+
+    unique := model.Options.Unique != nil && *model.Options.Unique
+    if unique != index.Unique {
+        return fmt.Sprintf("unique=%t", index.Unique)
+    }
+
+    declaredTTL := model.Options.ExpireAfterSeconds
+    if (declaredTTL == nil) != (index.TTL == nil) || (declaredTTL != nil && *declaredTTL != *index.TTL) {
+        if index.TTL == nil {
+            return "no expireAfterSeconds"
+        }
+        return fmt.Sprintf("expireAfterSeconds=%d", *index.TTL)
+    }
+    return ""
+}
+
+// sameKeys compares index keys in order, treating 1, int64(1) and 1.0 alike
+func sameKeys(a, b bson.D) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i].Key != b[i].Key {
+            return false
+        }
+        x, xok := keyDirection(a[i].Value)
+        y, yok := keyDirection(b[i].Value)
+        if xok != yok || (xok && x != y) || (!xok && a[i].Value != b[i].Value) {
+            return false
+        }
+    }
+    return true
+}
+
+func keyDirection(v interface{}) (float64, bool) {
+    switch n := v.(type) {
+    case int:
+        return float64(n), true
+    case int32:
+        return float64(n), true
+    case int64:
+        return float64(n), true
+    case float64:
+        return n, true
+    }
+    return 0, false
+}
+
+// sameDocument compares two documents after a BSON round trip, so Go types
+// such as []string and int match what the server returns
+func sameDocument(a, b interface{}) bool {
+    return reflect.DeepEqual(normalizeDocument(a), normalizeDocument(b))
+}
+
+func normalizeDocument(v interface{}) interface{} {
+    if m, ok := v.(bson.M); v == nil || (ok && m == nil) {
+        return nil
+    }
+    raw, err := bson.Marshal(bson.M{"v": v})
+    if err != nil {
+        return v
+    }
+    var doc bson.M
+    if err := bson.Unmarshal(raw, &doc); err != nil {
+        return v
+    }
+    return doc["v"]
+}
+
+// SyncSchema reconciles the live database with Collections: it creates
+// missing collections, updates validators with collMod, creates missing
+// indexes, recreates changed ones and drops undeclared ones. It returns the
+// drift it fixed.
+func SyncSchema(ctx context.Context, db *mongo.Database) ([]SchemaDrift, error) {
+    drift, err := DiffSchema(ctx, db)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, d := range drift {
+        schema, _ := collectionSchema(d.Collection)
+        coll := db.Collection(d.Collection)
+
+        switch d.Kind {
+        case DriftMissingCollection:
+            if err := createCollection(schema)(ctx, db); err != nil {
+                return nil, err
+            }
+            if len(schema.Indexes) > 0 {
+                if _, err := coll.Indexes().CreateMany(ctx, schema.Indexes); err != nil {
+                    return nil, err
+                }
+            }
+        case DriftValidator:
+            err = setValidator(ctx, db, d.Collection, schema.Validator)
+        case DriftMissingIndex:
+            _, err = coll.Indexes().CreateOne(ctx, declaredIndex(schema, d.Index))
+        case DriftChangedIndex:
+            if err = dropIndex(ctx, coll, d.Index); err == nil {
+                _, err = coll.Indexes().CreateOne(ctx, declaredIndex(schema, d.Index))
+            }
+        case DriftExtraIndex:
+            err = dropIndex(ctx, coll, d.Index)
+        }
+        if err != nil {
+            return nil, fmt.Errorf("%s: %w", d, err)
+        }
+        log.Printf("  ✓ Fixed %s", d)
+    }
+    return drift, nil
+}
+
+// ReconcileSchema logs the drift between the live database and Collections,
+// fixing it when apply is set
+func ReconcileSchema(ctx context.Context, db *mongo.Database, apply bool) error {
+    if apply {
+        drift, err := SyncSchema(ctx, db)
+        if err != nil {
+            return err
+        }
+        if len(drift) > 0 {
+            log.Printf("✅ Schema synced, %d change(s) applied", len(drift))
+        }
+        return nil
+    }
+
+    drift, err := DiffSchema(ctx, db)
+    if err != nil {
+        return err
+    }
+    for _, d := range drift {
+        log.Printf("⚠️  Schema drift: %s", d)
+    }
+    if len(drift) > 0 {
+        log.Println("⚠️  Run \"schema sync\" or set SCHEMA_SYNC=true to fix the drift")
+    }
+    return nil
+}
+
+// SchemaReady returns the readiness check of the validators and indexes: it
+// fails while they differ from Collections, like Migrator.Ready does for
+// pending migrations
+func SchemaReady(db *mongo.Database) func(context.Context) error {
+    return func(ctx context.Context) error {
+        drift, err := DiffSchema(ctx, db)
+        if err != nil {
+            return err
+        }
+        if len(drift) == 0 {
+            return nil
+        }
+        list := make([]string, len(drift))
+        for i, d := range drift {
+            list[i] = d.String()
+        }
+        return fmt.Errorf("schema drift: %s", strings.Join(list, "; "))
+    }
+}
+
+func declaredIndex(schema CollectionSchema, name string) mongo.IndexModel {
+    for _, model := range schema.Indexes {
+        if *model.Options.Name == name {
+            return model
+        }
+    }
+    return mongo.IndexModel{}
+}
+
+// dropIndex drops an index, skipping indexes or collections that are gone
+func dropIndex(ctx context.Context, coll *mongo.Collection, name string) error {
+    _, err := coll.Indexes().DropOne(ctx, name)
+
+    // NamespaceNotFound, IndexNotFound
+    var cmdErr mongo.CommandError
+    if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) {
+        return nil
+    }
+    return err
+}
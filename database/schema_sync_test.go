@@ -0,0 +1,66 @@
+package database
+
+import (
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSameDocumentMatchesServerTypes(t *testing.T) {
+    // What the server returns for usersValidator: arrays as bson.A, ints as int32
+    live := bson.M{}
+    raw, _ := bson.Marshal(usersValidator)
+    if err := bson.Unmarshal(raw, &live); err != nil {
+        t.Fatal(err)
+    }
+
+    if !sameDocument(live, usersValidator) {
+        t.Fatal("round-tripped validator should match the declaration")
+    }
+    if sameDocument(nil, usersValidator) {
+        t.Fatal("missing validator should not match")
+    }
+    if sameDocument(alumniValidator, usersValidator) {
+        t.Fatal("different validators should not match")
+    }
+}
+
+func TestIndexDifference(t *testing.T) {
+    schema, _ := collectionSchema(AlumniCollection)
+    nim := declaredIndex(schema, "idx_nim")
+    text := declaredIndex(schema, "idx_text_search")
+    rateLimits, _ := collectionSchema(RateLimitsCollection)
+    ttl := declaredIndex(rateLimits, "idx_rate_limits_ttl")
+    zero, hour := int32(0), int32(3600)
+
+    tests := []struct {
+        name  string
+        index liveIndex
+        model string
+        want  string
+    }{
+        {"same", liveIndex{Key: bson.D{{Key: "nim", Value: int32(1)}}, Unique: true}, "nim", ""},
+        {"double direction", liveIndex{Key: bson.D{{Key: "nim", Value: 1.0}}, Unique: true}, "nim", ""},
+        {"not unique", liveIndex{Key: bson.D{{Key: "nim", Value: int32(1)}}}, "nim", "unique=false"},
+        {"descending", liveIndex{Key: bson.D{{Key: "nim", Value: int32(-1)}}, Unique: true}, "nim", "keys"},
+        {"text", liveIndex{Key: bson.D{{Key: "_fts", Value: "text"}}, Weights: bson.M{"nama": 1, "nim": 1, "email": 1}}, "text", ""},
+        {"text fields", liveIndex{Key: bson.D{{Key: "_fts", Value: "text"}}, Weights: bson.M{"nama": 1}}, "text", "text fields nama"},
+        {"ttl", liveIndex{Key: bson.D{{Key: "expires_at", Value: int32(1)}}, TTL: &zero}, "ttl", ""},
+        {"ttl changed", liveIndex{Key: bson.D{{Key: "expires_at", Value: int32(1)}}, TTL: &hour}, "ttl", "expireAfterSeconds=3600"},
+        {"ttl missing", liveIndex{Key: bson.D{{Key: "expires_at", Value: int32(1)}}}, "ttl", "no expireAfterSeconds"},
+        {"ttl added", liveIndex{Key: bson.D{{Key: "nim", Value: int32(1)}}, Unique: true, TTL: &zero}, "nim", "expireAfterSeconds=0"},
+    }
+
+    for _, tt := range tests {
+        model := nim
+        switch tt.model {
+        case "text":
+            model = text
+        case "ttl":
+            model = ttl
+        }
+        if got := indexDifference(model, tt.index); got != tt.want {
+            t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
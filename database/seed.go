@@ -5,6 +5,7 @@ import (
     "log"
     "time"
 
+    "go-fiber/app/model"
     "go-fiber/utils"
 
     "go.mongodb.org/mongo-driver/bson"
@@ -338,7 +339,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Backend Developer",
             "bidang_industri":       "Technology",
             "lokasi_kerja":          "Jakarta",
-            "gaji_range":            "8-12 juta",
+            "gaji_min":              int64(8_000_000),
+            "gaji_max":              int64(12_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   twoYearsAgo,
             "tanggal_selesai_kerja": oneYearAgo,
             "status_pekerjaan":      "resign",
@@ -353,7 +357,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Senior Backend Engineer",
             "bidang_industri":       "Financial Technology",
             "lokasi_kerja":          "Jakarta",
-            "gaji_range":            "15-20 juta",
+            "gaji_min":              int64(15_000_000),
+            "gaji_max":              int64(20_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   oneYearAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -370,7 +377,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Business Analyst",
             "bidang_industri":       "Consulting",
             "lokasi_kerja":          "Bandung",
-            "gaji_range":            "10-15 juta",
+            "gaji_min":              int64(10_000_000),
+            "gaji_max":              int64(15_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   sixMonthsAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -387,7 +397,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Junior Programmer",
             "bidang_industri":       "Manufacturing",
             "lokasi_kerja":          "Surabaya",
-            "gaji_range":            "5-7 juta",
+            "gaji_min":              int64(5_000_000),
+            "gaji_max":              int64(7_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   twoYearsAgo.AddDate(-1, 0, 0),
             "tanggal_selesai_kerja": twoYearsAgo,
             "status_pekerjaan":      "resign",
@@ -402,7 +415,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Full Stack Developer",
             "bidang_industri":       "E-commerce",
             "lokasi_kerja":          "Jakarta",
-            "gaji_range":            "10-15 juta",
+            "gaji_min":              int64(10_000_000),
+            "gaji_max":              int64(15_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   twoYearsAgo,
             "tanggal_selesai_kerja": sixMonthsAgo,
             "status_pekerjaan":      "kontrak_habis",
@@ -417,7 +433,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Tech Lead",
             "bidang_industri":       "Information Technology",
             "lokasi_kerja":          "Jakarta",
-            "gaji_range":            "18-25 juta",
+            "gaji_min":              int64(18_000_000),
+            "gaji_max":              int64(25_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   sixMonthsAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -434,7 +453,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Network Engineer",
             "bidang_industri":       "Telecommunications",
             "lokasi_kerja":          "Medan",
-            "gaji_range":            "8-12 juta",
+            "gaji_min":              int64(8_000_000),
+            "gaji_max":              int64(12_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   oneYearAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -451,7 +473,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Data Analyst",
             "bidang_industri":       "Banking",
             "lokasi_kerja":          "Semarang",
-            "gaji_range":            "9-13 juta",
+            "gaji_min":              int64(9_000_000),
+            "gaji_max":              int64(13_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   threeMonthsAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -468,7 +493,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Frontend Developer",
             "bidang_industri":       "Technology",
             "lokasi_kerja":          "Yogyakarta",
-            "gaji_range":            "7-10 juta",
+            "gaji_min":              int64(7_000_000),
+            "gaji_max":              int64(10_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   threeMonthsAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -485,7 +513,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Security Analyst",
             "bidang_industri":       "Cybersecurity",
             "lokasi_kerja":          "Makassar",
-            "gaji_range":            "12-17 juta",
+            "gaji_min":              int64(12_000_000),
+            "gaji_max":              int64(17_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   oneYearAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -502,7 +533,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Product Manager",
             "bidang_industri":       "Media",
             "lokasi_kerja":          "Denpasar",
-            "gaji_range":            "11-16 juta",
+            "gaji_min":              int64(11_000_000),
+            "gaji_max":              int64(16_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   sixMonthsAgo,
             "tanggal_selesai_kerja": nil,
             "status_pekerjaan":      "aktif",
@@ -519,7 +553,10 @@ func seedPekerjaan(ctx context.Context, db *mongo.Database, alumniIDs []primitiv
             "posisi_jabatan":        "Junior Developer",
             "bidang_industri":       "Technology",
             "lokasi_kerja":          "Jakarta",
-            "gaji_range":            "5-7 juta",
+            "gaji_min":              int64(5_000_000),
+            "gaji_max":              int64(7_000_000),
+            "gaji_currency":         model.CurrencyIDR,
+            "gaji_period":           model.GajiMonthly,
             "tanggal_mulai_kerja":   twoYearsAgo.AddDate(-2, 0, 0),
             "tanggal_selesai_kerja": twoYearsAgo.AddDate(-1, 0, 0),
             "status_pekerjaan":      "resign",
@@ -585,6 +622,12 @@ func SeedSummary(db *mongo.Database) error {
     log.Printf("    - Active: %d", pekerjaanActive)
     log.Printf("    - Soft Deleted: %d", pekerjaanDeleted)
 
+    // Count companies and the pekerjaan linked to them
+    companyCount, _ := db.Collection(CompaniesCollection).CountDocuments(ctx, bson.M{})
+    pekerjaanLinked, _ := db.Collection(PekerjaanCollection).CountDocuments(ctx, bson.M{"company_id": bson.M{"$ne": nil}})
+    log.Printf("  Companies: %d", companyCount)
+    log.Printf("    - Linked Pekerjaan: %d", pekerjaanLinked)
+
     log.Println("==========================================")
 
     return nil
@@ -0,0 +1,287 @@
+package docs
+
+import (
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+
+    "go-fiber/app/model"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// Schema is a JSON object inside the OpenAPI document
+type Schema map[string]interface{}
+
+var datatableParams = []Schema{
+    queryParam("page", Schema{"type": "integer", "minimum": 1, "default": 1}, "Page number"),
+    queryParam("limit", Schema{"type": "integer", "minimum": 1, "default": 10}, "Items per page"),
+    queryParam("search", Schema{"type": "string"}, "Case-insensitive search term"),
+    queryParam("sortBy", Schema{"type": "string"}, "Field to sort by"),
+    queryParam("order", Schema{"type": "string", "enum": []string{"asc", "desc"}}, "Sort direction"),
+}
+
+func queryParam(name string, schema Schema, description string) Schema {
+    return Schema{"name": name, "in": "query", "schema": schema, "description": description}
+}
+
+// Build generates the OpenAPI 3.1 document for the registered routes.
+// Routes without a matching entry in Operations are left out; use
+// Undocumented to find them.
+func Build(routes []fiber.Route) Schema {
+    registry := newSchemaRegistry()
+    registered := routeKeys(routes)
+
+    registry.schemas["ErrorResponse"] = Schema{
+        "type": "object",
+        "properties": Schema{
+            "success": Schema{"type": "boolean", "const": false},
+            "error":   Schema{"type": "string"},
+            "message": Schema{"type": "string"},
+        },
+        "required": []string{"success"},
+    }
+
+    paths := Schema{}
+    for _, op := range Operations {
+        if !registered[op.key()] {
+            continue
+        }
+
+        path := openAPIPath(op.Path)
+        item, ok := paths[path].(Schema)
+        if !ok {
+            item = Schema{}
+            paths[path] = item
+        }
+        item[strings.ToLower(op.Method)] = op.build(registry)
+    }
+
+    return Schema{
+        "openapi": "3.1.0",
+        "info": Schema{
+            "title":       "Alumni API",
+            "version":     "1.0.0",
+            "description": "Alumni and pekerjaan alumni management API",
+        },
+        "paths": paths,
+        "components": Schema{
+            "schemas": registry.schemas,
+            "securitySchemes": Schema{
+                "bearerAuth": Schema{
+                    "type":         "http",
+                    "scheme":       "bearer",
+                    "bearerFormat": "JWT",
+                },
+            },
+        },
+    }
+}
+
+// Undocumented returns "METHOD /path" for every route without an Operation
+func Undocumented(routes []fiber.Route) []string {
+    documented := map[string]bool{}
+    for _, op := range Operations {
+        documented[op.key()] = true
+    }
+
+    var missing []string
+    for key := range routeKeys(routes) {
+        if !documented[key] {
+            missing = append(missing, key)
+        }
+    }
+    sort.Strings(missing)
+    return missing
+}
+
+// Unregistered returns "METHOD /path" for every Operation without a route
+func Unregistered(routes []fiber.Route) []string {
+    registered := routeKeys(routes)
+
+    var stale []string
+    for _, op := range Operations {
+        if !registered[op.key()] {
+            stale = append(stale, op.key())
+        }
+    }
+    return stale
+}
+
+// routeKeys indexes the routes by method and normalized path, skipping the
+// HEAD routes Fiber adds for every GET. Pass app.GetRoutes(true) so group
+// middleware is left out.
+func routeKeys(routes []fiber.Route) map[string]bool {
+    keys := map[string]bool{}
+    for _, route := range routes {
+        if route.Method == fiber.MethodHead {
+            continue
+        }
+        keys[route.Method+" "+normalizePath(route.Path)] = true
+    }
+    return keys
+}
+
+func normalizePath(path string) string {
+    if len(path) > 1 {
+        path = strings.TrimSuffix(path, "/")
+    }
+    return path
+}
+
+// openAPIPath converts /alumni/:id into /alumni/{id}
+func openAPIPath(path string) string {
+    segments := strings.Split(normalizePath(path), "/")
+    for i, segment := range segments {
+        if strings.HasPrefix(segment, ":") {
+            segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+        }
+    }
+    return strings.Join(segments, "/")
+}
+
+func (op Operation) key() string {
+    return op.Method + " " + normalizePath(op.Path)
+}
+
+func (op Operation) build(registry *schemaRegistry) Schema {
+    operation := Schema{
+        "tags":        []string{op.Tag},
+        "summary":     op.Summary,
+        "operationId": operationID(op),
+    }
+
+    var params []Schema
+    for _, segment := range strings.Split(op.Path, "/") {
+        if strings.HasPrefix(segment, ":") {
+            params = append(params, Schema{
+                "name":     strings.TrimPrefix(segment, ":"),
+                "in":       "path",
+                "required": true,
+                "schema":   Schema{"type": "string", "pattern": "^[a-f0-9]{24}$"},
+            })
+        }
+    }
+    if op.Datatable {
+        params = append(params, datatableParams...)
+    }
+    params = append(params, op.Query...)
+    if len(params) > 0 {
+        operation["parameters"] = params
+    }
+
+    if op.Request != nil {
+        operation["requestBody"] = Schema{
+            "required": true,
+            "content": Schema{
+                "application/json": Schema{"schema": registry.ref(op.Request)},
+            },
+        }
+    }
+
+    responses := Schema{
+        strconv.Itoa(op.statusOrDefault()): op.successResponse(registry),
+    }
+
+    errorRef := Schema{"$ref": "#/components/schemas/ErrorResponse"}
+    errorResponse := func(description string) Schema {
+        return Schema{
+            "description": description,
+            "content":     Schema{"application/json": Schema{"schema": errorRef}},
+        }
+    }
+
+    if op.Request != nil || strings.Contains(op.Path, ":") {
+        responses["400"] = errorResponse("Invalid input or id")
+    }
+    if op.Request != nil {
+        responses["413"] = errorResponse("Request body over the route's limit")
+    }
+    if op.Auth {
+        operation["security"] = []Schema{{"bearerAuth": []string{}}}
+        responses["401"] = errorResponse("Missing, malformed or expired token")
+    }
+    if op.AdminOnly {
+        responses["403"] = errorResponse("Admin only")
+    }
+    if strings.Contains(op.Path, ":") && op.Method != fiber.MethodGet {
+        responses["404"] = errorResponse("Data not found or not owned by the caller")
+    }
+    if op.Method == fiber.MethodGet && (op.Tag == "alumni" || op.Tag == "pekerjaan" || op.Tag == "users" || op.Tag == "companies" || op.Tag == "stats") {
+        responses["304"] = Schema{"description": "Unchanged since the If-None-Match ETag or If-Modified-Since date"}
+    }
+    if op.Tag == "auth" || op.Tag == "alumni" || op.Tag == "pekerjaan" || op.Tag == "users" || op.Tag == "companies" || op.Tag == "stats" || op.Tag == "reports" {
+        responses["429"] = errorResponse("Rate limit exceeded, see Retry-After")
+    }
+    if op.Produces == "" && op.Tag != "docs" {
+        responses["500"] = errorResponse("Database error")
+    }
+
+    operation["responses"] = responses
+    return operation
+}
+
+func (op Operation) successResponse(registry *schemaRegistry) Schema {
+    response := Schema{"description": http.StatusText(op.statusOrDefault())}
+
+    if op.Produces != "" {
+        response["content"] = Schema{op.Produces: Schema{"schema": Schema{"type": "string"}}}
+        return response
+    }
+
+    var schema Schema
+    if op.Raw != nil {
+        schema = registry.ref(op.Raw)
+    } else {
+        properties := Schema{
+            "message": Schema{"type": "string"},
+            "success": Schema{"type": "boolean", "const": true},
+        }
+        required := []string{"message", "success"}
+
+        if op.Data != nil {
+            properties["data"] = registry.ref(op.Data)
+            required = append(required, "data")
+        }
+        if op.Meta {
+            properties["meta"] = registry.ref(model.MetaInfo{})
+            required = append(required, "meta")
+        }
+
+        schema = Schema{"type": "object", "properties": properties, "required": required}
+    }
+
+    response["content"] = Schema{"application/json": Schema{"schema": schema}}
+    return response
+}
+
+func (op Operation) statusOrDefault() int {
+    if op.Status == 0 {
+        return http.StatusOK
+    }
+    return op.Status
+}
+
+// operationID builds a stable identifier such as getAlumniStatsJurusan
+func operationID(op Operation) string {
+    var b strings.Builder
+    b.WriteString(strings.ToLower(op.Method))
+
+    for _, segment := range strings.FieldsFunc(op.Path, func(r rune) bool {
+        return r == '/' || r == '_' || r == '.' || r == '-'
+    }) {
+        if strings.HasPrefix(segment, ":") {
+            segment = "by" + capitalize(strings.TrimPrefix(segment, ":"))
+        }
+        b.WriteString(capitalize(segment))
+    }
+    return b.String()
+}
+
+func capitalize(s string) string {
+    if s == "" {
+        return s
+    }
+    return strings.ToUpper(s[:1]) + s[1:]
+}
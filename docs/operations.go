@@ -0,0 +1,494 @@
+package docs
+
+import "go-fiber/app/model"
+
+// Operation describes a single registered route for the OpenAPI document.
+// Every route registered in routes.RegisterRoutes must have a matching entry.
+type Operation struct {
+    Method    string
+    Path      string // Fiber path, e.g. /alumni/:id
+    Tag       string
+    Summary   string
+    Auth      bool // Requires a bearer token
+    AdminOnly bool // Requires the admin role
+    Datatable bool // Accepts page, limit, search, sortBy and order query params
+    Query     []Schema // Further query params, built with queryParam
+    Request   interface{}
+    Status    int         // Success status, defaults to 200
+    Data      interface{} // Payload returned under "data" in the standard envelope
+    Meta      bool        // Envelope also carries pagination "meta"
+    Raw       interface{} // Response body returned as-is instead of the envelope
+    Produces  string      // Content type for non-JSON responses
+}
+
+// cohortQuery are the filters of the /stats endpoints
+var cohortQuery = []Schema{
+    queryParam("jurusan", Schema{"type": "string"}, "Only this jurusan"),
+    queryParam("angkatan", Schema{"type": "integer", "minimum": 1}, "Only this angkatan"),
+    queryParam("tahun_lulus_from", Schema{"type": "integer", "minimum": 1}, "First tahun_lulus included"),
+    queryParam("tahun_lulus_to", Schema{"type": "integer", "minimum": 1}, "Last tahun_lulus included"),
+}
+
+// Operations lists the documented API surface
+var Operations = []Operation{
+    // Auth
+    {
+        Method:  "POST",
+        Path:    "/auth/login",
+        Tag:     "auth",
+        Summary: "Login with username or email and receive a JWT",
+        Request: model.LoginRequest{},
+        Data:    model.LoginResponse{},
+    },
+
+    // Alumni
+    {
+        Method:    "GET",
+        Path:      "/alumni",
+        Tag:       "alumni",
+        Summary:   "List alumni with paging, search and sorting",
+        Auth:      true,
+        Datatable: true,
+        Data:      []model.AlumniResponse{},
+        Meta:      true,
+    },
+    {
+        Method:    "POST",
+        Path:      "/alumni",
+        Tag:       "alumni",
+        Summary:   "Create alumni",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.CreateAlumniRequest{},
+        Status:    201,
+        Data:      model.AlumniResponse{},
+    },
+    {
+        Method:    "PUT",
+        Path:      "/alumni/:id",
+        Tag:       "alumni",
+        Summary:   "Update alumni",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.UpdateAlumniRequest{},
+        Data:      model.AlumniResponse{},
+    },
+    {
+        Method:    "DELETE",
+        Path:      "/alumni/:id",
+        Tag:       "alumni",
+        Summary:   "Delete alumni",
+        Auth:      true,
+        AdminOnly: true,
+    },
+    {
+        Method:    "POST",
+        Path:      "/alumni/bulk",
+        Tag:       "alumni",
+        Summary:   "Run up to 500 create, update and delete operations in one bulk write; ordered batches stop at the first failure",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.AlumniBulkRequest{},
+        Data:      model.BulkResponse{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/alumni/stats/jurusan",
+        Tag:     "alumni",
+        Summary: "Count alumni per jurusan",
+        Auth:    true,
+        Data:    []model.AlumniStatsByJurusanResponse{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/alumni/:id/timeline",
+        Tag:     "alumni",
+        Summary: "Career history of one alumni with the gaps between pekerjaan (admin or the alumni's own account)",
+        Auth:    true,
+        Data:    model.AlumniTimelineResponse{},
+    },
+
+    // Pekerjaan
+    {
+        Method:    "GET",
+        Path:      "/pekerjaan",
+        Tag:       "pekerjaan",
+        Summary:   "List active, approved pekerjaan with paging, search, salary filters and sorting",
+        Auth:      true,
+        Datatable: true,
+        Query:     []Schema{
+            queryParam("gaji_min", Schema{"type": "integer", "minimum": 0}, "Only pekerjaan whose salary range reaches this amount"),
+            queryParam("gaji_max", Schema{"type": "integer", "minimum": 0}, "Only pekerjaan whose salary range starts at or below this amount"),
+            queryParam("gaji_currency", Schema{"type": "string", "pattern": "^[A-Za-z]{3}$"}, "ISO 4217 currency of the salary"),
+            queryParam("gaji_period", Schema{"type": "string", "enum": []string{model.GajiMonthly, model.GajiAnnual}}, "Salary period"),
+        },
+        Data:      []model.PekerjaanResponse{},
+        Meta:      true,
+    },
+    {
+        Method:    "GET",
+        Path:      "/pekerjaan/alumni/:alumni_id",
+        Tag:       "pekerjaan",
+        Summary:   "List active pekerjaan of one alumni",
+        Auth:      true,
+        AdminOnly: true,
+        Data:      []model.PekerjaanResponse{},
+    },
+    {
+        Method:  "POST",
+        Path:    "/pekerjaan",
+        Tag:     "pekerjaan",
+        Summary: "Create pekerjaan; alumni submit for their own record and wait for approval; 409 when it overlaps another pekerjaan of the alumni",
+        Auth:    true,
+        Request: model.CreatePekerjaanRequest{},
+        Status:  201,
+        Data:    model.PekerjaanResponse{},
+    },
+    {
+        Method:  "PUT",
+        Path:    "/pekerjaan/:id",
+        Tag:     "pekerjaan",
+        Summary: "Update pekerjaan; an alumni's edit of their own goes back to pending; 409 when it overlaps another pekerjaan of the alumni",
+        Auth:    true,
+        Request: model.UpdatePekerjaanRequest{},
+        Data:    model.PekerjaanResponse{},
+    },
+    {
+        Method:  "DELETE",
+        Path:    "/pekerjaan/:id",
+        Tag:     "pekerjaan",
+        Summary: "Move pekerjaan to trash (admin or owning alumni)",
+        Auth:    true,
+    },
+    {
+        Method:  "POST",
+        Path:    "/pekerjaan/bulk",
+        Tag:     "pekerjaan",
+        Summary: "Run up to 500 create, update, soft_delete and delete operations in one bulk write, each checked like its single-item endpoint; ordered batches stop at the first failure",
+        Auth:    true,
+        Request: model.PekerjaanBulkRequest{},
+        Data:    model.BulkResponse{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/pekerjaan/trash",
+        Tag:       "pekerjaan",
+        Summary:   "List soft-deleted pekerjaan visible to the caller",
+        Auth:      true,
+        Datatable: true,
+        Data:      []model.PekerjaanTrashResponse{},
+        Meta:      true,
+    },
+    {
+        Method:  "PUT",
+        Path:    "/pekerjaan/trash/restore/:id",
+        Tag:     "pekerjaan",
+        Summary: "Restore pekerjaan from trash",
+        Auth:    true,
+    },
+    {
+        Method:  "DELETE",
+        Path:    "/pekerjaan/trash/:id",
+        Tag:     "pekerjaan",
+        Summary: "Permanently delete pekerjaan from trash",
+        Auth:    true,
+    },
+    {
+        Method:  "POST",
+        Path:    "/pekerjaan/trash/restore",
+        Tag:     "pekerjaan",
+        Summary: "Restore several pekerjaan from the caller's trash; ids not found there are skipped",
+        Auth:    true,
+        Request: model.TrashBulkRequest{},
+        Data:    model.TrashBulkResponse{},
+    },
+    {
+        Method:  "POST",
+        Path:    "/pekerjaan/trash/purge",
+        Tag:     "pekerjaan",
+        Summary: "Permanently delete several pekerjaan from the caller's trash; ids not found there are skipped",
+        Auth:    true,
+        Request: model.TrashBulkRequest{},
+        Data:    model.TrashBulkResponse{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/pekerjaan/trash/purge/preview",
+        Tag:       "pekerjaan",
+        Summary:   "Dry run of the scheduled purge: pekerjaan trashed longer than the retention period",
+        Auth:      true,
+        AdminOnly: true,
+        Query:     []Schema{
+            queryParam("limit", Schema{"type": "integer", "minimum": 1, "default": 100}, "Maximum number of items listed"),
+        },
+        Data:      model.TrashPurgeReport{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/pekerjaan/moderation",
+        Tag:       "pekerjaan",
+        Summary:   "List pending or rejected pekerjaan; admins see every submission, alumni their own",
+        Auth:      true,
+        Datatable: true,
+        Query:     []Schema{
+            queryParam("status", Schema{"type": "string", "enum": []string{model.ModerasiPending, model.ModerasiRejected}, "default": model.ModerasiPending}, "Moderation status"),
+        },
+        Data:      []model.PekerjaanResponse{},
+        Meta:      true,
+    },
+    {
+        Method:    "PUT",
+        Path:      "/pekerjaan/moderation/approve/:id",
+        Tag:       "pekerjaan",
+        Summary:   "Approve a pending pekerjaan; 409 when it is not pending or overlaps another pekerjaan",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.ModerasiRequest{},
+        Data:      model.PekerjaanResponse{},
+    },
+    {
+        Method:    "PUT",
+        Path:      "/pekerjaan/moderation/reject/:id",
+        Tag:       "pekerjaan",
+        Summary:   "Reject a pending pekerjaan with an optional catatan; 409 when it is not pending",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.ModerasiRequest{},
+        Data:      model.PekerjaanResponse{},
+    },
+
+    // Companies
+    {
+        Method:    "GET",
+        Path:      "/companies",
+        Tag:       "companies",
+        Summary:   "List registered companies with paging, search and sorting",
+        Auth:      true,
+        Datatable: true,
+        Data:      []model.CompanyResponse{},
+        Meta:      true,
+    },
+    {
+        Method:  "GET",
+        Path:    "/companies/suggest",
+        Tag:     "companies",
+        Summary: "Suggest registered companies for a free-text employer name",
+        Auth:    true,
+        Query:   []Schema{
+            queryParam("nama", Schema{"type": "string"}, "Employer name as written in nama_perusahaan"),
+        },
+        Data:    []model.CompanyMatch{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/companies/:id",
+        Tag:     "companies",
+        Summary: "Get one company",
+        Auth:    true,
+        Data:    model.CompanyResponse{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/companies/:id/alumni",
+        Tag:       "companies",
+        Summary:   "List alumni with an active pekerjaan at the company",
+        Auth:      true,
+        AdminOnly: true,
+        Data:      []model.CompanyAlumniResponse{},
+    },
+    {
+        Method:    "POST",
+        Path:      "/companies",
+        Tag:       "companies",
+        Summary:   "Register company",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.CreateCompanyRequest{},
+        Status:    201,
+        Data:      model.CompanyResponse{},
+    },
+    {
+        Method:    "PUT",
+        Path:      "/companies/:id",
+        Tag:       "companies",
+        Summary:   "Update company names, industry and location",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.UpdateCompanyRequest{},
+        Data:      model.CompanyResponse{},
+    },
+    {
+        Method:    "DELETE",
+        Path:      "/companies/:id",
+        Tag:       "companies",
+        Summary:   "Delete company and unlink its pekerjaan",
+        Auth:      true,
+        AdminOnly: true,
+    },
+
+    // Stats
+    {
+        Method:  "GET",
+        Path:    "/stats/cohorts",
+        Tag:     "stats",
+        Summary: "Employment rate, median waiting time, share still aktif, job churn and top industries per jurusan and tahun_lulus, suppressed for cohorts under the minimum size",
+        Auth:    true,
+        Query:   append(cohortQuery,
+            queryParam("top", Schema{"type": "integer", "minimum": 1, "maximum": 20, "default": 3}, "Number of industries per cohort"),
+        ),
+        Data:    []model.CohortStats{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/stats/waiting-time",
+        Tag:     "stats",
+        Summary: "Months from graduation to the first pekerjaan per jurusan and tahun_lulus, in tracer study bands, suppressed under the minimum size",
+        Auth:    true,
+        Query:   cohortQuery,
+        Data:    []model.WaitingTimeStats{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/stats/distribution",
+        Tag:     "stats",
+        Summary: "Pekerjaan per industry, location or position; groups under the minimum size are merged into Lainnya",
+        Auth:    true,
+        Query:   append(cohortQuery,
+            queryParam("by", Schema{"type": "string", "enum": []string{model.DimensionIndustri, model.DimensionLokasi, model.DimensionPosisi}, "default": model.DimensionIndustri}, "Field to group by"),
+        ),
+        Data:    model.DistributionStats{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/stats/salary",
+        Tag:     "stats",
+        Summary: "Monthly salary percentiles per jurusan or angkatan, suppressed for groups under the minimum size",
+        Auth:    true,
+        Query:   append(cohortQuery,
+            queryParam("by", Schema{"type": "string", "enum": []string{model.SalaryByJurusan, model.SalaryByAngkatan}, "default": model.SalaryByJurusan}, "Grouping"),
+            queryParam("currency", Schema{"type": "string", "default": model.CurrencyIDR}, "Only salaries in this ISO currency"),
+        ),
+        Data:    model.SalaryStats{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/stats/trends",
+        Tag:     "stats",
+        Summary: "Pekerjaan, median monthly salary and top industries per year of tanggal_mulai_kerja",
+        Auth:    true,
+        Query:   append(cohortQuery,
+            queryParam("currency", Schema{"type": "string", "default": model.CurrencyIDR}, "Only salaries in this ISO currency"),
+            queryParam("top", Schema{"type": "integer", "minimum": 1, "maximum": 20, "default": 3}, "Number of industries per year"),
+        ),
+        Data:    model.TrendStats{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/stats/career-paths",
+        Tag:     "stats",
+        Summary: "Industry and position-level transition matrices, average tenure and common career paths of the alumni",
+        Auth:    true,
+        Query:   append(cohortQuery,
+            queryParam("paths", Schema{"type": "integer", "minimum": 1, "maximum": 50, "default": 10}, "Number of paths listed per kind"),
+        ),
+        Data:    model.CareerStats{},
+    },
+
+    // Reports
+    {
+        Method:    "GET",
+        Path:      "/reports",
+        Tag:       "reports",
+        Summary:   "List accreditation report jobs, newest first",
+        Auth:      true,
+        AdminOnly: true,
+        Query:     []Schema{
+            queryParam("page", Schema{"type": "integer", "minimum": 1, "default": 1}, "Page number"),
+            queryParam("limit", Schema{"type": "integer", "minimum": 1, "default": 10}, "Jobs per page"),
+        },
+        Data:      []model.ReportResponse{},
+        Meta:      true,
+    },
+    {
+        Method:    "POST",
+        Path:      "/reports",
+        Tag:       "reports",
+        Summary:   "Queue the tracer study report of a jurusan and tahun_lulus in PDF and/or XLSX; poll the job for its status",
+        Auth:      true,
+        AdminOnly: true,
+        Request:   model.CreateReportRequest{},
+        Status:    202,
+        Data:      model.ReportResponse{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/reports/:id",
+        Tag:       "reports",
+        Summary:   "Get a report job: pending, running, done with its files, or failed with the error",
+        Auth:      true,
+        AdminOnly: true,
+        Data:      model.ReportResponse{},
+    },
+    {
+        Method:    "GET",
+        Path:      "/reports/:id/download/:format",
+        Tag:       "reports",
+        Summary:   "Download the generated PDF or XLSX file of a done report; 409 while it is not done",
+        Auth:      true,
+        AdminOnly: true,
+        Produces:  "application/octet-stream",
+    },
+
+    // Users
+    {
+        Method:    "GET",
+        Path:      "/users",
+        Tag:       "users",
+        Summary:   "List users",
+        Auth:      true,
+        AdminOnly: true,
+        Datatable: true,
+        Raw:       model.UserListResponse{},
+    },
+
+    // Health
+    {
+        Method:  "GET",
+        Path:    "/healthz",
+        Tag:     "health",
+        Summary: "Liveness probe, ok while the process serves HTTP",
+        Data:    model.HealthResponse{},
+    },
+    {
+        Method:  "GET",
+        Path:    "/readyz",
+        Tag:     "health",
+        Summary: "Readiness probe, checks the database and pending migrations; 503 when not ready",
+        Data:    model.HealthResponse{},
+    },
+
+    // Metrics
+    {
+        Method:   "GET",
+        Path:     "/metrics",
+        Tag:      "health",
+        Summary:  "Prometheus metrics: HTTP, logins, repository calls, connection pools and totals",
+        Produces: "text/plain",
+    },
+
+    // Docs
+    {
+        Method:  "GET",
+        Path:    "/openapi.json",
+        Tag:     "docs",
+        Summary: "This OpenAPI document",
+        Raw:     map[string]interface{}{},
+    },
+    {
+        Method:   "GET",
+        Path:     "/docs",
+        Tag:      "docs",
+        Summary:  "Interactive API documentation",
+        Produces: "text/html",
+    },
+}
@@ -0,0 +1,122 @@
+package docs
+
+import (
+    "reflect"
+    "strings"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+    timeType     = reflect.TypeOf(time.Time{})
+    objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// schemaRegistry collects component schemas generated from Go structs
+type schemaRegistry struct {
+    schemas map[string]Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+    return &schemaRegistry{schemas: map[string]Schema{}}
+}
+
+// ref returns a $ref to the component schema of v, generating it on first use
+func (r *schemaRegistry) ref(v interface{}) Schema {
+    return r.schemaFor(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) Schema {
+    switch {
+    case t == timeType:
+        return Schema{"type": "string", "format": "date-time"}
+    case t == objectIDType:
+        return Schema{"type": "string", "pattern": "^[a-f0-9]{24}$"}
+    }
+
+    switch t.Kind() {
+    case reflect.Ptr:
+        inner := r.schemaFor(t.Elem())
+        if typ, ok := inner["type"].(string); ok {
+            inner["type"] = []string{typ, "null"}
+            return inner
+        }
+        return Schema{"oneOf": []Schema{inner, {"type": "null"}}}
+    case reflect.String:
+        return Schema{"type": "string"}
+    case reflect.Bool:
+        return Schema{"type": "boolean"}
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return Schema{"type": "integer"}
+    case reflect.Float32, reflect.Float64:
+        return Schema{"type": "number"}
+    case reflect.Slice, reflect.Array:
+        return Schema{"type": "array", "items": r.schemaFor(t.Elem())}
+    case reflect.Map:
+        return Schema{"type": "object", "additionalProperties": r.schemaFor(t.Elem())}
+    case reflect.Struct:
+        name := t.Name()
+        if _, ok := r.schemas[name]; !ok {
+            // Reserve the name first so self-referencing structs terminate
+            r.schemas[name] = Schema{}
+            r.schemas[name] = r.structSchema(t)
+        }
+        return Schema{"$ref": "#/components/schemas/" + name}
+    }
+
+    return Schema{}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) Schema {
+    properties := Schema{}
+    var required []string
+
+    // Request structs declare required fields through validate tags, response
+    // structs through the absence of omitempty
+    validated := false
+    for i := 0; i < t.NumField(); i++ {
+        if _, ok := t.Field(i).Tag.Lookup("validate"); ok {
+            validated = true
+            break
+        }
+    }
+
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if !field.IsExported() {
+            continue
+        }
+
+        tag := field.Tag.Get("json")
+        if tag == "-" {
+            continue
+        }
+        name, opts, _ := strings.Cut(tag, ",")
+
+        // Embedded structs without a json name are flattened, like encoding/json does
+        if field.Anonymous && name == "" {
+            continue
+        }
+        if name == "" {
+            name = field.Name
+        }
+
+        properties[name] = r.schemaFor(field.Type)
+
+        if validated {
+            if strings.Contains(field.Tag.Get("validate"), "required") {
+                required = append(required, name)
+            }
+        } else if !strings.Contains(opts, "omitempty") {
+            required = append(required, name)
+        }
+    }
+
+    schema := Schema{"type": "object", "properties": properties}
+    if len(required) > 0 {
+        schema["required"] = required
+    }
+    return schema
+}
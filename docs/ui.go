@@ -0,0 +1,12 @@
+package docs
+
+import _ "embed"
+
+// IndexHTML is the bundled documentation viewer served at /docs
+//
+//go:embed index.html
+var IndexHTML []byte
+
+// ContentSecurityPolicy allows the inline script and styles of IndexHTML and
+// its requests for the spec, nothing else
+const ContentSecurityPolicy = "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'; frame-ancestors 'none'"
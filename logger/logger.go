@@ -0,0 +1,153 @@
+package logger
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "log"
+    "log/slog"
+    "os"
+    "strings"
+    "sync"
+)
+
+// Output formats selectable with LOG_FORMAT. Auto picks JSON in production
+// and pretty everywhere else.
+const (
+    FormatAuto   = "auto"
+    FormatJSON   = "json"
+    FormatPretty = "pretty"
+)
+
+// ParseLevel maps LOG_LEVEL to a slog level
+func ParseLevel(level string) (slog.Level, error) {
+    switch strings.ToLower(level) {
+    case "debug":
+        return slog.LevelDebug, nil
+    case "", "info":
+        return slog.LevelInfo, nil
+    case "warn", "warning":
+        return slog.LevelWarn, nil
+    case "error":
+        return slog.LevelError, nil
+    }
+    return 0, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+}
+
+// New builds a logger writing to w. Every record is enriched with the fields
+// stored in its context, see WithFields.
+func New(w io.Writer, level, format, env string) (*slog.Logger, error) {
+    lvl, err := ParseLevel(level)
+    if err != nil {
+        return nil, err
+    }
+
+    if format == "" || format == FormatAuto {
+        format = FormatPretty
+        if env == "production" {
+            format = FormatJSON
+        }
+    }
+
+    opts := &slog.HandlerOptions{Level: lvl}
+    var handler slog.Handler
+    switch format {
+    case FormatJSON:
+        handler = slog.NewJSONHandler(w, opts)
+    case FormatPretty:
+        handler = newPrettyHandler(w, opts)
+    default:
+        return nil, fmt.Errorf("unknown log format %q, use auto, json or pretty", format)
+    }
+
+    return slog.New(contextHandler{handler}), nil
+}
+
+// Setup installs the logger as the slog default. The standard log package
+// writes through it too, so the existing log.Printf lines share its format.
+func Setup(level, format, env string) error {
+    l, err := New(os.Stderr, level, format, env)
+    if err != nil {
+        return err
+    }
+    slog.SetDefault(l)
+    log.SetFlags(0)
+    return nil
+}
+
+type fieldsKey struct{}
+
+// fields are the attributes of one request. They are shared by every context
+// derived from the request, so middleware running later (e.g. AuthRequired)
+// can add the user to lines logged by contexts created earlier.
+type fields struct {
+    mu    sync.Mutex
+    attrs []slog.Attr
+}
+
+// WithFields returns a context whose log lines carry args, given as
+// alternating keys and values like slog.Info. When ctx already has fields the
+// args are added to them instead.
+func WithFields(ctx context.Context, args ...interface{}) context.Context {
+    if f, ok := ctx.Value(fieldsKey{}).(*fields); ok {
+        f.add(args)
+        return ctx
+    }
+
+    f := &fields{}
+    f.add(args)
+    return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// AddFields adds args to the fields of ctx, doing nothing when ctx has none
+func AddFields(ctx context.Context, args ...interface{}) {
+    if f, ok := ctx.Value(fieldsKey{}).(*fields); ok {
+        f.add(args)
+    }
+}
+
+func (f *fields) add(args []interface{}) {
+    record := slog.Record{}
+    record.Add(args...)
+
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    record.Attrs(func(attr slog.Attr) bool {
+        for i := range f.attrs {
+            if f.attrs[i].Key == attr.Key {
+                f.attrs[i] = attr
+                return true
+            }
+        }
+        f.attrs = append(f.attrs, attr)
+        return true
+    })
+}
+
+func (f *fields) snapshot() []slog.Attr {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return append([]slog.Attr(nil), f.attrs...)
+}
+
+// contextHandler adds the context fields to each record
+type contextHandler struct {
+    slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+    if ctx != nil {
+        if f, ok := ctx.Value(fieldsKey{}).(*fields); ok {
+            r.AddAttrs(f.snapshot()...)
+        }
+    }
+    return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+    return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "strings"
+    "testing"
+)
+
+func TestJSONLinesCarryContextFields(t *testing.T) {
+    var buf bytes.Buffer
+    l, err := New(&buf, "info", FormatAuto, "production")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    ctx := WithFields(context.Background(), "request_id", "abc")
+    // Fields added later, like the user set by AuthRequired, reach contexts
+    // derived before
+    derived, cancel := context.WithCancel(ctx)
+    defer cancel()
+    AddFields(ctx, "user_id", "u1", "role", "admin")
+
+    l.InfoContext(derived, "slow query", "query", "AlumniRepository.GetAlumni")
+    l.DebugContext(derived, "hidden")
+
+    var line map[string]interface{}
+    if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+        t.Fatalf("not one JSON line: %q", buf.String())
+    }
+    for key, want := range map[string]string{
+        "msg": "slow query", "level": "INFO", "query": "AlumniRepository.GetAlumni",
+        "request_id": "abc", "user_id": "u1", "role": "admin",
+    } {
+        if line[key] != want {
+            t.Errorf("%s = %v, want %s", key, line[key], want)
+        }
+    }
+}
+
+func TestPrettyFormat(t *testing.T) {
+    var buf bytes.Buffer
+    l, err := New(&buf, "debug", FormatAuto, "development")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    l.With("component", "db").WithGroup("req").Debug("hello world", "path", "/alumni", "note", "two words")
+
+    out := buf.String()
+    if !strings.Contains(out, "DEBUG hello world component=db req.path=/alumni req.note=\"two words\"\n") {
+        t.Fatalf("output = %q", out)
+    }
+}
+
+func TestInvalidSettings(t *testing.T) {
+    if _, err := New(&bytes.Buffer{}, "verbose", FormatJSON, ""); err == nil {
+        t.Error("unknown level accepted")
+    }
+    if _, err := New(&bytes.Buffer{}, "info", "xml", ""); err == nil {
+        t.Error("unknown format accepted")
+    }
+    if level, _ := ParseLevel("WARN"); level != slog.LevelWarn {
+        t.Errorf("WARN parsed as %s", level)
+    }
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "log/slog"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// prettyHandler writes one human readable line per record for development:
+//
+//	15:04:05.000 INFO  request method=GET path=/alumni status=200
+type prettyHandler struct {
+    opts   *slog.HandlerOptions
+    prefix string // group names joined with dots
+    attrs  []slog.Attr
+    mu     *sync.Mutex
+    w      io.Writer
+}
+
+func newPrettyHandler(w io.Writer, opts *slog.HandlerOptions) *prettyHandler {
+    return &prettyHandler{opts: opts, mu: &sync.Mutex{}, w: w}
+}
+
+func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+    return level >= h.opts.Level.Level()
+}
+
+func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+    var b strings.Builder
+    b.WriteString(r.Time.Format("15:04:05.000"))
+    b.WriteByte(' ')
+    fmt.Fprintf(&b, "%-5s ", r.Level.String())
+    b.WriteString(r.Message)
+
+    for _, attr := range h.attrs {
+        writeAttr(&b, "", attr)
+    }
+    r.Attrs(func(attr slog.Attr) bool {
+        writeAttr(&b, h.prefix, attr)
+        return true
+    })
+    b.WriteByte('\n')
+
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    _, err := io.WriteString(h.w, b.String())
+    return err
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    clone := *h
+    clone.attrs = append([]slog.Attr(nil), h.attrs...)
+    for _, attr := range attrs {
+        if h.prefix != "" {
+            attr.Key = h.prefix + attr.Key
+        }
+        clone.attrs = append(clone.attrs, attr)
+    }
+    return &clone
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+    if name == "" {
+        return h
+    }
+    clone := *h
+    clone.prefix = h.prefix + name + "."
+    return &clone
+}
+
+func writeAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+    attr.Value = attr.Value.Resolve()
+    if attr.Equal(slog.Attr{}) {
+        return
+    }
+
+    if attr.Value.Kind() == slog.KindGroup {
+        if attr.Key != "" {
+            prefix += attr.Key + "."
+        }
+        for _, inner := range attr.Value.Group() {
+            writeAttr(b, prefix, inner)
+        }
+        return
+    }
+
+    b.WriteByte(' ')
+    b.WriteString(prefix + attr.Key)
+    b.WriteByte('=')
+
+    var value string
+    switch attr.Value.Kind() {
+    case slog.KindDuration:
+        value = attr.Value.Duration().Round(time.Microsecond).String()
+    case slog.KindTime:
+        value = attr.Value.Time().Format(time.RFC3339)
+    default:
+        value = attr.Value.String()
+    }
+    if value == "" || strings.ContainsAny(value, " \"=") {
+        value = strconv.Quote(value)
+    }
+    b.WriteString(value)
+}
@@ -1,88 +1,316 @@
 package main
 
 import (
+    "bufio"
+    "errors"
     "flag"
+    "fmt"
     "log"
+    "net/url"
     "os"
-    
+    "strings"
+
+    "go-fiber/app/repository"
     "go-fiber/config"
     "go-fiber/database"
-    "go-fiber/routes"
+    "go-fiber/logger"
+
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// Exit codes, so scripts can tell a failure from a usage error or a
+// declined confirmation
+const (
+    exitOK      = 0
+    exitFailure = 1
+    exitUsage   = 2
+    exitAborted = 3
+)
+
+var (
+    errUsage   = errors.New("usage error")
+    errAborted = errors.New("aborted")
 )
 
+// command is one CLI subcommand, named by one or two words. validate checks
+// the configuration before run; nil skips the check.
+type command struct {
+    name     string
+    args     string
+    summary  string
+    validate func(*config.Config) error
+    run      func(c *cli, args []string) error
+}
+
+var commands = []command{
+    {"serve", "", "Start the HTTP server (default)", (*config.Config).Validate, cmdServe},
+    {"migrate status", "", "List migrations and their state", (*config.Config).ValidateDatabase, cmdMigrateStatus},
+    {"migrate up", "[n]", "Apply the next n or all pending migrations", (*config.Config).ValidateDatabase, cmdMigrateUp},
+    {"migrate down", "[n]", "Roll back the newest n migrations (default 1)", (*config.Config).ValidateDatabase, cmdMigrateDown},
+    {"migrate redo", "", "Roll back and re-apply the newest migration", (*config.Config).ValidateDatabase, cmdMigrateRedo},
+    {"schema sync", "[--dry-run]", "Reconcile MongoDB validators and indexes", (*config.Config).ValidateDatabase, cmdSchemaSync},
+    {"seed", "", "Seed MongoDB with sample data", (*config.Config).ValidateDatabase, cmdSeed},
+    {"db summary", "", "Show MongoDB document counts", (*config.Config).ValidateDatabase, cmdDBSummary},
+    {"db reset", "", "Roll back and re-apply every migration, deleting all data", (*config.Config).ValidateDatabase, cmdDBReset},
+    {"db copy-to-postgres", "", "Copy all MongoDB data into PostgreSQL", validateCopy, cmdCopyToPostgres},
+    {"user create-admin", "--username <name> --email <email> [--password-stdin]", "Create an admin user", (*config.Config).ValidateDatabase, cmdUserCreateAdmin},
+    {"user set-password", "<username|email> [--password-stdin]", "Change the password of a user", (*config.Config).ValidateDatabase, cmdUserSetPassword},
+    {"alumni import", "<file.csv> [--dry-run]", "Import alumni from a CSV file", (*config.Config).ValidateDatabase, cmdAlumniImport},
+    {"companies backfill", "[--dry-run] [--create]", "Link existing pekerjaan to registered companies", (*config.Config).ValidateDatabase, cmdCompaniesBackfill},
+    {"pekerjaan expire-contracts", "", "Move aktif pekerjaan past their end date to kontrak_habis", (*config.Config).ValidateDatabase, cmdPekerjaanExpire},
+    {"pekerjaan purge-trash", "[--dry-run]", "Permanently delete pekerjaan trashed longer than PEKERJAAN_TRASH_RETENTION", (*config.Config).ValidateDatabase, cmdPekerjaanPurgeTrash},
+    {"export", "alumni|pekerjaan|users [--format csv|json] [--out <file>]", "Export data as CSV or JSON", (*config.Config).ValidateDatabase, cmdExport},
+    {"config print", "[--redacted]", "Print the effective configuration as YAML", nil, cmdConfigPrint},
+}
+
+// legacyFlags maps the old boolean flags to their subcommand
+var legacyFlags = map[string][]string{
+    "migrate":          {"migrate", "up"},
+    "seed":             {"seed"},
+    "reset":            {"db", "reset"},
+    "summary":          {"db", "summary"},
+    "copy-to-postgres": {"db", "copy-to-postgres"},
+}
+
+// cli carries the global options and the loaded configuration into the
+// commands
+type cli struct {
+    env        string
+    configFile string
+    yes        bool
+    in         *bufio.Reader
+    cfg        *config.Config
+}
+
 func main() {
-    // Command line flags
-    migrate := flag.Bool("migrate", false, "Run database migrations")
-    seed := flag.Bool("seed", false, "Seed database with initial data")
-    reset := flag.Bool("reset", false, "Drop all collections and re-migrate")
-    summary := flag.Bool("summary", false, "Show database summary")
-    flag.Parse()
-
-    // Load environment variables
-    config.LoadEnv()
-
-    // Connect to database
-    db := database.ConnectDB()
-
-    // Handle command line operations
-    if *reset {
-        log.Println("⚠️  Resetting database...")
-        if err := database.DropAllCollections(db); err != nil {
-            log.Fatal("Failed to drop collections:", err)
+    os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+    c := &cli{in: bufio.NewReader(os.Stdin)}
+
+    global := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+    global.StringVar(&c.env, "env", "", "Target environment: load .env.<name> before .env")
+    global.StringVar(&c.configFile, "config", "", "YAML configuration file, defaults to $CONFIG_FILE")
+    global.BoolVar(&c.yes, "yes", false, "Skip confirmation prompts of destructive commands")
+    global.Usage = func() { usage(global) }
+
+    args, err := legacyArgs(args)
+    if err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        return exitUsage
+    }
+    if err := global.Parse(args); err != nil {
+        if errors.Is(err, flag.ErrHelp) {
+            return exitOK
         }
-        log.Println("✅ Database reset completed")
-        return
+        return exitUsage
+    }
+
+    cmd, rest := findCommand(global.Args())
+    if cmd == nil {
+        usage(global)
+        return exitUsage
+    }
+
+    cfg, err := config.Load(c.env, c.configFile)
+    if err != nil {
+        log.Printf("❌ Error loading configuration: %v", err)
+        return exitFailure
+    }
+    if err := logger.Setup(cfg.Log.Level, cfg.Log.Format, cfg.Env); err != nil {
+        log.Printf("⚠️  %v, keeping the default logger", err)
     }
 
-    if *migrate {
-        log.Println("🔄 Running migrations...")
-        if err := database.RunMigrations(db); err != nil {
-            log.Fatal("Migration failed:", err)
+    if cmd.validate != nil {
+        if err := cmd.validate(cfg); err != nil {
+            log.Printf("❌ Invalid configuration:\n%v", err)
+            return exitFailure
         }
-        log.Println("✅ Migrations completed")
-        return
     }
+    config.Set(cfg)
+    c.cfg = cfg
+    repository.SlowQueryThreshold = cfg.Database.SlowQueryThreshold
 
-    if *seed {
-        log.Println("🌱 Seeding database...")
-        if err := database.SeedData(db); err != nil {
-            log.Fatal("Seeding failed:", err)
+    err = cmd.run(c, rest)
+    switch {
+    case err == nil:
+        return exitOK
+    case errors.Is(err, flag.ErrHelp):
+        return exitOK
+    case errors.Is(err, errUsage):
+        if err != errUsage {
+            fmt.Fprintln(os.Stderr, err)
         }
-        if err := database.SeedSummary(db); err != nil {
-            log.Fatal("Failed to show summary:", err)
+        fmt.Fprintf(os.Stderr, "Usage: %s %s %s\n", os.Args[0], cmd.name, cmd.args)
+        return exitUsage
+    case errors.Is(err, errAborted):
+        log.Printf("🛑 %v", err)
+        return exitAborted
+    }
+
+    log.Printf("❌ %s failed: %v", cmd.name, err)
+    return exitFailure
+}
+
+func usage(global *flag.FlagSet) {
+    out := global.Output()
+    fmt.Fprintf(out, "Usage: %s [--env <name>] [--config <file>] [--yes] <command> [args]\n\nCommands:\n", os.Args[0])
+    for _, cmd := range commands {
+        fmt.Fprintf(out, "  %-22s %s\n", cmd.name, cmd.summary)
+        if cmd.args != "" {
+            fmt.Fprintf(out, "  %-22s   %s\n", "", cmd.args)
         }
-        return
+    }
+    fmt.Fprintln(out, "\nGlobal flags:")
+    global.PrintDefaults()
+}
+
+// findCommand matches the longest command name, defaulting to serve
+func findCommand(args []string) (*command, []string) {
+    if len(args) == 0 {
+        return &commands[0], nil
     }
 
-    if *summary {
-        if err := database.SeedSummary(db); err != nil {
-            log.Fatal("Failed to show summary:", err)
+    for _, words := range []int{2, 1} {
+        if len(args) < words {
+            continue
         }
-        return
+        name := strings.Join(args[:words], " ")
+        for i := range commands {
+            if commands[i].name == name {
+                return &commands[i], args[words:]
+            }
+        }
+    }
+    return nil, nil
+}
+
+// legacyArgs rewrites the old -migrate, -seed, -reset, -summary and
+// -copy-to-postgres flags into subcommands. They used to be silently
+// combined; now more than one is an error.
+func legacyArgs(args []string) ([]string, error) {
+    var found []string
+    var rest []string
+    for _, arg := range args {
+        name := strings.TrimLeft(arg, "-")
+        if sub, ok := legacyFlags[name]; ok && strings.HasPrefix(arg, "-") {
+            found = append(found, arg)
+            rest = append(rest, sub...)
+            continue
+        }
+        rest = append(rest, arg)
+    }
+
+    switch len(found) {
+    case 0:
+        return args, nil
+    case 1:
+        log.Printf("⚠️  %s is deprecated, use \"%s\"", found[0], strings.Join(legacyFlags[strings.TrimLeft(found[0], "-")], " "))
+        return rest, nil
     }
+    return nil, fmt.Errorf("%s cannot be combined, run them as separate commands", strings.Join(found, ", "))
+}
 
-    // Regular application startup
-    log.Println("🚀 Starting application...")
+// parseArgs parses flags anywhere among args, so "migrate down 2 --yes"
+// works, and returns the positional arguments
+func (c *cli) parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+    fs.BoolVar(&c.yes, "yes", c.yes, "Skip the confirmation prompt")
 
-    // Run migrations automatically on startup (optional)
-    if os.Getenv("AUTO_MIGRATE") == "true" {
-        if err := database.RunMigrations(db); err != nil {
-            log.Printf("⚠️  Auto-migration failed: %v", err)
+    var positional []string
+    for {
+        if err := fs.Parse(args); err != nil {
+            if errors.Is(err, flag.ErrHelp) {
+                return nil, err
+            }
+            return nil, errUsage
         }
+        if fs.NArg() == 0 {
+            return positional, nil
+        }
+        positional = append(positional, fs.Arg(0))
+        args = fs.Args()[1:]
+    }
+}
+
+// noArgs is parseArgs for commands without positional arguments
+func (c *cli) noArgs(name string, args []string) error {
+    fs := flag.NewFlagSet(name, flag.ContinueOnError)
+    positional, err := c.parseArgs(fs, args)
+    if err != nil {
+        return err
     }
+    if len(positional) > 0 {
+        return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+    }
+    return nil
+}
 
-    // Create Fiber app
-    app := config.NewApp(db)
+// confirm asks before a destructive action. --yes skips the prompt; without
+// a terminal to ask on, the action is refused.
+func (c *cli) confirm(action string) error {
+    if c.yes {
+        return nil
+    }
 
-    // Register routes
-    routes.RegisterRoutes(app, db)
+    target := describeTarget(c.cfg)
+    if c.env != "" {
+        target += " (env " + c.env + ")"
+    }
 
-    // Start server
-    port := os.Getenv("APP_PORT")
-    if port == "" {
-        port = "3000"
+    if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+        return fmt.Errorf("%w: %s on %s needs --yes when not run interactively", errAborted, action, target)
     }
 
-    log.Printf("🌐 Server running on http://localhost:%s", port)
-    log.Fatal(app.Listen(":" + port))
-}
\ No newline at end of file
+    fmt.Fprintf(os.Stderr, "⚠️  %s on %s. Continue? [y/N] ", action, target)
+    answer, _ := c.in.ReadString('\n')
+    switch strings.ToLower(strings.TrimSpace(answer)) {
+    case "y", "yes":
+        return nil
+    }
+    return fmt.Errorf("%w: %s", errAborted, action)
+}
+
+// describeTarget names the database the command runs against
+func describeTarget(cfg *config.Config) string {
+    if cfg.Database.Driver == config.DriverPostgres {
+        u, err := url.Parse(cfg.Database.PostgresURL)
+        if err != nil || u.Host == "" {
+            return "PostgreSQL"
+        }
+        return fmt.Sprintf("PostgreSQL %q at %s", strings.TrimPrefix(u.Path, "/"), u.Host)
+    }
+    return fmt.Sprintf("MongoDB %q", cfg.Database.MongoDatabase)
+}
+
+// validateCopy needs both drivers configured
+func validateCopy(cfg *config.Config) error {
+    mongo, postgres := *cfg, *cfg
+    mongo.Database.Driver = config.DriverMongo
+    postgres.Database.Driver = config.DriverPostgres
+    return errors.Join(mongo.ValidateDatabase(), postgres.ValidateDatabase())
+}
+
+// mongo connects to MongoDB for the commands that only support it
+func (c *cli) mongo(name string) (*mongo.Database, error) {
+    if database.Driver() != database.DriverMongo {
+        return nil, fmt.Errorf("%s is only available for MongoDB (DB_DRIVER=%s)", name, database.Driver())
+    }
+    return database.ConnectDB(), nil
+}
+
+// repositories connects to the configured driver
+func (c *cli) repositories() repository.Repositories {
+    if database.Driver() == database.DriverPostgres {
+        return repository.NewPostgresRepositories(database.ConnectPostgres())
+    }
+    return repository.NewMongoRepositories(database.ConnectDB())
+}
+
+// migrator connects to the configured driver
+func (c *cli) migrator() *database.Migrator {
+    if database.Driver() == database.DriverPostgres {
+        return database.NewPostgresMigrator(database.ConnectPostgres())
+    }
+    return database.NewMongoMigrator(database.ConnectDB())
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+    "errors"
+    "flag"
+    "io"
+    "os"
+    "reflect"
+    "testing"
+
+    "go-fiber/config"
+)
+
+func TestFindCommand(t *testing.T) {
+    tests := []struct {
+        args []string
+        want string
+        rest []string
+    }{
+        {nil, "serve", nil},
+        {[]string{"migrate", "down", "2"}, "migrate down", []string{"2"}},
+        {[]string{"seed"}, "seed", []string{}},
+        {[]string{"export", "alumni", "--format", "json"}, "export", []string{"alumni", "--format", "json"}},
+        {[]string{"migrate"}, "", nil},
+        {[]string{"unknown"}, "", nil},
+    }
+
+    for _, tt := range tests {
+        cmd, rest := findCommand(tt.args)
+        name := ""
+        if cmd != nil {
+            name = cmd.name
+        }
+        if name != tt.want {
+            t.Errorf("%v: command %q, want %q", tt.args, name, tt.want)
+            continue
+        }
+        if cmd != nil && len(rest)+len(tt.rest) > 0 && !reflect.DeepEqual(rest, tt.rest) {
+            t.Errorf("%v: rest %v, want %v", tt.args, rest, tt.rest)
+        }
+    }
+}
+
+func TestLegacyArgs(t *testing.T) {
+    got, err := legacyArgs([]string{"-reset"})
+    if err != nil || !reflect.DeepEqual(got, []string{"db", "reset"}) {
+        t.Fatalf("-reset = %v, %v", got, err)
+    }
+
+    got, err = legacyArgs([]string{"--env", "staging", "--migrate"})
+    if err != nil || !reflect.DeepEqual(got, []string{"--env", "staging", "migrate", "up"}) {
+        t.Fatalf("--migrate = %v, %v", got, err)
+    }
+
+    if _, err := legacyArgs([]string{"-migrate", "-seed"}); err == nil {
+        t.Fatal("combining legacy flags should fail")
+    }
+
+    got, _ = legacyArgs([]string{"migrate", "status"})
+    if !reflect.DeepEqual(got, []string{"migrate", "status"}) {
+        t.Fatalf("subcommands should pass through, got %v", got)
+    }
+}
+
+func TestParseArgsInterspersed(t *testing.T) {
+    c := &cli{}
+    fs := flag.NewFlagSet("test", flag.ContinueOnError)
+    dryRun := fs.Bool("dry-run", false, "")
+
+    positional, err := c.parseArgs(fs, []string{"2", "--yes", "file.csv", "--dry-run"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(positional, []string{"2", "file.csv"}) || !c.yes || !*dryRun {
+        t.Fatalf("positional %v, yes %t, dry-run %t", positional, c.yes, *dryRun)
+    }
+
+    fs = flag.NewFlagSet("test", flag.ContinueOnError)
+    fs.SetOutput(io.Discard)
+    if _, err := c.parseArgs(fs, []string{"--nope"}); !errors.Is(err, errUsage) {
+        t.Fatalf("unknown flag: err = %v, want errUsage", err)
+    }
+}
+
+func TestRunExitCodes(t *testing.T) {
+    if code := run([]string{"nope"}); code != exitUsage {
+        t.Fatalf("unknown command exit code %d, want %d", code, exitUsage)
+    }
+    if code := run([]string{"-seed", "-reset"}); code != exitUsage {
+        t.Fatalf("combined legacy flags exit code %d, want %d", code, exitUsage)
+    }
+}
+
+func TestParseAlumniRow(t *testing.T) {
+    index := map[string]int{}
+    for i, name := range alumniColumns {
+        index[name] = i
+    }
+
+    alumni, err := parseAlumniRow([]string{"2101001", "Budi", "TI", "2021", "2025", "budi@example.com", "0812", "", ""}, index)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if alumni.Angkatan != 2021 || alumni.TahunLulus != 2025 || !alumni.UserID.IsZero() {
+        t.Fatalf("unexpected alumni %+v", alumni)
+    }
+
+    if _, err := parseAlumniRow([]string{"2101001", "Budi", "TI", "dua", "2025", "budi@example.com", "0812"}, index); err == nil {
+        t.Fatal("non-numeric angkatan should fail")
+    }
+    if _, err := parseAlumniRow([]string{"2101001", "", "TI", "2021", "2025", "budi@example.com", "0812"}, index); err == nil {
+        t.Fatal("missing nama should fail")
+    }
+}
+
+func TestConfirmNonInteractive(t *testing.T) {
+    r, w, err := os.Pipe()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer r.Close()
+    defer w.Close()
+    stdin := os.Stdin
+    os.Stdin = r
+    t.Cleanup(func() { os.Stdin = stdin })
+
+    c := &cli{cfg: config.Defaults()}
+    if err := c.confirm("permanently delete 2 trashed pekerjaan"); !errors.Is(err, errAborted) {
+        t.Fatalf("without a terminal: err = %v, want errAborted", err)
+    }
+    c.yes = true
+    if err := c.confirm("permanently delete 2 trashed pekerjaan"); err != nil {
+        t.Fatalf("with --yes: err = %v", err)
+    }
+}
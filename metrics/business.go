@@ -0,0 +1,75 @@
+package metrics
+
+import (
+    "context"
+    "log/slog"
+    "sync"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+)
+
+// businessCacheTTL is how long counted values are reused, so frequent
+// scrapes do not each run the count queries
+const businessCacheTTL = 30 * time.Second
+
+// BusinessCounts are the domain totals exported as gauges
+type BusinessCounts struct {
+    Alumni          int
+    ActivePekerjaan int
+    TrashPekerjaan  int
+}
+
+// businessCollector exports BusinessCounts, counting at most once per
+// businessCacheTTL
+type businessCollector struct {
+    count func(ctx context.Context) (BusinessCounts, error)
+
+    mu        sync.Mutex
+    last      BusinessCounts
+    countedAt time.Time
+
+    alumni, active, trash *prometheus.Desc
+}
+
+// RegisterBusiness exports the totals returned by count. Call it once, with
+// the repositories of the running server.
+func RegisterBusiness(count func(ctx context.Context) (BusinessCounts, error)) {
+    Registry.MustRegister(&businessCollector{
+        count:  count,
+        alumni: prometheus.NewDesc("alumni_count", "Alumni stored.", nil, nil),
+        active: prometheus.NewDesc("pekerjaan_active_count", "Pekerjaan with status aktif, not in the trash.", nil, nil),
+        trash:  prometheus.NewDesc("pekerjaan_trash_count", "Soft-deleted pekerjaan in the trash.", nil, nil),
+    })
+}
+
+func (b *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+    ch <- b.alumni
+    ch <- b.active
+    ch <- b.trash
+}
+
+func (b *businessCollector) Collect(ch chan<- prometheus.Metric) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if time.Since(b.countedAt) >= businessCacheTTL {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        counts, err := b.count(ctx)
+        cancel()
+
+        if err != nil {
+            // Keep serving the last values rather than failing the scrape
+            slog.Warn("counting business metrics failed", "error", err)
+        } else {
+            b.last, b.countedAt = counts, time.Now()
+        }
+    }
+    if b.countedAt.IsZero() {
+        return
+    }
+
+    ch <- prometheus.MustNewConstMetric(b.alumni, prometheus.GaugeValue, float64(b.last.Alumni))
+    ch <- prometheus.MustNewConstMetric(b.active, prometheus.GaugeValue, float64(b.last.ActivePekerjaan))
+    ch <- prometheus.MustNewConstMetric(b.trash, prometheus.GaugeValue, float64(b.last.TrashPekerjaan))
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+    "context"
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBusinessCollectorCachesCounts(t *testing.T) {
+    calls := 0
+    fail := false
+    collector := &businessCollector{
+        count: func(ctx context.Context) (BusinessCounts, error) {
+            calls++
+            if fail {
+                return BusinessCounts{}, errors.New("database down")
+            }
+            return BusinessCounts{Alumni: 12, ActivePekerjaan: 7, TrashPekerjaan: 2}, nil
+        },
+        alumni: prometheus.NewDesc("alumni_count", "Alumni stored.", nil, nil),
+        active: prometheus.NewDesc("pekerjaan_active_count", "Active pekerjaan.", nil, nil),
+        trash:  prometheus.NewDesc("pekerjaan_trash_count", "Trashed pekerjaan.", nil, nil),
+    }
+
+    want := `
+# HELP alumni_count Alumni stored.
+# TYPE alumni_count gauge
+alumni_count 12
+# HELP pekerjaan_trash_count Trashed pekerjaan.
+# TYPE pekerjaan_trash_count gauge
+pekerjaan_trash_count 2
+`
+    if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "alumni_count", "pekerjaan_trash_count"); err != nil {
+        t.Fatal(err)
+    }
+    if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "alumni_count", "pekerjaan_trash_count"); err != nil {
+        t.Fatal(err)
+    }
+    if calls != 1 {
+        t.Fatalf("count called %d times, want 1 within the cache TTL", calls)
+    }
+
+    // An expired cache with a failing count keeps the last values
+    fail = true
+    collector.countedAt = collector.countedAt.Add(-businessCacheTTL)
+    if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "alumni_count", "pekerjaan_trash_count"); err != nil {
+        t.Fatal(err)
+    }
+    if calls != 2 {
+        t.Fatalf("count called %d times, want 2", calls)
+    }
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Registry holds every metric served on /metrics, next to the Go runtime and
+// process collectors
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+    Registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+    )
+}
+
+var (
+    // HTTPRequests counts requests by method, route template and status
+    HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+        Name: "http_requests_total",
+        Help: "HTTP requests by method, route template and status code.",
+    }, []string{"method", "route", "status"})
+
+    // HTTPDuration observes request latency by method, route template and
+    // status
+    HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+        Name:    "http_request_duration_seconds",
+        Help:    "HTTP request latency by method, route template and status code.",
+        Buckets: prometheus.DefBuckets,
+    }, []string{"method", "route", "status"})
+
+    // Logins counts login attempts by result: success, unknown_user,
+    // wrong_password or error
+    Logins = factory.NewCounterVec(prometheus.CounterOpts{
+        Name: "auth_logins_total",
+        Help: "Login attempts by result.",
+    }, []string{"result"})
+
+    // DBDuration observes each repository call, e.g.
+    // method="AlumniRepository.GetAlumni"
+    DBDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+        Name:    "db_operation_duration_seconds",
+        Help:    "Duration of repository calls by method.",
+        Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+    }, []string{"method"})
+
+    // CacheRequests counts cache lookups by namespace and result, hit or
+    // miss
+    CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+        Name: "cache_requests_total",
+        Help: "Cache lookups by namespace and result.",
+    }, []string{"namespace", "result"})
+)
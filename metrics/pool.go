@@ -0,0 +1,57 @@
+package metrics
+
+import (
+    "database/sql"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "go.mongodb.org/mongo-driver/event"
+)
+
+var (
+    mongoPoolOpen = factory.NewGauge(prometheus.GaugeOpts{
+        Name: "mongo_pool_connections_open",
+        Help: "Connections in the MongoDB driver pools.",
+    })
+    mongoPoolInUse = factory.NewGauge(prometheus.GaugeOpts{
+        Name: "mongo_pool_connections_in_use",
+        Help: "MongoDB connections checked out by operations.",
+    })
+    mongoPoolCheckoutFailures = factory.NewCounterVec(prometheus.CounterOpts{
+        Name: "mongo_pool_checkout_failures_total",
+        Help: "Failed MongoDB connection checkouts by reason.",
+    }, []string{"reason"})
+    mongoPoolCleared = factory.NewCounter(prometheus.CounterOpts{
+        Name: "mongo_pool_cleared_total",
+        Help: "Times a MongoDB connection pool was cleared after an error.",
+    })
+)
+
+// MongoPoolMonitor feeds the mongo_pool_* metrics from the driver's pool
+// events; pass it to options.Client().SetPoolMonitor
+func MongoPoolMonitor() *event.PoolMonitor {
+    return &event.PoolMonitor{
+        Event: func(e *event.PoolEvent) {
+            switch e.Type {
+            case event.ConnectionCreated:
+                mongoPoolOpen.Inc()
+            case event.ConnectionClosed:
+                mongoPoolOpen.Dec()
+            case event.GetSucceeded:
+                mongoPoolInUse.Inc()
+            case event.ConnectionReturned:
+                mongoPoolInUse.Dec()
+            case event.GetFailed:
+                mongoPoolCheckoutFailures.WithLabelValues(e.Reason).Inc()
+            case event.PoolCleared:
+                mongoPoolCleared.Inc()
+            }
+        },
+    }
+}
+
+// RegisterPostgresPool exports the database/sql pool stats of db as
+// go_sql_* metrics
+func RegisterPostgresPool(db *sql.DB) {
+    Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
@@ -3,6 +3,7 @@ package middleware
 import (
     "strings"
     
+    "go-fiber/logger"
     "go-fiber/utils"
     
     "github.com/gofiber/fiber/v2"
@@ -49,6 +50,7 @@ func AuthRequired() fiber.Handler {
         c.Locals("user_id", userID)
         c.Locals("username", claims.Username)
         c.Locals("role", claims.Role)
+        logger.AddFields(c.UserContext(), "user_id", claims.UserID, "role", claims.Role)
 
         return c.Next()
     }
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+    "fmt"
+
+    "go-fiber/config"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit rejects requests whose body is larger than limit with 413. The
+// server already refuses bodies over the largest configured limit, so this
+// only tightens it for a route group.
+func BodyLimit(limit config.ByteSize) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        if int64(len(c.Body())) <= int64(limit) {
+            return c.Next()
+        }
+        return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+            "error":   fmt.Sprintf("Ukuran body melebihi batas %s", limit),
+            "success": false,
+        })
+    }
+}
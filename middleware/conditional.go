@@ -0,0 +1,64 @@
+package middleware
+
+import (
+    "log/slog"
+    "net/http"
+    "time"
+
+    "go-fiber/cache"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// ConditionalGet answers GET requests with 304 Not Modified when the client
+// already holds the response. Successful responses get an ETag of their body
+// and, when the cache is shared between replicas, Last-Modified from the last
+// change of the data namespaces they are built from. A request whose
+// If-Modified-Since is not older than that change is answered before the
+// handler runs; If-None-Match takes precedence and is checked against the new
+// body. A process-local cache does not see the writes of other replicas, so
+// only the ETag is used with it.
+func ConditionalGet(namespaces ...string) fiber.Handler {
+    tag := etag.New(etag.Config{Weak: true})
+    cached := cache.Default()
+
+    return func(c *fiber.Ctx) error {
+        if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+            return c.Next()
+        }
+        // Lists and trash depend on the caller
+        c.Vary(fiber.HeaderAuthorization)
+
+        if cached == nil || cached.Local() || len(namespaces) == 0 {
+            return tag(c)
+        }
+
+        var modified time.Time
+        for _, namespace := range namespaces {
+            version, err := cached.Version(c.UserContext(), namespace)
+            if err != nil {
+                slog.WarnContext(c.UserContext(), "cache unavailable", "namespace", namespace, "error", err)
+                return tag(c)
+            }
+            if version.After(modified) {
+                modified = version
+            }
+        }
+
+        if c.Get(fiber.HeaderIfNoneMatch) == "" {
+            if since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince)); err == nil && !modified.After(since) {
+                c.Set(fiber.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+                return c.SendStatus(fiber.StatusNotModified)
+            }
+        }
+
+        if err := tag(c); err != nil {
+            return err
+        }
+        if status := c.Response().StatusCode(); status == fiber.StatusOK || status == fiber.StatusNotModified {
+            c.Set(fiber.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+        }
+        return nil
+    }
+}
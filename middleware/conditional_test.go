@@ -0,0 +1,119 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "go-fiber/cache"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// sharedStore stands in for a database store seen by every replica
+type sharedStore struct {
+    *cache.LRU
+}
+
+func conditionalApp(t *testing.T, store cache.Store) (*fiber.App, *cache.Cache, *int) {
+    c := cache.New(store, time.Minute)
+    cache.SetDefault(c)
+    t.Cleanup(func() { cache.SetDefault(nil) })
+
+    calls := 0
+    app := fiber.New()
+    app.Get("/alumni", ConditionalGet("alumni"), func(c *fiber.Ctx) error {
+        calls++
+        return c.JSON(fiber.Map{"success": true, "data": []string{"Budi"}})
+    })
+    return app, c, &calls
+}
+
+func get(t *testing.T, app *fiber.App, headers map[string]string) (int, string, string) {
+    t.Helper()
+    req := httptest.NewRequest("GET", "/alumni", nil)
+    for k, v := range headers {
+        req.Header.Set(k, v)
+    }
+    resp, err := app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return resp.StatusCode, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+}
+
+func TestConditionalGetETag(t *testing.T) {
+    app, _, _ := conditionalApp(t, sharedStore{cache.NewLRU(10)})
+
+    status, etag, lastModified := get(t, app, nil)
+    if status != 200 || etag == "" || lastModified == "" {
+        t.Fatalf("status %d, ETag %q, Last-Modified %q", status, etag, lastModified)
+    }
+
+    if status, _, _ := get(t, app, map[string]string{"If-None-Match": etag}); status != 304 {
+        t.Errorf("matching ETag: status = %d, want 304", status)
+    }
+    if status, _, _ := get(t, app, map[string]string{"If-None-Match": `W/"1-1"`}); status != 200 {
+        t.Errorf("other ETag: status = %d, want 200", status)
+    }
+}
+
+func TestConditionalGetLastModified(t *testing.T) {
+    app, c, calls := conditionalApp(t, sharedStore{cache.NewLRU(10)})
+
+    _, _, lastModified := get(t, app, nil)
+    status, _, _ := get(t, app, map[string]string{"If-Modified-Since": lastModified})
+    if status != 304 || *calls != 1 {
+        t.Fatalf("unchanged: status %d after %d handler calls, want 304 without calling the handler", status, *calls)
+    }
+
+    c.Invalidate(context.Background(), "alumni")
+    status, _, changed := get(t, app, map[string]string{"If-Modified-Since": lastModified})
+    if status != 200 || changed == lastModified {
+        t.Errorf("after a write: status %d, Last-Modified %q, want 200 with a later date", status, changed)
+    }
+}
+
+func TestConditionalGetAcrossReplicas(t *testing.T) {
+    shared := sharedStore{cache.NewLRU(10)}
+    _, writer, _ := conditionalApp(t, shared)
+    reader, _, _ := conditionalApp(t, shared)
+
+    _, _, lastModified := get(t, reader, nil)
+    writer.Invalidate(context.Background(), "alumni")
+    if status, _, _ := get(t, reader, map[string]string{"If-Modified-Since": lastModified}); status != 200 {
+        t.Errorf("shared store: status = %d after a write on another replica, want 200", status)
+    }
+
+    // Each replica's LRU only sees its own writes, so Last-Modified is not
+    // trusted with it
+    _, local, _ := conditionalApp(t, cache.NewLRU(10))
+    reader, _, calls := conditionalApp(t, cache.NewLRU(10))
+
+    status, etag, lastModified := get(t, reader, nil)
+    if status != 200 || etag == "" || lastModified != "" {
+        t.Fatalf("local store: status %d, ETag %q, Last-Modified %q, want only an ETag", status, etag, lastModified)
+    }
+    local.Invalidate(context.Background(), "alumni")
+    since := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+    if status, _, _ := get(t, reader, map[string]string{"If-Modified-Since": since}); status != 200 || *calls != 2 {
+        t.Errorf("local store: status %d after %d handler calls, want 200 from the handler", status, *calls)
+    }
+}
+
+func TestConditionalGetWithoutCache(t *testing.T) {
+    app := fiber.New()
+    app.Get("/users", ConditionalGet("users"), func(c *fiber.Ctx) error {
+        return c.SendString("ok")
+    })
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if resp.Header.Get("ETag") == "" || resp.Header.Get("Last-Modified") != "" {
+        t.Errorf("ETag %q, Last-Modified %q, want only an ETag", resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"))
+    }
+}
@@ -1,11 +1,50 @@
 package middleware
 
 import (
-    "fmt"
+    "log/slog"
+    "strings"
+    "time"
+
     "github.com/gofiber/fiber/v2"
 )
 
-func LoggerMiddleware(c *fiber.Ctx) error {
-    fmt.Println("Request:", c.Method(), c.Path())
-    return c.Next()
+// quietPaths are the probes; successful hits are only logged at debug level
+var quietPaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// Logger writes one access log line per request. The line carries the
+// request ID and, once AuthRequired ran, the user ID and role from the
+// request context. Errors returned by handlers are passed to the app's error
+// handler first, so the logged status is the one the client receives.
+func Logger() fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        start := time.Now()
+
+        if err := c.Next(); err != nil {
+            if err := c.App().ErrorHandler(c, err); err != nil {
+                c.Status(fiber.StatusInternalServerError)
+            }
+        }
+
+        status := c.Response().StatusCode()
+        level := slog.LevelInfo
+        switch {
+        case status >= 500:
+            level = slog.LevelError
+        case status >= 400:
+            level = slog.LevelWarn
+        case quietPaths[c.Path()]:
+            level = slog.LevelDebug
+        }
+
+        slog.Log(c.UserContext(), level, "request",
+            "method", c.Method(),
+            "path", c.Path(),
+            "status", status,
+            "duration", time.Since(start),
+            "bytes", len(c.Response().Body()),
+            "ip", c.IP(),
+            "user_agent", strings.Clone(c.Get(fiber.HeaderUserAgent)),
+        )
+        return nil
+    }
 }
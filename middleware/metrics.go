@@ -0,0 +1,34 @@
+package middleware
+
+import (
+    "strconv"
+    "time"
+
+    "go-fiber/metrics"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// Metrics records every request in the http_* metrics. Requests are labeled
+// by route template (/alumni/:id, not /alumni/123) to keep the label set
+// bounded; requests no route matched share the route "unmatched".
+func Metrics() fiber.Handler {
+    routes := &routeTemplates{}
+
+    return func(c *fiber.Ctx) error {
+        start := time.Now()
+
+        if err := c.Next(); err != nil {
+            if err := c.App().ErrorHandler(c, err); err != nil {
+                c.Status(fiber.StatusInternalServerError)
+            }
+        }
+
+        route := routes.name(c)
+        status := strconv.Itoa(c.Response().StatusCode())
+
+        metrics.HTTPRequests.WithLabelValues(c.Method(), route, status).Inc()
+        metrics.HTTPDuration.WithLabelValues(c.Method(), route, status).Observe(time.Since(start).Seconds())
+        return nil
+    }
+}
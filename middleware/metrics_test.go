@@ -0,0 +1,44 @@
+package middleware
+
+import (
+    "net/http/httptest"
+    "testing"
+
+    "go-fiber/metrics"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsLabelsRouteTemplates(t *testing.T) {
+    app := fiber.New()
+    app.Use(Metrics())
+    alumni := app.Group("/metrics-test", func(c *fiber.Ctx) error { return c.Next() })
+    alumni.Get("/:id", func(c *fiber.Ctx) error {
+        if c.Params("id") == "missing" {
+            return fiber.ErrNotFound
+        }
+        return c.SendStatus(200)
+    })
+
+    for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/metrics-test/missing", "/metrics-test/1/x", "/nope"} {
+        if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    tests := []struct {
+        route, status string
+        want          float64
+    }{
+        {"/metrics-test/:id", "200", 2},
+        {"/metrics-test/:id", "404", 1},
+        {"unmatched", "404", 2},
+    }
+    for _, tt := range tests {
+        got := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", tt.route, tt.status))
+        if got != tt.want {
+            t.Errorf("%s %s: count = %v, want %v", tt.route, tt.status, got, tt.want)
+        }
+    }
+}
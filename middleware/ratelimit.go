@@ -0,0 +1,109 @@
+package middleware
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "log/slog"
+    "math"
+    "strconv"
+    "strings"
+    "time"
+
+    "go-fiber/config"
+    "go-fiber/ratelimit"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyHeader identifies clients of policies keyed by api_key
+const APIKeyHeader = "X-API-Key"
+
+// RateLimit limits the requests of a route group with the policy configured
+// for it (RATE_LIMIT_<GROUP>), using the store set with ratelimit.SetDefault
+// or one kept in memory. Responses carry the RateLimit-* headers; a client
+// over the limit gets 429 with Retry-After. When the store fails the request
+// is let through. Policies keyed by user must come after AuthRequired;
+// policies keyed by api_key only count keys listed in RATE_LIMIT_API_KEYS.
+func RateLimit(group string) fiber.Handler {
+    spec := config.RateLimitPolicy(group)
+    if spec == "" {
+        return func(c *fiber.Ctx) error { return c.Next() }
+    }
+    // Validated with the configuration
+    policy, err := ratelimit.ParsePolicy(spec)
+    if err != nil {
+        panic(err)
+    }
+    store := ratelimit.Default()
+    if store == nil {
+        store = ratelimit.NewMemoryStore()
+    }
+    apiKeys := apiKeyHashes(config.Get().RateLimit.APIKeys)
+
+    return func(c *fiber.Ctx) error {
+        key := group + ":" + rateLimitKey(c, policy.Key, apiKeys)
+        result, err := store.Take(c.UserContext(), key, policy)
+        if err != nil {
+            slog.WarnContext(c.UserContext(), "rate limit store failed", "group", group, "error", err)
+            return c.Next()
+        }
+
+        c.Set("RateLimit-Limit", strconv.Itoa(policy.Burst))
+        c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+        c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+        c.Set("RateLimit-Policy", policy.Header())
+
+        if !result.Allowed {
+            retry := max(ceilSeconds(result.RetryAfter), 1)
+            c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retry))
+            return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+                "error":   fmt.Sprintf("Terlalu banyak permintaan, coba lagi dalam %d detik", retry),
+                "success": false,
+            })
+        }
+        return c.Next()
+    }
+}
+
+// rateLimitKey identifies the client the way the policy counts them. An API
+// key only gets its own bucket when it is one of apiKeys; otherwise anyone
+// could mint buckets by sending random keys.
+func rateLimitKey(c *fiber.Ctx, key string, apiKeys map[string]bool) string {
+    if key == ratelimit.KeyAPIKey {
+        if apiKey := c.Get(APIKeyHeader); apiKey != "" {
+            if hash := apiKeyHash(apiKey); apiKeys[hash] {
+                return "api_key:" + hash
+            }
+        }
+        return "ip:" + c.IP()
+    }
+    if key == ratelimit.KeyUser {
+        if userID, ok := c.Locals("user_id").(primitive.ObjectID); ok {
+            return "user:" + userID.Hex()
+        }
+    }
+    return "ip:" + c.IP()
+}
+
+// apiKeyHashes hashes the comma separated keys of RATE_LIMIT_API_KEYS
+func apiKeyHashes(keys string) map[string]bool {
+    hashes := map[string]bool{}
+    for _, key := range strings.Split(keys, ",") {
+        if key = strings.TrimSpace(key); key != "" {
+            hashes[apiKeyHash(key)] = true
+        }
+    }
+    return hashes
+}
+
+// apiKeyHash keeps raw keys out of the bucket names
+func apiKeyHash(key string) string {
+    sum := sha256.Sum256([]byte(key))
+    return hex.EncodeToString(sum[:12])
+}
+
+func ceilSeconds(d time.Duration) int {
+    return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+    "encoding/json"
+    "net/http/httptest"
+    "testing"
+
+    "go-fiber/config"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func rateLimitApp(t *testing.T, spec string) *fiber.App {
+    cfg := config.Defaults()
+    cfg.RateLimit.Policies = map[string]string{"test": spec}
+    cfg.RateLimit.APIKeys = "one, two"
+    config.Set(cfg)
+    t.Cleanup(func() { config.Set(config.Defaults()) })
+
+    app := fiber.New()
+    app.Use(func(c *fiber.Ctx) error {
+        if id := c.Get("X-User"); id != "" {
+            userID, _ := primitive.ObjectIDFromHex(id)
+            c.Locals("user_id", userID)
+        }
+        return c.Next()
+    })
+    app.Get("/", RateLimit("test"), func(c *fiber.Ctx) error {
+        return c.SendString("ok")
+    })
+    return app
+}
+
+func TestRateLimitHeadersAndRejection(t *testing.T) {
+    app := rateLimitApp(t, "60/1m burst=2 key=ip")
+
+    for _, remaining := range []string{"1", "0"} {
+        resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if resp.StatusCode != 200 {
+            t.Fatalf("status = %d, want 200", resp.StatusCode)
+        }
+        if got := resp.Header.Get("RateLimit-Remaining"); got != remaining {
+            t.Errorf("RateLimit-Remaining = %q, want %q", got, remaining)
+        }
+        if got := resp.Header.Get("RateLimit-Limit"); got != "2" {
+            t.Errorf("RateLimit-Limit = %q, want 2", got)
+        }
+        if got := resp.Header.Get("RateLimit-Policy"); got != "60;w=60;burst=2" {
+            t.Errorf("RateLimit-Policy = %q", got)
+        }
+    }
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if resp.StatusCode != 429 {
+        t.Fatalf("status = %d, want 429", resp.StatusCode)
+    }
+    if got := resp.Header.Get("Retry-After"); got != "1" {
+        t.Errorf("Retry-After = %q, want 1", got)
+    }
+    var body struct {
+        Error   string `json:"error"`
+        Success bool   `json:"success"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        t.Fatal(err)
+    }
+    if body.Success || body.Error == "" {
+        t.Errorf("body = %+v, want the error envelope", body)
+    }
+}
+
+func TestRateLimitKeys(t *testing.T) {
+    alice := primitive.NewObjectID().Hex()
+    bob := primitive.NewObjectID().Hex()
+
+    tests := []struct {
+        name   string
+        spec   string
+        first  map[string]string
+        second map[string]string
+        shared bool
+    }{
+        {"users have their own bucket", "1/1m key=user", map[string]string{"X-User": alice}, map[string]string{"X-User": bob}, false},
+        {"user falls back to the IP", "1/1m key=user", nil, nil, true},
+        {"ip ignores the user", "1/1m key=ip", map[string]string{"X-User": alice}, map[string]string{"X-User": bob}, true},
+        {"api keys have their own bucket", "1/1m key=api_key", map[string]string{"X-API-Key": "one"}, map[string]string{"X-API-Key": "two"}, false},
+        {"unknown api keys share the IP", "1/1m key=api_key", map[string]string{"X-API-Key": "three"}, map[string]string{"X-API-Key": "four"}, true},
+        {"api key falls back to the IP", "1/1m key=api_key", map[string]string{"X-User": alice}, map[string]string{"X-User": bob}, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            app := rateLimitApp(t, tt.spec)
+            status := func(headers map[string]string) int {
+                req := httptest.NewRequest("GET", "/", nil)
+                for k, v := range headers {
+                    req.Header.Set(k, v)
+                }
+                resp, err := app.Test(req)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                return resp.StatusCode
+            }
+
+            status(tt.first)
+            want := 200
+            if tt.shared {
+                want = 429
+            }
+            if got := status(tt.second); got != want {
+                t.Errorf("second request status = %d, want %d", got, want)
+            }
+        })
+    }
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+    cfg := config.Defaults()
+    cfg.RateLimit.Enabled = false
+    config.Set(cfg)
+    t.Cleanup(func() { config.Set(config.Defaults()) })
+
+    app := fiber.New()
+    app.Get("/", RateLimit("auth"), func(c *fiber.Ctx) error { return c.SendString("ok") })
+    for i := 0; i < 20; i++ {
+        resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if resp.StatusCode != 200 || resp.Header.Get("RateLimit-Limit") != "" {
+            t.Fatalf("request %d: status %d, limited while disabled", i, resp.StatusCode)
+        }
+    }
+}
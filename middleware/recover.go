@@ -0,0 +1,35 @@
+package middleware
+
+import (
+    "fmt"
+    "log/slog"
+    "runtime/debug"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// Recover turns a panic in a later handler into a 500 with the standard
+// error envelope. The panic value and stack are logged with the request
+// fields, never sent to the client.
+func Recover() fiber.Handler {
+    return func(c *fiber.Ctx) (err error) {
+        defer func() {
+            r := recover()
+            if r == nil {
+                return
+            }
+            slog.ErrorContext(c.UserContext(), "panic recovered",
+                "panic", fmt.Sprint(r),
+                "method", c.Method(),
+                "path", c.Path(),
+                "stack", string(debug.Stack()),
+            )
+            err = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+                "error":   "Terjadi kesalahan pada server",
+                "success": false,
+            })
+        }()
+
+        return c.Next()
+    }
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+    "encoding/json"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "go-fiber/config"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+func TestRecover(t *testing.T) {
+    app := fiber.New()
+    app.Use(Recover())
+    app.Get("/panic", func(c *fiber.Ctx) error {
+        panic("nil map write in secret handler")
+    })
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if resp.StatusCode != 500 {
+        t.Fatalf("status = %d, want 500", resp.StatusCode)
+    }
+    var body map[string]interface{}
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        t.Fatal(err)
+    }
+    if body["success"] != false || strings.Contains(body["error"].(string), "secret") {
+        t.Errorf("body = %v, want the error envelope without the panic value", body)
+    }
+}
+
+func TestBodyLimit(t *testing.T) {
+    app := fiber.New()
+    app.Post("/login", BodyLimit(16*config.B), func(c *fiber.Ctx) error {
+        return c.SendString("ok")
+    })
+
+    for body, want := range map[string]int{`{"a":"b"}`: 200, `{"username":"admin","password":"x"}`: 413} {
+        resp, err := app.Test(httptest.NewRequest("POST", "/login", strings.NewReader(body)))
+        if err != nil {
+            t.Fatal(err)
+        }
+        if resp.StatusCode != want {
+            t.Errorf("%d byte body: status = %d, want %d", len(body), resp.StatusCode, want)
+        }
+    }
+}
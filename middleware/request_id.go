@@ -0,0 +1,52 @@
+package middleware
+
+import (
+    "context"
+    "strings"
+
+    "go-fiber/logger"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds IDs accepted from clients and proxies
+const maxRequestIDLength = 128
+
+// RequestID reuses the X-Request-ID of the caller, or generates one, and
+// echoes it in the response. The ID is stored in c.Locals("request_id") and
+// in the request context, so every log line written with c.UserContext()
+// carries it. The context derives from base, owned by the server and only
+// cancelled once draining on shutdown is over; fasthttp's own request
+// context is done as soon as the shutdown starts.
+func RequestID(base context.Context) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        id := strings.Clone(c.Get(RequestIDHeader))
+        if !validRequestID(id) {
+            id = utils.UUIDv4()
+        }
+
+        c.Set(RequestIDHeader, id)
+        c.Locals("request_id", id)
+        c.SetUserContext(logger.WithFields(base, "request_id", id))
+
+        return c.Next()
+    }
+}
+
+// validRequestID rejects empty, overlong and non-printable IDs, which would
+// otherwise end up in the logs
+func validRequestID(id string) bool {
+    if id == "" || len(id) > maxRequestIDLength {
+        return false
+    }
+    for _, r := range id {
+        if r < 0x21 || r > 0x7e {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/config"
+    "go-fiber/logger"
+    "go-fiber/utils"
+
+    "github.com/gofiber/fiber/v2"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRequestIDAndUserAttribution(t *testing.T) {
+    cfg := config.Defaults()
+    cfg.JWT.Secret = "test-secret"
+    config.Set(cfg)
+    t.Cleanup(func() { config.Set(config.Defaults()) })
+
+    var buf bytes.Buffer
+    l, err := logger.New(&buf, "info", logger.FormatJSON, "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    previous := slog.Default()
+    slog.SetDefault(l)
+    t.Cleanup(func() { slog.SetDefault(previous) })
+
+    app := fiber.New()
+    app.Use(RequestID(context.Background()), Logger())
+    app.Get("/me", RequestTimeout(time.Second), AuthRequired(), RequestTimeout(2*time.Second), func(c *fiber.Ctx) error {
+        slog.InfoContext(c.UserContext(), "handler")
+        return c.SendStatus(204)
+    })
+
+    userID := primitive.NewObjectID()
+    token, err := utils.GenerateToken(model.User{ID: userID, Username: "admin", Role: "admin"})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    req := httptest.NewRequest("GET", "/me", nil)
+    req.Header.Set("Authorization", "Bearer "+token)
+    req.Header.Set(RequestIDHeader, "trace-123")
+    resp, err := app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := resp.Header.Get(RequestIDHeader); got != "trace-123" {
+        t.Fatalf("response %s = %q, want the incoming ID", RequestIDHeader, got)
+    }
+
+    lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+    if len(lines) != 2 {
+        t.Fatalf("lines = %q", lines)
+    }
+    for _, line := range lines {
+        var record map[string]interface{}
+        if err := json.Unmarshal([]byte(line), &record); err != nil {
+            t.Fatal(err)
+        }
+        if record["request_id"] != "trace-123" || record["user_id"] != userID.Hex() || record["role"] != "admin" {
+            t.Errorf("%s: missing request fields", line)
+        }
+    }
+
+    // Unusable IDs are replaced
+    req = httptest.NewRequest("GET", "/me", nil)
+    req.Header.Set(RequestIDHeader, "bad id\n")
+    resp, err = app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := resp.Header.Get(RequestIDHeader); len(got) != 36 {
+        t.Fatalf("generated ID = %q, want a UUID", got)
+    }
+}
+
+func TestRequestContextSurvivesShutdown(t *testing.T) {
+    base, stop := context.WithCancel(context.Background())
+    defer stop()
+
+    started, release, result := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+    app := fiber.New(fiber.Config{DisableStartupMessage: true})
+    app.Use(RequestID(base))
+    app.Get("/slow", RequestTimeout(time.Minute), func(c *fiber.Ctx) error {
+        close(started)
+        <-release
+        result <- c.UserContext().Err()
+        return c.SendStatus(204)
+    })
+
+    addr := listen(t, app)
+    go http.Get("http://" + addr + "/slow")
+    <-started
+
+    // fasthttp closes its own request context as soon as the shutdown
+    // starts; in-flight requests must keep theirs while they drain
+    shutdown := make(chan error, 1)
+    go func() { shutdown <- app.ShutdownWithTimeout(5 * time.Second) }()
+    time.Sleep(50 * time.Millisecond)
+    close(release)
+    if err := <-result; err != nil {
+        t.Errorf("draining request context: %v, want it still running", err)
+    }
+    if err := <-shutdown; err != nil {
+        t.Errorf("shutdown: %v", err)
+    }
+
+    // Cancelling base ends the requests that did not drain in time
+    ctx, _ := watchDisconnect(logger.WithFields(base, "request_id", "x"), nil)
+    stop()
+    if ctx.Err() == nil {
+        t.Error("request context should end with the server context")
+    }
+}
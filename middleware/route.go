@@ -0,0 +1,33 @@
+package middleware
+
+import (
+    "sync"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// routeTemplates names requests by route template (/alumni/:id, not
+// /alumni/123), keeping metric labels and span names bounded
+type routeTemplates struct {
+    once   sync.Once
+    routes map[string]bool
+}
+
+// name returns the template of the route that handled the request, or
+// "unmatched". Call it after c.Next(). Middleware also shows up as
+// c.Route(), e.g. the group prefix "/alumni" when nothing below it matched,
+// so the route is checked against the registered ones, read on the first
+// request when every route is registered.
+func (t *routeTemplates) name(c *fiber.Ctx) string {
+    t.once.Do(func() {
+        t.routes = map[string]bool{}
+        for _, route := range c.App().GetRoutes(true) {
+            t.routes[route.Method+" "+route.Path] = true
+        }
+    })
+
+    if route := c.Route(); t.routes[route.Method+" "+route.Path] {
+        return route.Path
+    }
+    return "unmatched"
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+    "context"
+    "errors"
+    "net"
+    "syscall"
+    "time"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// timeoutParentKey is the Locals key of the context RequestTimeout derives
+// from, kept so a second RequestTimeout does not nest inside the first
+const timeoutParentKey = "timeout_parent"
+
+// disconnectInterval is how often RequestTimeout checks that the client is
+// still connected
+var disconnectInterval = 100 * time.Millisecond
+
+// ErrClientDisconnected is the cause of a request context cancelled because
+// the client closed the connection
+var ErrClientDisconnected = errors.New("client disconnected")
+
+// RequestTimeout gives the request a context that is cancelled after d, when
+// the client closes the connection or when the server stops draining. Services
+// hand c.UserContext() to the repositories, so in-flight queries stop with
+// it. The deadline is added to the context set by RequestID, keeping its log
+// fields. A later RequestTimeout on a route replaces the one set on its
+// group.
+func RequestTimeout(d time.Duration) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        parent, ok := c.Locals(timeoutParentKey).(context.Context)
+        if !ok {
+            var stop context.CancelFunc
+            parent, stop = watchDisconnect(c.UserContext(), c.Context().Conn())
+            defer stop()
+            c.Locals(timeoutParentKey, parent)
+        }
+
+        ctx, cancel := context.WithTimeout(parent, d)
+        defer cancel()
+
+        c.SetUserContext(ctx)
+        return c.Next()
+    }
+}
+
+// watchDisconnect returns a context cancelled with ErrClientDisconnected once
+// the client closes conn. fasthttp has read the request before the handler
+// runs, so the socket is peeked without taking pipelined bytes from it.
+// Connections that are not sockets, like those of app.Test, are not watched.
+func watchDisconnect(parent context.Context, conn net.Conn) (context.Context, context.CancelFunc) {
+    ctx, cancel := context.WithCancelCause(parent)
+    stop := func() { cancel(nil) }
+
+    raw := rawConn(conn)
+    if raw == nil || !peekSupported {
+        return ctx, stop
+    }
+
+    go func() {
+        ticker := time.NewTicker(disconnectInterval)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+                if peekClosed(raw) {
+                    cancel(ErrClientDisconnected)
+                    return
+                }
+            }
+        }
+    }()
+    return ctx, stop
+}
+
+// rawConn returns the socket under conn, unwrapping TLS, or nil
+func rawConn(conn net.Conn) syscall.RawConn {
+    if tlsConn, ok := conn.(interface{ NetConn() net.Conn }); ok {
+        conn = tlsConn.NetConn()
+    }
+    sc, ok := conn.(syscall.Conn)
+    if !ok {
+        return nil
+    }
+    raw, err := sc.SyscallConn()
+    if err != nil {
+        return nil
+    }
+    return raw
+}
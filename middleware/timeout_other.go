@@ -0,0 +1,12 @@
+//go:build !unix
+
+package middleware
+
+import "syscall"
+
+// Without MSG_PEEK disconnects are not detected, only the deadline applies
+const peekSupported = false
+
+func peekClosed(raw syscall.RawConn) bool {
+    return false
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+    "bufio"
+    "context"
+    "errors"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// listen serves app on a loopback port, for the tests needing a real socket
+func listen(t *testing.T, app *fiber.App) string {
+    ln, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    go app.Listener(ln)
+    t.Cleanup(func() { app.ShutdownWithTimeout(time.Second) })
+    return ln.Addr().String()
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+    t.Helper()
+    defer resp.Body.Close()
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return string(body)
+}
+
+func TestRequestTimeoutDeadline(t *testing.T) {
+    app := fiber.New()
+    app.Get("/slow", RequestTimeout(20*time.Millisecond), func(c *fiber.Ctx) error {
+        <-c.UserContext().Done()
+        return c.SendString(c.UserContext().Err().Error())
+    })
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/slow", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if body := readBody(t, resp); body != context.DeadlineExceeded.Error() {
+        t.Errorf("body = %q, want the deadline error", body)
+    }
+}
+
+func TestRequestTimeoutCancelsOnDisconnect(t *testing.T) {
+    if !peekSupported {
+        t.Skip("disconnects are not detected on this platform")
+    }
+
+    started, cause := make(chan struct{}), make(chan error, 1)
+    app := fiber.New(fiber.Config{DisableStartupMessage: true})
+    app.Get("/slow", RequestTimeout(time.Minute), RequestTimeout(30*time.Second), func(c *fiber.Ctx) error {
+        close(started)
+        select {
+        case <-c.UserContext().Done():
+            cause <- context.Cause(c.UserContext())
+        case <-time.After(5 * time.Second):
+            cause <- nil
+        }
+        return c.SendStatus(fiber.StatusServiceUnavailable)
+    })
+
+    conn, err := net.Dial("tcp", listen(t, app))
+    if err != nil {
+        t.Fatal(err)
+    }
+    conn.Write([]byte("GET /slow HTTP/1.1\r\nHost: test\r\n\r\n"))
+    <-started
+    conn.Close()
+
+    if err := <-cause; !errors.Is(err, ErrClientDisconnected) {
+        t.Errorf("cause = %v, want %v", err, ErrClientDisconnected)
+    }
+}
+
+func TestRequestTimeoutKeepsPipelinedRequests(t *testing.T) {
+    app := fiber.New(fiber.Config{DisableStartupMessage: true})
+    app.Get("/", RequestTimeout(time.Minute), func(c *fiber.Ctx) error {
+        // Long enough for a few disconnect checks
+        time.Sleep(3 * disconnectInterval)
+        if err := c.UserContext().Err(); err != nil {
+            return c.Status(fiber.StatusServiceUnavailable).SendString(err.Error())
+        }
+        return c.SendString("ok")
+    })
+
+    conn, err := net.Dial("tcp", listen(t, app))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+    conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\nGET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+
+    reader := bufio.NewReader(conn)
+    for i := 0; i < 2; i++ {
+        conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+        resp, err := http.ReadResponse(reader, nil)
+        if err != nil {
+            t.Fatalf("response %d: %v", i+1, err)
+        }
+        if body := readBody(t, resp); resp.StatusCode != 200 || body != "ok" {
+            t.Errorf("response %d: %d %q", i+1, resp.StatusCode, body)
+        }
+    }
+}
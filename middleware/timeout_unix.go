@@ -0,0 +1,32 @@
+//go:build unix
+
+package middleware
+
+import (
+    "errors"
+    "syscall"
+)
+
+const peekSupported = true
+
+// peekClosed reports whether the peer has closed the socket: a peek reads
+// end of file or fails with anything but "no data yet". Pending bytes, such
+// as a pipelined request, mean it is still open. A TLS close_notify counts
+// as pending, so there the peer's FIN is only seen once it is read.
+func peekClosed(raw syscall.RawConn) bool {
+    var closed bool
+    buf := make([]byte, 1)
+    err := raw.Read(func(fd uintptr) bool {
+        n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+        switch {
+        case err == nil:
+            closed = n == 0
+        case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EWOULDBLOCK), errors.Is(err, syscall.EINTR):
+            closed = false
+        default:
+            closed = true
+        }
+        return true
+    })
+    return closed || err != nil
+}
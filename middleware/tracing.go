@@ -0,0 +1,69 @@
+package middleware
+
+import (
+    "fmt"
+    "strings"
+
+    "go-fiber/logger"
+    "go-fiber/tracing"
+
+    "github.com/gofiber/fiber/v2"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request, continuing the trace of
+// an incoming traceparent header. The span is named after the route
+// template once the request is handled, e.g. "GET /alumni/:id". Services and
+// repositories see it through c.UserContext(), and the trace ID is added to
+// the log fields. Register it after RequestID.
+func Tracing() fiber.Handler {
+    routes := &routeTemplates{}
+
+    return func(c *fiber.Ctx) error {
+        carrier := propagation.HeaderCarrier{}
+        c.Request().Header.VisitAll(func(key, value []byte) {
+            carrier.Set(string(key), string(value))
+        })
+        ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+        ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(
+                attribute.String("http.request.method", c.Method()),
+                attribute.String("url.path", strings.Clone(c.Path())),
+                attribute.String("client.address", c.IP()),
+            ),
+        )
+        defer span.End()
+
+        if id, ok := c.Locals("request_id").(string); ok {
+            span.SetAttributes(attribute.String("request_id", id))
+        }
+        if span.SpanContext().IsValid() {
+            logger.AddFields(ctx, "trace_id", span.SpanContext().TraceID().String())
+        }
+        c.SetUserContext(ctx)
+
+        if err := c.Next(); err != nil {
+            if err := c.App().ErrorHandler(c, err); err != nil {
+                c.Status(fiber.StatusInternalServerError)
+            }
+        }
+
+        route := routes.name(c)
+        status := c.Response().StatusCode()
+        span.SetName(c.Method() + " " + route)
+        span.SetAttributes(
+            attribute.String("http.route", route),
+            attribute.Int("http.response.status_code", status),
+        )
+        if status >= 500 {
+            span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+        }
+        return nil
+    }
+}
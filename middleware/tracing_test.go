@@ -0,0 +1,81 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "net/http/httptest"
+    "testing"
+
+    "go-fiber/config"
+    "go-fiber/tracing"
+
+    "github.com/gofiber/fiber/v2"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTracingContinuesIncomingTrace(t *testing.T) {
+    var buf bytes.Buffer
+    exporter, err := stdouttrace.New(stdouttrace.WithWriter(&buf))
+    if err != nil {
+        t.Fatal(err)
+    }
+    provider := tracing.NewProvider(exporter, config.Defaults().Tracing, sdktrace.WithSyncer(exporter))
+    previous := otel.GetTracerProvider()
+    otel.SetTracerProvider(provider)
+    otel.SetTextMapPropagator(propagation.TraceContext{})
+    t.Cleanup(func() {
+        provider.Shutdown(context.Background())
+        otel.SetTracerProvider(previous)
+    })
+
+    app := fiber.New()
+    app.Use(RequestID(context.Background()), Tracing())
+    app.Get("/alumni/:id", func(c *fiber.Ctx) error {
+        _, span := tracing.Start(c.UserContext(), "AlumniService.GetAlumni")
+        span.End()
+        return c.SendStatus(500)
+    })
+
+    req := httptest.NewRequest("GET", "/alumni/42", nil)
+    req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    if _, err := app.Test(req); err != nil {
+        t.Fatal(err)
+    }
+
+    type span struct {
+        Name        string
+        SpanContext struct{ TraceID, SpanID string }
+        Parent      struct{ TraceID, SpanID string }
+        Status      struct{ Code string }
+    }
+    var spans []span
+    decoder := json.NewDecoder(&buf)
+    for {
+        var s span
+        if err := decoder.Decode(&s); err == io.EOF {
+            break
+        } else if err != nil {
+            t.Fatal(err)
+        }
+        spans = append(spans, s)
+    }
+    if len(spans) != 2 {
+        t.Fatalf("spans = %+v", spans)
+    }
+    service, server := spans[0], spans[1]
+
+    if server.Name != "GET /alumni/:id" || server.Status.Code != "Error" {
+        t.Errorf("server span = %+v", server)
+    }
+    if server.SpanContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || server.Parent.SpanID != "00f067aa0ba902b7" {
+        t.Errorf("server span did not continue the incoming trace: %+v", server)
+    }
+    if service.Parent.SpanID != server.SpanContext.SpanID {
+        t.Errorf("service span is not a child of the server span")
+    }
+}
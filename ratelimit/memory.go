@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+// sweepInterval is how often the memory store drops refilled buckets
+const sweepInterval = time.Minute
+
+// bucket is the state of one key
+type bucket struct {
+    tokens  float64
+    updated time.Time
+}
+
+// take refills the bucket for the time passed since its last use and takes a
+// token when one is available
+func (b bucket) take(p Policy, now time.Time) (bucket, bool) {
+    if b.updated.IsZero() {
+        b.tokens = float64(p.Burst)
+    } else if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+        b.tokens = min(float64(p.Burst), b.tokens+elapsed*p.rate())
+    }
+    b.updated = now
+
+    if b.tokens < 1 {
+        return b, false
+    }
+    b.tokens--
+    return b, true
+}
+
+// MemoryStore keeps the buckets in this process. Every replica counts on
+// its own, so use a shared store when running more than one.
+type MemoryStore struct {
+    mu      sync.Mutex
+    buckets map[string]memoryBucket
+    swept   time.Time
+    now     func() time.Time
+}
+
+type memoryBucket struct {
+    bucket
+    full time.Time // when the bucket is refilled and can be forgotten
+}
+
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{buckets: map[string]memoryBucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := s.now()
+    if now.Sub(s.swept) >= sweepInterval {
+        for k, b := range s.buckets {
+            if now.After(b.full) {
+                delete(s.buckets, k)
+            }
+        }
+        s.swept = now
+    }
+
+    b, allowed := s.buckets[key].take(p, now)
+    r := result(p, b.tokens, allowed)
+    s.buckets[key] = memoryBucket{bucket: b, full: now.Add(r.Reset)}
+    return r, nil
+}
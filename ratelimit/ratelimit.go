@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+    "context"
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Keys a policy can count requests by
+const (
+    KeyIP     = "ip"
+    KeyUser   = "user"    // the authenticated user, falling back to the IP
+    KeyAPIKey = "api_key" // a configured X-API-Key, falling back to the IP
+)
+
+// Policy is a token bucket: it holds up to Burst tokens and refills
+// Requests tokens every Period; each request takes one
+type Policy struct {
+    Requests int
+    Period   time.Duration
+    Burst    int
+    Key      string
+}
+
+// ParsePolicy reads a policy written as "<requests>/<period>" followed by
+// optional "burst=<n>" and "key=ip|user|api_key", e.g. "10/1m burst=5
+// key=ip". Burst defaults to requests and key to ip.
+func ParsePolicy(spec string) (Policy, error) {
+    fields := strings.Fields(spec)
+    if len(fields) == 0 {
+        return Policy{}, fmt.Errorf("empty rate limit policy")
+    }
+
+    count, period, ok := strings.Cut(fields[0], "/")
+    requests, err := strconv.Atoi(count)
+    if !ok || err != nil || requests <= 0 {
+        return Policy{}, fmt.Errorf("rate limit %q: expected <requests>/<period>, e.g. 10/1m", spec)
+    }
+    p := Policy{Requests: requests, Burst: requests, Key: KeyIP}
+    if p.Period, err = time.ParseDuration(period); err != nil || p.Period <= 0 {
+        return Policy{}, fmt.Errorf("rate limit %q: invalid period %q", spec, period)
+    }
+
+    for _, field := range fields[1:] {
+        name, value, _ := strings.Cut(field, "=")
+        switch name {
+        case "burst":
+            if p.Burst, err = strconv.Atoi(value); err != nil || p.Burst <= 0 {
+                return Policy{}, fmt.Errorf("rate limit %q: invalid burst %q", spec, value)
+            }
+        case "key":
+            if value != KeyIP && value != KeyUser && value != KeyAPIKey {
+                return Policy{}, fmt.Errorf("rate limit %q: key must be ip, user or api_key", spec)
+            }
+            p.Key = value
+        default:
+            return Policy{}, fmt.Errorf("rate limit %q: unknown option %q", spec, field)
+        }
+    }
+    return p, nil
+}
+
+// rate is the refill speed in tokens per second
+func (p Policy) rate() float64 {
+    return float64(p.Requests) / p.Period.Seconds()
+}
+
+// Header renders the policy for the RateLimit-Policy response header
+func (p Policy) Header() string {
+    return fmt.Sprintf("%d;w=%d;burst=%d", p.Requests, int(math.Ceil(p.Period.Seconds())), p.Burst)
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+    Allowed    bool
+    Remaining  int           // whole tokens left
+    Reset      time.Duration // until the bucket is full again
+    RetryAfter time.Duration // until the next token, when not allowed
+}
+
+// Store keeps the buckets. Take refills the bucket of key and takes one
+// token if there is one.
+type Store interface {
+    Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// result describes a bucket holding tokens after the request was counted
+func result(p Policy, tokens float64, allowed bool) Result {
+    r := Result{
+        Allowed:   allowed,
+        Remaining: int(math.Floor(tokens)),
+        Reset:     seconds((float64(p.Burst) - tokens) / p.rate()),
+    }
+    if !allowed {
+        r.RetryAfter = seconds((1 - tokens) / p.rate())
+    }
+    return r
+}
+
+func seconds(s float64) time.Duration {
+    if s <= 0 {
+        return 0
+    }
+    return time.Duration(s * float64(time.Second))
+}
+
+var defaultStore Store
+
+// SetDefault makes store the one used by rate limits created afterwards
+func SetDefault(store Store) {
+    defaultStore = store
+}
+
+// Default returns the store set with SetDefault, or nil
+func Default() Store {
+    return defaultStore
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+    "context"
+    "testing"
+    "time"
+)
+
+func TestParsePolicy(t *testing.T) {
+    tests := []struct {
+        spec string
+        want Policy
+        err  bool
+    }{
+        {"10/1m", Policy{Requests: 10, Period: time.Minute, Burst: 10, Key: KeyIP}, false},
+        {"300/1m burst=60 key=user", Policy{Requests: 300, Period: time.Minute, Burst: 60, Key: KeyUser}, false},
+        {"5/1s key=api_key", Policy{Requests: 5, Period: time.Second, Burst: 5, Key: KeyAPIKey}, false},
+        {"", Policy{}, true},
+        {"10", Policy{}, true},
+        {"0/1m", Policy{}, true},
+        {"10/forever", Policy{}, true},
+        {"10/1m burst=0", Policy{}, true},
+        {"10/1m key=session", Policy{}, true},
+        {"10/1m window=5", Policy{}, true},
+    }
+    for _, tt := range tests {
+        got, err := ParsePolicy(tt.spec)
+        if tt.err {
+            if err == nil {
+                t.Errorf("ParsePolicy(%q) = %+v, want error", tt.spec, got)
+            }
+            continue
+        }
+        if err != nil || got != tt.want {
+            t.Errorf("ParsePolicy(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+        }
+    }
+}
+
+func TestPolicyHeader(t *testing.T) {
+    p, _ := ParsePolicy("300/1m burst=60")
+    if got := p.Header(); got != "300;w=60;burst=60" {
+        t.Errorf("Header() = %q", got)
+    }
+}
+
+func TestMemoryStoreRefills(t *testing.T) {
+    p := Policy{Requests: 60, Period: time.Minute, Burst: 2, Key: KeyIP}
+    now := time.Unix(1700000000, 0)
+    store := NewMemoryStore()
+    store.now = func() time.Time { return now }
+    ctx := context.Background()
+
+    for i, remaining := range []int{1, 0} {
+        r, _ := store.Take(ctx, "a", p)
+        if !r.Allowed || r.Remaining != remaining {
+            t.Fatalf("request %d: %+v, want allowed with %d remaining", i, r, remaining)
+        }
+    }
+
+    r, _ := store.Take(ctx, "a", p)
+    if r.Allowed || r.RetryAfter != time.Second || r.Reset != 2*time.Second {
+        t.Fatalf("over the limit: %+v, want denied, retry after 1s, reset 2s", r)
+    }
+    if r, _ := store.Take(ctx, "b", p); !r.Allowed {
+        t.Fatal("other keys have their own bucket")
+    }
+
+    now = now.Add(time.Second)
+    if r, _ := store.Take(ctx, "a", p); !r.Allowed || r.Remaining != 0 {
+        t.Fatalf("after one second: %+v, want one token refilled", r)
+    }
+
+    now = now.Add(time.Hour)
+    if r, _ := store.Take(ctx, "a", p); !r.Allowed || r.Remaining != 1 {
+        t.Fatalf("after an hour: %+v, want the bucket capped at burst", r)
+    }
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+    p := Policy{Requests: 1, Period: time.Second, Burst: 1, Key: KeyIP}
+    now := time.Unix(1700000000, 0)
+    store := NewMemoryStore()
+    store.now = func() time.Time { return now }
+
+    store.Take(context.Background(), "a", p)
+    now = now.Add(sweepInterval)
+    store.Take(context.Background(), "b", p)
+
+    if _, ok := store.buckets["a"]; ok {
+        t.Error("refilled bucket was not swept")
+    }
+    if _, ok := store.buckets["b"]; !ok {
+        t.Error("bucket in use was swept")
+    }
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "log/slog"
+    "sync"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore shares the buckets between replicas in a MongoDB collection,
+// one document per key. Each Take is a single atomic upsert computed with the
+// server clock; a TTL index on expires_at removes refilled buckets.
+type MongoStore struct {
+    coll *mongo.Collection
+}
+
+func NewMongoStore(coll *mongo.Collection) *MongoStore {
+    return &MongoStore{coll: coll}
+}
+
+func (s *MongoStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+    burst := float64(p.Burst)
+    elapsed := bson.M{"$divide": bson.A{
+        bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$updated_at", "$$NOW"}}}},
+        1000,
+    }}
+
+    pipeline := mongo.Pipeline{
+        {{Key: "$set", Value: bson.M{
+            "tokens": bson.M{"$min": bson.A{burst, bson.M{"$add": bson.A{
+                bson.M{"$ifNull": bson.A{"$tokens", burst}},
+                bson.M{"$multiply": bson.A{elapsed, p.rate()}},
+            }}}},
+            "updated_at": "$$NOW",
+        }}},
+        {{Key: "$set", Value: bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}}},
+        {{Key: "$set", Value: bson.M{
+            "tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+            "expires_at": bson.M{"$add": bson.A{"$$NOW", fullAfter(p).Milliseconds()}},
+        }}},
+    }
+
+    var doc struct {
+        Tokens  float64 `bson:"tokens"`
+        Allowed bool    `bson:"allowed"`
+    }
+    err := s.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline,
+        options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+    ).Decode(&doc)
+    if err != nil {
+        return Result{}, err
+    }
+    return result(p, doc.Tokens, doc.Allowed), nil
+}
+
+// PostgresStore shares the buckets between replicas in the rate_limits
+// table. Each Take is a single upsert computed with the server clock; rows
+// of refilled buckets are deleted once per sweepInterval.
+type PostgresStore struct {
+    db *sql.DB
+
+    mu    sync.Mutex
+    swept time.Time
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+    return &PostgresStore{db: db}
+}
+
+// postgresRefilled is the token count of the existing row refilled up to now;
+// $2 is the burst and $3 the rate per second
+const postgresRefilled = `LEAST($2::float8, r.tokens + EXTRACT(EPOCH FROM clock_timestamp() - r.updated_at) * $3::float8)`
+
+var postgresTake = fmt.Sprintf(`
+    INSERT INTO rate_limits AS r (key, tokens, allowed, updated_at, expires_at)
+    VALUES ($1, $2::float8 - 1, true, clock_timestamp(), clock_timestamp() + $4::float8 * interval '1 second')
+    ON CONFLICT (key) DO UPDATE SET
+        tokens = CASE WHEN %[1]s >= 1 THEN %[1]s - 1 ELSE %[1]s END,
+        allowed = %[1]s >= 1,
+        updated_at = clock_timestamp(),
+        expires_at = clock_timestamp() + $4::float8 * interval '1 second'
+    RETURNING tokens, allowed`, postgresRefilled)
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+    s.sweep(ctx)
+
+    var tokens float64
+    var allowed bool
+    err := s.db.QueryRowContext(ctx, postgresTake, key, p.Burst, p.rate(), fullAfter(p).Seconds()).Scan(&tokens, &allowed)
+    if err != nil {
+        return Result{}, err
+    }
+    return result(p, tokens, allowed), nil
+}
+
+func (s *PostgresStore) sweep(ctx context.Context) {
+    s.mu.Lock()
+    if time.Since(s.swept) < sweepInterval {
+        s.mu.Unlock()
+        return
+    }
+    s.swept = time.Now()
+    s.mu.Unlock()
+
+    if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limits WHERE expires_at < clock_timestamp()`); err != nil {
+        slog.WarnContext(ctx, "sweeping rate limits failed", "error", err)
+    }
+}
+
+// fullAfter is how long an empty bucket takes to refill completely, after
+// which its stored state is no longer needed
+func fullAfter(p Policy) time.Duration {
+    return seconds(float64(p.Burst) / p.rate())
+}
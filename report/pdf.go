@@ -0,0 +1,94 @@
+package report
+
+import (
+    "bytes"
+    "fmt"
+
+    "go-fiber/app/model"
+
+    "github.com/go-pdf/fpdf"
+)
+
+// Layout of the A4 page, in millimetres
+const (
+    pdfWidth      = 190 // between the default margins
+    pdfNumberCell = 40
+    pdfRowHeight  = 7
+)
+
+// PDF renders the report as an A4 document with one table per section
+func PDF(r model.AccreditationReport) ([]byte, error) {
+    pdf := fpdf.New("P", "mm", "A4", "")
+    // The core fonts are cp1252, so names are translated from UTF-8
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+    pdf.SetTitle(Title+" "+r.Jurusan+" "+fmt.Sprint(r.TahunLulus), true)
+    pdf.SetCreator("alumni-api", true)
+    pdf.SetCreationDate(r.GeneratedAt)
+    pdf.SetModificationDate(r.GeneratedAt)
+    pdf.AliasNbPages("")
+    pdf.SetFooterFunc(func() {
+        pdf.SetY(-15)
+        pdf.SetFont("Helvetica", "I", 8)
+        pdf.CellFormat(0, 10, fmt.Sprintf("Halaman %d dari {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+    })
+    pdf.AddPage()
+
+    pdf.SetFont("Helvetica", "B", 16)
+    pdf.CellFormat(0, 10, tr(Title), "", 1, "L", false, 0, "")
+    pdf.SetFont("Helvetica", "", 10)
+    for _, line := range subtitle(r) {
+        pdf.CellFormat(0, 6, tr(line), "", 1, "L", false, 0, "")
+    }
+
+    for _, t := range tables(r) {
+        pdf.Ln(6)
+        pdf.SetFont("Helvetica", "B", 12)
+        pdf.CellFormat(0, 8, tr(t.Title), "", 1, "L", false, 0, "")
+
+        widths := make([]float64, len(t.Header))
+        widths[0] = pdfWidth - pdfNumberCell*float64(len(t.Header)-1)
+        for i := 1; i < len(widths); i++ {
+            widths[i] = pdfNumberCell
+        }
+
+        pdf.SetFont("Helvetica", "B", 10)
+        pdf.SetFillColor(220, 230, 241)
+        for i, header := range t.Header {
+            pdf.CellFormat(widths[i], pdfRowHeight, tr(header), "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+
+        pdf.SetFont("Helvetica", "", 10)
+        for _, row := range t.Rows {
+            for i, cell := range row {
+                align := "R"
+                if i == 0 {
+                    align = "L"
+                }
+                pdf.CellFormat(widths[i], pdfRowHeight, clip(pdf, tr(text(cell)), widths[i]), "1", 0, align, false, 0, "")
+            }
+            pdf.Ln(-1)
+        }
+
+        if t.Note != "" {
+            pdf.SetFont("Helvetica", "I", 8)
+            pdf.MultiCell(0, 5, tr(t.Note), "", "L", false)
+        }
+    }
+
+    var buf bytes.Buffer
+    if err := pdf.Output(&buf); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// clip shortens s to the first line that fits a cell of width w
+func clip(pdf *fpdf.Fpdf, s string, w float64) string {
+    lines := pdf.SplitText(s, w-2)
+    if len(lines) <= 1 {
+        return s
+    }
+    return lines[0] + "..."
+}
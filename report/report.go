@@ -0,0 +1,120 @@
+// Package report renders the accreditation report of one jurusan and
+// tahun_lulus. Every format lays out the same tables, built once by tables.
+package report
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+
+    "go-fiber/app/model"
+)
+
+// Title heads every generated document
+const Title = "Laporan Tracer Study Alumni"
+
+// Render generates the report in format, one of model.ReportFormats
+func Render(format string, r model.AccreditationReport) ([]byte, error) {
+    switch format {
+    case model.ReportPDF:
+        return PDF(r)
+    case model.ReportXLSX:
+        return XLSX(r)
+    }
+    return nil, fmt.Errorf("format laporan %q tidak dikenal", format)
+}
+
+// table is one section of the report. Cells are strings, ints or float64s;
+// the spreadsheet keeps numbers as numbers.
+type table struct {
+    Title  string
+    Sheet  string
+    Header []string
+    Rows   [][]interface{}
+    Note   string
+}
+
+// subtitle lists the cohort and generation time under the title
+func subtitle(r model.AccreditationReport) []string {
+    return []string{
+        "Jurusan: " + r.Jurusan,
+        "Tahun lulus: " + strconv.Itoa(r.TahunLulus),
+        "Dibuat: " + r.GeneratedAt.UTC().Format("02-01-2006 15:04 UTC"),
+    }
+}
+
+// months is a waiting time for the summary, "-" without employed alumni
+func months(m *float64) interface{} {
+    if m == nil {
+        return "-"
+    }
+    return *m
+}
+
+func tables(r model.AccreditationReport) []table {
+    summary := table{
+        Title:  "Ringkasan",
+        Sheet:  "Ringkasan",
+        Header: []string{"Indikator", "Nilai"},
+        Rows: [][]interface{}{
+            {"Jumlah lulusan", r.Alumni},
+            {"Jumlah responden", r.Respondents},
+            {"Tingkat respons (%)", r.ResponseRate},
+            {"Responden bekerja", r.Employed},
+            {"Tingkat keterserapan (%)", r.EmploymentRate},
+            {"Median masa tunggu (bulan)", months(r.WaitingTime.MedianWaitingMonths)},
+            {"Rata-rata masa tunggu (bulan)", months(r.WaitingTime.MeanWaitingMonths)},
+        },
+        Note: "Responden adalah alumni yang mencatat minimal satu pekerjaan; responden bekerja masih memiliki pekerjaan aktif.",
+    }
+
+    waiting := table{
+        Title:  "Masa Tunggu Pekerjaan Pertama",
+        Sheet:  "Masa Tunggu",
+        Header: []string{"Masa tunggu", "Jumlah", "Persentase (%)"},
+        Note:   "Dihitung dari Januari tahun lulus sampai mulai pekerjaan pertama.",
+    }
+    if r.WaitingTime.Suppressed {
+        waiting.Note = fmt.Sprintf("Tidak ditampilkan: kurang dari %d alumni bekerja.", r.MinGroupSize)
+    }
+    for _, band := range r.WaitingTime.Bands {
+        waiting.Rows = append(waiting.Rows, []interface{}{band.Label, band.Total, band.Percent})
+    }
+
+    industries := table{
+        Title:  "Bidang Industri",
+        Sheet:  "Bidang Industri",
+        Header: []string{"Bidang industri", "Jumlah pekerjaan", "Persentase (%)"},
+        Note:   fmt.Sprintf("Bidang dengan kurang dari %d alumni digabung ke %s.", r.MinGroupSize, model.OtherGroup),
+    }
+    for _, group := range r.Industries.Groups {
+        industries.Rows = append(industries.Rows, []interface{}{group.Value, group.Total, group.Percent})
+    }
+
+    salary := table{
+        Title:  "Gaji Pekerjaan Pertama",
+        Sheet:  "Gaji",
+        Header: []string{"Gaji per bulan (Rp)", "Jumlah", "Persentase (%)"},
+        Note:   fmt.Sprintf("Dari %d responden yang melaporkan gaji pekerjaan pertama dalam Rupiah.", r.Salaries),
+    }
+    if r.SalarySuppressed {
+        salary.Note = fmt.Sprintf("Tidak ditampilkan: kurang dari %d responden melaporkan gaji.", r.MinGroupSize)
+    } else {
+        for _, band := range r.SalaryBands {
+            salary.Rows = append(salary.Rows, []interface{}{band.Label, band.Total, band.Percent})
+        }
+    }
+
+    return []table{summary, waiting, industries, salary}
+}
+
+// text writes a cell the Indonesian way, with a decimal comma
+func text(cell interface{}) string {
+    switch v := cell.(type) {
+    case int:
+        return strconv.Itoa(v)
+    case float64:
+        return strings.Replace(strconv.FormatFloat(v, 'f', 2, 64), ".", ",", 1)
+    }
+    return fmt.Sprint(cell)
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+    "bytes"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+
+    "github.com/xuri/excelize/v2"
+)
+
+func sample() model.AccreditationReport {
+    median := 6.5
+    return model.AccreditationReport{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022, MinGroupSize: 3,
+        GeneratedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC),
+        Alumni: 4, Respondents: 3, ResponseRate: 75, Employed: 2, EmploymentRate: 66.67,
+        WaitingTime:      model.WaitingTimeStats{MedianWaitingMonths: &median},
+        SalarySuppressed: true,
+    }
+}
+
+func TestRenderPDF(t *testing.T) {
+    data, err := Render(model.ReportPDF, sample())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.HasPrefix(data, []byte("%PDF")) {
+        t.Errorf("not a pdf: %q", data[:16])
+    }
+}
+
+func TestRenderXLSX(t *testing.T) {
+    data, err := Render(model.ReportXLSX, sample())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    f, err := excelize.OpenReader(bytes.NewReader(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+
+    if sheets := f.GetSheetList(); len(sheets) != 4 || sheets[0] != "Ringkasan" || sheets[3] != "Gaji" {
+        t.Errorf("sheets = %v", sheets)
+    }
+    tests := []struct {
+        sheet, cell, want string
+    }{
+        {"Ringkasan", "A1", Title},
+        {"Ringkasan", "A2", "Jurusan: Teknik Informatika"},
+        {"Ringkasan", "A7", "Indikator"},
+        {"Ringkasan", "B8", "4"},
+        {"Ringkasan", "B10", "75"},
+        {"Ringkasan", "B13", "6.5"},
+        {"Ringkasan", "B14", "-"},
+        {"Gaji", "A9", "Tidak ditampilkan: kurang dari 3 responden melaporkan gaji."},
+    }
+    for _, tt := range tests {
+        got, err := f.GetCellValue(tt.sheet, tt.cell)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got != tt.want {
+            t.Errorf("%s!%s = %q, want %q", tt.sheet, tt.cell, got, tt.want)
+        }
+    }
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+    if _, err := Render("docx", sample()); err == nil {
+        t.Error("expected an error for an unknown format")
+    }
+}
+
+func TestText(t *testing.T) {
+    for cell, want := range map[interface{}]string{12: "12", 66.67: "66,67", 75.0: "75,00", "-": "-"} {
+        if got := text(cell); got != want {
+            t.Errorf("text(%v) = %q, want %q", cell, got, want)
+        }
+    }
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+    "go-fiber/app/model"
+
+    "github.com/xuri/excelize/v2"
+)
+
+// XLSX renders the report as a workbook with one sheet per section. The
+// title and cohort head every sheet so each can be copied on its own.
+func XLSX(r model.AccreditationReport) ([]byte, error) {
+    f := excelize.NewFile()
+    defer f.Close()
+
+    err := f.SetDocProps(&excelize.DocProperties{
+        Title:   Title + " " + r.Jurusan,
+        Creator: "alumni-api",
+        Created: r.GeneratedAt.UTC().Format("2006-01-02T15:04:05Z"),
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+    if err != nil {
+        return nil, err
+    }
+    header, err := f.NewStyle(&excelize.Style{
+        Font:   &excelize.Font{Bold: true},
+        Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DCE6F1"}},
+        Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+    })
+    if err != nil {
+        return nil, err
+    }
+    note, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Italic: true, Size: 9}})
+    if err != nil {
+        return nil, err
+    }
+
+    for i, t := range tables(r) {
+        sheet := t.Sheet
+        if i == 0 {
+            err = f.SetSheetName("Sheet1", sheet)
+        } else {
+            _, err = f.NewSheet(sheet)
+        }
+        if err != nil {
+            return nil, err
+        }
+
+        lines := append([]string{Title}, subtitle(r)...)
+        for row, line := range lines {
+            if err := f.SetCellValue(sheet, cell(1, row+1), line); err != nil {
+                return nil, err
+            }
+        }
+        if err := f.SetCellStyle(sheet, "A1", "A1", bold); err != nil {
+            return nil, err
+        }
+
+        row := len(lines) + 2
+        if err := f.SetCellValue(sheet, cell(1, row), t.Title); err != nil {
+            return nil, err
+        }
+        if err := f.SetCellStyle(sheet, cell(1, row), cell(1, row), bold); err != nil {
+            return nil, err
+        }
+
+        row++
+        if err := f.SetSheetRow(sheet, cell(1, row), &t.Header); err != nil {
+            return nil, err
+        }
+        if err := f.SetCellStyle(sheet, cell(1, row), cell(len(t.Header), row), header); err != nil {
+            return nil, err
+        }
+        for _, values := range t.Rows {
+            row++
+            if err := f.SetSheetRow(sheet, cell(1, row), &values); err != nil {
+                return nil, err
+            }
+        }
+
+        if t.Note != "" {
+            row += 2
+            if err := f.SetCellValue(sheet, cell(1, row), t.Note); err != nil {
+                return nil, err
+            }
+            if err := f.SetCellStyle(sheet, cell(1, row), cell(1, row), note); err != nil {
+                return nil, err
+            }
+        }
+
+        if err := f.SetColWidth(sheet, "A", "A", 36); err != nil {
+            return nil, err
+        }
+        if err := f.SetColWidth(sheet, "B", "C", 18); err != nil {
+            return nil, err
+        }
+    }
+
+    buf, err := f.WriteToBuffer()
+    if err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// cell names the cell at col and row, both counted from 1
+func cell(col, row int) string {
+    name, _ := excelize.CoordinatesToCellName(col, row)
+    return name
+}
@@ -1,33 +1,28 @@
 package routes
 
 import (
+    "go-fiber/app/repository"
     "go-fiber/app/service"
+    "go-fiber/config"
     "go-fiber/middleware"
     
     "github.com/gofiber/fiber/v2"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func AlumniRoutes(app *fiber.App, db *mongo.Database) {
-    alumni := app.Group("/alumni", middleware.AuthRequired())
+func AlumniRoutes(app *fiber.App, s *service.AlumniService) {
+    alumni := app.Group("/alumni", middleware.RequestTimeout(config.RequestTimeout("alumni")), middleware.BodyLimit(config.BodyLimit("alumni")), middleware.AuthRequired(), middleware.RateLimit("alumni"))
 
-    alumni.Get("/", func(c *fiber.Ctx) error {
-        return service.GetAllAlumniServiceDatatable(c, db)
-    })
+    alumni.Get("/", middleware.ConditionalGet(repository.AlumniCacheNamespace), s.GetAllAlumniDatatable)
 
-    alumni.Post("/", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.CreateAlumniService(c, db)
-    })
+    alumni.Post("/", middleware.AdminOnly(), s.CreateAlumni)
 
-    alumni.Put("/:id", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.UpdateAlumniService(c, db)
-    })
+    alumni.Put("/:id", middleware.AdminOnly(), s.UpdateAlumni)
 
-    alumni.Delete("/:id", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.DeleteAlumniService(c, db)
-    })
+    alumni.Delete("/:id", middleware.AdminOnly(), s.DeleteAlumni)
 
-    alumni.Get("/stats/jurusan", func(c *fiber.Ctx) error {
-        return service.GetAlumniStatsService(c, db)
-    })
+    alumni.Post("/bulk", middleware.AdminOnly(), s.BulkAlumni)
+
+    alumni.Get("/:id/timeline", middleware.ConditionalGet(repository.AlumniCacheNamespace, repository.PekerjaanCacheNamespace), s.GetAlumniTimeline)
+
+    alumni.Get("/stats/jurusan", middleware.RequestTimeout(config.RequestTimeout("stats")), middleware.ConditionalGet(repository.AlumniCacheNamespace), s.GetAlumniStats)
 }
\ No newline at end of file
@@ -4,13 +4,14 @@ package routes
 import (
     "go-fiber/app/model"
     "go-fiber/app/service"
+    "go-fiber/config"
+    "go-fiber/middleware"
     
     "github.com/gofiber/fiber/v2"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func AuthRoutes(app *fiber.App, db *mongo.Database) {
-    auth := app.Group("/auth")
+func AuthRoutes(app *fiber.App, s *service.AuthService) {
+    auth := app.Group("/auth", middleware.RequestTimeout(config.RequestTimeout("auth")), middleware.BodyLimit(config.BodyLimit("auth")), middleware.RateLimit("auth"))
 
     auth.Post("/login", func(c *fiber.Ctx) error {
         var req model.LoginRequest
@@ -21,7 +22,7 @@ func AuthRoutes(app *fiber.App, db *mongo.Database) {
             })
         }
 
-        response, err := service.LoginService(db, req)
+        response, err := s.Login(c.UserContext(), req)
         if err != nil {
             return c.Status(401).JSON(fiber.Map{
                 "error":   err.Error(),
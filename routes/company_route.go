@@ -0,0 +1,28 @@
+package routes
+
+import (
+    "go-fiber/app/service"
+    "go-fiber/config"
+    "go-fiber/middleware"
+    
+    "github.com/gofiber/fiber/v2"
+)
+
+func CompanyRoutes(app *fiber.App, s *service.CompanyService) {
+    companies := app.Group("/companies", middleware.RequestTimeout(config.RequestTimeout("companies")), middleware.BodyLimit(config.BodyLimit("companies")), middleware.AuthRequired(), middleware.RateLimit("companies"))
+
+    companies.Get("/", middleware.ConditionalGet(), s.GetAllCompaniesDatatable)
+
+    companies.Get("/suggest", s.SuggestCompanies)
+
+    companies.Get("/:id", middleware.ConditionalGet(), s.GetCompany)
+
+    // Everyone who has worked there, trashed pekerjaan excluded
+    companies.Get("/:id/alumni", middleware.AdminOnly(), middleware.ConditionalGet(), s.GetCompanyAlumni)
+
+    companies.Post("/", middleware.AdminOnly(), s.CreateCompany)
+
+    companies.Put("/:id", middleware.AdminOnly(), s.UpdateCompany)
+
+    companies.Delete("/:id", middleware.AdminOnly(), s.DeleteCompany)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+    "sync"
+
+    "go-fiber/docs"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+func DocsRoutes(app *fiber.App) {
+    // The spec is built on first request so it sees every registered route
+    var (
+        once sync.Once
+        spec docs.Schema
+    )
+
+    app.Get("/openapi.json", func(c *fiber.Ctx) error {
+        once.Do(func() {
+            spec = docs.Build(app.GetRoutes(true))
+        })
+        return c.JSON(spec)
+    })
+
+    app.Get("/docs", func(c *fiber.Ctx) error {
+        c.Type("html", "utf-8")
+        c.Set(fiber.HeaderContentSecurityPolicy, docs.ContentSecurityPolicy)
+        return c.Send(docs.IndexHTML)
+    })
+}
@@ -0,0 +1,87 @@
+package routes
+
+import (
+    "encoding/json"
+    "io"
+    "net/http/httptest"
+    "testing"
+
+    "go-fiber/app/repository"
+    "go-fiber/app/service"
+    "go-fiber/docs"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+func newDocsTestApp() *fiber.App {
+    app := fiber.New()
+    RegisterRoutes(app, service.NewServices(repository.NewMemoryRepositories()))
+    return app
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+    app := newDocsTestApp()
+    routes := app.GetRoutes(true)
+
+    for _, route := range docs.Undocumented(routes) {
+        t.Errorf("route %s is not described in docs.Operations", route)
+    }
+    for _, op := range docs.Unregistered(routes) {
+        t.Errorf("docs.Operations describes %s but no such route is registered", op)
+    }
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+    app := newDocsTestApp()
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if resp.StatusCode != 200 {
+        t.Fatalf("status = %d, want 200", resp.StatusCode)
+    }
+
+    body, _ := io.ReadAll(resp.Body)
+    var spec struct {
+        OpenAPI    string                            `json:"openapi"`
+        Paths      map[string]map[string]interface{} `json:"paths"`
+        Components struct {
+            Schemas         map[string]interface{} `json:"schemas"`
+            SecuritySchemes map[string]interface{} `json:"securitySchemes"`
+        } `json:"components"`
+    }
+    if err := json.Unmarshal(body, &spec); err != nil {
+        t.Fatalf("invalid JSON: %v", err)
+    }
+
+    if spec.OpenAPI != "3.1.0" {
+        t.Errorf("openapi = %q, want 3.1.0", spec.OpenAPI)
+    }
+    if _, ok := spec.Paths["/alumni/{id}"]["put"]; !ok {
+        t.Error("missing PUT /alumni/{id}")
+    }
+    for _, name := range []string{"AlumniResponse", "PekerjaanResponse", "MetaInfo", "UserListResponse", "ErrorResponse"} {
+        if _, ok := spec.Components.Schemas[name]; !ok {
+            t.Errorf("missing component schema %s", name)
+        }
+    }
+    if _, ok := spec.Components.SecuritySchemes["bearerAuth"]; !ok {
+        t.Error("missing bearerAuth security scheme")
+    }
+}
+
+func TestDocsUI(t *testing.T) {
+    app := newDocsTestApp()
+
+    resp, err := app.Test(httptest.NewRequest("GET", "/docs", nil))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if resp.StatusCode != 200 {
+        t.Fatalf("status = %d, want 200", resp.StatusCode)
+    }
+    if ct := resp.Header.Get("Content-Type"); ct != "text/html; charset=utf-8" {
+        t.Errorf("content type = %q", ct)
+    }
+}
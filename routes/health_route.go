@@ -0,0 +1,14 @@
+package routes
+
+import (
+    "go-fiber/app/service"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// HealthRoutes registers the Kubernetes probes. They need no token and skip
+// the request timeout, the readiness checks bound themselves.
+func HealthRoutes(app *fiber.App, s *service.HealthService) {
+    app.Get("/healthz", s.Liveness)
+    app.Get("/readyz", s.Readiness)
+}
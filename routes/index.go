@@ -1,13 +1,20 @@
 package routes
 
 import (
-    "go.mongodb.org/mongo-driver/mongo"
+    "go-fiber/app/service"
+
     "github.com/gofiber/fiber/v2"
 )
 
-func RegisterRoutes(app *fiber.App, db *mongo.Database) {
-    AlumniRoutes(app, db)
-    PekerjaanRoutes(app, db)
-    AuthRoutes(app, db) 
-    UserRoutes(app, db)
+func RegisterRoutes(app *fiber.App, services *service.Services) {
+    AlumniRoutes(app, services.Alumni)
+    PekerjaanRoutes(app, services.Pekerjaan)
+    CompanyRoutes(app, services.Company)
+    StatsRoutes(app, services.Stats)
+    ReportRoutes(app, services.Reports)
+    AuthRoutes(app, services.Auth)
+    UserRoutes(app, services.Auth)
+    HealthRoutes(app, services.Health)
+    MetricsRoutes(app)
+    DocsRoutes(app)
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package routes
+
+import (
+    "go-fiber/metrics"
+
+    "github.com/gofiber/fiber/v2"
+    "github.com/gofiber/fiber/v2/middleware/adaptor"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsRoutes serves the Prometheus metrics. Like the probes it needs no
+// token; keep it off the public ingress.
+func MetricsRoutes(app *fiber.App) {
+    app.Get("/metrics", adaptor.HTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+}
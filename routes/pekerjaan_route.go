@@ -1,47 +1,51 @@
 package routes
 
 import (
+    "go-fiber/app/repository"
     "go-fiber/app/service"
+    "go-fiber/config"
     "go-fiber/middleware"
     
     "github.com/gofiber/fiber/v2"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func PekerjaanRoutes(app *fiber.App, db *mongo.Database) {
-    pekerjaan := app.Group("/pekerjaan", middleware.AuthRequired())
+func PekerjaanRoutes(app *fiber.App, s *service.PekerjaanService) {
+    pekerjaan := app.Group("/pekerjaan", middleware.RequestTimeout(config.RequestTimeout("pekerjaan")), middleware.BodyLimit(config.BodyLimit("pekerjaan")), middleware.AuthRequired(), middleware.RateLimit("pekerjaan"))
 
-    pekerjaan.Get("/alumni/:alumni_id", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.GetPekerjaanByAlumniIDService(c, db)
-    })
+    pekerjaan.Get("/alumni/:alumni_id", middleware.AdminOnly(), middleware.ConditionalGet(repository.PekerjaanCacheNamespace), s.GetPekerjaanByAlumniID)
 
-    pekerjaan.Post("/", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.CreatePekerjaanService(c, db)
-    })
+    // Alumni submit their own pekerjaan, which wait in the moderation queue
+    pekerjaan.Post("/", s.CreatePekerjaan)
 
-    pekerjaan.Put("/:id", middleware.AdminOnly(), func(c *fiber.Ctx) error {
-        return service.UpdatePekerjaanService(c, db)
-    })
+    pekerjaan.Put("/:id", s.UpdatePekerjaan)
 
-    pekerjaan.Delete("/:id", func(c *fiber.Ctx) error {
-        return service.SoftDeletePekerjaanService(c, db)
-    })
+    pekerjaan.Delete("/:id", s.SoftDeletePekerjaan)
 
-    pekerjaan.Get("/", func(c *fiber.Ctx) error {
-        return service.GetAllPekerjaanServiceDatatable(c, db)
-    })
+    // Each operation is checked like the single-item endpoint above it
+    pekerjaan.Post("/bulk", s.BulkPekerjaan)
+
+    pekerjaan.Get("/", middleware.ConditionalGet(repository.PekerjaanCacheNamespace), s.GetAllPekerjaanDatatable)
 
     trash := pekerjaan.Group("/trash")
 
-    trash.Get("/", func(c *fiber.Ctx) error {
-        return service.GetTrashPekerjaanService(c, db)
-    })
+    // Ownership of trashed pekerjaan follows the alumni user links
+    trash.Get("/", middleware.ConditionalGet(repository.PekerjaanCacheNamespace, repository.AlumniCacheNamespace), s.GetTrashPekerjaan)
+
+    trash.Put("/restore/:id", s.RestorePekerjaan)
+
+    trash.Delete("/:id", s.HardDeletePekerjaan)
+
+    trash.Post("/restore", s.RestorePekerjaanBulk)
+
+    trash.Post("/purge", s.PurgePekerjaanBulk)
+
+    trash.Get("/purge/preview", middleware.AdminOnly(), s.GetTrashPurgeReport)
+
+    moderation := pekerjaan.Group("/moderation")
+
+    moderation.Get("/", middleware.ConditionalGet(repository.PekerjaanCacheNamespace, repository.AlumniCacheNamespace), s.GetModerationPekerjaan)
 
-    trash.Put("/restore/:id", func(c *fiber.Ctx) error {
-        return service.RestorePekerjaanService(c, db)
-    })
+    moderation.Put("/approve/:id", middleware.AdminOnly(), s.ApprovePekerjaan)
 
-    trash.Delete("/:id", func(c *fiber.Ctx) error {
-        return service.HardDeletePekerjaanService(c, db)
-    })
+    moderation.Put("/reject/:id", middleware.AdminOnly(), s.RejectPekerjaan)
 }
\ No newline at end of file
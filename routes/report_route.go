@@ -0,0 +1,23 @@
+package routes
+
+import (
+    "go-fiber/app/service"
+    "go-fiber/config"
+    "go-fiber/middleware"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// ReportRoutes serves the accreditation report jobs, for admins only
+func ReportRoutes(app *fiber.App, s *service.ReportService) {
+    reports := app.Group("/reports", middleware.RequestTimeout(config.RequestTimeout("reports")), middleware.BodyLimit(config.BodyLimit("reports")), middleware.AuthRequired(), middleware.AdminOnly(), middleware.RateLimit("reports"))
+
+    reports.Get("/", s.GetReports)
+
+    // Generated in the background; poll GET /reports/:id for the status
+    reports.Post("/", s.CreateReport)
+
+    reports.Get("/:id", s.GetReport)
+
+    reports.Get("/:id/download/:format", s.DownloadReport)
+}
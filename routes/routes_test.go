@@ -0,0 +1,282 @@
+package routes
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "go-fiber/app/model"
+    "go-fiber/app/repository"
+    "go-fiber/app/service"
+    "go-fiber/config"
+    "go-fiber/utils"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+type routesFixture struct {
+    app        *fiber.App
+    repos      repository.Repositories
+    services   *service.Services
+    adminToken string
+    userToken  string
+    alumni     model.Alumni
+    pekerjaan  model.Pekerjaan
+    company    model.Company
+}
+
+func newRoutesFixture(t *testing.T) routesFixture {
+    cfg := config.Defaults()
+    cfg.JWT.Secret = "test-secret"
+    config.Set(cfg)
+    t.Cleanup(func() { config.Set(config.Defaults()) })
+
+    store := repository.NewMemoryStore()
+    repos := store.Repositories()
+
+    hash, err := utils.HashPassword("secret123")
+    if err != nil {
+        t.Fatal(err)
+    }
+    store.AddUser(model.User{Username: "admin", Email: "admin@university.ac.id", Role: "admin", PasswordHash: hash})
+    user := store.AddUser(model.User{Username: "johndoe", Email: "john.doe@university.ac.id", Role: "user", PasswordHash: hash})
+
+    alumni, err := repos.Alumni.CreateAlumni(context.Background(), model.Alumni{
+        NIM: "1234567001", Nama: "John Doe", Jurusan: "Teknik Informatika",
+        Angkatan: 2018, TahunLulus: 2022, Email: "john.doe@university.ac.id",
+        NoTelepon: "081234567001", UserID: user.ID,
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    pekerjaan, err := repos.Pekerjaan.CreatePekerjaan(context.Background(), model.Pekerjaan{
+        AlumniID: alumni.ID, NamaPerusahaan: "PT Digital Indonesia", PosisiJabatan: "Backend Developer",
+        BidangIndustri: "Technology", LokasiKerja: "Jakarta", StatusPekerjaan: "aktif",
+        TanggalMulaiKerja: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    company := model.Company{BidangIndustri: "Technology", Lokasi: "Jakarta"}
+    company.SetNames("PT Digital Indonesia", []string{"Digital Indonesia"})
+    created, err := repos.Company.CreateCompany(context.Background(), company)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    app := fiber.New()
+    services := service.NewServices(repos)
+    RegisterRoutes(app, services)
+    // Report jobs queued by a test must not outlive it
+    t.Cleanup(services.Reports.Wait)
+
+    f := routesFixture{app: app, repos: repos, services: services, alumni: *alumni, pekerjaan: *pekerjaan, company: *created}
+    f.adminToken = f.login(t, "admin")
+    f.userToken = f.login(t, "johndoe")
+    return f
+}
+
+func (f routesFixture) request(t *testing.T, method, url, token string, body interface{}) (int, map[string]interface{}) {
+    t.Helper()
+
+    var reader io.Reader
+    if body != nil {
+        b, _ := json.Marshal(body)
+        reader = bytes.NewReader(b)
+    }
+    req := httptest.NewRequest(method, url, reader)
+    req.Header.Set("Content-Type", "application/json")
+    if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+
+    resp, err := f.app.Test(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+
+    var out map[string]interface{}
+    raw, _ := io.ReadAll(resp.Body)
+    if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+        if err := json.Unmarshal(raw, &out); err != nil {
+            t.Fatalf("%s %s: invalid JSON %q", method, url, raw)
+        }
+    }
+    return resp.StatusCode, out
+}
+
+func (f routesFixture) login(t *testing.T, username string) string {
+    t.Helper()
+
+    status, body := f.request(t, "POST", "/auth/login", "", model.LoginRequest{Username: username, Password: "secret123"})
+    if status != 200 {
+        t.Fatalf("login %s: status = %d, body = %v", username, status, body)
+    }
+    return body["data"].(map[string]interface{})["token"].(string)
+}
+
+func TestLoginRoute(t *testing.T) {
+    f := newRoutesFixture(t)
+
+    status, body := f.request(t, "POST", "/auth/login", "", model.LoginRequest{Username: "admin", Password: "wrong"})
+    if status != 401 || body["success"] != false {
+        t.Errorf("wrong password: status = %d, body = %v", status, body)
+    }
+
+    status, _ = f.request(t, "POST", "/auth/login", "", "garbage")
+    if status != 400 {
+        t.Errorf("invalid body: status = %d, want 400", status)
+    }
+}
+
+func TestAuthRequired(t *testing.T) {
+    f := newRoutesFixture(t)
+
+    status, body := f.request(t, "GET", "/alumni", "", nil)
+    if status != 401 || body["error"] != "Missing authorization header" {
+        t.Errorf("no token: status = %d, body = %v", status, body)
+    }
+
+    status, _ = f.request(t, "GET", "/alumni", "not-a-jwt", nil)
+    if status != 401 {
+        t.Errorf("bad token: status = %d, want 401", status)
+    }
+}
+
+// TestRouteAccess hits every registered route as anonymous, user and admin
+func TestRouteAccess(t *testing.T) {
+    f := newRoutesFixture(t)
+    alumniID := f.alumni.ID.Hex()
+    pekerjaanID := f.pekerjaan.ID.Hex()
+    companyID := f.company.ID.Hex()
+
+    report, err := f.repos.Report.CreateReport(context.Background(), model.Report{
+        Jurusan: "Teknik Informatika", TahunLulus: 2022, Formats: []string{model.ReportPDF},
+        Status: model.ReportDone, CreatedAt: time.Now(),
+        Files: []model.ReportFile{{Format: model.ReportPDF, Name: "tracer-study-teknik-informatika-2022.pdf", ContentType: "application/pdf", Size: 4}},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := f.repos.Report.SaveReportFile(context.Background(), model.ReportContent{ReportID: report.ID, Format: model.ReportPDF, Data: []byte("%PDF")}); err != nil {
+        t.Fatal(err)
+    }
+    reportID := report.ID.Hex()
+    reportBody := model.CreateReportRequest{Jurusan: "Teknik Informatika", TahunLulus: 2022}
+
+    alumniBody := model.UpdateAlumniRequest{
+        NIM: "1234567001", Nama: "John Doe", Jurusan: "Teknik Informatika",
+        Angkatan: 2018, TahunLulus: 2022, Email: "john.doe@university.ac.id", NoTelepon: "081234567001",
+    }
+    pekerjaanBody := model.UpdatePekerjaanRequest{
+        AlumniID: alumniID, NamaPerusahaan: "PT Digital Indonesia", PosisiJabatan: "Backend Developer",
+        BidangIndustri: "Technology", LokasiKerja: "Jakarta", StatusPekerjaan: "aktif",
+        TanggalMulaiKerja: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+    }
+    endedAt := time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)
+    pastBody := pekerjaanBody
+    pastBody.NamaPerusahaan, pastBody.StatusPekerjaan = "PT Startup Indonesia", "resign"
+    pastBody.TanggalMulaiKerja, pastBody.TanggalSelesaiKerja = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), &endedAt
+    alumniBulk := model.AlumniBulkRequest{Operations: []model.AlumniBulkOperation{
+        {Op: model.BulkUpdate, ID: alumniID, Alumni: &model.CreateAlumniRequest{
+            NIM: alumniBody.NIM, Nama: alumniBody.Nama, Jurusan: alumniBody.Jurusan, Angkatan: alumniBody.Angkatan,
+            TahunLulus: alumniBody.TahunLulus, Email: alumniBody.Email, NoTelepon: alumniBody.NoTelepon,
+        }},
+    }}
+    pastCreate := model.CreatePekerjaanRequest(pastBody)
+    pekerjaanBulk := model.PekerjaanBulkRequest{Operations: []model.PekerjaanBulkOperation{
+        {Op: model.BulkCreate, Pekerjaan: &pastCreate},
+    }}
+    companyBody := model.UpdateCompanyRequest{
+        Nama: "PT Digital Indonesia", Aliases: []string{"Digital Indonesia"}, BidangIndustri: "Technology", Lokasi: "Jakarta",
+    }
+
+    tests := []struct {
+        method, path string
+        body         interface{}
+        public       bool
+        adminOnly    bool
+        userStatus   int // Expected status for the owning user when not admin only
+        adminStatus  int
+    }{
+        {"GET", "/alumni", nil, false, false, 200, 200},
+        {"POST", "/alumni", alumniBody, false, true, 0, 201},
+        {"PUT", "/alumni/" + alumniID, alumniBody, false, true, 0, 200},
+        {"POST", "/alumni/bulk", alumniBulk, false, true, 0, 200},
+        {"GET", "/alumni/stats/jurusan", nil, false, false, 200, 200},
+        {"GET", "/alumni/" + alumniID + "/timeline", nil, false, false, 200, 200},
+        {"GET", "/pekerjaan", nil, false, false, 200, 200},
+        {"GET", "/pekerjaan/alumni/" + alumniID, nil, false, true, 0, 200},
+        {"POST", "/pekerjaan", pastBody, false, false, 201, 201},
+        {"PUT", "/pekerjaan/" + pekerjaanID, pekerjaanBody, false, false, 200, 200},
+        {"POST", "/pekerjaan/bulk", pekerjaanBulk, false, false, 200, 200},
+        {"GET", "/pekerjaan/moderation", nil, false, false, 200, 200},
+        {"PUT", "/pekerjaan/moderation/approve/" + pekerjaanID, nil, false, true, 0, 200},
+        {"PUT", "/pekerjaan/moderation/reject/" + pekerjaanID, model.ModerasiRequest{}, false, true, 0, 409}, // approved just above
+        {"GET", "/pekerjaan/trash", nil, false, false, 200, 200},
+        {"DELETE", "/pekerjaan/" + pekerjaanID, nil, false, false, 200, 200},
+        {"PUT", "/pekerjaan/trash/restore/" + pekerjaanID, nil, false, false, 200, 404},
+        {"DELETE", "/pekerjaan/" + pekerjaanID, nil, false, false, 200, 200},
+        {"DELETE", "/pekerjaan/trash/" + pekerjaanID, nil, false, false, 200, 404},
+        {"POST", "/pekerjaan/trash/restore", model.TrashBulkRequest{IDs: []string{pekerjaanID}}, false, false, 200, 200},
+        {"POST", "/pekerjaan/trash/purge", model.TrashBulkRequest{IDs: []string{pekerjaanID}}, false, false, 200, 200},
+        {"GET", "/pekerjaan/trash/purge/preview", nil, false, true, 0, 200},
+        {"GET", "/companies", nil, false, false, 200, 200},
+        {"GET", "/companies/suggest?nama=Digital+Indonesia", nil, false, false, 200, 200},
+        {"GET", "/companies/" + companyID, nil, false, false, 200, 200},
+        {"GET", "/companies/" + companyID + "/alumni", nil, false, true, 0, 200},
+        {"POST", "/companies", model.CreateCompanyRequest{Nama: "PT Sumber Data"}, false, true, 0, 201},
+        {"PUT", "/companies/" + companyID, companyBody, false, true, 0, 200},
+        {"DELETE", "/companies/" + companyID, nil, false, true, 0, 200},
+        {"GET", "/stats/cohorts?jurusan=Teknik+Informatika&top=5", nil, false, false, 200, 200},
+        {"GET", "/stats/waiting-time?tahun_lulus_from=2020", nil, false, false, 200, 200},
+        {"GET", "/stats/distribution?by=lokasi_kerja", nil, false, false, 200, 200},
+        {"GET", "/stats/salary?by=angkatan", nil, false, false, 200, 200},
+        {"GET", "/stats/trends?currency=idr", nil, false, false, 200, 200},
+        {"GET", "/stats/career-paths?jurusan=Teknik+Informatika&angkatan=2018", nil, false, false, 200, 200},
+        {"GET", "/reports", nil, false, true, 0, 200},
+        {"POST", "/reports", reportBody, false, true, 0, 202},
+        {"GET", "/reports/" + reportID, nil, false, true, 0, 200},
+        {"GET", "/reports/" + reportID + "/download/pdf", nil, false, true, 0, 200},
+        {"GET", "/users", nil, false, true, 0, 200},
+        {"DELETE", "/alumni/" + alumniID, nil, false, true, 0, 200},
+        {"GET", "/healthz", nil, true, false, 200, 200},
+        {"GET", "/readyz", nil, true, false, 200, 200},
+        {"GET", "/metrics", nil, true, false, 200, 200},
+        {"GET", "/openapi.json", nil, true, false, 200, 200},
+        {"GET", "/docs", nil, true, false, 200, 200},
+    }
+
+    for _, tt := range tests {
+        name := tt.method + " " + tt.path
+
+        if !tt.public {
+            if status, _ := f.request(t, tt.method, tt.path, "", tt.body); status != 401 {
+                t.Errorf("%s anonymous: status = %d, want 401", name, status)
+            }
+        }
+
+        if tt.adminOnly {
+            if status, _ := f.request(t, tt.method, tt.path, f.userToken, tt.body); status != 403 {
+                t.Errorf("%s as user: status = %d, want 403", name, status)
+            }
+        } else {
+            if status, body := f.request(t, tt.method, tt.path, f.userToken, tt.body); status != tt.userStatus {
+                t.Errorf("%s as user: status = %d, want %d (%v)", name, status, tt.userStatus, body)
+            }
+        }
+
+        // Trash routes were already exercised by the owner above, so admin
+        // sees the follow-up state
+        if status, body := f.request(t, tt.method, tt.path, f.adminToken, tt.body); status != tt.adminStatus {
+            t.Errorf("%s as admin: status = %d, want %d (%v)", name, status, tt.adminStatus, body)
+        }
+    }
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+    "go-fiber/app/repository"
+    "go-fiber/app/service"
+    "go-fiber/config"
+    "go-fiber/middleware"
+
+    "github.com/gofiber/fiber/v2"
+)
+
+// StatsRoutes serves the analytics, which read alumni and pekerjaan alike
+func StatsRoutes(app *fiber.App, s *service.StatsService) {
+    stats := app.Group("/stats", middleware.RequestTimeout(config.RequestTimeout("stats")), middleware.BodyLimit(config.BodyLimit("stats")), middleware.AuthRequired(), middleware.RateLimit("stats"))
+    fresh := middleware.ConditionalGet(repository.AlumniCacheNamespace, repository.PekerjaanCacheNamespace)
+
+    stats.Get("/cohorts", fresh, s.GetCohortStats)
+
+    stats.Get("/waiting-time", fresh, s.GetWaitingTime)
+
+    stats.Get("/distribution", fresh, s.GetDistribution)
+
+    stats.Get("/salary", fresh, s.GetSalary)
+
+    stats.Get("/trends", fresh, s.GetTrends)
+
+    stats.Get("/career-paths", fresh, s.GetCareerPaths)
+}
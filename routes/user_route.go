@@ -2,14 +2,14 @@ package routes
 
 import (
     "go-fiber/app/service"
+    "go-fiber/config"
     "go-fiber/middleware"
     
     "github.com/gofiber/fiber/v2"
-    "go.mongodb.org/mongo-driver/mongo"
 )
 
-func UserRoutes(app *fiber.App, db *mongo.Database) {
-    users := app.Group("/users", middleware.AuthRequired(), middleware.AdminOnly())
+func UserRoutes(app *fiber.App, s *service.AuthService) {
+    users := app.Group("/users", middleware.RequestTimeout(config.RequestTimeout("users")), middleware.BodyLimit(config.BodyLimit("users")), middleware.AuthRequired(), middleware.RateLimit("users"), middleware.AdminOnly())
 
-    users.Get("/", service.GetUsersService(db))
+    users.Get("/", middleware.ConditionalGet(), s.GetUsers)
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+package tlsreload
+
+import (
+    "context"
+    "crypto/tls"
+    "log/slog"
+    "os"
+    "sync"
+    "time"
+)
+
+// Reloader serves a certificate read from files and picks up new files,
+// e.g. renewed by certbot or cert-manager, without a restart
+type Reloader struct {
+    certFile, keyFile string
+
+    mu       sync.RWMutex
+    cert     *tls.Certificate
+    modified time.Time
+}
+
+// New loads the key pair; it fails when the files cannot be read
+func New(certFile, keyFile string) (*Reloader, error) {
+    r := &Reloader{certFile: certFile, keyFile: keyFile}
+    if err := r.Reload(); err != nil {
+        return nil, err
+    }
+    return r, nil
+}
+
+// Reload reads the key pair again. On error the previous certificate is
+// kept.
+func (r *Reloader) Reload() error {
+    modified, err := r.lastModified()
+    if err != nil {
+        return err
+    }
+    cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+    if err != nil {
+        return err
+    }
+
+    r.mu.Lock()
+    r.cert = &cert
+    r.modified = modified
+    r.mu.Unlock()
+    return nil
+}
+
+// Watch reloads the key pair every interval in which one of the files
+// changed, until ctx is done
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+
+        modified, err := r.lastModified()
+        if err != nil {
+            slog.Warn("checking TLS certificate failed", "error", err)
+            continue
+        }
+        r.mu.RLock()
+        changed := modified.After(r.modified)
+        r.mu.RUnlock()
+        if !changed {
+            continue
+        }
+
+        if err := r.Reload(); err != nil {
+            slog.Error("reloading TLS certificate failed, keeping the previous one", "error", err)
+            continue
+        }
+        slog.Info("TLS certificate reloaded", "cert_file", r.certFile)
+    }
+}
+
+// lastModified is the later modification time of the two files
+func (r *Reloader) lastModified() (time.Time, error) {
+    var latest time.Time
+    for _, name := range []string{r.certFile, r.keyFile} {
+        info, err := os.Stat(name)
+        if err != nil {
+            return time.Time{}, err
+        }
+        if info.ModTime().After(latest) {
+            latest = info.ModTime()
+        }
+    }
+    return latest, nil
+}
+
+// GetCertificate returns the current certificate, for tls.Config
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.cert, nil
+}
+
+// Config is a TLS configuration serving the current certificate
+func (r *Reloader) Config() *tls.Config {
+    return &tls.Config{
+        MinVersion:     tls.VersionTLS12,
+        GetCertificate: r.GetCertificate,
+    }
+}
@@ -0,0 +1,102 @@
+package tlsreload
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// writeKeyPair writes a self-signed certificate for name, modified at mtime
+func writeKeyPair(t *testing.T, dir, name string, mtime time.Time) (string, string) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(time.Now().UnixNano()),
+        Subject:      pkix.Name{CommonName: name},
+        DNSNames:     []string{name},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    keyDER, err := x509.MarshalECPrivateKey(key)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+    os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+    os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+    for _, file := range []string{certFile, keyFile} {
+        os.Chtimes(file, mtime, mtime)
+    }
+    return certFile, keyFile
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+    t.Helper()
+    cert, _ := r.GetCertificate(nil)
+    parsed, err := x509.ParseCertificate(cert.Certificate[0])
+    if err != nil {
+        t.Fatal(err)
+    }
+    return parsed.Subject.CommonName
+}
+
+func TestWatchReloadsChangedFiles(t *testing.T) {
+    dir := t.TempDir()
+    start := time.Now().Add(-time.Minute)
+    certFile, keyFile := writeKeyPair(t, dir, "old.example.ac.id", start)
+
+    r, err := New(certFile, keyFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go r.Watch(ctx, 10*time.Millisecond)
+
+    writeKeyPair(t, dir, "new.example.ac.id", start.Add(time.Second))
+    deadline := time.Now().Add(2 * time.Second)
+    for commonName(t, r) != "new.example.ac.id" {
+        if time.Now().After(deadline) {
+            t.Fatal("renewed certificate was not loaded")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
+
+func TestReloadKeepsCertificateOnError(t *testing.T) {
+    dir := t.TempDir()
+    certFile, keyFile := writeKeyPair(t, dir, "old.example.ac.id", time.Now())
+
+    r, err := New(certFile, keyFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    os.WriteFile(keyFile, []byte("half written"), 0o600)
+    if err := r.Reload(); err == nil {
+        t.Fatal("Reload of a broken key should fail")
+    }
+    if commonName(t, r) != "old.example.ac.id" {
+        t.Error("previous certificate was dropped")
+    }
+
+    if _, err := New(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+        t.Error("New with a missing file should fail")
+    }
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "sync"
+
+    "go.mongodb.org/mongo-driver/event"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// MongoMonitor starts a client span for every MongoDB command, e.g.
+// "find alumni" or "count pekerjaan_alumni", as a child of the span in the
+// operation's context. Command documents are not recorded, they hold user
+// data. Pass it to options.Client().SetMonitor.
+func MongoMonitor() *event.CommandMonitor {
+    var spans sync.Map // commandKey -> trace.Span
+
+    end := func(key commandKey, err string) {
+        value, ok := spans.LoadAndDelete(key)
+        if !ok {
+            return
+        }
+        span := value.(trace.Span)
+        if err != "" {
+            span.SetStatus(codes.Error, err)
+        }
+        span.End()
+    }
+
+    return &event.CommandMonitor{
+        Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+            collection := ""
+            if value, err := e.Command.LookupErr(e.CommandName); err == nil {
+                collection, _ = value.StringValueOK()
+            }
+
+            name := e.CommandName
+            attrs := []attribute.KeyValue{
+                attribute.String("db.system", "mongodb"),
+                attribute.String("db.namespace", e.DatabaseName),
+                attribute.String("db.operation.name", e.CommandName),
+                attribute.String("db.mongodb.connection_id", e.ConnectionID),
+            }
+            if collection != "" {
+                name += " " + collection
+                attrs = append(attrs, attribute.String("db.collection.name", collection))
+            }
+
+            _, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+            if !span.IsRecording() {
+                return
+            }
+            spans.Store(commandKey{e.ConnectionID, e.RequestID}, span)
+        },
+        Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+            end(commandKey{e.ConnectionID, e.RequestID}, "")
+        },
+        Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+            failure := e.Failure
+            if failure == "" {
+                failure = fmt.Sprintf("%s failed", e.CommandName)
+            }
+            end(commandKey{e.ConnectionID, e.RequestID}, failure)
+        },
+    }
+}
+
+// commandKey matches the finished event to its started event; request IDs
+// are only unique per connection
+type commandKey struct {
+    connection string
+    request    int64
+}
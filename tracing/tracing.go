@@ -0,0 +1,83 @@
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "os"
+
+    "go-fiber/config"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by this application
+const tracerName = "go-fiber"
+
+// Setup installs the global tracer provider and the W3C trace-context and
+// baggage propagators. With the none exporter only propagation is set up and
+// spans are not recorded. The returned function flushes buffered spans.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+        propagation.TraceContext{},
+        propagation.Baggage{},
+    ))
+
+    var exporter sdktrace.SpanExporter
+    var err error
+    switch cfg.Exporter {
+    case config.TracingNone, "":
+        return func(context.Context) error { return nil }, nil
+    case config.TracingOTLP:
+        var opts []otlptracehttp.Option
+        if cfg.Endpoint != "" {
+            opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+        }
+        exporter, err = otlptracehttp.New(ctx, opts...)
+    case config.TracingStdout:
+        exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+    default:
+        err = fmt.Errorf("unknown span exporter %q", cfg.Exporter)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    provider := NewProvider(exporter, cfg, sdktrace.WithBatcher(exporter))
+    otel.SetTracerProvider(provider)
+    return provider.Shutdown, nil
+}
+
+// NewProvider builds the tracer provider for the configured service and
+// sample ratio. opts attach the exporter, e.g. sdktrace.WithSyncer in tests.
+func NewProvider(exporter sdktrace.SpanExporter, cfg config.TracingConfig, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+    res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+        attribute.String("service.name", cfg.ServiceName),
+    ))
+    if err != nil {
+        res = resource.Default()
+    }
+
+    opts = append([]sdktrace.TracerProviderOption{
+        sdktrace.WithResource(res),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+    }, opts...)
+    return sdktrace.NewTracerProvider(opts...)
+}
+
+// Tracer returns the tracer of this application from the global provider
+func Tracer() trace.Tracer {
+    return otel.Tracer(tracerName)
+}
+
+// Start starts a span named after the operation, e.g.
+// "AlumniService.GetAlumni", as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+    return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "io"
+    "testing"
+
+    "go-fiber/config"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/event"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exportedSpan is the part of the stdout exporter output the tests read
+type exportedSpan struct {
+    Name        string
+    SpanContext struct{ TraceID, SpanID string }
+    Parent      struct{ TraceID, SpanID string }
+    SpanKind    int
+    Status      struct{ Code string }
+    Attributes  []struct {
+        Key   string
+        Value struct{ Value interface{} }
+    }
+}
+
+// useStdout installs a provider exporting spans synchronously as JSON into
+// the returned buffer, restoring the previous provider after the test
+func useStdout(t *testing.T) *bytes.Buffer {
+    t.Helper()
+
+    var buf bytes.Buffer
+    exporter, err := stdouttrace.New(stdouttrace.WithWriter(&buf))
+    if err != nil {
+        t.Fatal(err)
+    }
+    cfg := config.Defaults().Tracing
+    provider := NewProvider(exporter, cfg, sdktrace.WithSyncer(exporter))
+
+    previous := otel.GetTracerProvider()
+    otel.SetTracerProvider(provider)
+    t.Cleanup(func() {
+        provider.Shutdown(context.Background())
+        otel.SetTracerProvider(previous)
+    })
+    return &buf
+}
+
+func readSpans(t *testing.T, buf *bytes.Buffer) []exportedSpan {
+    t.Helper()
+
+    var spans []exportedSpan
+    decoder := json.NewDecoder(buf)
+    for {
+        var span exportedSpan
+        if err := decoder.Decode(&span); err == io.EOF {
+            return spans
+        } else if err != nil {
+            t.Fatal(err)
+        }
+        spans = append(spans, span)
+    }
+}
+
+func TestMongoMonitorSpans(t *testing.T) {
+    buf := useStdout(t)
+    monitor := MongoMonitor()
+
+    ctx, parent := Start(context.Background(), "AlumniService.GetAllAlumniDatatable")
+
+    find, _ := bson.Marshal(bson.D{{Key: "find", Value: "alumni"}, {Key: "filter", Value: bson.D{}}})
+    count, _ := bson.Marshal(bson.D{{Key: "count", Value: "alumni"}})
+
+    monitor.Started(ctx, &event.CommandStartedEvent{Command: find, CommandName: "find", DatabaseName: "alumni_db", RequestID: 1, ConnectionID: "db:27017[-1]"})
+    monitor.Started(ctx, &event.CommandStartedEvent{Command: count, CommandName: "count", DatabaseName: "alumni_db", RequestID: 2, ConnectionID: "db:27017[-1]"})
+    monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, ConnectionID: "db:27017[-1]"}})
+    monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "count", RequestID: 2, ConnectionID: "db:27017[-1]"}, Failure: "timeout"})
+    parent.End()
+
+    spans := readSpans(t, buf)
+    if len(spans) != 3 {
+        t.Fatalf("spans = %+v", spans)
+    }
+    find0, count0, service := spans[0], spans[1], spans[2]
+
+    if find0.Name != "find alumni" || count0.Name != "count alumni" {
+        t.Fatalf("names = %q, %q", find0.Name, count0.Name)
+    }
+    for _, span := range []exportedSpan{find0, count0} {
+        if span.Parent.SpanID != service.SpanContext.SpanID || span.SpanContext.TraceID != service.SpanContext.TraceID {
+            t.Errorf("%s is not a child of the service span", span.Name)
+        }
+    }
+    if count0.Status.Code != "Error" || find0.Status.Code == "Error" {
+        t.Errorf("status: find %q, count %q", find0.Status.Code, count0.Status.Code)
+    }
+}
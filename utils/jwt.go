@@ -1,10 +1,10 @@
 package utils
 
 import (
-    "os"
     "time"
     
     "go-fiber/app/model"
+    "go-fiber/config"
     
     "github.com/golang-jwt/jwt/v5"
     "go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,19 +16,19 @@ func GenerateToken(user model.User) (string, error) {
         Username: user.Username,
         Role:     user.Role,
         RegisteredClaims: jwt.RegisteredClaims{
-            ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+            ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Get().JWT.TTL)),
             IssuedAt:  jwt.NewNumericDate(time.Now()),
         },
     }
 
     token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    secret := os.Getenv("JWT_SECRET")
+    secret := config.Get().JWT.Secret
     
     return token.SignedString([]byte(secret))
 }
 
 func ParseToken(tokenString string) (*model.JWTClaims, error) {
-    secret := os.Getenv("JWT_SECRET")
+    secret := config.Get().JWT.Secret
     
     token, err := jwt.ParseWithClaims(tokenString, &model.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
         return []byte(secret), nil